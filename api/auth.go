@@ -0,0 +1,112 @@
+package api
+
+import (
+	"crypto/sha256"
+	"crypto/subtle"
+	"net/http"
+	"strings"
+
+	"github.com/OpenBazaar/openbazaar-go/core"
+	"github.com/OpenBazaar/openbazaar-go/repo"
+)
+
+// Scopes which can be granted to API tokens
+const (
+	ScopeRead     = "read"
+	ScopeListings = "listings"
+	ScopeOrders   = "orders"
+	ScopeWallet   = "wallet"
+	ScopeAdmin    = "admin"
+)
+
+var Scopes = []string{ScopeRead, ScopeListings, ScopeOrders, ScopeWallet, ScopeAdmin}
+
+// Does the list of granted scopes include the required one? Admin includes every scope.
+func hasScope(granted []string, required string) bool {
+	for _, s := range granted {
+		if s == required || s == ScopeAdmin {
+			return true
+		}
+	}
+	return false
+}
+
+func validScope(scope string) bool {
+	for _, s := range Scopes {
+		if s == scope {
+			return true
+		}
+	}
+	return false
+}
+
+// Tokens are random so a plain hash is enough to keep them out of the database
+func hashToken(token string) []byte {
+	h := sha256.Sum256([]byte(token))
+	return h[:]
+}
+
+// Checks the credentials on requests to the JSON API and the websocket
+type authenticator struct {
+	authenticated bool
+	cookie        http.Cookie
+	username      string
+	password      string
+	datastore     repo.Datastore
+}
+
+func newAuthenticator(node *core.OpenBazaarNode, authCookie http.Cookie, config repo.APIConfig) *authenticator {
+	return &authenticator{
+		authenticated: config.Authenticated,
+		cookie:        authCookie,
+		username:      config.Username,
+		password:      config.Password,
+		datastore:     node.Datastore,
+	}
+}
+
+// Return the scopes granted to a request. A request with an API token gets the token's
+// scopes. Otherwise the auth cookie or basic auth user gets full access, as does
+// anyone if authentication is disabled.
+func (a *authenticator) scopes(r *http.Request, token string) ([]string, bool) {
+	if token == "" {
+		if auth := r.Header.Get("Authorization"); strings.HasPrefix(auth, "Bearer ") {
+			token = strings.TrimPrefix(auth, "Bearer ")
+		}
+	}
+	if token != "" {
+		t, err := a.datastore.APITokens().GetByHash(hashToken(token))
+		if err != nil {
+			return nil, false
+		}
+		return t.Scopes, true
+	}
+	if !a.authenticated {
+		return []string{ScopeAdmin}, true
+	}
+	if a.username == "" || a.password == "" {
+		cookie, err := r.Cookie("OpenBazaar_Auth_Cookie")
+		if err != nil || subtle.ConstantTimeCompare([]byte(a.cookie.Value), []byte(cookie.Value)) != 1 {
+			return nil, false
+		}
+	} else {
+		username, password, ok := r.BasicAuth()
+		if !ok || username != a.username || subtle.ConstantTimeCompare([]byte(password), []byte(a.password)) != 1 {
+			return nil, false
+		}
+	}
+	return []string{ScopeAdmin}, true
+}
+
+// Return a copy of the request with the credentials it carries replaced so it can be logged
+func redactCredentials(r *http.Request) *http.Request {
+	redacted := *r
+	redacted.Header = make(http.Header)
+	for k, v := range r.Header {
+		if k == "Authorization" || k == "Cookie" {
+			v = []string{"[redacted]"}
+		}
+		redacted.Header[k] = v
+	}
+	return &redacted
+}
@@ -1,129 +1,81 @@
 package api
 
-import (
-	"net/http"
-	"strings"
-)
+// Build the routes of the JSON API. Each route carries the scope an API token needs to call it.
+func newRouter(i *jsonAPIHandler) *router {
+	rt := new(router)
 
-func put(i *jsonAPIHandler, path string, w http.ResponseWriter, r *http.Request) {
-	switch path {
-	case "/ob/profile", "/ob/profile/":
-		i.PUTProfile(w, r)
-	case "/ob/settings", "/ob/settings/":
-		i.PUTSettings(w, r)
-	case "/ob/moderator", "/ob/moderator/":
-		i.PUTModerator(w, r)
-	case "/ob/listing", "/ob/listing/":
-		i.PUTListing(w, r)
-	default:
-		ErrorResponse(w, http.StatusNotFound, "Not Found")
-	}
-}
+	// Profile, settings and moderation
+	rt.handle("POST", "/ob/profile", ScopeAdmin, i.POSTProfile)
+	rt.handle("PUT", "/ob/profile", ScopeAdmin, i.PUTProfile)
+	rt.handle("POST", "/ob/avatar", ScopeAdmin, i.POSTAvatar)
+	rt.handle("POST", "/ob/header", ScopeAdmin, i.POSTHeader)
+	rt.handle("GET", "/ob/settings", ScopeRead, i.GETSettings)
+	rt.handle("POST", "/ob/settings", ScopeAdmin, i.POSTSettings)
+	rt.handle("PUT", "/ob/settings", ScopeAdmin, i.PUTSettings)
+	rt.handle("PATCH", "/ob/settings", ScopeAdmin, i.PATCHSettings)
+	rt.handle("POST", "/ob/moderator", ScopeAdmin, i.POSTModerator)
+	rt.handle("PUT", "/ob/moderator", ScopeAdmin, i.PUTModerator)
+	rt.handle("DELETE", "/ob/moderator", ScopeAdmin, i.DELETEModerator)
+	rt.handle("GET", "/ob/moderators", ScopeRead, i.GETModerators)
+	rt.handle("GET", "/ob/config", ScopeRead, i.GETConfig)
 
-func post(i *jsonAPIHandler, path string, w http.ResponseWriter, r *http.Request) {
-	switch path {
-	case "/ob/listing", "/ob/listing/":
-		i.POSTListing(w, r)
-	case "/ob/purchase", "/ob/purchase/":
-		i.POSTPurchase(w, r)
-	case "/ob/follow", "/ob/follow/":
-		i.POSTFollow(w, r)
-	case "/ob/unfollow", "/ob/unfollow/":
-		i.POSTUnfollow(w, r)
-	case "/ob/profile", "/ob/profile/":
-		i.POSTProfile(w, r)
-	case "/ob/images", "/ob/images/":
-		i.POSTImage(w, r)
-	case "/wallet/spend", "/wallet/spend/":
-		i.POSTSpendCoins(w, r)
-	case "/ob/settings", "/ob/settings/":
-		i.POSTSettings(w, r)
-	case "/ob/inventory", "/ob/inventory/":
-		i.POSTInventory(w, r)
-	case "/ob/moderator", "/ob/moderator/":
-		i.POSTModerator(w, r)
-	case "/ob/avatar", "/ob/avatar/":
-		i.POSTAvatar(w, r)
-	case "/ob/header", "/ob/header/":
-		i.POSTHeader(w, r)
-	case "/ob/orderconfirmation", "/ob/orderconfirmation/":
-		i.POSTOrderConfirmation(w, r)
-	case "/ob/ordercancel", "/ob/ordercancel/":
-		i.POSTOrderCancel(w, r)
-	case "/ob/orderfulfillment", "/ob/orderfulfillment/":
-		i.POSTOrderFulfill(w, r)
-	case "/ob/ordercompletion", "/ob/ordercompletion/":
-		i.POSTOrderComplete(w, r)
-	case "/ob/refund", "/ob/refund/":
-		i.POSTRefund(w, r)
-	case "/wallet/resyncblockchain", "/wallet/resyncblockchain/":
-		i.POSTResyncBlockchain(w, r)
-	case "/ob/shutdown", "/ob/shutdown/":
-		i.POSTShutdown(w, r)
-	default:
-		ErrorResponse(w, http.StatusNotFound, "Not Found")
-	}
-}
+	// Listings
+	rt.handle("GET", "/ob/listings", ScopeRead, i.GETListings)
+	rt.handle("GET", "/ob/listing/{listingId}", ScopeRead, i.GETListing)
+	rt.handle("POST", "/ob/listing", ScopeListings, i.POSTListing)
+	rt.handle("PUT", "/ob/listing", ScopeListings, i.PUTListing)
+	rt.handle("DELETE", "/ob/listing", ScopeListings, i.DELETEListing)
+	rt.handle("GET", "/ob/inventory", ScopeRead, i.GETInventory)
+	rt.handle("POST", "/ob/inventory", ScopeListings, i.POSTInventory)
+	rt.handle("POST", "/ob/images", ScopeListings, i.POSTImage)
+	rt.handle("GET", "/ob/digitalasset/{slug}", ScopeRead, i.GETDigitalAsset)
+	rt.handle("POST", "/ob/digitalasset", ScopeListings, i.POSTDigitalAsset)
+	rt.handle("DELETE", "/ob/digitalasset", ScopeListings, i.DELETEDigitalAsset)
 
-func get(i *jsonAPIHandler, path string, w http.ResponseWriter, r *http.Request) {
-	switch {
-	case strings.Contains(path, "/ob/status/"):
-		i.GETStatus(w, r)
-	case strings.Contains(path, "/ob/peers"):
-		i.GETPeers(w, r)
-	case strings.Contains(path, "/ob/config"):
-		i.GETConfig(w, r)
-	case strings.Contains(path, "/wallet/address"):
-		i.GETAddress(w, r)
-	case strings.Contains(path, "/wallet/mnemonic"):
-		i.GETMnemonic(w, r)
-	case strings.Contains(path, "/wallet/balance"):
-		i.GETBalance(w, r)
-	case strings.Contains(path, "/ob/settings"):
-		i.GETSettings(w, r)
-	case strings.Contains(path, "/ob/closestpeers"):
-		i.GETClosestPeers(w, r)
-	case strings.Contains(path, "/ob/exchangerate"):
-		i.GETExchangeRate(w, r)
-	case strings.Contains(path, "/ob/followers"):
-		i.GETFollowers(w, r)
-	case strings.Contains(path, "/ob/following"):
-		i.GETFollowing(w, r)
-	case strings.Contains(path, "/ob/inventory"):
-		i.GETInventory(w, r)
-	case strings.Contains(path, "/ob/listings"):
-		i.GETListings(w, r)
-	case strings.Contains(path, "/ob/listing"):
-		i.GETListing(w, r)
-	case strings.Contains(path, "/ob/followsme"):
-		i.GETFollowsMe(w, r)
-	case strings.Contains(path, "/ob/isfollowing"):
-		i.GETIsFollowing(w, r)
-	case strings.Contains(path, "/ob/order"):
-		i.GETOrder(w, r)
-	case strings.Contains(path, "/ob/moderators"):
-		i.GETModerators(w, r)
-	default:
-		ErrorResponse(w, http.StatusNotFound, "Not Found")
-	}
-}
+	// Orders
+	rt.handle("GET", "/ob/orders", ScopeRead, i.GETOrders)
+	rt.handle("GET", "/ob/order/{orderId}", ScopeRead, i.GETOrder)
+	rt.handle("POST", "/ob/purchase", ScopeOrders, i.POSTPurchase)
+	rt.handle("POST", "/ob/orderconfirmation", ScopeOrders, i.POSTOrderConfirmation)
+	rt.handle("POST", "/ob/ordercancel", ScopeOrders, i.POSTOrderCancel)
+	rt.handle("POST", "/ob/orderfulfillment", ScopeOrders, i.POSTOrderFulfill)
+	rt.handle("POST", "/ob/ordercompletion", ScopeOrders, i.POSTOrderComplete)
+	// Refunds of direct orders are spent from the wallet
+	rt.handle("POST", "/ob/refund", ScopeWallet, i.POSTRefund)
+	rt.handle("POST", "/ob/recover", ScopeAdmin, i.POSTRecover)
 
-func patch(i *jsonAPIHandler, path string, w http.ResponseWriter, r *http.Request) {
-	switch path {
-	case "/ob/settings", "/ob/settings/":
-		i.PATCHSettings(w, r)
-	default:
-		ErrorResponse(w, http.StatusNotFound, "Not Found")
-	}
-}
+	// Peers and follows
+	rt.handle("GET", "/ob/peers", ScopeRead, i.GETPeers)
+	rt.handle("GET", "/ob/health", ScopeRead, i.GETHealth)
+	rt.handle("GET", "/ob/publish", ScopeRead, i.GETPublishStatus)
+	rt.handle("GET", "/ob/crosspost", ScopeRead, i.GETCrosspostStatus)
+	rt.handle("POST", "/ob/fetchmessages", ScopeOrders, i.POSTFetchMessages)
+	rt.handle("GET", "/ob/status/{peerId:peer}", ScopeRead, i.GETStatus)
+	rt.handle("GET", "/ob/closestpeers/{peerId:peer}", ScopeRead, i.GETClosestPeers)
+	rt.handle("POST", "/ob/follow", ScopeAdmin, i.POSTFollow)
+	rt.handle("POST", "/ob/unfollow", ScopeAdmin, i.POSTUnfollow)
+	rt.handle("GET", "/ob/followers", ScopeRead, i.GETFollowers)
+	rt.handle("GET", "/ob/following", ScopeRead, i.GETFollowing)
+	rt.handle("GET", "/ob/followsme/{peerId:peer}", ScopeRead, i.GETFollowsMe)
+	rt.handle("GET", "/ob/isfollowing/{peerId:peer}", ScopeRead, i.GETIsFollowing)
+
+	// Wallet
+	rt.handle("GET", "/wallet/address", ScopeRead, i.GETAddress)
+	rt.handle("GET", "/wallet/balance", ScopeRead, i.GETBalance)
+	rt.handle("GET", "/wallet/mnemonic", ScopeAdmin, i.GETMnemonic)
+	rt.handle("POST", "/wallet/spend", ScopeWallet, i.POSTSpendCoins)
+	rt.handle("POST", "/wallet/resyncblockchain", ScopeWallet, i.POSTResyncBlockchain)
+	rt.handle("GET", "/ob/exchangerate", ScopeRead, i.GETExchangeRate)
+	rt.handle("GET", "/ob/exchangerate/{currencyCode}", ScopeRead, i.GETExchangeRate)
+
+	// Node administration
+	rt.handle("POST", "/ob/shutdown", ScopeAdmin, i.POSTShutdown)
+	rt.handle("POST", "/ob/databasepassword", ScopeAdmin, i.POSTDatabasePassword)
+	rt.handle("POST", "/ob/backup", ScopeAdmin, i.POSTBackup)
+	rt.handle("GET", "/ob/apitokens", ScopeAdmin, i.GETAPITokens)
+	rt.handle("POST", "/ob/apitokens", ScopeAdmin, i.POSTAPIToken)
+	rt.handle("DELETE", "/ob/apitokens", ScopeAdmin, i.DELETEAPIToken)
+	rt.handle("GET", "/ob/webhooks/deliveries", ScopeRead, i.GETWebhookDeliveries)
 
-func deleter(i *jsonAPIHandler, path string, w http.ResponseWriter, r *http.Request) {
-	switch path {
-	case "/ob/moderator", "/ob/moderator/":
-		i.DELETEModerator(w, r)
-	case "/ob/listing", "/ob/listing/":
-		i.DELETEListing(w, r)
-	default:
-		ErrorResponse(w, http.StatusNotFound, "Not Found")
-	}
+	return rt
 }
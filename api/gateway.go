@@ -3,6 +3,7 @@ package api
 import (
 	manet "gx/ipfs/QmPpRcbNUXauP3zWZ1NJMLWpe4QnmEHrd2ba2D3yqWznw7/go-multiaddr-net"
 	"gx/ipfs/QmQopLATEYMNg7dVqZRNDfeE2S1yKy8zrRh5xnYiuqeZBn/goprocess"
+	ma "gx/ipfs/QmYzDkkgAEmrcNzFCiYo6L1dTX4EAG1gZkbtdbd9trL4vd/go-multiaddr"
 	"net"
 	"net/http"
 	"time"
@@ -12,45 +13,83 @@ import (
 	"github.com/ipfs/go-ipfs/commands"
 	"github.com/ipfs/go-ipfs/core/corehttp"
 	"github.com/op/go-logging"
+	"google.golang.org/grpc"
 )
 
 var log = logging.MustGetLogger("api")
 
-func makeHandler(n *core.OpenBazaarNode, ctx commands.Context, authCookie http.Cookie, l net.Listener, config repo.APIConfig, options ...corehttp.ServeOption) (http.Handler, error) {
+func makeHandler(n *core.OpenBazaarNode, ctx commands.Context, authCookie http.Cookie, l net.Listener, config repo.APIConfig, options ...corehttp.ServeOption) (http.Handler, *grpc.Server, error) {
 	topMux := http.NewServeMux()
 
 	restAPI, err := newJsonAPIHandler(n, authCookie, config)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
-	wsAPI, err := newWSAPIHandler(n, ctx, config.Authenticated, authCookie, config.Username, config.Password)
+	wsAPI, err := newWSAPIHandler(n, ctx, restAPI.auth)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
+	}
+	if n.Webhooks != nil {
+		wsAPI.h.webhooks = n.Webhooks.Notify
 	}
 	n.Broadcast = wsAPI.h.Broadcast
+	var grpcServer *grpc.Server
+	if config.GRPCAddress != "" {
+		grpcServer, err = newGRPCServer(n, restAPI.auth, wsAPI.h, config)
+		if err != nil {
+			return nil, nil, err
+		}
+	}
 
 	topMux.Handle("/ob/", restAPI)
 	topMux.Handle("/wallet/", restAPI)
+	topMux.Handle(apiVersionPrefix+"/", restAPI)
 	topMux.Handle("/ws", wsAPI)
+	if config.Metrics {
+		metricsAPI, err := newMetricsHandler(n, restAPI.auth)
+		if err != nil {
+			return nil, nil, err
+		}
+		topMux.Handle("/metrics", metricsAPI)
+	}
 
 	mux := topMux
 	for _, option := range options {
 		var err error
 		mux, err = option(n.IpfsNode, l, mux)
 		if err != nil {
-			return nil, err
+			return nil, nil, err
 		}
 	}
-	return topMux, nil
+	return topMux, grpcServer, nil
 }
 
 func Serve(cb chan<- bool, node *core.OpenBazaarNode, ctx commands.Context, authCookie http.Cookie, lis net.Listener, config repo.APIConfig, options ...corehttp.ServeOption) error {
-	handler, err := makeHandler(node, ctx, authCookie, lis, config, options...)
+	handler, grpcServer, err := makeHandler(node, ctx, authCookie, lis, config, options...)
 	cb <- true
 	if err != nil {
 		return err
 	}
 
+	// The gRPC API has its own listener and stops with the gateway
+	if grpcServer != nil {
+		grpcAddr, err := ma.NewMultiaddr(config.GRPCAddress)
+		if err != nil {
+			return err
+		}
+		grpcLis, err := manet.Listen(grpcAddr)
+		if err != nil {
+			return err
+		}
+		log.Infof("gRPC server listening on %s", grpcLis.Multiaddr())
+		go func() {
+			if err := grpcServer.Serve(grpcLis.NetListener()); err != nil {
+				log.Error("gRPC server exited:", err)
+			}
+		}()
+		defer grpcServer.Stop()
+	}
+
 	addr, err := manet.FromNetAddr(lis.Addr())
 	if err != nil {
 		return err
@@ -0,0 +1,375 @@
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"path"
+	"strings"
+
+	"github.com/OpenBazaar/jsonpb"
+	"github.com/OpenBazaar/openbazaar-go/core"
+	"github.com/OpenBazaar/openbazaar-go/pb"
+	"github.com/OpenBazaar/openbazaar-go/repo"
+	"github.com/OpenBazaar/spvwallet"
+	btc "github.com/btcsuite/btcutil"
+	mh "github.com/jbenet/go-multihash"
+	"golang.org/x/net/context"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/metadata"
+)
+
+// The scope an API token needs for each call. The scopes match those of the JSON API
+// endpoint with the same purpose.
+var grpcScopes = map[string]string{
+	"/OpenBazaar/GetListings":    ScopeRead,
+	"/OpenBazaar/GetListing":     ScopeRead,
+	"/OpenBazaar/CreateListing":  ScopeListings,
+	"/OpenBazaar/UpdateListing":  ScopeListings,
+	"/OpenBazaar/DeleteListing":  ScopeListings,
+	"/OpenBazaar/GetOrders":      ScopeRead,
+	"/OpenBazaar/GetOrder":       ScopeRead,
+	"/OpenBazaar/ConfirmOrder":   ScopeOrders,
+	"/OpenBazaar/CancelOrder":    ScopeOrders,
+	"/OpenBazaar/FulfillOrder":   ScopeOrders,
+	"/OpenBazaar/RefundOrder":    ScopeWallet,
+	"/OpenBazaar/UpdateProfile":  ScopeAdmin,
+	"/OpenBazaar/GetSettings":    ScopeRead,
+	"/OpenBazaar/UpdateSettings": ScopeAdmin,
+	"/OpenBazaar/GetBalance":     ScopeRead,
+	"/OpenBazaar/GetAddress":     ScopeRead,
+	"/OpenBazaar/Spend":          ScopeWallet,
+	"/OpenBazaar/Subscribe":      ScopeRead,
+}
+
+// Implements the OpenBazaar service declared in pb/protos/service.proto
+type grpcServer struct {
+	node    *core.OpenBazaarNode
+	auth    *authenticator
+	hub     *hub
+	enabled bool
+}
+
+func newGRPCServer(node *core.OpenBazaarNode, auth *authenticator, h *hub, config repo.APIConfig) (*grpc.Server, error) {
+	s := &grpcServer{node: node, auth: auth, hub: h, enabled: config.Enabled}
+	opts := []grpc.ServerOption{
+		grpc.UnaryInterceptor(func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+			if err := s.authorize(ctx, info.FullMethod); err != nil {
+				return nil, err
+			}
+			return handler(ctx, req)
+		}),
+		grpc.StreamInterceptor(func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+			if err := s.authorize(ss.Context(), info.FullMethod); err != nil {
+				return err
+			}
+			return handler(srv, ss)
+		}),
+	}
+	if config.SSL {
+		creds, err := credentials.NewServerTLSFromFile(config.SSLCert, config.SSLKey)
+		if err != nil {
+			return nil, err
+		}
+		opts = append(opts, grpc.Creds(creds))
+	}
+	server := grpc.NewServer(opts...)
+	pb.RegisterOpenBazaarServer(server, s)
+	return server, nil
+}
+
+// Check the credentials sent in the call metadata the same way as the JSON API checks
+// the request headers
+func (s *grpcServer) authorize(ctx context.Context, method string) error {
+	if !s.enabled {
+		return grpc.Errorf(codes.PermissionDenied, "Forbidden")
+	}
+	r := &http.Request{Header: make(http.Header)}
+	if md, ok := metadata.FromContext(ctx); ok {
+		for _, v := range md["authorization"] {
+			r.Header.Add("Authorization", v)
+		}
+		for _, v := range md["cookie"] {
+			r.Header.Add("Cookie", v)
+		}
+	}
+	granted, ok := s.auth.scopes(r, "")
+	if !ok {
+		return grpc.Errorf(codes.Unauthenticated, "Forbidden")
+	}
+	scope, ok := grpcScopes[method]
+	if !ok {
+		return grpc.Errorf(codes.Unimplemented, "Unknown method %s", method)
+	}
+	if !hasScope(granted, scope) {
+		return grpc.Errorf(codes.PermissionDenied, "This API token does not have the %s scope", scope)
+	}
+	return nil
+}
+
+func grpcError(code codes.Code, err error) error {
+	return grpc.Errorf(code, "%s", err.Error())
+}
+
+func (s *grpcServer) listingPath(slug string) string {
+	return path.Join(s.node.RepoPath, "root", "listings", slug+".json")
+}
+
+func (s *grpcServer) GetListings(ctx context.Context, req *pb.Empty) (*pb.ListingIndex, error) {
+	listingsBytes, err := s.node.GetListings()
+	if err != nil {
+		return nil, grpcError(codes.NotFound, err)
+	}
+	resp := new(pb.ListingIndex)
+	u := jsonpb.Unmarshaler{AllowUnknownFields: true}
+	wrapped := fmt.Sprintf(`{"listings": %s}`, listingsBytes)
+	if err := u.Unmarshal(bytes.NewReader([]byte(wrapped)), resp); err != nil {
+		return nil, grpcError(codes.Internal, err)
+	}
+	return resp, nil
+}
+
+func (s *grpcServer) GetListing(ctx context.Context, req *pb.ListingRequest) (*pb.ListingRespApi, error) {
+	var contract *pb.RicardianContract
+	var inventory []*pb.Inventory
+	_, err := mh.FromB58String(req.ListingId)
+	if err == nil {
+		contract, inventory, err = s.node.GetListingFromHash(req.ListingId)
+	} else {
+		contract, inventory, err = s.node.GetListingFromSlug(req.ListingId)
+	}
+	if err != nil {
+		return nil, grpcError(codes.NotFound, err)
+	}
+	return &pb.ListingRespApi{Contract: contract, Inventory: inventory}, nil
+}
+
+func (s *grpcServer) CreateListing(ctx context.Context, req *pb.ListingReqApi) (*pb.ListingSlug, error) {
+	if req.Listing == nil {
+		return nil, grpc.Errorf(codes.InvalidArgument, "Missing listing")
+	}
+	if req.Listing.Slug != "" {
+		if _, err := os.Stat(s.listingPath(req.Listing.Slug)); !os.IsNotExist(err) {
+			return nil, grpc.Errorf(codes.AlreadyExists, "Listing already exists. Use UpdateListing.")
+		}
+	}
+	contract, err := saveListing(s.node, req)
+	if err != nil {
+		return nil, grpcError(codes.Internal, err)
+	}
+	return &pb.ListingSlug{Slug: contract.VendorListings[0].Slug}, nil
+}
+
+func (s *grpcServer) UpdateListing(ctx context.Context, req *pb.ListingReqApi) (*pb.Empty, error) {
+	if req.Listing == nil {
+		return nil, grpc.Errorf(codes.InvalidArgument, "Missing listing")
+	}
+	if _, err := os.Stat(s.listingPath(req.Listing.Slug)); os.IsNotExist(err) {
+		return nil, grpc.Errorf(codes.NotFound, "Listing not found. Use CreateListing to create a new listing.")
+	}
+	if _, err := saveListing(s.node, req); err != nil {
+		return nil, grpcError(codes.Internal, err)
+	}
+	return new(pb.Empty), nil
+}
+
+func (s *grpcServer) DeleteListing(ctx context.Context, req *pb.ListingSlug) (*pb.Empty, error) {
+	if _, err := os.Stat(s.listingPath(req.Slug)); os.IsNotExist(err) {
+		return nil, grpc.Errorf(codes.NotFound, "Listing not found")
+	}
+	if err := s.node.DeleteListing(req.Slug); err != nil {
+		return nil, grpcError(codes.Internal, err)
+	}
+	if err := s.node.SeedNode(); err != nil {
+		return nil, grpcError(codes.Internal, err)
+	}
+	return new(pb.Empty), nil
+}
+
+func (s *grpcServer) GetOrders(ctx context.Context, req *pb.Empty) (*pb.OrderList, error) {
+	orders, err := orderSummaries(s.node)
+	if err != nil {
+		return nil, grpcError(codes.Internal, err)
+	}
+	return &pb.OrderList{Orders: orders}, nil
+}
+
+func (s *grpcServer) GetOrder(ctx context.Context, req *pb.OrderRequest) (*pb.OrderRespApi, error) {
+	resp, isSale, err := orderResponse(s.node, req.OrderId)
+	if err != nil {
+		return nil, grpcError(codes.NotFound, err)
+	}
+	if isSale {
+		s.node.Datastore.Sales().MarkAsRead(req.OrderId)
+	} else {
+		s.node.Datastore.Purchases().MarkAsRead(req.OrderId)
+	}
+	return resp, nil
+}
+
+func (s *grpcServer) ConfirmOrder(ctx context.Context, req *pb.OrderConfirmationRequest) (*pb.Empty, error) {
+	contract, state, funded, records, _, err := s.node.Datastore.Sales().GetByOrderId(req.OrderId)
+	if err != nil {
+		return nil, grpcError(codes.NotFound, err)
+	}
+	if state != pb.OrderState_PENDING {
+		return nil, grpc.Errorf(codes.FailedPrecondition, "order has already been confirmed")
+	}
+	if !funded && !req.Reject {
+		return nil, grpc.Errorf(codes.FailedPrecondition, "payment address must be funded before confirmation")
+	}
+	if req.Reject {
+		err = s.node.RejectOfflineOrder(contract, records)
+	} else {
+		err = s.node.ConfirmOfflineOrder(contract, records)
+	}
+	if err != nil {
+		return nil, grpcError(codes.Internal, err)
+	}
+	return new(pb.Empty), nil
+}
+
+func (s *grpcServer) CancelOrder(ctx context.Context, req *pb.OrderRequest) (*pb.Empty, error) {
+	contract, state, _, records, _, err := s.node.Datastore.Purchases().GetByOrderId(req.OrderId)
+	if err != nil {
+		return nil, grpc.Errorf(codes.NotFound, "order not found")
+	}
+	if state != pb.OrderState_PENDING {
+		return nil, grpc.Errorf(codes.FailedPrecondition, "order has already been confirmed")
+	}
+	if err := s.node.CancelOfflineOrder(contract, records); err != nil {
+		return nil, grpcError(codes.Internal, err)
+	}
+	return new(pb.Empty), nil
+}
+
+// Load a sale which can still be fulfilled or refunded
+func (s *grpcServer) fundedSale(orderId, action string) (*pb.RicardianContract, []*spvwallet.TransactionRecord, error) {
+	contract, records, err := fundedSale(s.node, orderId, action)
+	if err == errOrderNotFound {
+		return nil, nil, grpcError(codes.NotFound, err)
+	} else if err != nil {
+		return nil, nil, grpcError(codes.FailedPrecondition, err)
+	}
+	return contract, records, nil
+}
+
+func (s *grpcServer) FulfillOrder(ctx context.Context, req *pb.OrderFulfillment) (*pb.Empty, error) {
+	unlock := s.node.LockOrder(req.OrderId)
+	defer unlock()
+	contract, records, err := s.fundedSale(req.OrderId, "fulfilling")
+	if err != nil {
+		return nil, err
+	}
+	if err := s.node.FulfillOrder(req, contract, records); err != nil {
+		return nil, grpcError(codes.Internal, err)
+	}
+	return new(pb.Empty), nil
+}
+
+func (s *grpcServer) RefundOrder(ctx context.Context, req *pb.Refund) (*pb.Empty, error) {
+	unlock := s.node.LockOrder(req.OrderID)
+	defer unlock()
+	contract, records, err := s.fundedSale(req.OrderID, "refunding")
+	if err != nil {
+		return nil, err
+	}
+	refund := &pb.Refund{Amount: req.Amount, Items: req.Items, Memo: req.Memo}
+	if err := s.node.RefundOrder(contract, records, refund); err != nil {
+		return nil, grpcError(codes.Internal, err)
+	}
+	return new(pb.Empty), nil
+}
+
+func (s *grpcServer) UpdateProfile(ctx context.Context, req *pb.Profile) (*pb.Profile, error) {
+	if _, err := os.Stat(path.Join(s.node.RepoPath, "root", "profile")); os.IsNotExist(err) {
+		return nil, grpc.Errorf(codes.NotFound, "Profile doesn't exist yet. Use the JSON API to create it.")
+	}
+	if err := s.node.UpdateProfile(req); err != nil {
+		return nil, grpcError(codes.Internal, err)
+	}
+	if err := s.node.UpdateFollow(); err != nil {
+		return nil, grpcError(codes.Internal, err)
+	}
+	if err := s.node.SeedNode(); err != nil {
+		return nil, grpcError(codes.Internal, err)
+	}
+	return req, nil
+}
+
+func (s *grpcServer) GetSettings(ctx context.Context, req *pb.Empty) (*pb.Settings, error) {
+	settings, err := s.node.Datastore.Settings().Get()
+	if err != nil {
+		return nil, grpcError(codes.NotFound, err)
+	}
+	settingsJson, err := json.Marshal(&settings)
+	if err != nil {
+		return nil, grpcError(codes.Internal, err)
+	}
+	return &pb.Settings{Json: settingsJson}, nil
+}
+
+func (s *grpcServer) UpdateSettings(ctx context.Context, req *pb.Settings) (*pb.Empty, error) {
+	var settings repo.SettingsData
+	if err := json.Unmarshal(req.Json, &settings); err != nil {
+		return nil, grpcError(codes.InvalidArgument, err)
+	}
+	if err := s.node.Datastore.Settings().Update(settings); err != nil {
+		return nil, grpcError(codes.Internal, err)
+	}
+	return new(pb.Empty), nil
+}
+
+func (s *grpcServer) GetBalance(ctx context.Context, req *pb.Empty) (*pb.Balance, error) {
+	confirmed, unconfirmed := s.node.Wallet.Balance()
+	return &pb.Balance{Confirmed: uint64(confirmed), Unconfirmed: uint64(unconfirmed)}, nil
+}
+
+func (s *grpcServer) GetAddress(ctx context.Context, req *pb.Empty) (*pb.Address, error) {
+	addr := s.node.Wallet.CurrentAddress(spvwallet.EXTERNAL)
+	return &pb.Address{Address: addr.EncodeAddress()}, nil
+}
+
+func (s *grpcServer) Spend(ctx context.Context, req *pb.SpendRequest) (*pb.Empty, error) {
+	addr, err := btc.DecodeAddress(req.Address, s.node.Wallet.Params())
+	if err != nil {
+		return nil, grpcError(codes.InvalidArgument, err)
+	}
+	var feeLevel spvwallet.FeeLevel
+	switch strings.ToUpper(req.FeeLevel) {
+	case "PRIORITY":
+		feeLevel = spvwallet.PRIOIRTY
+	case "NORMAL":
+		feeLevel = spvwallet.NORMAL
+	case "ECONOMIC":
+		feeLevel = spvwallet.ECONOMIC
+	}
+	if err := s.node.Wallet.Spend(req.Amount, addr, feeLevel); err != nil {
+		return nil, grpcError(codes.Internal, err)
+	}
+	return new(pb.Empty), nil
+}
+
+// Stream the notifications which are broadcast to websocket clients
+func (s *grpcServer) Subscribe(req *pb.Empty, stream pb.OpenBazaar_SubscribeServer) error {
+	c := &connection{send: make(chan []byte, 256), h: s.hub}
+	s.hub.register <- registration{c: c}
+	defer func() { s.hub.unregister <- c }()
+	for {
+		select {
+		case m, ok := <-c.send:
+			if !ok {
+				return grpc.Errorf(codes.Unavailable, "Subscriber fell behind")
+			}
+			if err := stream.Send(&pb.Notification{Json: m}); err != nil {
+				return err
+			}
+		case <-stream.Context().Done():
+			return nil
+		}
+	}
+}
@@ -0,0 +1,235 @@
+package api
+
+import (
+	"encoding/hex"
+	"errors"
+	"net"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/OpenBazaar/openbazaar-go/bitcoin"
+	"github.com/OpenBazaar/openbazaar-go/core"
+	"github.com/OpenBazaar/openbazaar-go/pb"
+	"github.com/OpenBazaar/openbazaar-go/repo"
+	"github.com/btcsuite/btcd/chaincfg"
+	"golang.org/x/net/context"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+)
+
+type testWallet struct {
+	bitcoin.BitcoinWallet
+}
+
+func (w *testWallet) Params() *chaincfg.Params {
+	return &chaincfg.MainNetParams
+}
+
+func (w *testWallet) Balance() (confirmed, unconfirmed int64) {
+	return 1000, 50
+}
+
+type testTokens struct {
+	repo.APITokens
+	scopes map[string][]string
+}
+
+func (t *testTokens) GetByHash(hash []byte) (repo.APIToken, error) {
+	scopes, ok := t.scopes[hex.EncodeToString(hash)]
+	if !ok {
+		return repo.APIToken{}, errors.New("Not found")
+	}
+	return repo.APIToken{Scopes: scopes}, nil
+}
+
+type testDatastore struct {
+	repo.Datastore
+	tokens *testTokens
+}
+
+func (d *testDatastore) APITokens() repo.APITokens {
+	return d.tokens
+}
+
+type testEvents struct {
+	repo.WebsocketEvents
+}
+
+func (e *testEvents) Put(event repo.WebsocketEvent) error { return nil }
+func (e *testEvents) LastSeq() (uint64, error)            { return 0, nil }
+
+// Start the gRPC service on a loopback listener. The "read" token only has the read scope
+// and the "wallet" token only has the wallet scope.
+func newTestGRPC(t *testing.T) (*grpc.Server, *hub, *grpc.ClientConn) {
+	tokens := &testTokens{scopes: make(map[string][]string)}
+	tokens.scopes[hex.EncodeToString(hashToken("read"))] = []string{ScopeRead}
+	tokens.scopes[hex.EncodeToString(hashToken("wallet"))] = []string{ScopeWallet}
+	node := &core.OpenBazaarNode{Datastore: &testDatastore{tokens: tokens}, Wallet: new(testWallet)}
+	config := repo.APIConfig{Enabled: true, Authenticated: true}
+	h := newHub(new(testEvents))
+	go h.run()
+	server, err := newGRPCServer(node, newAuthenticator(node, http.Cookie{Name: "OpenBazaar_Auth_Cookie", Value: "secret"}, config), h, config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	go server.Serve(lis)
+	conn, err := grpc.Dial(lis.Addr().String(), grpc.WithInsecure(), grpc.WithBlock(), grpc.WithTimeout(5*time.Second))
+	if err != nil {
+		server.Stop()
+		t.Fatal(err)
+	}
+	return server, h, conn
+}
+
+func withToken(token string) context.Context {
+	return metadata.NewContext(context.Background(), metadata.Pairs("authorization", "Bearer "+token))
+}
+
+func TestGRPCScopes(t *testing.T) {
+	server := grpc.NewServer()
+	pb.RegisterOpenBazaarServer(server, new(grpcServer))
+	info, ok := server.GetServiceInfo()["OpenBazaar"]
+	if !ok {
+		t.Fatal("OpenBazaar service not registered")
+	}
+	for _, m := range info.Methods {
+		if _, ok := grpcScopes["/OpenBazaar/"+m.Name]; !ok {
+			t.Errorf("%s has no scope", m.Name)
+		}
+	}
+	// Anything which can move funds out of the wallet needs the wallet scope
+	for _, method := range []string{"/OpenBazaar/Spend", "/OpenBazaar/RefundOrder"} {
+		if grpcScopes[method] != ScopeWallet {
+			t.Errorf("%s requires %s, want %s", method, grpcScopes[method], ScopeWallet)
+		}
+	}
+}
+
+func TestGRPCAuthorize(t *testing.T) {
+	server, _, conn := newTestGRPC(t)
+	defer server.Stop()
+	defer conn.Close()
+	client := pb.NewOpenBazaarClient(conn)
+
+	cookie := metadata.NewContext(context.Background(), metadata.Pairs("cookie", "OpenBazaar_Auth_Cookie=secret"))
+	badCookie := metadata.NewContext(context.Background(), metadata.Pairs("cookie", "OpenBazaar_Auth_Cookie=wrong"))
+	tests := []struct {
+		name string
+		ctx  context.Context
+		call func(ctx context.Context) error
+		code codes.Code
+	}{
+		{"no credentials", context.Background(), func(ctx context.Context) error {
+			_, err := client.GetBalance(ctx, new(pb.Empty))
+			return err
+		}, codes.Unauthenticated},
+		{"wrong cookie", badCookie, func(ctx context.Context) error {
+			_, err := client.GetBalance(ctx, new(pb.Empty))
+			return err
+		}, codes.Unauthenticated},
+		{"unknown token", withToken("unknown"), func(ctx context.Context) error {
+			_, err := client.GetBalance(ctx, new(pb.Empty))
+			return err
+		}, codes.Unauthenticated},
+		{"read token reading", withToken("read"), func(ctx context.Context) error {
+			_, err := client.GetBalance(ctx, new(pb.Empty))
+			return err
+		}, codes.OK},
+		{"cookie reading", cookie, func(ctx context.Context) error {
+			_, err := client.GetBalance(ctx, new(pb.Empty))
+			return err
+		}, codes.OK},
+		{"read token spending", withToken("read"), func(ctx context.Context) error {
+			_, err := client.Spend(ctx, &pb.SpendRequest{Address: "invalid"})
+			return err
+		}, codes.PermissionDenied},
+		{"read token refunding", withToken("read"), func(ctx context.Context) error {
+			_, err := client.RefundOrder(ctx, &pb.Refund{OrderID: "order"})
+			return err
+		}, codes.PermissionDenied},
+		{"wallet token reading", withToken("wallet"), func(ctx context.Context) error {
+			_, err := client.GetBalance(ctx, new(pb.Empty))
+			return err
+		}, codes.PermissionDenied},
+		{"wallet token spending", withToken("wallet"), func(ctx context.Context) error {
+			_, err := client.Spend(ctx, &pb.SpendRequest{Address: "invalid"})
+			return err
+		}, codes.InvalidArgument},
+		{"wallet token subscribing", withToken("wallet"), func(ctx context.Context) error {
+			stream, err := client.Subscribe(ctx, new(pb.Empty))
+			if err != nil {
+				return err
+			}
+			_, err = stream.Recv()
+			return err
+		}, codes.PermissionDenied},
+	}
+	for _, test := range tests {
+		err := test.call(test.ctx)
+		if grpc.Code(err) != test.code {
+			t.Errorf("%s: got %v, want %s", test.name, err, test.code)
+		}
+	}
+}
+
+func TestGRPCGetBalance(t *testing.T) {
+	server, _, conn := newTestGRPC(t)
+	defer server.Stop()
+	defer conn.Close()
+
+	balance, err := pb.NewOpenBazaarClient(conn).GetBalance(withToken("read"), new(pb.Empty))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if balance.Confirmed != 1000 || balance.Unconfirmed != 50 {
+		t.Errorf("got balance %d/%d, want 1000/50", balance.Confirmed, balance.Unconfirmed)
+	}
+}
+
+func TestGRPCSubscribe(t *testing.T) {
+	server, h, conn := newTestGRPC(t)
+	defer server.Stop()
+	defer conn.Close()
+
+	ctx, cancel := context.WithCancel(withToken("read"))
+	defer cancel()
+	stream, err := pb.NewOpenBazaarClient(conn).Subscribe(ctx, new(pb.Empty))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The subscription is registered with the hub asynchronously so keep broadcasting
+	// until the message arrives
+	done := make(chan struct{})
+	defer close(done)
+	go func() {
+		ticker := time.NewTicker(10 * time.Millisecond)
+		defer ticker.Stop()
+		for {
+			select {
+			case h.Broadcast <- []byte(`{"notification": {"type": "test"}}`):
+			case <-done:
+				return
+			}
+			select {
+			case <-ticker.C:
+			case <-done:
+				return
+			}
+		}
+	}()
+	n, err := stream.Recv()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(n.Json), `"type": "test"`) {
+		t.Errorf("unexpected notification %s", n.Json)
+	}
+}
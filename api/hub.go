@@ -1,5 +1,62 @@
 package api
 
+import (
+	"encoding/json"
+	"strings"
+	"time"
+
+	"github.com/OpenBazaar/openbazaar-go/repo"
+)
+
+// Topics websocket clients can subscribe to. Moderator search results are sent on
+// search:<id> where id is the one returned by the search request.
+const (
+	TopicOrders     = "orders"
+	TopicWallet     = "wallet"
+	TopicPublishing = "publishing"
+	TopicSearch     = "search"
+	TopicChat       = "chat"
+	TopicFollows    = "follows"
+	TopicMessages   = "messages"
+	TopicOther      = "other"
+
+	// Messages about the connection itself. These have no sequence number and are never replayed.
+	TopicSystem = "system"
+)
+
+var Topics = []string{TopicOrders, TopicWallet, TopicPublishing, TopicSearch, TopicChat, TopicFollows, TopicMessages, TopicOther}
+
+// The number of messages kept for replay
+const replayBufferSize = 1000
+
+// Every message sent over the websocket is wrapped in an envelope. Sequence numbers
+// increase by one for each message broadcast and survive restarts so a client can
+// ask for anything it missed while disconnected.
+type envelope struct {
+	Seq       uint64          `json:"seq"`
+	Topic     string          `json:"topic"`
+	Type      string          `json:"type"`
+	Timestamp int64           `json:"timestamp"`
+	Payload   json.RawMessage `json:"payload"`
+}
+
+type replayRequest struct {
+	c   *connection
+	seq uint64
+}
+
+// A connection to add to the hub. If since is set the saved messages after it are sent
+// before the connection receives any new broadcast so none are missed or sent twice.
+type registration struct {
+	c     *connection
+	since *uint64
+}
+
+type directMessage struct {
+	c       *connection
+	message []byte
+}
+
 type hub struct {
 	// Registered connections
 	connections map[*connection]bool
@@ -8,42 +65,190 @@ type hub struct {
 	Broadcast chan []byte
 
 	// Register requests from the connections
-	register chan *connection
+	register chan registration
 
 	// Unregister requests from connections
 	unregister chan *connection
+
+	// Requests to resend the messages after a sequence number
+	replay chan replayRequest
+
+	// Messages for a single connection
+	direct chan directMessage
+
+	// Called with each broadcast message if webhooks are configured
+	webhooks func([]byte)
+
+	// Buffer of recent messages for replay
+	events repo.WebsocketEvents
+
+	// Sequence number of the last message broadcast
+	seq uint64
 }
 
-func newHub() *hub {
-	return &hub{
+func newHub(events repo.WebsocketEvents) *hub {
+	h := &hub{
 		Broadcast:   make(chan []byte),
-		register:    make(chan *connection),
+		register:    make(chan registration),
 		unregister:  make(chan *connection),
+		replay:      make(chan replayRequest),
+		direct:      make(chan directMessage),
 		connections: make(map[*connection]bool),
+		events:      events,
 	}
+	if seq, err := events.LastSeq(); err == nil {
+		h.seq = seq
+	} else {
+		log.Error("Error reading websocket sequence number:", err)
+	}
+	return h
 }
 
 func (h *hub) run() {
 	for {
 		select {
-		case c := <-h.register:
-			h.connections[c] = true
+		case r := <-h.register:
+			h.connections[r.c] = true
 			log.Debug("Registered new websocket connection")
+			if r.since != nil {
+				h.resend(r.c, *r.since)
+			}
 		case c := <-h.unregister:
 			if _, ok := h.connections[c]; ok {
 				delete(h.connections, c)
 				close(c.send)
 			}
 			log.Debug("Unregistered websocket connection")
+		case r := <-h.replay:
+			h.resend(r.c, r.seq)
+		case d := <-h.direct:
+			h.send(d.c, d.message)
 		case m := <-h.Broadcast:
+			if h.webhooks != nil {
+				h.webhooks(m)
+			}
+			topic, b := h.wrap(m)
 			for c := range h.connections {
-				select {
-				case c.send <- m:
-				default:
-					delete(h.connections, c)
-					close(c.send)
+				if c.subscribed(topic) {
+					h.send(c, b)
 				}
 			}
 		}
 	}
 }
+
+// Queue a message to a connection, dropping the connection if it can't keep up
+func (h *hub) send(c *connection, m []byte) {
+	if _, ok := h.connections[c]; !ok {
+		return
+	}
+	select {
+	case c.send <- m:
+	default:
+		delete(h.connections, c)
+		close(c.send)
+	}
+}
+
+// Give a message the next sequence number, wrap it in an envelope and save it for replay
+func (h *hub) wrap(m []byte) (string, []byte) {
+	h.seq++
+	topic, msgType := classify(m)
+	payload := json.RawMessage(m)
+	var v interface{}
+	if err := json.Unmarshal(m, &v); err != nil {
+		payload, _ = json.Marshal(string(m))
+	}
+	b, _ := json.MarshalIndent(envelope{h.seq, topic, msgType, time.Now().Unix(), payload}, "", "    ")
+	if err := h.events.Put(repo.WebsocketEvent{Seq: h.seq, Topic: topic, Envelope: b}); err != nil {
+		log.Error("Error saving websocket message:", err)
+	}
+	if h.seq%100 == 0 {
+		if err := h.events.Prune(replayBufferSize); err != nil {
+			log.Error("Error pruning websocket messages:", err)
+		}
+	}
+	return topic, b
+}
+
+// Send a connection the saved messages after seq on the topics it is subscribed to. If some
+// of those messages have already been dropped from the buffer the client is told first.
+func (h *hub) resend(c *connection, seq uint64) {
+	events, err := h.events.GetSince(seq)
+	if err != nil {
+		log.Error("Error reading websocket messages:", err)
+		return
+	}
+	if seq < h.seq && (len(events) == 0 || events[0].Seq > seq+1) {
+		oldest := h.seq + 1
+		if len(events) > 0 {
+			oldest = events[0].Seq
+		}
+		h.send(c, systemMessage("replayIncomplete", struct {
+			Oldest uint64 `json:"oldest"`
+		}{oldest}))
+	}
+	for _, e := range events {
+		if c.subscribed(e.Topic) {
+			h.send(c, e.Envelope)
+		}
+	}
+}
+
+func systemMessage(msgType string, payload interface{}) []byte {
+	p, _ := json.Marshal(payload)
+	b, _ := json.MarshalIndent(envelope{Topic: TopicSystem, Type: msgType, Timestamp: time.Now().Unix(), Payload: p}, "", "    ")
+	return b
+}
+
+// Work out the topic and type of a broadcast message from its contents
+func classify(m []byte) (topic, msgType string) {
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(m, &fields); err != nil {
+		return TopicOther, "unknown"
+	}
+	if _, ok := fields["status"]; ok {
+		return TopicPublishing, "status"
+	}
+	if id, ok := fields["id"]; ok {
+		if _, ok := fields["moderator"]; ok {
+			var searchId string
+			json.Unmarshal(id, &searchId)
+			return TopicSearch + ":" + searchId, "moderator"
+		}
+	}
+	if _, ok := fields["chat"]; ok {
+		return TopicChat, "message"
+	}
+	if _, ok := fields["messageRetriever"]; ok {
+		return TopicMessages, "retriever"
+	}
+	var notification map[string]json.RawMessage
+	if err := json.Unmarshal(fields["notification"], &notification); err == nil {
+		for t := range notification {
+			switch t {
+			case "walletTransaction":
+				return TopicWallet, t
+			case "follow", "unfollow":
+				return TopicFollows, t
+			default:
+				return TopicOrders, t
+			}
+		}
+	}
+	return TopicOther, "unknown"
+}
+
+// A topic to subscribe to is one of Topics or search:<id>. Subscribing to search gets the
+// results of every search.
+func validTopic(topic string) bool {
+	if strings.HasPrefix(topic, TopicSearch+":") {
+		return len(topic) > len(TopicSearch)+1
+	}
+	for _, t := range Topics {
+		if t == topic {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,77 @@
+package api
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/OpenBazaar/openbazaar-go/repo"
+)
+
+// Keeps websocket events in memory
+type memEvents struct {
+	events []repo.WebsocketEvent
+}
+
+func (m *memEvents) Put(event repo.WebsocketEvent) error {
+	m.events = append(m.events, event)
+	return nil
+}
+
+func (m *memEvents) GetSince(seq uint64) ([]repo.WebsocketEvent, error) {
+	var events []repo.WebsocketEvent
+	for _, e := range m.events {
+		if e.Seq > seq {
+			events = append(events, e)
+		}
+	}
+	return events, nil
+}
+
+func (m *memEvents) LastSeq() (uint64, error) {
+	if len(m.events) == 0 {
+		return 0, nil
+	}
+	return m.events[len(m.events)-1].Seq, nil
+}
+
+func (m *memEvents) Prune(keep int) error { return nil }
+
+func receiveSeq(t *testing.T, c *connection) uint64 {
+	select {
+	case m := <-c.send:
+		var e envelope
+		if err := json.Unmarshal(m, &e); err != nil {
+			t.Fatal(err)
+		}
+		return e.Seq
+	case <-time.After(5 * time.Second):
+		t.Fatal("Timed out waiting for a message")
+	}
+	return 0
+}
+
+func TestHubRegisterReplaysBeforeBroadcasts(t *testing.T) {
+	h := newHub(new(memEvents))
+	go h.run()
+	for i := 0; i < 3; i++ {
+		h.Broadcast <- []byte(`{"notification": {"type": "test"}}`)
+	}
+
+	since := uint64(1)
+	c := &connection{send: make(chan []byte, 256), h: h}
+	h.register <- registration{c, &since}
+	h.Broadcast <- []byte(`{"notification": {"type": "test"}}`)
+
+	// The replayed messages come first, followed by the live one, each exactly once
+	for _, expected := range []uint64{2, 3, 4} {
+		if seq := receiveSeq(t, c); seq != expected {
+			t.Errorf("Expected message %d, got %d", expected, seq)
+		}
+	}
+	select {
+	case m := <-c.send:
+		t.Errorf("Unexpected message %s", m)
+	case <-time.After(50 * time.Millisecond):
+	}
+}
@@ -3,30 +3,33 @@ package api
 import (
 	"crypto/rand"
 	"encoding/json"
+	"errors"
 	"fmt"
 	mh "gx/ipfs/QmYf7ng2hG5XBtJA3tN34DQ2GUN5HNksEw1rLDkmr6vGku/go-multihash"
+	ma "gx/ipfs/QmYzDkkgAEmrcNzFCiYo6L1dTX4EAG1gZkbtdbd9trL4vd/go-multiaddr"
+	"io"
 	"net/http"
 	"net/http/httputil"
 	"net/url"
 	"os"
 	"path"
-	"path/filepath"
 	"runtime/debug"
 	"strconv"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/OpenBazaar/jsonpb"
 	"github.com/OpenBazaar/openbazaar-go/core"
 	"github.com/OpenBazaar/openbazaar-go/ipfs"
+	"github.com/OpenBazaar/openbazaar-go/metrics"
 	"github.com/OpenBazaar/openbazaar-go/pb"
 	"github.com/OpenBazaar/openbazaar-go/repo"
 	"github.com/OpenBazaar/spvwallet"
 	btc "github.com/btcsuite/btcutil"
 	"github.com/btcsuite/btcutil/base58"
-	lockfile "github.com/ipfs/go-ipfs/repo/fsrepo/lock"
+	"github.com/golang/protobuf/ptypes/timestamp"
 	routing "github.com/ipfs/go-ipfs/routing/dht"
-	"github.com/jbenet/go-multiaddr"
 	"github.com/jbenet/go-multihash"
 	"golang.org/x/net/context"
 )
@@ -44,6 +47,8 @@ type JsonAPIConfig struct {
 type jsonAPIHandler struct {
 	config JsonAPIConfig
 	node   *core.OpenBazaarNode
+	auth   *authenticator
+	router *router
 }
 
 func newJsonAPIHandler(node *core.OpenBazaarNode, authCookie http.Cookie, config repo.APIConfig) (*jsonAPIHandler, error) {
@@ -59,14 +64,16 @@ func newJsonAPIHandler(node *core.OpenBazaarNode, authCookie http.Cookie, config
 			Password:      config.Password,
 		},
 		node: node,
+		auth: newAuthenticator(node, authCookie, config),
 	}
+	i.router = newRouter(i)
 	return i, nil
 }
 
 func (i *jsonAPIHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	w.Header().Add("Content-Type", "application/json")
 	if !i.config.Enabled {
-		w.WriteHeader(http.StatusForbidden)
-		fmt.Fprint(w, "403 - Forbidden")
+		ErrorResponse(w, http.StatusForbidden, "Forbidden")
 		return
 	}
 	if i.config.Cors != nil {
@@ -79,34 +86,17 @@ func (i *jsonAPIHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 		w.Header()[k] = v
 	}
 
-	if i.config.Authenticated {
-		if i.config.Username == "" || i.config.Password == "" {
-			cookie, err := r.Cookie("OpenBazaar_Auth_Cookie")
-			if err != nil {
-				w.WriteHeader(http.StatusForbidden)
-				fmt.Fprint(w, "403 - Forbidden")
-				return
-			}
-			if i.config.Cookie.Value != cookie.Value {
-				w.WriteHeader(http.StatusForbidden)
-				fmt.Fprint(w, "403 - Forbidden")
-				return
-			}
-		} else {
-			username, password, ok := r.BasicAuth()
-			if !ok || username != i.config.Username || password != i.config.Password {
-				w.WriteHeader(http.StatusForbidden)
-				fmt.Fprint(w, "403 - Forbidden")
-				return
-			}
-		}
+	granted, ok := i.auth.scopes(r, "")
+	if !ok {
+		ErrorResponse(w, http.StatusForbidden, "Forbidden")
+		return
 	}
 
 	// Stop here if its Preflighted OPTIONS request
 	if r.Method == "OPTIONS" {
 		return
 	}
-	dump, err := httputil.DumpRequest(r, false)
+	dump, err := httputil.DumpRequest(redactCredentials(r), false)
 	if err != nil {
 		log.Error("Error reading http request:", err)
 	}
@@ -119,23 +109,21 @@ func (i *jsonAPIHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 		}
 	}()
 
-	u, err := url.Parse(r.URL.Path)
-	if err != nil {
-		panic(err)
+	route, params, rerr := i.router.lookup(r.Method, r.URL.Path)
+	if rerr != nil {
+		if len(rerr.allow) > 0 {
+			w.Header().Set("Allow", strings.Join(rerr.allow, ", "))
+		}
+		ErrorResponse(w, rerr.status, rerr.reason)
+		return
 	}
-	w.Header().Add("Content-Type", "application/json")
-	switch r.Method {
-	case "GET":
-		get(i, u.String(), w, r)
-	case "POST":
-		post(i, u.String(), w, r)
-	case "PUT":
-		put(i, u.String(), w, r)
-	case "DELETE":
-		deleter(i, u.String(), w, r)
-	case "PATCH":
-		patch(i, u.String(), w, r)
+	if !hasScope(granted, route.scope) {
+		ErrorResponse(w, http.StatusForbidden, "This API token does not have the "+route.scope+" scope")
+		return
 	}
+	start := time.Now()
+	route.handler(w, withParams(r, params))
+	metrics.APIRequestDuration.WithLabelValues(route.method, route.pattern).Observe(time.Since(start).Seconds())
 }
 
 func ErrorResponse(w http.ResponseWriter, errorCode int, reason string) {
@@ -386,53 +374,11 @@ func (i *jsonAPIHandler) POSTListing(w http.ResponseWriter, r *http.Request) {
 			return
 		}
 	}
-	contract, err := i.node.SignListing(ld.Listing)
-	if err != nil {
-		ErrorResponse(w, http.StatusInternalServerError, err.Error())
-		return
-	}
-	listingPath = path.Join(i.node.RepoPath, "root", "listings", contract.VendorListings[0].Slug+".json")
-	err = i.node.SetListingInventory(ld.Listing, ld.Inventory)
-	if err != nil {
-		ErrorResponse(w, http.StatusInternalServerError, err.Error())
-		return
-	}
-	f, err := os.Create(listingPath)
+	contract, err := saveListing(i.node, ld)
 	if err != nil {
 		ErrorResponse(w, http.StatusInternalServerError, err.Error())
 		return
 	}
-	m := jsonpb.Marshaler{
-		EnumsAsInts:  false,
-		EmitDefaults: false,
-		Indent:       "    ",
-		OrigName:     false,
-	}
-	out, err := m.MarshalToString(contract)
-	if err != nil {
-		ErrorResponse(w, http.StatusInternalServerError, err.Error())
-		return
-	}
-
-	if _, err := f.WriteString(out); err != nil {
-		ErrorResponse(w, http.StatusInternalServerError, err.Error())
-		return
-	}
-	err = i.node.UpdateListingIndex(contract)
-	if err != nil {
-		ErrorResponse(w, http.StatusInternalServerError, err.Error())
-		return
-	}
-	// Update followers/following
-	err = i.node.UpdateFollow()
-	if err != nil {
-		ErrorResponse(w, http.StatusInternalServerError, err.Error())
-		return
-	}
-	if err := i.node.SeedNode(); err != nil {
-		ErrorResponse(w, http.StatusInternalServerError, err.Error())
-		return
-	}
 	fmt.Fprintf(w, `{"slug": "%s"}`, contract.VendorListings[0].Slug)
 	return
 }
@@ -450,20 +396,24 @@ func (i *jsonAPIHandler) PUTListing(w http.ResponseWriter, r *http.Request) {
 		ErrorResponse(w, http.StatusNotFound, "Listing not found. Use POST to create a new listing.")
 		return
 	}
-	contract, err := i.node.SignListing(ld.Listing)
-	if err != nil {
+	if _, err := saveListing(i.node, ld); err != nil {
 		ErrorResponse(w, http.StatusInternalServerError, err.Error())
 		return
 	}
-	err = i.node.SetListingInventory(ld.Listing, ld.Inventory)
+	fmt.Fprint(w, `{}`)
+	return
+}
+
+// Sign and save a listing, then update the index and republish. Used by both the JSON and
+// the gRPC API.
+func saveListing(node *core.OpenBazaarNode, ld *pb.ListingReqApi) (*pb.RicardianContract, error) {
+	contract, err := node.SignListing(ld.Listing)
 	if err != nil {
-		ErrorResponse(w, http.StatusInternalServerError, err.Error())
-		return
+		return nil, err
 	}
-	f, err := os.Create(listingPath)
+	err = node.SetListingInventory(ld.Listing, ld.Inventory)
 	if err != nil {
-		ErrorResponse(w, http.StatusInternalServerError, err.Error())
-		return
+		return nil, err
 	}
 	m := jsonpb.Marshaler{
 		EnumsAsInts:  false,
@@ -473,32 +423,30 @@ func (i *jsonAPIHandler) PUTListing(w http.ResponseWriter, r *http.Request) {
 	}
 	out, err := m.MarshalToString(contract)
 	if err != nil {
-		ErrorResponse(w, http.StatusInternalServerError, err.Error())
-		return
+		return nil, err
 	}
-
+	listingPath := path.Join(node.RepoPath, "root", "listings", contract.VendorListings[0].Slug+".json")
+	f, err := os.Create(listingPath)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
 	if _, err := f.WriteString(out); err != nil {
-		ErrorResponse(w, http.StatusInternalServerError, err.Error())
-		return
+		return nil, err
 	}
-	err = i.node.UpdateListingIndex(contract)
+	err = node.UpdateListingIndex(contract)
 	if err != nil {
-		ErrorResponse(w, http.StatusInternalServerError, err.Error())
-		return
+		return nil, err
 	}
-
 	// Update followers/following
-	err = i.node.UpdateFollow()
+	err = node.UpdateFollow()
 	if err != nil {
-		ErrorResponse(w, http.StatusInternalServerError, "File Write Error: "+err.Error())
-		return
+		return nil, err
 	}
-	if err := i.node.SeedNode(); err != nil {
-		ErrorResponse(w, http.StatusInternalServerError, err.Error())
-		return
+	if err := node.SeedNode(); err != nil {
+		return nil, err
 	}
-	fmt.Fprint(w, `{}`)
-	return
+	return contract, nil
 }
 
 func (i *jsonAPIHandler) DELETEListing(w http.ResponseWriter, r *http.Request) {
@@ -561,7 +509,7 @@ func (i *jsonAPIHandler) POSTPurchase(w http.ResponseWriter, r *http.Request) {
 }
 
 func (i *jsonAPIHandler) GETStatus(w http.ResponseWriter, r *http.Request) {
-	_, peerId := path.Split(r.URL.Path)
+	peerId := pathParam(r, "peerId")
 	status := i.node.GetPeerStatus(peerId)
 	fmt.Fprintf(w, `{"status": "%s"}`, status)
 }
@@ -758,7 +706,7 @@ func (i *jsonAPIHandler) PATCHSettings(w http.ResponseWriter, r *http.Request) {
 }
 
 func (i *jsonAPIHandler) GETClosestPeers(w http.ResponseWriter, r *http.Request) {
-	_, peerId := path.Split(r.URL.Path)
+	peerId := pathParam(r, "peerId")
 	var peerIds []string
 	peers, err := ipfs.Query(i.node.Context, peerId)
 	if err == nil {
@@ -774,8 +722,8 @@ func (i *jsonAPIHandler) GETClosestPeers(w http.ResponseWriter, r *http.Request)
 }
 
 func (i *jsonAPIHandler) GETExchangeRate(w http.ResponseWriter, r *http.Request) {
-	_, currencyCode := path.Split(r.URL.Path)
-	if currencyCode == "" || strings.ToLower(currencyCode) == "exchangerate" {
+	currencyCode := pathParam(r, "currencyCode")
+	if currencyCode == "" {
 		currencyMap, err := i.node.ExchangeRates.GetAllRates()
 		if err != nil {
 			ErrorResponse(w, http.StatusInternalServerError, err.Error())
@@ -1016,7 +964,7 @@ func (i *jsonAPIHandler) GETListings(w http.ResponseWriter, r *http.Request) {
 func (i *jsonAPIHandler) GETListing(w http.ResponseWriter, r *http.Request) {
 	contract := new(pb.RicardianContract)
 	inventory := []*pb.Inventory{}
-	_, listingID := path.Split(r.URL.Path)
+	listingID := pathParam(r, "listingId")
 	_, err := mh.FromB58String(listingID)
 	if err == nil {
 		contract, inventory, err = i.node.GetListingFromHash(listingID)
@@ -1046,12 +994,12 @@ func (i *jsonAPIHandler) GETListing(w http.ResponseWriter, r *http.Request) {
 }
 
 func (i *jsonAPIHandler) GETFollowsMe(w http.ResponseWriter, r *http.Request) {
-	_, peerId := path.Split(r.URL.Path)
+	peerId := pathParam(r, "peerId")
 	fmt.Fprintf(w, `{"followsMe": "%t"}`, i.node.Datastore.Followers().FollowsMe(peerId))
 }
 
 func (i *jsonAPIHandler) GETIsFollowing(w http.ResponseWriter, r *http.Request) {
-	_, peerId := path.Split(r.URL.Path)
+	peerId := pathParam(r, "peerId")
 	fmt.Fprintf(w, `{"isFollowing": "%t"}`, i.node.Datastore.Following().IsFollowing(peerId))
 }
 
@@ -1133,20 +1081,39 @@ func (i *jsonAPIHandler) POSTResyncBlockchain(w http.ResponseWriter, r *http.Req
 }
 
 func (i *jsonAPIHandler) GETOrder(w http.ResponseWriter, r *http.Request) {
-	_, orderId := path.Split(r.URL.Path)
-	var err error
+	orderId := pathParam(r, "orderId")
+	resp, isSale, err := orderResponse(i.node, orderId)
+	if err != nil {
+		ErrorResponse(w, http.StatusNotFound, err.Error())
+		return
+	}
+	m := jsonpb.Marshaler{
+		EnumsAsInts:  false,
+		EmitDefaults: true,
+		Indent:       "    ",
+		OrigName:     false,
+	}
+	out, err := m.MarshalToString(resp)
+	if err != nil {
+		ErrorResponse(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	if isSale {
+		i.node.Datastore.Sales().MarkAsRead(orderId)
+	} else {
+		i.node.Datastore.Purchases().MarkAsRead(orderId)
+	}
+	fmt.Fprint(w, out)
+}
+
+// Look up a purchase or sale and report whether it is a sale
+func orderResponse(node *core.OpenBazaarNode, orderId string) (*pb.OrderRespApi, bool, error) {
 	var isSale bool
-	var contract *pb.RicardianContract
-	var state pb.OrderState
-	var funded bool
-	var records []*spvwallet.TransactionRecord
-	var read bool
-	contract, state, funded, records, read, err = i.node.Datastore.Purchases().GetByOrderId(orderId)
-	if err != nil {
-		contract, state, funded, records, read, err = i.node.Datastore.Sales().GetByOrderId(orderId)
+	contract, state, funded, records, read, err := node.Datastore.Purchases().GetByOrderId(orderId)
+	if err != nil {
+		contract, state, funded, records, read, err = node.Datastore.Sales().GetByOrderId(orderId)
 		if err != nil {
-			ErrorResponse(w, http.StatusNotFound, "Order not found")
-			return
+			return nil, false, errors.New("Order not found")
 		}
 		isSale = true
 	}
@@ -1166,23 +1133,21 @@ func (i *jsonAPIHandler) GETOrder(w http.ResponseWriter, r *http.Request) {
 
 	resp.Transactions = txs
 
-	m := jsonpb.Marshaler{
-		EnumsAsInts:  false,
-		EmitDefaults: true,
-		Indent:       "    ",
-		OrigName:     false,
-	}
-	out, err := m.MarshalToString(resp)
-	if err != nil {
-		ErrorResponse(w, http.StatusInternalServerError, err.Error())
-		return
+	// Report which listings in the order the vendor has fulfilled so far
+	fulfilled := make(map[string]bool)
+	for _, f := range contract.VendorOrderFulfillment {
+		fulfilled[f.Slug] = true
 	}
-	if isSale {
-		i.node.Datastore.Sales().MarkAsRead(orderId)
-	} else {
-		i.node.Datastore.Purchases().MarkAsRead(orderId)
+	fulfillment := []*pb.FulfillmentStatus{}
+	for _, listing := range contract.VendorListings {
+		status := &pb.FulfillmentStatus{Slug: listing.Slug, Fulfilled: fulfilled[listing.Slug]}
+		if listing.Item != nil {
+			status.Title = listing.Item.Title
+		}
+		fulfillment = append(fulfillment, status)
 	}
-	fmt.Fprint(w, out)
+	resp.Fulfillment = fulfillment
+	return resp, isSale, nil
 }
 
 func (i *jsonAPIHandler) POSTShutdown(w http.ResponseWriter, r *http.Request) {
@@ -1190,39 +1155,48 @@ func (i *jsonAPIHandler) POSTShutdown(w http.ResponseWriter, r *http.Request) {
 		log.Info("OpenBazaar Server shutting down...")
 		time.Sleep(time.Second)
 		if core.Node != nil {
-			core.Node.Datastore.Close()
-			repoLockFile := filepath.Join(core.Node.RepoPath, lockfile.LockFile)
-			os.Remove(repoLockFile)
-			core.Node.Wallet.Close()
-			core.Node.IpfsNode.Close()
+			core.Node.Shutdown()
 		}
-		os.Exit(1)
+		os.Exit(0)
 	}
+	fmt.Fprint(w, `{}`)
 	go shutdown()
 	return
 }
 
 func (i *jsonAPIHandler) POSTRefund(w http.ResponseWriter, r *http.Request) {
-	type orderCancel struct {
-		OrderId string `json:"orderId"`
+	type refundItem struct {
+		ListingHash string `json:"listingHash"`
+		Quantity    uint32 `json:"quantity"`
+	}
+	type orderRefund struct {
+		OrderId string       `json:"orderId"`
+		Amount  uint64       `json:"amount"`
+		Items   []refundItem `json:"items"`
+		Memo    string       `json:"memo"`
 	}
 	decoder := json.NewDecoder(r.Body)
-	var can orderCancel
-	err := decoder.Decode(&can)
+	var ref orderRefund
+	err := decoder.Decode(&ref)
 	if err != nil {
 		ErrorResponse(w, http.StatusBadRequest, err.Error())
 		return
 	}
-	contract, state, _, records, _, err := i.node.Datastore.Sales().GetByOrderId(can.OrderId)
-	if err != nil {
-		ErrorResponse(w, http.StatusNotFound, "order not found")
+	unlock := i.node.LockOrder(ref.OrderId)
+	defer unlock()
+	contract, records, err := fundedSale(i.node, ref.OrderId, "refunding")
+	if err == errOrderNotFound {
+		ErrorResponse(w, http.StatusNotFound, err.Error())
 		return
-	}
-	if state != pb.OrderState_FUNDED {
-		ErrorResponse(w, http.StatusBadRequest, "order must be funded before refunding")
+	} else if err != nil {
+		ErrorResponse(w, http.StatusBadRequest, err.Error())
 		return
 	}
-	err = i.node.RefundOrder(contract, records)
+	refund := &pb.Refund{Amount: ref.Amount, Memo: ref.Memo}
+	for _, item := range ref.Items {
+		refund.Items = append(refund.Items, &pb.Refund_Item{ListingHash: item.ListingHash, Quantity: item.Quantity})
+	}
+	err = i.node.RefundOrder(contract, records, refund)
 	if err != nil {
 		ErrorResponse(w, http.StatusInternalServerError, err.Error())
 		return
@@ -1231,9 +1205,32 @@ func (i *jsonAPIHandler) POSTRefund(w http.ResponseWriter, r *http.Request) {
 	return
 }
 
+var errOrderNotFound = errors.New("order not found")
+
+// Load a sale which can still be fulfilled or refunded. Used by both the JSON and the gRPC API.
+func fundedSale(node *core.OpenBazaarNode, orderId, action string) (*pb.RicardianContract, []*spvwallet.TransactionRecord, error) {
+	contract, state, _, records, _, err := node.Datastore.Sales().GetByOrderId(orderId)
+	if err != nil {
+		return nil, nil, errOrderNotFound
+	}
+	if state != pb.OrderState_FUNDED && state != pb.OrderState_PARTIALLY_REFUNDED && state != pb.OrderState_PARTIALLY_FULFILLED {
+		return nil, nil, fmt.Errorf("order must be funded before %s", action)
+	}
+	return contract, records, nil
+}
+
+// Returns the peer IDs of the moderators which are online and match the filter. With profiles=true
+// each moderator is returned with its profile and moderation file instead.
 func (i *jsonAPIHandler) GETModerators(w http.ResponseWriter, r *http.Request) {
 	query := r.URL.Query().Get("async")
 	async, _ := strconv.ParseBool(query)
+	profiles, _ := strconv.ParseBool(r.URL.Query().Get("profiles"))
+
+	filter, err := parseModeratorFilter(r.URL.Query())
+	if err != nil {
+		ErrorResponse(w, http.StatusBadRequest, err.Error())
+		return
+	}
 
 	ctx := context.Background()
 	if !async {
@@ -1242,26 +1239,26 @@ func (i *jsonAPIHandler) GETModerators(w http.ResponseWriter, r *http.Request) {
 			ErrorResponse(w, http.StatusInternalServerError, err.Error())
 			return
 		}
-		var mods []string
+		peerChan := make(chan string, len(peerInfoList))
 		for _, p := range peerInfoList {
-			addr := p.Addrs[0]
-			if addr.Protocols()[0].Code != multiaddr.P_IPFS {
-				continue
-			}
-			val, err := addr.ValueForProtocol(multiaddr.P_IPFS)
-			if err != nil {
+			if len(p.Addrs) == 0 {
 				continue
 			}
-			mh, err := multihash.FromB58String(val)
+			peerId, err := moderatorPeerId(p.Addrs[0])
 			if err != nil {
 				continue
 			}
-			d, err := multihash.Decode(mh)
-			if err != nil {
-				continue
-			}
-			mods = append(mods, string(d.Digest))
+			peerChan <- peerId
 		}
+		close(peerChan)
+
+		mods := []json.RawMessage{}
+		var lock sync.Mutex
+		i.resolveModerators(peerChan, filter, profiles, func(mod []byte) {
+			lock.Lock()
+			mods = append(mods, json.RawMessage(mod))
+			lock.Unlock()
+		})
 		resp, err := json.MarshalIndent(mods, "", "    ")
 		if err != nil {
 			ErrorResponse(w, http.StatusInternalServerError, err.Error())
@@ -1280,36 +1277,34 @@ func (i *jsonAPIHandler) GETModerators(w http.ResponseWriter, r *http.Request) {
 		respJson, _ := json.MarshalIndent(response, "", "    ")
 		w.WriteHeader(http.StatusAccepted)
 		fmt.Fprint(w, string(respJson))
-		peerChan := ipfs.FindPointersAsync(i.node.IpfsNode.Routing.(*routing.IpfsDHT), ctx, core.ModeratorPointerID, 64)
+		pointerChan := ipfs.FindPointersAsync(i.node.IpfsNode.Routing.(*routing.IpfsDHT), ctx, core.ModeratorPointerID, 64)
+
+		peerChan := make(chan string)
+		go func() {
+			for p := range pointerChan {
+				if len(p.Addrs) == 0 {
+					continue
+				}
+				peerId, err := moderatorPeerId(p.Addrs[0])
+				if err != nil {
+					continue
+				}
+				peerChan <- peerId
+			}
+			close(peerChan)
+		}()
 
 		type wsResp struct {
-			Id        string `json:"id"`
-			Moderator string `json:"moderator"`
+			Id        string          `json:"id"`
+			Moderator json.RawMessage `json:"moderator"`
 		}
-		for p := range peerChan {
-			addr := p.Addrs[0]
-			if addr.Protocols()[0].Code != multiaddr.P_IPFS {
-				continue
-			}
-			val, err := addr.ValueForProtocol(multiaddr.P_IPFS)
+		i.resolveModerators(peerChan, filter, profiles, func(mod []byte) {
+			respJson, err := json.MarshalIndent(wsResp{id, json.RawMessage(mod)}, "", "    ")
 			if err != nil {
-				continue
-			}
-			mh, err := multihash.FromB58String(val)
-			if err != nil {
-				continue
-			}
-			d, err := multihash.Decode(mh)
-			if err != nil {
-				continue
-			}
-			resp := wsResp{id, string(d.Digest)}
-			respJson, err := json.MarshalIndent(resp, "", "    ")
-			if err != nil {
-				continue
+				return
 			}
 			i.node.Broadcast <- respJson
-		}
+		})
 	}
 }
 
@@ -1321,13 +1316,14 @@ func (i *jsonAPIHandler) POSTOrderFulfill(w http.ResponseWriter, r *http.Request
 		ErrorResponse(w, http.StatusBadRequest, err.Error())
 		return
 	}
-	contract, state, _, records, _, err := i.node.Datastore.Sales().GetByOrderId(fulfill.OrderId)
-	if err != nil {
-		ErrorResponse(w, http.StatusNotFound, "order not found")
+	unlock := i.node.LockOrder(fulfill.OrderId)
+	defer unlock()
+	contract, records, err := fundedSale(i.node, fulfill.OrderId, "fulfilling")
+	if err == errOrderNotFound {
+		ErrorResponse(w, http.StatusNotFound, err.Error())
 		return
-	}
-	if state != pb.OrderState_FUNDED {
-		ErrorResponse(w, http.StatusBadRequest, "order must be funded before fulfilling")
+	} else if err != nil {
+		ErrorResponse(w, http.StatusBadRequest, err.Error())
 		return
 	}
 	err = i.node.FulfillOrder(&fulfill, contract, records)
@@ -1387,3 +1383,692 @@ func (i *jsonAPIHandler) POSTOrderComplete(w http.ResponseWriter, r *http.Reques
 	fmt.Fprint(w, `{}`)
 	return
 }
+
+// Number of moderators resolved concurrently by GETModerators
+const moderatorResolveWorkers = 8
+
+// Resolve and verify each moderator read from peerChan, passing the moderators which match the filter
+// to found serialized as a JSON string of the peer ID, or as the full moderator if profiles is set.
+// Duplicate peer IDs are skipped. Returns once peerChan is closed and drained.
+func (i *jsonAPIHandler) resolveModerators(peerChan <-chan string, filter core.ModeratorFilter, profiles bool, found func(mod []byte)) {
+	m := jsonpb.Marshaler{
+		EnumsAsInts:  false,
+		EmitDefaults: true,
+		Indent:       "    ",
+		OrigName:     false,
+	}
+	var seenLock sync.Mutex
+	seen := make(map[string]bool)
+	var wg sync.WaitGroup
+	for w := 0; w < moderatorResolveWorkers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for peerId := range peerChan {
+				seenLock.Lock()
+				dup := seen[peerId]
+				seen[peerId] = true
+				seenLock.Unlock()
+				if dup {
+					continue
+				}
+				mod, err := i.node.ResolveModerator(peerId)
+				if err != nil {
+					log.Debugf("Dropping moderator %s: %s", peerId, err.Error())
+					continue
+				}
+				if !i.node.MatchModerator(mod.Moderator, filter) {
+					continue
+				}
+				if !profiles {
+					out, err := json.Marshal(peerId)
+					if err != nil {
+						continue
+					}
+					found(out)
+					continue
+				}
+				out, err := m.MarshalToString(mod)
+				if err != nil {
+					continue
+				}
+				found([]byte(out))
+			}
+		}()
+	}
+	wg.Wait()
+}
+
+// Moderator pointers point to the multihash encoded peer ID of the moderator
+func moderatorPeerId(addr ma.Multiaddr) (string, error) {
+	if addr.Protocols()[0].Code != ma.P_IPFS {
+		return "", errors.New("Pointer is not an IPFS address")
+	}
+	val, err := addr.ValueForProtocol(ma.P_IPFS)
+	if err != nil {
+		return "", err
+	}
+	mh, err := multihash.FromB58String(val)
+	if err != nil {
+		return "", err
+	}
+	d, err := multihash.Decode(mh)
+	if err != nil {
+		return "", err
+	}
+	return string(d.Digest), nil
+}
+
+func parseModeratorFilter(query url.Values) (core.ModeratorFilter, error) {
+	var filter core.ModeratorFilter
+	for _, l := range query["language"] {
+		for _, lang := range strings.Split(l, ",") {
+			if lang = strings.TrimSpace(lang); lang != "" {
+				filter.Languages = append(filter.Languages, lang)
+			}
+		}
+	}
+	for _, f := range query["feeType"] {
+		for _, feeType := range strings.Split(f, ",") {
+			t, ok := pb.Moderator_Fee_FeeType_value[strings.ToUpper(strings.TrimSpace(feeType))]
+			if !ok {
+				return filter, fmt.Errorf("Unknown fee type %s", feeType)
+			}
+			filter.FeeTypes = append(filter.FeeTypes, pb.Moderator_Fee_FeeType(t))
+		}
+	}
+	filter.Currency = query.Get("currency")
+	if maxFee := query.Get("maxFee"); maxFee != "" {
+		fee, err := strconv.ParseUint(maxFee, 10, 64)
+		if err != nil {
+			return filter, fmt.Errorf("Invalid maxFee: %s", err.Error())
+		}
+		filter.MaxFixedFee = fee
+	}
+	if maxPercentage := query.Get("maxPercentage"); maxPercentage != "" {
+		pct, err := strconv.ParseFloat(maxPercentage, 32)
+		if err != nil {
+			return filter, fmt.Errorf("Invalid maxPercentage: %s", err.Error())
+		}
+		filter.MaxPercentage = float32(pct)
+	}
+	return filter, nil
+}
+
+func (i *jsonAPIHandler) POSTDigitalAsset(w http.ResponseWriter, r *http.Request) {
+	type digitalAsset struct {
+		Slug        string   `json:"slug"`
+		Filename    string   `json:"filename"`
+		File        []byte   `json:"file"` // Base64 encoded
+		LicenseKeys []string `json:"licenseKeys"`
+	}
+	decoder := json.NewDecoder(r.Body)
+	var asset digitalAsset
+	err := decoder.Decode(&asset)
+	if err != nil {
+		ErrorResponse(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	listingPath := path.Join(i.node.RepoPath, "root", "listings", asset.Slug+".json")
+	if _, ferr := os.Stat(listingPath); os.IsNotExist(ferr) {
+		ErrorResponse(w, http.StatusNotFound, "Listing not found")
+		return
+	}
+	if len(asset.File) == 0 && len(asset.LicenseKeys) == 0 {
+		ErrorResponse(w, http.StatusBadRequest, "A file or license keys must be provided")
+		return
+	}
+	if len(asset.File) > 0 {
+		if asset.Filename == "" {
+			ErrorResponse(w, http.StatusBadRequest, "Filename must be set")
+			return
+		}
+		err = i.node.SetDigitalFile(asset.Slug, core.DigitalFile{Filename: asset.Filename, Data: asset.File})
+		if err != nil {
+			ErrorResponse(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+	}
+	if len(asset.LicenseKeys) > 0 {
+		err = i.node.AddLicenseKeys(asset.Slug, asset.LicenseKeys)
+		if err != nil {
+			ErrorResponse(w, http.StatusBadRequest, err.Error())
+			return
+		}
+	}
+	fmt.Fprint(w, `{}`)
+	return
+}
+
+func (i *jsonAPIHandler) GETDigitalAsset(w http.ResponseWriter, r *http.Request) {
+	slug := pathParam(r, "slug")
+	asset, err := i.node.GetDigitalAsset(slug)
+	if err != nil {
+		ErrorResponse(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	ret, err := json.MarshalIndent(asset, "", "    ")
+	if err != nil {
+		ErrorResponse(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	fmt.Fprint(w, string(ret))
+	return
+}
+
+func (i *jsonAPIHandler) DELETEDigitalAsset(w http.ResponseWriter, r *http.Request) {
+	type deleteReq struct {
+		Slug string `json:"slug"`
+	}
+	decoder := json.NewDecoder(r.Body)
+	var req deleteReq
+	err := decoder.Decode(&req)
+	if err != nil {
+		ErrorResponse(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	err = i.node.DeleteDigitalAsset(req.Slug)
+	if err != nil {
+		ErrorResponse(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	fmt.Fprint(w, `{}`)
+	return
+}
+
+func (i *jsonAPIHandler) POSTDatabasePassword(w http.ResponseWriter, r *http.Request) {
+	if !i.config.Authenticated {
+		ErrorResponse(w, http.StatusForbidden, "The database password can only be changed when API authentication is enabled")
+		return
+	}
+	type passwordChange struct {
+		CurrentPassword string `json:"currentPassword"`
+		NewPassword     string `json:"newPassword"`
+	}
+	decoder := json.NewDecoder(r.Body)
+	var change passwordChange
+	err := decoder.Decode(&change)
+	if err != nil {
+		ErrorResponse(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	switch {
+	case change.CurrentPassword == "" && change.NewPassword == "":
+		ErrorResponse(w, http.StatusBadRequest, "A current or new password must be provided")
+		return
+	case change.NewPassword != "" && len(change.NewPassword) < 8:
+		ErrorResponse(w, http.StatusBadRequest, "The new password must be at least 8 characters")
+		return
+	case change.CurrentPassword == "":
+		err = i.node.Datastore.Encrypt(change.NewPassword)
+	case change.NewPassword == "":
+		err = i.node.Datastore.Decrypt(change.CurrentPassword)
+	default:
+		err = i.node.Datastore.ChangePassword(change.CurrentPassword, change.NewPassword)
+	}
+	switch err {
+	case nil:
+	case repo.ErrAlreadyEncrypted, repo.ErrNotEncrypted:
+		ErrorResponse(w, http.StatusConflict, err.Error())
+		return
+	case repo.ErrInvalidPassword:
+		ErrorResponse(w, http.StatusUnauthorized, err.Error())
+		return
+	default:
+		ErrorResponse(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	fmt.Fprint(w, `{}`)
+	return
+}
+
+func (i *jsonAPIHandler) POSTBackup(w http.ResponseWriter, r *http.Request) {
+	if !i.config.Authenticated {
+		ErrorResponse(w, http.StatusForbidden, "Backups can only be made when API authentication is enabled")
+		return
+	}
+	type backupRequest struct {
+		Password string `json:"password"`
+	}
+	decoder := json.NewDecoder(r.Body)
+	var req backupRequest
+	err := decoder.Decode(&req)
+	if err != nil {
+		ErrorResponse(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if len(req.Password) < 8 {
+		ErrorResponse(w, http.StatusBadRequest, "The backup password must be at least 8 characters")
+		return
+	}
+	filename := "openbazaar-" + i.node.IpfsNode.Identity.Pretty() + "-" + time.Now().Format("20060102150405") + ".backup"
+	w.Header().Set("Content-Type", "application/octet-stream")
+	w.Header().Set("Content-Disposition", `attachment; filename="`+filename+`"`)
+	// The archive is streamed so once it starts errors can only be reported by cutting it short
+	if err := i.node.WriteBackup(w, req.Password); err != nil {
+		log.Errorf("Error writing backup: %s", err.Error())
+	}
+}
+
+func (i *jsonAPIHandler) POSTRecover(w http.ResponseWriter, r *http.Request) {
+	if !i.config.Authenticated {
+		ErrorResponse(w, http.StatusForbidden, "Orders can only be recovered when API authentication is enabled")
+		return
+	}
+	type recoverRequest struct {
+		Peers []string `json:"peers"`
+	}
+	var req recoverRequest
+	// An empty body asks the peers we follow and our followers
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil && err != io.EOF {
+		ErrorResponse(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	recovered, err := i.node.RecoverOrders(req.Peers)
+	if err != nil {
+		ErrorResponse(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	if recovered == nil {
+		recovered = []core.RecoveredOrder{}
+	}
+	ret, err := json.MarshalIndent(recovered, "", "    ")
+	if err != nil {
+		ErrorResponse(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	fmt.Fprint(w, string(ret))
+}
+
+func (i *jsonAPIHandler) GETOrders(w http.ResponseWriter, r *http.Request) {
+	type orderSummary struct {
+		OrderId   string    `json:"orderId"`
+		Type      string    `json:"type"`
+		PeerId    string    `json:"peerId"`
+		Title     string    `json:"title"`
+		Total     uint64    `json:"total"`
+		State     string    `json:"state"`
+		Funded    bool      `json:"funded"`
+		Read      bool      `json:"read"`
+		Timestamp time.Time `json:"timestamp"`
+	}
+	summaries, err := orderSummaries(i.node)
+	if err != nil {
+		ErrorResponse(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	orders := []orderSummary{}
+	for _, o := range summaries {
+		s := orderSummary{OrderId: o.OrderId, Type: o.Type, PeerId: o.PeerId, Title: o.Title, Total: o.Total, State: o.State.String(), Funded: o.Funded, Read: o.Read}
+		if o.Timestamp != nil {
+			s.Timestamp = time.Unix(o.Timestamp.Seconds, 0)
+		}
+		orders = append(orders, s)
+	}
+	ret, err := json.MarshalIndent(orders, "", "    ")
+	if err != nil {
+		ErrorResponse(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	fmt.Fprint(w, string(ret))
+}
+
+// Summarize every purchase and sale. Used by both the JSON and the gRPC API.
+func orderSummaries(node *core.OpenBazaarNode) ([]*pb.OrderList_Summary, error) {
+	summarize := func(orderId, orderType string, contract *pb.RicardianContract, state pb.OrderState, funded, read bool) *pb.OrderList_Summary {
+		o := &pb.OrderList_Summary{OrderId: orderId, Type: orderType, State: state, Funded: funded, Read: read}
+		if len(contract.VendorListings) > 0 && contract.VendorListings[0].Item != nil {
+			o.Title = contract.VendorListings[0].Item.Title
+		}
+		if order := contract.BuyerOrder; order != nil {
+			if order.Payment != nil {
+				o.Total = order.Payment.Amount
+			}
+			if order.Timestamp != nil {
+				o.Timestamp = &timestamp.Timestamp{Seconds: order.Timestamp.Seconds}
+			}
+			if orderType == "sale" && order.BuyerID != nil {
+				o.PeerId = order.BuyerID.Guid
+			}
+		}
+		if orderType == "purchase" && len(contract.VendorListings) > 0 && contract.VendorListings[0].VendorID != nil {
+			o.PeerId = contract.VendorListings[0].VendorID.Guid
+		}
+		return o
+	}
+	var orders []*pb.OrderList_Summary
+	purchases, err := node.Datastore.Purchases().GetAll()
+	if err != nil {
+		return nil, err
+	}
+	for _, orderId := range purchases {
+		contract, state, funded, _, read, err := node.Datastore.Purchases().GetByOrderId(orderId)
+		if err != nil {
+			continue
+		}
+		orders = append(orders, summarize(orderId, "purchase", contract, state, funded, read))
+	}
+	sales, err := node.Datastore.Sales().GetAll()
+	if err != nil {
+		return nil, err
+	}
+	for _, orderId := range sales {
+		contract, state, funded, _, read, err := node.Datastore.Sales().GetByOrderId(orderId)
+		if err != nil {
+			continue
+		}
+		orders = append(orders, summarize(orderId, "sale", contract, state, funded, read))
+	}
+	return orders, nil
+}
+
+func (i *jsonAPIHandler) POSTAPIToken(w http.ResponseWriter, r *http.Request) {
+	type tokenRequest struct {
+		Name   string   `json:"name"`
+		Scopes []string `json:"scopes"`
+	}
+	decoder := json.NewDecoder(r.Body)
+	var req tokenRequest
+	err := decoder.Decode(&req)
+	if err != nil {
+		ErrorResponse(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if req.Name == "" {
+		ErrorResponse(w, http.StatusBadRequest, "A name is required")
+		return
+	}
+	if len(req.Scopes) == 0 {
+		ErrorResponse(w, http.StatusBadRequest, "At least one scope is required")
+		return
+	}
+	for _, scope := range req.Scopes {
+		if !validScope(scope) {
+			ErrorResponse(w, http.StatusBadRequest, "Unknown scope "+scope+". Valid scopes are "+strings.Join(Scopes, ", "))
+			return
+		}
+	}
+	tokens, err := i.node.Datastore.APITokens().GetAll()
+	if err != nil {
+		ErrorResponse(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	for _, t := range tokens {
+		if t.Name == req.Name {
+			ErrorResponse(w, http.StatusConflict, "A token with this name already exists")
+			return
+		}
+	}
+	tokenBytes := make([]byte, 32)
+	rand.Read(tokenBytes)
+	token := base58.Encode(tokenBytes)
+	if err := i.node.Datastore.APITokens().Put(req.Name, hashToken(token), req.Scopes); err != nil {
+		ErrorResponse(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	// This is the only time the token is available. Only its hash is stored.
+	type tokenResponse struct {
+		Name   string   `json:"name"`
+		Token  string   `json:"token"`
+		Scopes []string `json:"scopes"`
+	}
+	ret, err := json.MarshalIndent(tokenResponse{req.Name, token, req.Scopes}, "", "    ")
+	if err != nil {
+		ErrorResponse(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	fmt.Fprint(w, string(ret))
+}
+
+func (i *jsonAPIHandler) GETAPITokens(w http.ResponseWriter, r *http.Request) {
+	tokens, err := i.node.Datastore.APITokens().GetAll()
+	if err != nil {
+		ErrorResponse(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	if tokens == nil {
+		tokens = []repo.APIToken{}
+	}
+	ret, err := json.MarshalIndent(tokens, "", "    ")
+	if err != nil {
+		ErrorResponse(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	fmt.Fprint(w, string(ret))
+}
+
+func (i *jsonAPIHandler) DELETEAPIToken(w http.ResponseWriter, r *http.Request) {
+	type deleteReq struct {
+		Name string `json:"name"`
+	}
+	decoder := json.NewDecoder(r.Body)
+	var req deleteReq
+	err := decoder.Decode(&req)
+	if err != nil {
+		ErrorResponse(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	err = i.node.Datastore.APITokens().Delete(req.Name)
+	if err == repo.ErrTokenNotFound {
+		ErrorResponse(w, http.StatusNotFound, err.Error())
+		return
+	} else if err != nil {
+		ErrorResponse(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	fmt.Fprint(w, `{}`)
+}
+
+func (i *jsonAPIHandler) GETWebhookDeliveries(w http.ResponseWriter, r *http.Request) {
+	status := r.URL.Query().Get("status")
+	if status != "" && status != repo.DeliveryPending && status != repo.DeliveryDelivered && status != repo.DeliveryFailed {
+		ErrorResponse(w, http.StatusBadRequest, "status must be pending, delivered or failed")
+		return
+	}
+	limit := r.URL.Query().Get("limit")
+	if limit == "" {
+		limit = "100"
+	}
+	l, err := strconv.ParseInt(limit, 10, 32)
+	if err != nil {
+		ErrorResponse(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	deliveries, err := i.node.Datastore.WebhookDeliveries().GetAll(status, int(l))
+	if err != nil {
+		ErrorResponse(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	if deliveries == nil {
+		deliveries = []repo.WebhookDelivery{}
+	}
+	ret, err := json.MarshalIndent(deliveries, "", "    ")
+	if err != nil {
+		ErrorResponse(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	fmt.Fprint(w, string(ret))
+}
+
+// How far behind the best height known to its peers the wallet can be and still be ready
+const maxBlocksBehind = 2
+
+// How old the exchange rates can be before they are considered stale
+const maxExchangeRateAge = time.Hour
+
+// The pointers are republished daily so allow a missed run before reporting it
+const maxRepublishAge = 48 * time.Hour
+
+type ipfsHealth struct {
+	Ready  bool `json:"ready"`
+	Online bool `json:"online"`
+	Peers  int  `json:"peers"`
+}
+
+type publishHealth struct {
+	Ready         bool       `json:"ready"`
+	LastPublished *time.Time `json:"lastPublished,omitempty"`
+	RootHash      string     `json:"rootHash"`
+	Error         string     `json:"error,omitempty"`
+}
+
+type walletHealth struct {
+	Ready           bool   `json:"ready"`
+	Height          uint32 `json:"height"`
+	BestKnownHeight uint32 `json:"bestKnownHeight"`
+}
+
+type retrieverHealth struct {
+	Ready   bool       `json:"ready"`
+	LastRun *time.Time `json:"lastRun,omitempty"`
+	Errors  []string   `json:"errors"`
+}
+
+type republisherHealth struct {
+	Ready   bool       `json:"ready"`
+	LastRun *time.Time `json:"lastRun,omitempty"`
+}
+
+type exchangeRateHealth struct {
+	Ready       bool       `json:"ready"`
+	LastUpdated *time.Time `json:"lastUpdated,omitempty"`
+}
+
+type datastoreHealth struct {
+	Ready    bool   `json:"ready"`
+	Writable bool   `json:"writable"`
+	Error    string `json:"error,omitempty"`
+}
+
+// Subsystems which are not running, such as a disabled wallet, are left out
+type healthReport struct {
+	Healthy            bool                `json:"healthy"`
+	IPFS               ipfsHealth          `json:"ipfs"`
+	Publishing         publishHealth       `json:"publishing"`
+	Wallet             *walletHealth       `json:"wallet,omitempty"`
+	MessageRetriever   *retrieverHealth    `json:"messageRetriever,omitempty"`
+	PointerRepublisher *republisherHealth  `json:"pointerRepublisher,omitempty"`
+	ExchangeRates      *exchangeRateHealth `json:"exchangeRates,omitempty"`
+	Datastore          datastoreHealth     `json:"datastore"`
+}
+
+// Report whether each subsystem is ready. The response is 200 if they all are and 503 otherwise
+// so load balancers and supervisors can act on it.
+func (i *jsonAPIHandler) GETHealth(w http.ResponseWriter, r *http.Request) {
+	var h healthReport
+	h.Healthy = true
+
+	h.IPFS.Online = i.node.IpfsNode.OnlineMode()
+	if h.IPFS.Online {
+		h.IPFS.Peers = len(i.node.IpfsNode.PeerHost.Network().Peers())
+	}
+	h.IPFS.Ready = h.IPFS.Online && h.IPFS.Peers > 0
+	h.Healthy = h.Healthy && h.IPFS.Ready
+
+	publish := i.node.PublishManager.Status()
+	h.Publishing.LastPublished = publish.LastPublished
+	h.Publishing.RootHash = i.node.RootHash
+	if publish.LastResult != nil {
+		h.Publishing.Error = publish.LastResult.Error
+	}
+	h.Publishing.Ready = h.Publishing.Error == ""
+	h.Healthy = h.Healthy && h.Publishing.Ready
+
+	if i.node.Wallet != nil {
+		h.Wallet = &walletHealth{
+			Height:          i.node.Wallet.ChainTip(),
+			BestKnownHeight: i.node.Wallet.BestKnownHeight(),
+		}
+		h.Wallet.Ready = h.Wallet.BestKnownHeight > 0 && h.Wallet.Height+maxBlocksBehind >= h.Wallet.BestKnownHeight
+		h.Healthy = h.Healthy && h.Wallet.Ready
+	}
+
+	if i.node.MessageRetriever != nil {
+		status := i.node.MessageRetriever.Status()
+		h.MessageRetriever = &retrieverHealth{
+			Ready:   !status.LastRun.IsZero(),
+			LastRun: timeOrNil(status.LastRun),
+			Errors:  status.Errors,
+		}
+		if h.MessageRetriever.Errors == nil {
+			h.MessageRetriever.Errors = []string{}
+		}
+		h.Healthy = h.Healthy && h.MessageRetriever.Ready
+	}
+
+	if i.node.PointerRepublisher != nil {
+		lastRun := i.node.PointerRepublisher.LastRun()
+		h.PointerRepublisher = &republisherHealth{
+			Ready:   !lastRun.IsZero() && time.Since(lastRun) < maxRepublishAge,
+			LastRun: timeOrNil(lastRun),
+		}
+		h.Healthy = h.Healthy && h.PointerRepublisher.Ready
+	}
+
+	if i.node.ExchangeRates != nil {
+		updated := i.node.ExchangeRates.LastUpdated()
+		h.ExchangeRates = &exchangeRateHealth{
+			Ready:       !updated.IsZero() && time.Since(updated) < maxExchangeRateAge,
+			LastUpdated: timeOrNil(updated),
+		}
+		h.Healthy = h.Healthy && h.ExchangeRates.Ready
+	}
+
+	if err := i.node.Datastore.CheckWritable(); err != nil {
+		h.Datastore.Error = err.Error()
+	} else {
+		h.Datastore.Writable = true
+	}
+	h.Datastore.Ready = h.Datastore.Writable
+	h.Healthy = h.Healthy && h.Datastore.Ready
+
+	ret, err := json.MarshalIndent(h, "", "    ")
+	if err != nil {
+		ErrorResponse(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	if !h.Healthy {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}
+	fmt.Fprint(w, string(ret))
+}
+
+// Times which haven't happened yet are left out of responses
+func timeOrNil(t time.Time) *time.Time {
+	if t.IsZero() {
+		return nil
+	}
+	return &t
+}
+
+func (i *jsonAPIHandler) GETPublishStatus(w http.ResponseWriter, r *http.Request) {
+	ret, err := json.MarshalIndent(i.node.PublishManager.Status(), "", "    ")
+	if err != nil {
+		ErrorResponse(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	fmt.Fprint(w, string(ret))
+}
+
+// Check for offline messages now. Progress is reported on the messages websocket topic.
+func (i *jsonAPIHandler) POSTFetchMessages(w http.ResponseWriter, r *http.Request) {
+	if i.node.MessageRetriever == nil {
+		ErrorResponse(w, http.StatusServiceUnavailable, "Message retriever is not running")
+		return
+	}
+	i.node.MessageRetriever.FetchNow()
+	fmt.Fprint(w, `{}`)
+}
+
+func (i *jsonAPIHandler) GETCrosspostStatus(w http.ResponseWriter, r *http.Request) {
+	status := []core.CrosspostStatus{}
+	if i.node.Crossposter != nil {
+		status = i.node.Crossposter.Status()
+	}
+	ret, err := json.MarshalIndent(status, "", "    ")
+	if err != nil {
+		ErrorResponse(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	fmt.Fprint(w, string(ret))
+}
@@ -0,0 +1,42 @@
+package api
+
+import (
+	"net/url"
+	"reflect"
+	"testing"
+
+	"github.com/OpenBazaar/openbazaar-go/core"
+	"github.com/OpenBazaar/openbazaar-go/pb"
+)
+
+func TestParseModeratorFilter(t *testing.T) {
+	tests := []struct {
+		query  string
+		filter core.ModeratorFilter
+	}{
+		{"", core.ModeratorFilter{}},
+		{"language=English,+german&language=&language=French", core.ModeratorFilter{Languages: []string{"English", "german", "French"}}},
+		{"feeType=fixed,PERCENTAGE", core.ModeratorFilter{FeeTypes: []pb.Moderator_Fee_FeeType{pb.Moderator_Fee_FIXED, pb.Moderator_Fee_PERCENTAGE}}},
+		{"currency=USD&maxFee=5000&maxPercentage=2.5", core.ModeratorFilter{Currency: "USD", MaxFixedFee: 5000, MaxPercentage: 2.5}},
+	}
+	for _, test := range tests {
+		query, err := url.ParseQuery(test.query)
+		if err != nil {
+			t.Fatal(err)
+		}
+		filter, err := parseModeratorFilter(query)
+		if err != nil {
+			t.Errorf("%q: %s", test.query, err)
+			continue
+		}
+		if !reflect.DeepEqual(filter, test.filter) {
+			t.Errorf("%q: expected %+v, got %+v", test.query, test.filter, filter)
+		}
+	}
+	for _, invalid := range []string{"feeType=free", "maxFee=-1", "maxFee=lots", "maxPercentage=half"} {
+		query, _ := url.ParseQuery(invalid)
+		if _, err := parseModeratorFilter(query); err == nil {
+			t.Errorf("%q: expected an error", invalid)
+		}
+	}
+}
@@ -0,0 +1,86 @@
+package api
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/OpenBazaar/openbazaar-go/core"
+	"github.com/OpenBazaar/spvwallet"
+	prometheus "gx/ipfs/QmdhsRK1EK2fvAz2i2SH5DEfkL6seDuyMYEsxKa9Braim3/client_golang/prometheus"
+)
+
+var (
+	peersMetric = prometheus.NewDesc(
+		prometheus.BuildFQName("openbazaar", "p2p", "peers"),
+		"Number of connected peers", nil, nil)
+	chainTipMetric = prometheus.NewDesc(
+		prometheus.BuildFQName("openbazaar", "wallet", "chain_tip"),
+		"Height of the best block known to the wallet", nil, nil)
+	balanceMetric = prometheus.NewDesc(
+		prometheus.BuildFQName("openbazaar", "wallet", "balance_satoshis"),
+		"Wallet balance", []string{"status"}, nil)
+	utxosMetric = prometheus.NewDesc(
+		prometheus.BuildFQName("openbazaar", "wallet", "utxos"),
+		"Number of unspent outputs in the wallet", nil, nil)
+	exchangeRateAgeMetric = prometheus.NewDesc(
+		prometheus.BuildFQName("openbazaar", "exchange_rates", "age_seconds"),
+		"Time since the exchange rates were last fetched", nil, nil)
+)
+
+// Collects the metrics which are read from the node when scraped
+type nodeCollector struct {
+	node *core.OpenBazaarNode
+}
+
+func (c nodeCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- peersMetric
+	ch <- chainTipMetric
+	ch <- balanceMetric
+	ch <- utxosMetric
+	ch <- exchangeRateAgeMetric
+}
+
+func (c nodeCollector) Collect(ch chan<- prometheus.Metric) {
+	ch <- prometheus.MustNewConstMetric(peersMetric, prometheus.GaugeValue, float64(len(c.node.IpfsNode.PeerHost.Network().Peers())))
+
+	if c.node.Wallet != nil {
+		ch <- prometheus.MustNewConstMetric(chainTipMetric, prometheus.GaugeValue, float64(c.node.Wallet.ChainTip()))
+		confirmed, unconfirmed := c.node.Wallet.Balance()
+		ch <- prometheus.MustNewConstMetric(balanceMetric, prometheus.GaugeValue, float64(confirmed), "confirmed")
+		ch <- prometheus.MustNewConstMetric(balanceMetric, prometheus.GaugeValue, float64(unconfirmed), "unconfirmed")
+	}
+	if store, ok := c.node.Datastore.(interface {
+		Utxos() spvwallet.Utxos
+	}); ok {
+		if utxos, err := store.Utxos().GetAll(); err == nil {
+			ch <- prometheus.MustNewConstMetric(utxosMetric, prometheus.GaugeValue, float64(len(utxos)))
+		}
+	}
+	if c.node.ExchangeRates != nil {
+		if updated := c.node.ExchangeRates.LastUpdated(); !updated.IsZero() {
+			ch <- prometheus.MustNewConstMetric(exchangeRateAgeMetric, prometheus.GaugeValue, time.Since(updated).Seconds())
+		}
+	}
+}
+
+// Serves the Prometheus metrics to clients allowed to read from the JSON API
+type metricsHandler struct {
+	auth    *authenticator
+	handler http.Handler
+}
+
+func newMetricsHandler(node *core.OpenBazaarNode, auth *authenticator) (*metricsHandler, error) {
+	if _, err := prometheus.RegisterOrGet(nodeCollector{node}); err != nil {
+		return nil, err
+	}
+	return &metricsHandler{auth, prometheus.UninstrumentedHandler()}, nil
+}
+
+func (m *metricsHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	granted, ok := m.auth.scopes(r, "")
+	if !ok || !hasScope(granted, ScopeRead) {
+		ErrorResponse(w, http.StatusForbidden, "Forbidden")
+		return
+	}
+	m.handler.ServeHTTP(w, r)
+}
@@ -36,6 +36,10 @@ type completionWrapper struct {
 	CompletionNotification `json:"orderCompletion"`
 }
 
+type walletTransactionWrapper struct {
+	WalletTransactionNotification `json:"walletTransaction"`
+}
+
 type OrderNotification struct {
 	Title             string `json:"title"`
 	BuyerGuid         string `json:"buyerGuid"`
@@ -60,10 +64,12 @@ type OrderCancelNotification struct {
 
 type RefundNotification struct {
 	OrderId string `json:"orderId"`
+	Amount  uint64 `json:"amount"` // Zero if the whole order was refunded
 }
 
 type FulfillmentNotification struct {
 	OrderId string `json:"orderId"`
+	Slug    string `json:"slug"`
 }
 
 type CompletionNotification struct {
@@ -78,6 +84,17 @@ type UnfollowNotification struct {
 	Unfollow string `json:"unfollow"`
 }
 
+type WalletTransactionNotification struct {
+	Txid    string          `json:"txid"`
+	Inputs  []TransactionIO `json:"inputs"`
+	Outputs []TransactionIO `json:"outputs"`
+}
+
+type TransactionIO struct {
+	Address string `json:"address"`
+	Value   int64  `json:"value"`
+}
+
 func Serialize(i interface{}) []byte {
 	var n notificationWrapper
 	switch i.(type) {
@@ -123,6 +140,12 @@ func Serialize(i interface{}) []byte {
 				CompletionNotification: i.(CompletionNotification),
 			},
 		}
+	case WalletTransactionNotification:
+		n = notificationWrapper{
+			walletTransactionWrapper{
+				WalletTransactionNotification: i.(WalletTransactionNotification),
+			},
+		}
 	case FollowNotification:
 		n = notificationWrapper{
 			i.(FollowNotification),
@@ -0,0 +1,167 @@
+package api
+
+import (
+	"context"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+
+	"gx/ipfs/QmRBqJF7hb8ZSpRcMwUt8hNhydWcxGEhtk81HKq6oUwKvs/go-libp2p-peer"
+)
+
+// Every route is served under this prefix. The same routes without it are kept for existing clients.
+const apiVersionPrefix = "/v1"
+
+// Validators for typed path parameters. A parameter written as {name} is a string,
+// {name:type} must pass the validator for type.
+var paramTypes = map[string]func(string) bool{
+	"string": func(s string) bool {
+		return s != ""
+	},
+	"peer": func(s string) bool {
+		_, err := peer.IDB58Decode(s)
+		return err == nil
+	},
+	"int": func(s string) bool {
+		_, err := strconv.Atoi(s)
+		return err == nil
+	},
+}
+
+type segment struct {
+	literal   string
+	param     string
+	paramType string
+}
+
+type route struct {
+	method   string
+	pattern  string
+	segments []segment
+	scope    string
+	handler  http.HandlerFunc
+}
+
+// A method and path router with typed path parameters
+type router struct {
+	routes []*route
+}
+
+// Add a route. Patterns are paths where any segment may be a {name} or {name:type} parameter.
+func (rt *router) handle(method, pattern, scope string, handler http.HandlerFunc) {
+	r := &route{method: method, pattern: pattern, scope: scope, handler: handler}
+	for _, s := range splitPath(pattern) {
+		if strings.HasPrefix(s, "{") && strings.HasSuffix(s, "}") {
+			name, paramType := s[1:len(s)-1], "string"
+			if i := strings.Index(name, ":"); i >= 0 {
+				name, paramType = name[:i], name[i+1:]
+			}
+			if _, ok := paramTypes[paramType]; !ok {
+				panic("unknown path parameter type " + paramType + " in " + pattern)
+			}
+			r.segments = append(r.segments, segment{param: name, paramType: paramType})
+		} else {
+			r.segments = append(r.segments, segment{literal: s})
+		}
+	}
+	rt.routes = append(rt.routes, r)
+}
+
+type routeError struct {
+	status int
+	reason string
+	allow  []string
+}
+
+// Find the route for a request and return it with its path parameters. Routes with more literal
+// segments win over those with parameters. A path which only matches routes for other methods
+// is a 405 and one which only fails on the type of a parameter is a 400.
+func (rt *router) lookup(method, path string) (*route, map[string]string, *routeError) {
+	if path == apiVersionPrefix || strings.HasPrefix(path, apiVersionPrefix+"/") {
+		path = strings.TrimPrefix(path, apiVersionPrefix)
+	}
+	parts := splitPath(path)
+	var best *route
+	var bestParams map[string]string
+	bestLiterals := -1
+	allowed := make(map[string]bool)
+	var badParam string
+	for _, r := range rt.routes {
+		params, literals, invalid, ok := r.match(parts)
+		if !ok {
+			continue
+		}
+		if r.method != method {
+			allowed[r.method] = true
+			continue
+		}
+		if invalid != "" {
+			badParam = invalid
+			continue
+		}
+		if literals > bestLiterals {
+			best, bestParams, bestLiterals = r, params, literals
+		}
+	}
+	if best != nil {
+		return best, bestParams, nil
+	}
+	if badParam != "" {
+		return nil, nil, &routeError{status: http.StatusBadRequest, reason: "Invalid " + badParam}
+	}
+	if len(allowed) > 0 {
+		var methods []string
+		for m := range allowed {
+			methods = append(methods, m)
+		}
+		sort.Strings(methods)
+		return nil, nil, &routeError{http.StatusMethodNotAllowed, "Method Not Allowed", methods}
+	}
+	return nil, nil, &routeError{status: http.StatusNotFound, reason: "Not Found"}
+}
+
+// Match the route against the parts of a path. If the path fits the route but a parameter
+// has the wrong type the name of the parameter is returned.
+func (r *route) match(parts []string) (params map[string]string, literals int, invalid string, ok bool) {
+	if len(parts) != len(r.segments) {
+		return nil, 0, "", false
+	}
+	params = make(map[string]string)
+	for n, s := range r.segments {
+		if s.param == "" {
+			if s.literal != parts[n] {
+				return nil, 0, "", false
+			}
+			literals++
+			continue
+		}
+		if !paramTypes[s.paramType](parts[n]) && invalid == "" {
+			invalid = s.param
+		}
+		params[s.param] = parts[n]
+	}
+	return params, literals, invalid, true
+}
+
+func splitPath(path string) []string {
+	var parts []string
+	for _, p := range strings.Split(path, "/") {
+		if p != "" {
+			parts = append(parts, p)
+		}
+	}
+	return parts
+}
+
+type paramsKey struct{}
+
+func withParams(r *http.Request, params map[string]string) *http.Request {
+	return r.WithContext(context.WithValue(r.Context(), paramsKey{}, params))
+}
+
+// Return a path parameter of the route which matched the request
+func pathParam(r *http.Request, name string) string {
+	params, _ := r.Context().Value(paramsKey{}).(map[string]string)
+	return params[name]
+}
@@ -0,0 +1,273 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/http/httputil"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/OpenBazaar/openbazaar-go/repo"
+)
+
+const testPeerID = "QmSwHSqtUi9GhHTegi8gA5n2fsjP7LcnxGQeedj8ScLi8q"
+
+func ok(w http.ResponseWriter, r *http.Request) {}
+
+func newTestRouter() *router {
+	rt := new(router)
+	rt.handle("GET", "/ob/listing/{listingId}", ScopeRead, ok)
+	rt.handle("GET", "/ob/listing/index", ScopeRead, ok)
+	rt.handle("POST", "/ob/listing", ScopeListings, ok)
+	rt.handle("PUT", "/ob/listing", ScopeListings, ok)
+	rt.handle("GET", "/ob/status/{peerId:peer}", ScopeRead, ok)
+	rt.handle("GET", "/ob/page/{n:int}", ScopeRead, ok)
+	rt.handle("DELETE", "/ob/page/{n:int}", ScopeListings, ok)
+	rt.handle("GET", "/ob/exchangerate", ScopeRead, ok)
+	rt.handle("GET", "/ob/exchangerate/{currencyCode}", ScopeRead, ok)
+	return rt
+}
+
+func TestRouterLookup(t *testing.T) {
+	rt := newTestRouter()
+	tests := []struct {
+		method  string
+		path    string
+		pattern string
+		params  map[string]string
+		status  int
+		allow   []string
+	}{
+		// Literal segments win over parameters whatever the order the routes were added in
+		{"GET", "/ob/listing/index", "/ob/listing/index", map[string]string{}, 0, nil},
+		{"GET", "/ob/listing/my-slug", "/ob/listing/{listingId}", map[string]string{"listingId": "my-slug"}, 0, nil},
+		{"GET", "/ob/exchangerate", "/ob/exchangerate", map[string]string{}, 0, nil},
+		{"GET", "/ob/exchangerate/USD", "/ob/exchangerate/{currencyCode}", map[string]string{"currencyCode": "USD"}, 0, nil},
+
+		// Trailing and repeated slashes are ignored
+		{"GET", "/ob/listing/my-slug/", "/ob/listing/{listingId}", map[string]string{"listingId": "my-slug"}, 0, nil},
+		{"POST", "/ob/listing/", "/ob/listing", map[string]string{}, 0, nil},
+		{"GET", "//ob//listing//index", "/ob/listing/index", map[string]string{}, 0, nil},
+
+		// Every route is also served under the version prefix, but only as a whole segment
+		{"GET", "/v1/ob/listing/my-slug", "/ob/listing/{listingId}", map[string]string{"listingId": "my-slug"}, 0, nil},
+		{"POST", "/v1/ob/listing", "/ob/listing", map[string]string{}, 0, nil},
+		{"GET", "/v1/ob/status/" + testPeerID, "/ob/status/{peerId:peer}", map[string]string{"peerId": testPeerID}, 0, nil},
+		{"GET", "/v1", "", nil, http.StatusNotFound, nil},
+		{"GET", "/v1ob/listing/my-slug", "", nil, http.StatusNotFound, nil},
+		{"GET", "/v2/ob/listing/my-slug", "", nil, http.StatusNotFound, nil},
+
+		// A path with routes for other methods lists them in Allow
+		{"DELETE", "/ob/listing", "", nil, http.StatusMethodNotAllowed, []string{"POST", "PUT"}},
+		{"GET", "/v1/ob/listing", "", nil, http.StatusMethodNotAllowed, []string{"POST", "PUT"}},
+		{"POST", "/ob/listing/index", "", nil, http.StatusMethodNotAllowed, []string{"GET"}},
+
+		// Typed parameters are validated
+		{"GET", "/ob/status/" + testPeerID, "/ob/status/{peerId:peer}", map[string]string{"peerId": testPeerID}, 0, nil},
+		{"GET", "/ob/status/notapeer", "", nil, http.StatusBadRequest, nil},
+		{"GET", "/ob/status/" + testPeerID[:20], "", nil, http.StatusBadRequest, nil},
+		{"GET", "/ob/page/2", "/ob/page/{n:int}", map[string]string{"n": "2"}, 0, nil},
+
+		// A bad parameter is only reported for routes with the request's method
+		{"POST", "/ob/status/notapeer", "", nil, http.StatusMethodNotAllowed, []string{"GET"}},
+		{"GET", "/ob/page/two", "", nil, http.StatusBadRequest, nil},
+		{"DELETE", "/ob/page/two", "", nil, http.StatusBadRequest, nil},
+		{"PUT", "/ob/page/two", "", nil, http.StatusMethodNotAllowed, []string{"DELETE", "GET"}},
+
+		{"GET", "/ob/unknown", "", nil, http.StatusNotFound, nil},
+		{"GET", "/ob/listing/my-slug/extra", "", nil, http.StatusNotFound, nil},
+		{"GET", "/", "", nil, http.StatusNotFound, nil},
+	}
+	for _, test := range tests {
+		route, params, rerr := rt.lookup(test.method, test.path)
+		if test.status != 0 {
+			if rerr == nil {
+				t.Errorf("%s %s: expected status %d, matched %s", test.method, test.path, test.status, route.pattern)
+				continue
+			}
+			if rerr.status != test.status {
+				t.Errorf("%s %s: expected status %d, got %d", test.method, test.path, test.status, rerr.status)
+			}
+			if !reflect.DeepEqual(rerr.allow, test.allow) {
+				t.Errorf("%s %s: expected Allow %v, got %v", test.method, test.path, test.allow, rerr.allow)
+			}
+			continue
+		}
+		if rerr != nil {
+			t.Errorf("%s %s: expected %s, got status %d", test.method, test.path, test.pattern, rerr.status)
+			continue
+		}
+		if route.pattern != test.pattern {
+			t.Errorf("%s %s: expected %s, matched %s", test.method, test.path, test.pattern, route.pattern)
+		}
+		if !reflect.DeepEqual(params, test.params) {
+			t.Errorf("%s %s: expected params %v, got %v", test.method, test.path, test.params, params)
+		}
+	}
+}
+
+func TestRouterUnknownParamType(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Error("Added a route with an unknown parameter type")
+		}
+	}()
+	new(router).handle("GET", "/ob/thing/{id:uuid}", ScopeRead, ok)
+}
+
+func TestHasScope(t *testing.T) {
+	tests := []struct {
+		granted  []string
+		required string
+		want     bool
+	}{
+		{[]string{ScopeRead}, ScopeRead, true},
+		{[]string{ScopeRead}, ScopeListings, false},
+		{[]string{ScopeRead, ScopeOrders}, ScopeOrders, true},
+		{[]string{ScopeWallet}, ScopeAdmin, false},
+		{[]string{ScopeAdmin}, ScopeWallet, true},
+		{[]string{ScopeAdmin}, ScopeAdmin, true},
+		{nil, ScopeRead, false},
+	}
+	for _, test := range tests {
+		if got := hasScope(test.granted, test.required); got != test.want {
+			t.Errorf("hasScope(%v, %s): expected %t, got %t", test.granted, test.required, test.want, got)
+		}
+	}
+}
+
+func TestRouteScopes(t *testing.T) {
+	rt := newRouter(new(jsonAPIHandler))
+	tests := []struct {
+		method string
+		path   string
+		scope  string
+	}{
+		{"GET", "/ob/listings", ScopeRead},
+		{"POST", "/ob/listing", ScopeListings},
+		{"POST", "/ob/orderfulfillment", ScopeOrders},
+		{"POST", "/v1/ob/refund", ScopeWallet},
+		{"POST", "/wallet/spend", ScopeWallet},
+		{"GET", "/wallet/mnemonic", ScopeAdmin},
+		{"POST", "/ob/apitokens", ScopeAdmin},
+		{"POST", "/ob/shutdown", ScopeAdmin},
+	}
+	for _, test := range tests {
+		route, _, rerr := rt.lookup(test.method, test.path)
+		if rerr != nil {
+			t.Errorf("%s %s: got status %d", test.method, test.path, rerr.status)
+			continue
+		}
+		if route.scope != test.scope {
+			t.Errorf("%s %s: expected scope %s, got %s", test.method, test.path, test.scope, route.scope)
+		}
+	}
+}
+
+type memoryTokens map[string]repo.APIToken
+
+func (m memoryTokens) Put(name string, hash []byte, scopes []string) error {
+	m[string(hash)] = repo.APIToken{Name: name, Scopes: scopes}
+	return nil
+}
+
+func (m memoryTokens) GetByHash(hash []byte) (repo.APIToken, error) {
+	t, ok := m[string(hash)]
+	if !ok {
+		return repo.APIToken{}, errors.New("Not found")
+	}
+	return t, nil
+}
+
+func (m memoryTokens) GetAll() ([]repo.APIToken, error) {
+	return nil, nil
+}
+
+func (m memoryTokens) Delete(name string) error {
+	return nil
+}
+
+// Only the API tokens are used by the authenticator
+type tokenDatastore struct {
+	repo.Datastore
+	tokens memoryTokens
+}
+
+func (d tokenDatastore) APITokens() repo.APITokens {
+	return d.tokens
+}
+
+func TestRouteScopeEnforcement(t *testing.T) {
+	tokens := make(memoryTokens)
+	tokens.Put("reader", hashToken("read-token"), []string{ScopeRead})
+	tokens.Put("seller", hashToken("listings-token"), []string{ScopeRead, ScopeListings})
+	tokens.Put("admin", hashToken("admin-token"), []string{ScopeAdmin})
+	auth := &authenticator{
+		authenticated: true,
+		username:      "user",
+		password:      "pass",
+		datastore:     tokenDatastore{tokens: tokens},
+	}
+	i := &jsonAPIHandler{config: JsonAPIConfig{Enabled: true}, auth: auth, router: newTestRouter()}
+
+	tests := []struct {
+		method string
+		path   string
+		token  string
+		status int
+	}{
+		{"GET", "/ob/listing/index", "read-token", http.StatusOK},
+		{"POST", "/ob/listing", "read-token", http.StatusForbidden},
+		{"POST", "/v1/ob/listing", "listings-token", http.StatusOK},
+		{"GET", "/ob/exchangerate", "listings-token", http.StatusOK},
+		{"PUT", "/ob/listing", "admin-token", http.StatusOK},
+		{"GET", "/ob/listing/index", "unknown-token", http.StatusForbidden},
+		{"GET", "/ob/listing/index", "", http.StatusForbidden},
+
+		// Routing errors are only reported to authorized callers
+		{"DELETE", "/ob/listing", "read-token", http.StatusMethodNotAllowed},
+		{"DELETE", "/ob/listing", "unknown-token", http.StatusForbidden},
+	}
+	for _, test := range tests {
+		r := httptest.NewRequest(test.method, test.path, nil)
+		if test.token != "" {
+			r.Header.Set("Authorization", "Bearer "+test.token)
+		}
+		w := httptest.NewRecorder()
+		i.ServeHTTP(w, r)
+		if w.Code != test.status {
+			t.Errorf("%s %s with %q: expected status %d, got %d", test.method, test.path, test.token, test.status, w.Code)
+		}
+	}
+
+	// Basic auth gets every scope
+	r := httptest.NewRequest("POST", "/ob/listing", nil)
+	r.SetBasicAuth("user", "pass")
+	w := httptest.NewRecorder()
+	i.ServeHTTP(w, r)
+	if w.Code != http.StatusOK {
+		t.Errorf("Basic auth: expected status 200, got %d", w.Code)
+	}
+}
+
+func TestRedactCredentials(t *testing.T) {
+	r, _ := http.NewRequest("GET", "/ob/profile", nil)
+	r.Header.Set("Authorization", "Bearer secret")
+	r.Header.Set("Cookie", "OpenBazaar_Auth_Cookie=secret")
+	r.Header.Set("Accept", "application/json")
+	dump, err := httputil.DumpRequest(redactCredentials(r), false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(dump), "secret") {
+		t.Errorf("Credentials were not redacted:\n%s", dump)
+	}
+	if !strings.Contains(string(dump), "application/json") {
+		t.Error("Other headers were dropped")
+	}
+	if r.Header.Get("Authorization") != "Bearer secret" {
+		t.Error("Redacting modified the original request")
+	}
+}
@@ -0,0 +1,281 @@
+package webhooks
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/OpenBazaar/openbazaar-go/repo"
+	"github.com/op/go-logging"
+)
+
+var log = logging.MustGetLogger("webhooks")
+
+const (
+	// HMAC-SHA256 of the request body keyed with the endpoint's secret, as sha256=<hex>
+	SignatureHeader = "X-OpenBazaar-Signature"
+
+	EventHeader = "X-OpenBazaar-Event"
+
+	// The delivery ID. Retries of a delivery carry the same key.
+	IdempotencyHeader = "Idempotency-Key"
+)
+
+const (
+	maxAttempts    = 10
+	initialBackoff = 30 * time.Second
+	maxBackoff     = 6 * time.Hour
+	pollInterval   = 30 * time.Second
+)
+
+// The body of each webhook POST
+type event struct {
+	Id      string          `json:"id"`
+	Event   string          `json:"event"`
+	Created int64           `json:"created"`
+	Data    json.RawMessage `json:"data"`
+}
+
+// Delivers notifications to the webhook endpoints in the config. Deliveries are saved
+// to the datastore before they are attempted and failed attempts are retried with
+// exponential backoff, so nothing is lost if the endpoint or this node goes down.
+type Dispatcher struct {
+	db     repo.Datastore
+	hooks  []repo.WebhookConfig
+	client *http.Client
+	wake   chan struct{}
+
+	// Endpoints with deliveries in progress. Each endpoint is delivered to in its own
+	// goroutine so a slow or unreachable one doesn't hold up the others.
+	lock     sync.Mutex
+	busy     map[string]bool
+	inflight sync.WaitGroup
+
+	stop     chan struct{}
+	stopped  chan struct{}
+	stopOnce sync.Once
+}
+
+func NewDispatcher(db repo.Datastore, hooks []repo.WebhookConfig) *Dispatcher {
+	return &Dispatcher{
+		db:      db,
+		hooks:   hooks,
+		client:  &http.Client{Timeout: 30 * time.Second},
+		wake:    make(chan struct{}, 1),
+		busy:    make(map[string]bool),
+		stop:    make(chan struct{}),
+		stopped: make(chan struct{}),
+	}
+}
+
+func (d *Dispatcher) Run() {
+	defer close(d.stopped)
+	defer d.inflight.Wait()
+	t := time.NewTicker(pollInterval)
+	defer t.Stop()
+	for {
+		d.deliverDue()
+		select {
+		case <-t.C:
+		case <-d.wake:
+		case <-d.stop:
+			return
+		}
+	}
+}
+
+// Stop delivering and wait for the attempts in progress to finish. Anything not yet
+// delivered is sent after the next start.
+func (d *Dispatcher) Stop() {
+	d.stopOnce.Do(func() { close(d.stop) })
+	<-d.stopped
+}
+
+// Queue a message sent to the websocket. Notifications are delivered to the endpoints
+// subscribed to their type, other messages such as the publishing status are ignored.
+func (d *Dispatcher) Notify(message []byte) {
+	var n struct {
+		Notification map[string]json.RawMessage `json:"notification"`
+	}
+	if err := json.Unmarshal(message, &n); err != nil {
+		return
+	}
+	for eventType, data := range n.Notification {
+		d.enqueue(eventType, data)
+	}
+}
+
+func (d *Dispatcher) enqueue(eventType string, data []byte) {
+	queued := false
+	for _, hook := range d.hooks {
+		if !subscribed(hook, eventType) {
+			continue
+		}
+		id := make([]byte, 16)
+		rand.Read(id)
+		now := time.Now()
+		e := event{
+			Id:      hex.EncodeToString(id),
+			Event:   eventType,
+			Created: now.Unix(),
+			Data:    data,
+		}
+		body, err := json.Marshal(e)
+		if err != nil {
+			log.Error(err)
+			continue
+		}
+		delivery := repo.WebhookDelivery{
+			Id:          e.Id,
+			URL:         hook.URL,
+			Event:       eventType,
+			Payload:     body,
+			Status:      repo.DeliveryPending,
+			Created:     now,
+			NextAttempt: now,
+		}
+		if err := d.db.WebhookDeliveries().Put(delivery); err != nil {
+			log.Errorf("Failed to save webhook delivery: %s", err)
+			continue
+		}
+		queued = true
+	}
+	if queued {
+		select {
+		case d.wake <- struct{}{}:
+		default:
+		}
+	}
+}
+
+func subscribed(hook repo.WebhookConfig, eventType string) bool {
+	if len(hook.Events) == 0 {
+		return true
+	}
+	for _, e := range hook.Events {
+		if e == eventType {
+			return true
+		}
+	}
+	return false
+}
+
+// Start delivering what is due to each endpoint which isn't already busy. Deliveries to
+// an endpoint are attempted in order, one at a time.
+func (d *Dispatcher) deliverDue() {
+	deliveries, err := d.db.WebhookDeliveries().GetDue(time.Now())
+	if err != nil {
+		log.Error(err)
+		return
+	}
+	var urls []string
+	byURL := make(map[string][]repo.WebhookDelivery)
+	for _, delivery := range deliveries {
+		if _, ok := byURL[delivery.URL]; !ok {
+			urls = append(urls, delivery.URL)
+		}
+		byURL[delivery.URL] = append(byURL[delivery.URL], delivery)
+	}
+	d.lock.Lock()
+	defer d.lock.Unlock()
+	for _, url := range urls {
+		if d.busy[url] {
+			continue
+		}
+		d.busy[url] = true
+		d.inflight.Add(1)
+		go d.deliverTo(url, byURL[url])
+	}
+}
+
+func (d *Dispatcher) deliverTo(url string, deliveries []repo.WebhookDelivery) {
+	defer d.inflight.Done()
+	defer func() {
+		d.lock.Lock()
+		delete(d.busy, url)
+		d.lock.Unlock()
+	}()
+	for _, delivery := range deliveries {
+		select {
+		case <-d.stop:
+			return
+		default:
+		}
+		d.attempt(delivery)
+	}
+}
+
+func (d *Dispatcher) attempt(delivery repo.WebhookDelivery) {
+	var hook *repo.WebhookConfig
+	for i := range d.hooks {
+		if d.hooks[i].URL == delivery.URL {
+			hook = &d.hooks[i]
+		}
+	}
+	delivery.Attempts++
+	if hook == nil {
+		delivery.Status = repo.DeliveryFailed
+		delivery.LastError = "endpoint is no longer in the config"
+	} else if err := d.post(hook, delivery); err != nil {
+		log.Warningf("Webhook delivery %s to %s failed: %s", delivery.Id, delivery.URL, err)
+		delivery.LastError = err.Error()
+		if delivery.Attempts >= maxAttempts {
+			delivery.Status = repo.DeliveryFailed
+		} else {
+			delivery.NextAttempt = time.Now().Add(backoff(delivery.Attempts))
+		}
+	} else {
+		delivery.Status = repo.DeliveryDelivered
+		delivery.LastError = ""
+	}
+	if err := d.db.WebhookDeliveries().Put(delivery); err != nil {
+		log.Error(err)
+	}
+}
+
+func (d *Dispatcher) post(hook *repo.WebhookConfig, delivery repo.WebhookDelivery) error {
+	req, err := http.NewRequest("POST", hook.URL, bytes.NewReader(delivery.Payload))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(EventHeader, delivery.Event)
+	req.Header.Set(IdempotencyHeader, delivery.Id)
+	req.Header.Set(SignatureHeader, "sha256="+Sign(hook.Secret, delivery.Payload))
+	resp, err := d.client.Do(req)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return errors.New(resp.Status)
+	}
+	return nil
+}
+
+// The delay before the next attempt after the given number of failed attempts
+func backoff(attempts int) time.Duration {
+	delay := initialBackoff
+	for i := 1; i < attempts && delay < maxBackoff; i++ {
+		delay *= 2
+	}
+	if delay > maxBackoff {
+		delay = maxBackoff
+	}
+	return delay
+}
+
+// Return the hex encoded HMAC-SHA256 of a body keyed with a secret. Endpoints should
+// compute the same over the raw request body and compare it with the signature header.
+func Sign(secret string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
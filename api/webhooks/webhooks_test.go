@@ -0,0 +1,268 @@
+package webhooks
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/OpenBazaar/openbazaar-go/repo"
+)
+
+// Keeps deliveries in memory in the order they were first saved
+type memDeliveries struct {
+	lock       sync.Mutex
+	deliveries []repo.WebhookDelivery
+}
+
+func (m *memDeliveries) Put(delivery repo.WebhookDelivery) error {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	for i, d := range m.deliveries {
+		if d.Id == delivery.Id {
+			m.deliveries[i] = delivery
+			return nil
+		}
+	}
+	m.deliveries = append(m.deliveries, delivery)
+	return nil
+}
+
+func (m *memDeliveries) GetDue(now time.Time) ([]repo.WebhookDelivery, error) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	var due []repo.WebhookDelivery
+	for _, d := range m.deliveries {
+		if d.Status == repo.DeliveryPending && !d.NextAttempt.After(now) {
+			due = append(due, d)
+		}
+	}
+	return due, nil
+}
+
+func (m *memDeliveries) GetAll(status string, limit int) ([]repo.WebhookDelivery, error) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	var all []repo.WebhookDelivery
+	for _, d := range m.deliveries {
+		if status == "" || d.Status == status {
+			all = append(all, d)
+		}
+	}
+	return all, nil
+}
+
+func (m *memDeliveries) get(t *testing.T, url string) repo.WebhookDelivery {
+	all, _ := m.GetAll("", -1)
+	for _, d := range all {
+		if d.URL == url {
+			return d
+		}
+	}
+	t.Fatalf("No delivery to %s", url)
+	return repo.WebhookDelivery{}
+}
+
+type testDatastore struct {
+	repo.Datastore
+	deliveries *memDeliveries
+}
+
+func (d *testDatastore) WebhookDeliveries() repo.WebhookDeliveries {
+	return d.deliveries
+}
+
+func newTestDispatcher(hooks ...repo.WebhookConfig) (*Dispatcher, *memDeliveries) {
+	deliveries := new(memDeliveries)
+	return NewDispatcher(&testDatastore{deliveries: deliveries}, hooks), deliveries
+}
+
+// Attempt everything which is due and wait for the attempts to finish
+func deliver(d *Dispatcher) {
+	d.deliverDue()
+	d.inflight.Wait()
+}
+
+func TestSign(t *testing.T) {
+	// RFC 4231 test case 2
+	expected := "5bdcc146bf60754e6a042426089575c75a003f089d2739839dec58b964ec3843"
+	if sig := Sign("Jefe", []byte("what do ya want for nothing?")); sig != expected {
+		t.Errorf("Expected signature %s, got %s", expected, sig)
+	}
+}
+
+func TestBackoff(t *testing.T) {
+	tests := []struct {
+		attempts int
+		delay    time.Duration
+	}{
+		{1, 30 * time.Second},
+		{2, time.Minute},
+		{3, 2 * time.Minute},
+		{9, 128 * time.Minute},
+		{10, 256 * time.Minute},
+		{11, maxBackoff},
+		{100, maxBackoff},
+	}
+	for _, test := range tests {
+		if delay := backoff(test.attempts); delay != test.delay {
+			t.Errorf("Backoff after %d attempts: expected %s, got %s", test.attempts, test.delay, delay)
+		}
+	}
+}
+
+func TestNotifyFiltering(t *testing.T) {
+	d, deliveries := newTestDispatcher(
+		repo.WebhookConfig{URL: "http://orders", Events: []string{"order"}},
+		repo.WebhookConfig{URL: "http://all"},
+	)
+	d.Notify([]byte(`{"notification": {"order": {"orderId": "1"}}}`))
+	d.Notify([]byte(`{"notification": {"follow": "QmPeer"}}`))
+	d.Notify([]byte(`{"status": "publishing"}`))
+	d.Notify([]byte(`not json`))
+
+	all, _ := deliveries.GetAll("", -1)
+	got := make(map[string][]string)
+	for _, delivery := range all {
+		got[delivery.URL] = append(got[delivery.URL], delivery.Event)
+		if delivery.Status != repo.DeliveryPending {
+			t.Errorf("New delivery is %s", delivery.Status)
+		}
+	}
+	if len(got["http://orders"]) != 1 || got["http://orders"][0] != "order" {
+		t.Errorf("Expected the order endpoint to get only the order, got %v", got["http://orders"])
+	}
+	if len(got["http://all"]) != 2 {
+		t.Errorf("Expected the catch all endpoint to get both notifications, got %v", got["http://all"])
+	}
+}
+
+func TestDeliverSignsRequest(t *testing.T) {
+	var req *http.Request
+	var body []byte
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		req = r
+		body, _ = ioutil.ReadAll(r.Body)
+	}))
+	defer server.Close()
+	d, deliveries := newTestDispatcher(repo.WebhookConfig{URL: server.URL, Secret: "secret"})
+	d.Notify([]byte(`{"notification": {"order": {"orderId": "1"}}}`))
+	deliver(d)
+
+	if req == nil {
+		t.Fatal("Endpoint was not called")
+	}
+	if sig := req.Header.Get(SignatureHeader); sig != "sha256="+Sign("secret", body) {
+		t.Errorf("Incorrect signature %s", sig)
+	}
+	var e event
+	if err := json.Unmarshal(body, &e); err != nil {
+		t.Fatal(err)
+	}
+	if e.Event != "order" || string(e.Data) != `{"orderId":"1"}` {
+		t.Errorf("Incorrect event %s", body)
+	}
+	if key := req.Header.Get(IdempotencyHeader); key == "" || key != e.Id {
+		t.Errorf("Idempotency key %s does not match the event ID %s", key, e.Id)
+	}
+	if req.Header.Get(EventHeader) != "order" {
+		t.Errorf("Incorrect event header %s", req.Header.Get(EventHeader))
+	}
+	delivery := deliveries.get(t, server.URL)
+	if delivery.Status != repo.DeliveryDelivered || delivery.Attempts != 1 {
+		t.Errorf("Expected one delivered attempt, got %s after %d", delivery.Status, delivery.Attempts)
+	}
+}
+
+func TestDeliverRetries(t *testing.T) {
+	var keys []string
+	status := http.StatusInternalServerError
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		keys = append(keys, r.Header.Get(IdempotencyHeader))
+		w.WriteHeader(status)
+	}))
+	defer server.Close()
+	d, deliveries := newTestDispatcher(repo.WebhookConfig{URL: server.URL})
+	d.Notify([]byte(`{"notification": {"order": {"orderId": "1"}}}`))
+	deliver(d)
+
+	delivery := deliveries.get(t, server.URL)
+	if delivery.Status != repo.DeliveryPending || delivery.Attempts != 1 {
+		t.Fatalf("Expected a pending delivery after one attempt, got %s after %d", delivery.Status, delivery.Attempts)
+	}
+	if delivery.LastError != "500 Internal Server Error" {
+		t.Errorf("Incorrect last error %s", delivery.LastError)
+	}
+	if wait := delivery.NextAttempt.Sub(time.Now()); wait < initialBackoff-time.Minute/2 || wait > initialBackoff {
+		t.Errorf("Expected a retry in %s, got %s", initialBackoff, wait)
+	}
+
+	// Not retried before it is due
+	deliver(d)
+	if len(keys) != 1 {
+		t.Fatalf("Retried before the backoff, %d attempts", len(keys))
+	}
+
+	delivery.NextAttempt = time.Now()
+	deliveries.Put(delivery)
+	status = http.StatusOK
+	deliver(d)
+	if len(keys) != 2 || keys[0] != keys[1] {
+		t.Errorf("Expected the retry to carry the same idempotency key, got %v", keys)
+	}
+	if delivery := deliveries.get(t, server.URL); delivery.Status != repo.DeliveryDelivered || delivery.Attempts != 2 {
+		t.Errorf("Expected delivery on the second attempt, got %s after %d", delivery.Status, delivery.Attempts)
+	}
+}
+
+func TestDeliverGivesUp(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+	d, deliveries := newTestDispatcher(repo.WebhookConfig{URL: server.URL})
+	d.Notify([]byte(`{"notification": {"order": {"orderId": "1"}}}`))
+	for i := 0; i < maxAttempts; i++ {
+		deliver(d)
+		delivery := deliveries.get(t, server.URL)
+		if i < maxAttempts-1 && delivery.Status != repo.DeliveryPending {
+			t.Fatalf("Gave up after %d attempts", delivery.Attempts)
+		}
+		delivery.NextAttempt = time.Now()
+		deliveries.Put(delivery)
+	}
+	delivery := deliveries.get(t, server.URL)
+	if delivery.Status != repo.DeliveryFailed || delivery.Attempts != maxAttempts {
+		t.Errorf("Expected to give up after %d attempts, got %s after %d", maxAttempts, delivery.Status, delivery.Attempts)
+	}
+	deliver(d)
+	if delivery := deliveries.get(t, server.URL); delivery.Attempts != maxAttempts {
+		t.Error("Failed delivery was attempted again")
+	}
+}
+
+func TestDeliverEndpointsConcurrently(t *testing.T) {
+	release := make(chan struct{})
+	slow := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-release
+	}))
+	defer slow.Close()
+	delivered := make(chan struct{}, 1)
+	fast := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		delivered <- struct{}{}
+	}))
+	defer fast.Close()
+	d, _ := newTestDispatcher(repo.WebhookConfig{URL: slow.URL}, repo.WebhookConfig{URL: fast.URL})
+	d.Notify([]byte(`{"notification": {"order": {"orderId": "1"}}}`))
+	d.deliverDue()
+	select {
+	case <-delivered:
+	case <-time.After(5 * time.Second):
+		t.Error("A slow endpoint held up delivery to another")
+	}
+	close(release)
+	d.inflight.Wait()
+}
@@ -1,11 +1,15 @@
 package api
 
 import (
+	"encoding/json"
 	"fmt"
 	"github.com/OpenBazaar/openbazaar-go/core"
 	"github.com/gorilla/websocket"
 	"github.com/ipfs/go-ipfs/commands"
 	"net/http"
+	"strconv"
+	"strings"
+	"sync"
 )
 
 type connection struct {
@@ -17,6 +21,57 @@ type connection struct {
 
 	// The hub
 	h *hub
+
+	// Topics the client is subscribed to. Nil means every topic.
+	topics map[string]bool
+	lock   sync.Mutex
+}
+
+// Messages clients send to manage their subscriptions. Replay asks for every
+// message after the given sequence number on the subscribed topics.
+type wsCommand struct {
+	Subscribe   []string `json:"subscribe"`
+	Unsubscribe []string `json:"unsubscribe"`
+	Replay      *uint64  `json:"replay"`
+}
+
+func (c *connection) subscribed(topic string) bool {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	if c.topics == nil {
+		return true
+	}
+	if strings.HasPrefix(topic, TopicSearch+":") && c.topics[TopicSearch] {
+		return true
+	}
+	return c.topics[topic]
+}
+
+// Update the subscriptions. The first subscribe replaces the default of every topic.
+func (c *connection) subscribe(add, remove []string) error {
+	for _, t := range append(add, remove...) {
+		if !validTopic(t) {
+			return fmt.Errorf("unknown topic %s", t)
+		}
+	}
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	if c.topics == nil && len(add) > 0 {
+		c.topics = make(map[string]bool)
+	}
+	for _, t := range add {
+		c.topics[t] = true
+	}
+	if c.topics == nil && len(remove) > 0 {
+		c.topics = make(map[string]bool)
+		for _, t := range Topics {
+			c.topics[t] = true
+		}
+	}
+	for _, t := range remove {
+		delete(c.topics, t)
+	}
+	return nil
 }
 
 func (c *connection) reader() {
@@ -27,12 +82,29 @@ func (c *connection) reader() {
 		}
 		log.Debugf("Incoming websocket message: %s", string(message))
 
-		// Just echo for now until we set up the API
-		c.h.Broadcast <- message
+		var cmd wsCommand
+		if err := json.Unmarshal(message, &cmd); err != nil {
+			c.reply("error", err.Error())
+			continue
+		}
+		if err := c.subscribe(cmd.Subscribe, cmd.Unsubscribe); err != nil {
+			c.reply("error", err.Error())
+			continue
+		}
+		if cmd.Replay != nil {
+			c.h.replay <- replayRequest{c, *cmd.Replay}
+		}
 	}
 	c.ws.Close()
 }
 
+// Send a system message to this connection only
+func (c *connection) reply(msgType, reason string) {
+	c.h.direct <- directMessage{c, systemMessage(msgType, struct {
+		Reason string `json:"reason"`
+	}{reason})}
+}
+
 func (c *connection) writer() {
 	for message := range c.send {
 		err := c.ws.WriteMessage(websocket.TextMessage, message)
@@ -52,60 +124,54 @@ var upgrader = &websocket.Upgrader{
 var handler wsHandler
 
 type wsHandler struct {
-	h             *hub
-	path          string
-	context       commands.Context
-	authenticated bool
-	cookie        http.Cookie
-	username      string
-	password      string
+	h       *hub
+	path    string
+	context commands.Context
+	auth    *authenticator
 }
 
-func newWSAPIHandler(node *core.OpenBazaarNode, ctx commands.Context, authenticated bool, authCookie http.Cookie, username, password string) (*wsHandler, error) {
-	hub := newHub()
+func newWSAPIHandler(node *core.OpenBazaarNode, ctx commands.Context, auth *authenticator) (*wsHandler, error) {
+	hub := newHub(node.Datastore.WebsocketEvents())
 	go hub.run()
 	handler = wsHandler{
-		h:             hub,
-		path:          ctx.ConfigRoot,
-		context:       ctx,
-		authenticated: authenticated,
-		cookie:        authCookie,
-		username:      username,
-		password:      password,
+		h:       hub,
+		path:    ctx.ConfigRoot,
+		context: ctx,
+		auth:    auth,
 	}
 	return &handler, nil
 }
 
 func (wsh wsHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	// Browsers can't set headers on websocket requests so tokens may also be passed in the query
+	granted, ok := wsh.auth.scopes(r, r.URL.Query().Get("token"))
+	if !ok || !hasScope(granted, ScopeRead) {
+		w.WriteHeader(http.StatusForbidden)
+		fmt.Fprint(w, "403 - Forbidden")
+		return
+	}
 	ws, err := upgrader.Upgrade(w, r, nil)
 	if err != nil {
 		log.Error("Error upgrading to websockets:", err)
 		return
 	}
-	if wsh.authenticated {
-		if wsh.username == "" || wsh.password == "" {
-			cookie, err := r.Cookie("OpenBazaar_Auth_Cookie")
-			if err != nil {
-				w.WriteHeader(http.StatusForbidden)
-				fmt.Fprint(w, "403 - Forbidden")
-				return
-			}
-			if wsh.cookie.Value != cookie.Value {
-				w.WriteHeader(http.StatusForbidden)
-				fmt.Fprint(w, "403 - Forbidden")
-				return
-			}
-		} else {
-			username, password, ok := r.BasicAuth()
-			if !ok || username != wsh.username || password != wsh.password {
-				w.WriteHeader(http.StatusForbidden)
-				fmt.Fprint(w, "403 - Forbidden")
-				return
-			}
+	c := &connection{send: make(chan []byte, 256), ws: ws, h: wsh.h}
+	if topics := r.URL.Query().Get("topics"); topics != "" {
+		if err := c.subscribe(strings.Split(topics, ","), nil); err != nil {
+			ws.WriteMessage(websocket.TextMessage, systemMessage("error", struct {
+				Reason string `json:"reason"`
+			}{err.Error()}))
+			ws.Close()
+			return
+		}
+	}
+	reg := registration{c: c}
+	if since := r.URL.Query().Get("since"); since != "" {
+		if seq, err := strconv.ParseUint(since, 10, 64); err == nil {
+			reg.since = &seq
 		}
 	}
-	c := &connection{send: make(chan []byte, 256), ws: ws, h: wsh.h}
-	c.h.register <- c
+	c.h.register <- reg
 	defer func() { c.h.unregister <- c }()
 	go c.writer()
 	c.reader()
@@ -153,6 +153,20 @@ func (w *BitcoindWallet) ChainTip() uint32 {
 	return uint32(info.Blocks)
 }
 
+func (w *BitcoindWallet) BestKnownHeight() uint32 {
+	peers, err := w.rpcClient.GetPeerInfo()
+	if err != nil {
+		return uint32(0)
+	}
+	var best int32
+	for _, p := range peers {
+		if p.StartingHeight > best {
+			best = p.StartingHeight
+		}
+	}
+	return uint32(best)
+}
+
 func (w *BitcoindWallet) Spend(amount int64, addr btc.Address, feeLevel spvwallet.FeeLevel) error {
 	amt, err := btc.NewAmount(float64(amount))
 	if err != nil {
@@ -21,8 +21,9 @@ type ExchangeRateProvider interface {
 
 type BitcoinPriceFetcher struct {
 	sync.Mutex
-	cache     map[string]float64
-	providers []ExchangeRateProvider
+	cache       map[string]float64
+	providers   []ExchangeRateProvider
+	lastUpdated time.Time
 }
 
 func NewBitcoinPriceFetcher() *BitcoinPriceFetcher {
@@ -63,6 +64,12 @@ func (b *BitcoinPriceFetcher) GetAllRates() (map[string]float64, error) {
 	return b.cache, nil
 }
 
+func (b *BitcoinPriceFetcher) LastUpdated() time.Time {
+	b.Lock()
+	defer b.Unlock()
+	return b.lastUpdated
+}
+
 func (b *BitcoinPriceFetcher) UnitsPerCoin() int {
 	return SatoshiPerBTC
 }
@@ -81,6 +88,7 @@ func (b *BitcoinPriceFetcher) fetchCurrentRates() error {
 	for _, provider := range b.providers {
 		err := provider.fetch()
 		if err == nil {
+			b.lastUpdated = time.Now()
 			return nil
 		}
 	}
@@ -24,11 +24,18 @@ func TestFetchCurrentRates(t *testing.T) {
 	if err != nil {
 		t.Error("Failed to fetch bitcoin exchange rates")
 	}
+	updated := b.LastUpdated()
+	if updated.IsZero() {
+		t.Error("Failed to record the time the rates were fetched")
+	}
 	b.providers = []ExchangeRateProvider{&testExchangeRateProvider{errors.New("Query fail")}, &testExchangeRateProvider{errors.New("Query fail")}, &testExchangeRateProvider{errors.New("Query fail")}}
 	err = b.fetchCurrentRates()
 	if err == nil {
 		t.Error("Failed to handle error when fetching exchange rates")
 	}
+	if !b.LastUpdated().Equal(updated) {
+		t.Error("Failed fetch changed the time the rates were last updated")
+	}
 }
 
 func TestGetLastRate(t *testing.T) {
@@ -1,5 +1,7 @@
 package bitcoin
 
+import "time"
+
 type ExchangeRates interface {
 
 	/* Fetch the exchange rate for the given currency
@@ -13,6 +15,9 @@ type ExchangeRates interface {
 	   It is OK if this returns from cach. */
 	GetAllRates() (map[string]float64, error)
 
+	// The time the rates were last fetched successfully. Zero if they never have been.
+	LastUpdated() time.Time
+
 	/* Return the number of currency units per coin. For example, in bitcoin
 	   this is 100m satoshi per BTC. This is used when converting from fiat
 	   to the smaller currency unit. */
@@ -20,14 +20,17 @@ import (
 var log = logging.MustGetLogger("transaction-listener")
 
 type TransactionListener struct {
-	db        repo.Datastore
-	broadcast chan []byte
-	params    *chaincfg.Params
+	db           repo.Datastore
+	broadcast    chan []byte
+	params       *chaincfg.Params
+	onSaleFunded func(orderId string)
 	*sync.Mutex
 }
 
-func NewTransactionListener(db repo.Datastore, broadcast chan []byte, params *chaincfg.Params) *TransactionListener {
-	l := &TransactionListener{db, broadcast, params, new(sync.Mutex)}
+// onSaleFunded is called with the order ID once the payments into a sale cover the order total.
+// It is called while the listener is processing the transaction so it must not block.
+func NewTransactionListener(db repo.Datastore, broadcast chan []byte, params *chaincfg.Params, onSaleFunded func(orderId string)) *TransactionListener {
+	l := &TransactionListener{db, broadcast, params, onSaleFunded, new(sync.Mutex)}
 	return l
 }
 
@@ -101,6 +104,30 @@ func (l *TransactionListener) OnTransactionReceived(cb spvwallet.TransactionCall
 			}
 		}
 	}
+	l.broadcastTransaction(cb)
+}
+
+// Tell the UI about every transaction the wallet sees, not just those paying for orders
+func (l *TransactionListener) broadcastTransaction(cb spvwallet.TransactionCallback) {
+	chainHash, err := chainhash.NewHash(cb.Txid)
+	if err != nil {
+		return
+	}
+	address := func(script []byte) string {
+		_, addrs, _, err := txscript.ExtractPkScriptAddrs(script, l.params)
+		if err != nil || len(addrs) == 0 {
+			return ""
+		}
+		return addrs[0].EncodeAddress()
+	}
+	n := notifications.WalletTransactionNotification{Txid: chainHash.String()}
+	for _, input := range cb.Inputs {
+		n.Inputs = append(n.Inputs, notifications.TransactionIO{Address: address(input.LinkedScriptPubKey), Value: input.Value})
+	}
+	for _, output := range cb.Outputs {
+		n.Outputs = append(n.Outputs, notifications.TransactionIO{Address: address(output.ScriptPubKey), Value: output.Value})
+	}
+	l.broadcast <- notifications.Serialize(n)
 }
 
 func (l *TransactionListener) processSalePayment(txid []byte, output spvwallet.TransactionOutput, contract *pb.RicardianContract, state pb.OrderState, funded bool, records []*spvwallet.TransactionRecord) {
@@ -151,6 +178,9 @@ func (l *TransactionListener) processSalePayment(txid []byte, output spvwallet.T
 	}
 	records = append(records, record)
 	l.db.Sales().UpdateFunding(orderId, funded, records)
+	if funded && l.onSaleFunded != nil {
+		l.onSaleFunded(orderId)
+	}
 }
 
 func (l *TransactionListener) processPurchasePayment(txid []byte, output spvwallet.TransactionOutput, contract *pb.RicardianContract, state pb.OrderState, funded bool, records []*spvwallet.TransactionRecord) {
@@ -0,0 +1,132 @@
+package spv
+
+import (
+	"math/rand"
+	"net"
+	"sync"
+	"time"
+
+	"github.com/OpenBazaar/spvwallet"
+	"github.com/btcsuite/btcd/chaincfg"
+	"github.com/btcsuite/btcd/wire"
+	"github.com/op/go-logging"
+)
+
+var log = logging.MustGetLogger("spv")
+
+const (
+	// How long the best known height is kept before the peers are asked again
+	bestHeightInterval = 5 * time.Minute
+
+	// How many peers are asked for their height
+	bestHeightPeers = 3
+
+	peerTimeout = 10 * time.Second
+)
+
+// Wraps an SPV wallet to report the best block height known to the network, which the
+// wallet keeps to itself. The height is read from the version message of a few peers
+// and refreshed in the background once it is older than bestHeightInterval.
+type Wallet struct {
+	*spvwallet.SPVWallet
+	params      *chaincfg.Params
+	trustedPeer string
+
+	lock       sync.Mutex
+	best       uint32
+	checked    time.Time
+	refreshing bool
+}
+
+func NewWallet(wallet *spvwallet.SPVWallet, params *chaincfg.Params, trustedPeer string) *Wallet {
+	return &Wallet{SPVWallet: wallet, params: params, trustedPeer: trustedPeer}
+}
+
+// Return the highest block height reported by the peers we last asked, or zero if none
+// have answered yet
+func (w *Wallet) BestKnownHeight() uint32 {
+	w.lock.Lock()
+	defer w.lock.Unlock()
+	if !w.refreshing && time.Since(w.checked) > bestHeightInterval {
+		w.refreshing = true
+		go w.refresh()
+	}
+	return w.best
+}
+
+func (w *Wallet) refresh() {
+	var best int32
+	for _, addr := range w.peerAddrs() {
+		height, err := peerHeight(addr, w.params)
+		if err != nil {
+			log.Debugf("Could not get the block height from %s: %s", addr, err)
+			continue
+		}
+		if height > best {
+			best = height
+		}
+	}
+	w.lock.Lock()
+	defer w.lock.Unlock()
+	if best > 0 {
+		w.best = uint32(best)
+	}
+	w.checked = time.Now()
+	w.refreshing = false
+}
+
+// The trusted peer if there is one, like the wallet itself, otherwise a few peers from
+// the DNS seeds
+func (w *Wallet) peerAddrs() []string {
+	if w.trustedPeer != "" {
+		return []string{w.trustedPeer}
+	}
+	var addrs []string
+	for _, seed := range w.params.DNSSeeds {
+		hosts, err := net.LookupHost(seed)
+		if err != nil {
+			continue
+		}
+		for _, host := range hosts {
+			addrs = append(addrs, net.JoinHostPort(host, w.params.DefaultPort))
+		}
+	}
+	for i := range addrs {
+		j := rand.Intn(i + 1)
+		addrs[i], addrs[j] = addrs[j], addrs[i]
+	}
+	if len(addrs) > bestHeightPeers {
+		addrs = addrs[:bestHeightPeers]
+	}
+	return addrs
+}
+
+// Exchange version messages with a peer and return the height it says it is at
+func peerHeight(addr string, params *chaincfg.Params) (int32, error) {
+	conn, err := net.DialTimeout("tcp", addr, peerTimeout)
+	if err != nil {
+		return 0, err
+	}
+	defer conn.Close()
+	conn.SetDeadline(time.Now().Add(peerTimeout))
+	nonce, err := wire.RandomUint64()
+	if err != nil {
+		return 0, err
+	}
+	version, err := wire.NewMsgVersionFromConn(conn, nonce, 0)
+	if err != nil {
+		return 0, err
+	}
+	if err := wire.WriteMessage(conn, version, wire.ProtocolVersion, params.Net); err != nil {
+		return 0, err
+	}
+	for {
+		msg, _, err := wire.ReadMessage(conn, wire.ProtocolVersion, params.Net)
+		if err != nil {
+			return 0, err
+		}
+		if v, ok := msg.(*wire.MsgVersion); ok {
+			return v.LastBlock, nil
+		}
+	}
+}
@@ -33,6 +33,9 @@ type BitcoinWallet interface {
 	// Get the height of the blockchain
 	ChainTip() uint32
 
+	// Get the highest block height reported by the wallet's peers, or zero if it isn't known
+	BestKnownHeight() uint32
+
 	// Get the current fee per byte
 	GetFeePerByte(feeLevel spvwallet.FeeLevel) uint64
 
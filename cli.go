@@ -0,0 +1,393 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"strconv"
+	"text/tabwriter"
+	"time"
+
+	"github.com/btcsuite/btcutil"
+	"github.com/jessevdk/go-flags"
+)
+
+// Options shared by the commands which talk to a running daemon
+type ClientOptions struct {
+	DataDir string `short:"d" long:"datadir" description:"specify the data directory to be used"`
+	Testnet bool   `short:"t" long:"testnet" description:"use the test network"`
+	JSON    bool   `long:"json" description:"print the raw JSON response"`
+	Token   string `long:"token" env:"OB_API_TOKEN" description:"authenticate with an API token instead of the configured credentials"`
+}
+
+// Make a request to the daemon and either print the raw response or pass it to the printer
+func (o *ClientOptions) call(method, endpoint string, body interface{}, printer func(resp []byte) error) error {
+	repoPath, err := getRepoPath(o.Testnet)
+	if err != nil {
+		return err
+	}
+	if o.DataDir != "" {
+		repoPath = o.DataDir
+	}
+	client, err := newAPIClient(repoPath)
+	if err != nil {
+		return err
+	}
+	client.token = o.Token
+	resp, err := client.request(method, endpoint, body)
+	if err != nil {
+		return err
+	}
+	if o.JSON {
+		fmt.Println(string(resp))
+		return nil
+	}
+	return printer(resp)
+}
+
+// Print a fixed message once a request succeeds
+func printMessage(format string, a ...interface{}) func([]byte) error {
+	return func([]byte) error {
+		fmt.Printf(format+"\n", a...)
+		return nil
+	}
+}
+
+func formatSatoshi(satoshi int64) string {
+	return btcutil.Amount(satoshi).String()
+}
+
+func requireArgs(args []string, names ...string) error {
+	if len(args) != len(names) {
+		return fmt.Errorf("expected %d argument(s): %v", len(names), names)
+	}
+	return nil
+}
+
+type Listings struct {
+	ClientOptions
+}
+
+func (x *Listings) Execute(args []string) error {
+	return x.call("GET", "/ob/listings", nil, func(resp []byte) error {
+		var listings []struct {
+			Slug         string `json:"slug"`
+			Title        string `json:"title"`
+			ContractType string `json:"contractType"`
+			Price        struct {
+				CurrencyCode string `json:"currencyCode"`
+				Amount       uint64 `json:"amount"`
+			} `json:"price"`
+		}
+		if err := json.Unmarshal(resp, &listings); err != nil {
+			return err
+		}
+		tw := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+		fmt.Fprintln(tw, "SLUG\tTITLE\tTYPE\tPRICE")
+		for _, l := range listings {
+			fmt.Fprintf(tw, "%s\t%s\t%s\t%d %s\n", l.Slug, l.Title, l.ContractType, l.Price.Amount, l.Price.CurrencyCode)
+		}
+		return tw.Flush()
+	})
+}
+
+type ListingAdd struct {
+	ClientOptions
+}
+
+func (x *ListingAdd) Execute(args []string) error {
+	if err := requireArgs(args, "file"); err != nil {
+		return err
+	}
+	listing, err := ioutil.ReadFile(args[0])
+	if err != nil {
+		return err
+	}
+	var v interface{}
+	if err := json.Unmarshal(listing, &v); err != nil {
+		return errors.New("the listing file is not valid JSON")
+	}
+	return x.call("POST", "/ob/listing", json.RawMessage(listing), func(resp []byte) error {
+		var added struct {
+			Slug string `json:"slug"`
+		}
+		if err := json.Unmarshal(resp, &added); err != nil {
+			return err
+		}
+		fmt.Printf("Added listing %s\n", added.Slug)
+		return nil
+	})
+}
+
+type ListingRemove struct {
+	ClientOptions
+}
+
+func (x *ListingRemove) Execute(args []string) error {
+	if err := requireArgs(args, "slug"); err != nil {
+		return err
+	}
+	req := struct {
+		Slug string `json:"slug"`
+	}{args[0]}
+	return x.call("DELETE", "/ob/listing", req, printMessage("Removed listing %s", args[0]))
+}
+
+type Orders struct {
+	ClientOptions
+	Purchases bool `long:"purchases" description:"only show purchases"`
+	Sales     bool `long:"sales" description:"only show sales"`
+}
+
+func (x *Orders) Execute(args []string) error {
+	return x.call("GET", "/ob/orders", nil, func(resp []byte) error {
+		var orders []struct {
+			OrderId   string    `json:"orderId"`
+			Type      string    `json:"type"`
+			Title     string    `json:"title"`
+			Total     int64     `json:"total"`
+			State     string    `json:"state"`
+			Funded    bool      `json:"funded"`
+			Timestamp time.Time `json:"timestamp"`
+		}
+		if err := json.Unmarshal(resp, &orders); err != nil {
+			return err
+		}
+		tw := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+		fmt.Fprintln(tw, "ORDER ID\tTYPE\tDATE\tSTATE\tFUNDED\tTOTAL\tTITLE")
+		for _, o := range orders {
+			if (x.Purchases && o.Type != "purchase") || (x.Sales && o.Type != "sale") {
+				continue
+			}
+			fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%t\t%s\t%s\n", o.OrderId, o.Type, o.Timestamp.Format("2006-01-02 15:04"), o.State, o.Funded, formatSatoshi(o.Total), o.Title)
+		}
+		return tw.Flush()
+	})
+}
+
+type OrderConfirm struct {
+	ClientOptions
+	Reject bool `long:"reject" description:"reject the order instead of confirming it"`
+}
+
+func (x *OrderConfirm) Execute(args []string) error {
+	if err := requireArgs(args, "orderId"); err != nil {
+		return err
+	}
+	req := struct {
+		OrderId string `json:"orderId"`
+		Reject  bool   `json:"reject"`
+	}{args[0], x.Reject}
+	message := "Confirmed order %s"
+	if x.Reject {
+		message = "Rejected order %s"
+	}
+	return x.call("POST", "/ob/orderconfirmation", req, printMessage(message, args[0]))
+}
+
+type OrderFulfill struct {
+	ClientOptions
+	Slug           string `long:"slug" description:"the listing in the order being fulfilled" required:"true"`
+	Shipper        string `long:"shipper" description:"the shipping company for physical goods"`
+	TrackingNumber string `long:"tracking" description:"the tracking number for physical goods"`
+	URL            string `long:"url" description:"the download URL for digital goods"`
+	Password       string `long:"password" description:"the download password for digital goods"`
+}
+
+func (x *OrderFulfill) Execute(args []string) error {
+	if err := requireArgs(args, "orderId"); err != nil {
+		return err
+	}
+	type physicalDelivery struct {
+		Shipper        string `json:"shipper"`
+		TrackingNumber string `json:"trackingNumber"`
+	}
+	type digitalDelivery struct {
+		Url      string `json:"url"`
+		Password string `json:"password"`
+	}
+	req := struct {
+		OrderId          string             `json:"orderId"`
+		Slug             string             `json:"slug"`
+		PhysicalDelivery []physicalDelivery `json:"physicalDelivery,omitempty"`
+		DigitalDelivery  []digitalDelivery  `json:"digitalDelivery,omitempty"`
+	}{OrderId: args[0], Slug: x.Slug}
+	if x.Shipper != "" || x.TrackingNumber != "" {
+		req.PhysicalDelivery = []physicalDelivery{{x.Shipper, x.TrackingNumber}}
+	}
+	if x.URL != "" {
+		req.DigitalDelivery = []digitalDelivery{{x.URL, x.Password}}
+	}
+	return x.call("POST", "/ob/orderfulfillment", req, printMessage("Fulfilled %s in order %s", x.Slug, args[0]))
+}
+
+type OrderRefund struct {
+	ClientOptions
+	Amount uint64 `long:"amount" description:"refund this many satoshi instead of the full amount"`
+	Memo   string `long:"memo" description:"a note to the buyer"`
+}
+
+func (x *OrderRefund) Execute(args []string) error {
+	if err := requireArgs(args, "orderId"); err != nil {
+		return err
+	}
+	req := struct {
+		OrderId string `json:"orderId"`
+		Amount  uint64 `json:"amount"`
+		Memo    string `json:"memo"`
+	}{args[0], x.Amount, x.Memo}
+	return x.call("POST", "/ob/refund", req, printMessage("Refunded order %s", args[0]))
+}
+
+type WalletBalance struct {
+	ClientOptions
+}
+
+func (x *WalletBalance) Execute(args []string) error {
+	return x.call("GET", "/wallet/balance", nil, func(resp []byte) error {
+		var balance struct {
+			Confirmed   string `json:"confirmed"`
+			Unconfirmed string `json:"unconfirmed"`
+		}
+		if err := json.Unmarshal(resp, &balance); err != nil {
+			return err
+		}
+		confirmed, _ := strconv.ParseInt(balance.Confirmed, 10, 64)
+		unconfirmed, _ := strconv.ParseInt(balance.Unconfirmed, 10, 64)
+		fmt.Printf("Confirmed:   %s\n", formatSatoshi(confirmed))
+		fmt.Printf("Unconfirmed: %s\n", formatSatoshi(unconfirmed))
+		return nil
+	})
+}
+
+type WalletSend struct {
+	ClientOptions
+	FeeLevel string `long:"feelevel" description:"the fee level [priority, normal, economic]" default:"normal"`
+}
+
+func (x *WalletSend) Execute(args []string) error {
+	if err := requireArgs(args, "address", "satoshi"); err != nil {
+		return err
+	}
+	amount, err := strconv.ParseInt(args[1], 10, 64)
+	if err != nil || amount <= 0 {
+		return errors.New("the amount must be a positive number of satoshi")
+	}
+	req := struct {
+		Address  string `json:"address"`
+		Amount   int64  `json:"amount"`
+		FeeLevel string `json:"feeLevel"`
+	}{args[0], amount, x.FeeLevel}
+	return x.call("POST", "/wallet/spend", req, printMessage("Sent %s to %s", formatSatoshi(amount), args[0]))
+}
+
+type WalletAddress struct {
+	ClientOptions
+}
+
+func (x *WalletAddress) Execute(args []string) error {
+	return x.call("GET", "/wallet/address", nil, func(resp []byte) error {
+		var addr struct {
+			Address string `json:"address"`
+		}
+		if err := json.Unmarshal(resp, &addr); err != nil {
+			return err
+		}
+		fmt.Println(addr.Address)
+		return nil
+	})
+}
+
+type Follow struct {
+	ClientOptions
+}
+
+func (x *Follow) Execute(args []string) error {
+	if err := requireArgs(args, "peerId"); err != nil {
+		return err
+	}
+	req := struct {
+		ID string `json:"id"`
+	}{args[0]}
+	return x.call("POST", "/ob/follow", req, printMessage("Following %s", args[0]))
+}
+
+type Unfollow struct {
+	ClientOptions
+}
+
+func (x *Unfollow) Execute(args []string) error {
+	if err := requireArgs(args, "peerId"); err != nil {
+		return err
+	}
+	req := struct {
+		ID string `json:"id"`
+	}{args[0]}
+	return x.call("POST", "/ob/unfollow", req, printMessage("Unfollowed %s", args[0]))
+}
+
+// Register the client commands with the parser
+func addClientCommands(parser *flags.Parser) {
+	parser.AddCommand("listings",
+		"list our listings",
+		"The listings command prints the listings in our store",
+		&Listings{})
+	listing, _ := parser.AddCommand("listing",
+		"add or remove a listing",
+		"The listing command adds a listing from a JSON file or removes one by its slug",
+		&struct{}{})
+	listing.AddCommand("add",
+		"add a listing from a JSON file",
+		"Creates a listing from a JSON file in the format accepted by POST /ob/listing",
+		&ListingAdd{})
+	listing.AddCommand("rm",
+		"remove a listing",
+		"Removes the listing with the given slug",
+		&ListingRemove{})
+	parser.AddCommand("orders",
+		"list purchases and sales",
+		"The orders command prints a summary of our purchases and sales",
+		&Orders{})
+	order, _ := parser.AddCommand("order",
+		"confirm, fulfill or refund an order",
+		"The order command manages a sale",
+		&struct{}{})
+	order.AddCommand("confirm",
+		"confirm or reject an order",
+		"Confirms a funded order or rejects it with --reject",
+		&OrderConfirm{})
+	order.AddCommand("fulfill",
+		"fulfill an order",
+		"Fulfills a listing in an order with shipping or download details",
+		&OrderFulfill{})
+	order.AddCommand("refund",
+		"refund an order",
+		"Refunds an order in full or, with --amount, in part",
+		&OrderRefund{})
+	wallet, _ := parser.AddCommand("wallet",
+		"check the balance, send coins or get an address",
+		"The wallet command works with the node's bitcoin wallet",
+		&struct{}{})
+	wallet.AddCommand("balance",
+		"print the wallet balance",
+		"Prints the confirmed and unconfirmed wallet balance",
+		&WalletBalance{})
+	wallet.AddCommand("send",
+		"send coins to an address",
+		"Sends the given number of satoshi to an address",
+		&WalletSend{})
+	wallet.AddCommand("address",
+		"print a receiving address",
+		"Prints the current receiving address of the wallet",
+		&WalletAddress{})
+	parser.AddCommand("follow",
+		"follow a peer",
+		"The follow command follows the peer with the given ID",
+		&Follow{})
+	parser.AddCommand("unfollow",
+		"unfollow a peer",
+		"The unfollow command unfollows the peer with the given ID",
+		&Unfollow{})
+}
@@ -0,0 +1,155 @@
+package main
+
+import (
+	"bytes"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"errors"
+	"fmt"
+	manet "gx/ipfs/QmPpRcbNUXauP3zWZ1NJMLWpe4QnmEHrd2ba2D3yqWznw7/go-multiaddr-net"
+	ma "gx/ipfs/QmYzDkkgAEmrcNzFCiYo6L1dTX4EAG1gZkbtdbd9trL4vd/go-multiaddr"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/OpenBazaar/openbazaar-go/repo"
+)
+
+var daemonNotRunningError = errors.New("the daemon is not running")
+
+// A client for the JSON API of a daemon running on this machine
+type apiClient struct {
+	baseUrl  string
+	address  string
+	username string
+	password string
+	cookie   *http.Cookie
+	token    string
+	client   *http.Client
+}
+
+// Build a client from the API address and authentication settings in the repo config
+func newAPIClient(repoPath string) (*apiClient, error) {
+	cfgPath := path.Join(repoPath, "config")
+	apiConfig, err := repo.GetAPIConfig(cfgPath)
+	if err != nil {
+		return nil, err
+	}
+	if !apiConfig.Enabled {
+		return nil, errors.New("the JSON API is disabled in the config")
+	}
+	gateway, err := repo.GetGatewayAddress(cfgPath)
+	if err != nil {
+		return nil, err
+	}
+	maddr, err := ma.NewMultiaddr(gateway)
+	if err != nil {
+		return nil, err
+	}
+	_, address, err := manet.DialArgs(maddr)
+	if err != nil {
+		return nil, err
+	}
+	// A daemon listening on all interfaces is reached over loopback
+	host, port, err := net.SplitHostPort(address)
+	if err != nil {
+		return nil, err
+	}
+	if ip := net.ParseIP(host); ip != nil && ip.IsUnspecified() {
+		if ip.To4() != nil {
+			host = "127.0.0.1"
+		} else {
+			host = "::1"
+		}
+	}
+	address = net.JoinHostPort(host, port)
+
+	c := &apiClient{
+		address:  address,
+		username: apiConfig.Username,
+		password: apiConfig.Password,
+		client:   &http.Client{Timeout: time.Minute},
+	}
+	scheme := "http"
+	if apiConfig.SSL {
+		scheme = "https"
+		cert, err := ioutil.ReadFile(apiConfig.SSLCert)
+		if err != nil {
+			return nil, err
+		}
+		pool := x509.NewCertPool()
+		pool.AppendCertsFromPEM(cert)
+		c.client.Transport = &http.Transport{TLSClientConfig: &tls.Config{RootCAs: pool}}
+	}
+	c.baseUrl = scheme + "://" + address + "/v1"
+
+	// The daemon uses the cookie when no username and password are configured
+	cookie, err := ioutil.ReadFile(path.Join(repoPath, ".cookie"))
+	if err == nil {
+		split := strings.SplitN(string(cookie), "=", 2)
+		if len(split) == 2 {
+			c.cookie = &http.Cookie{Name: split[0], Value: split[1]}
+		}
+	}
+	return c, nil
+}
+
+// Make a request to the API and return the body of a successful response.
+// The reason in an error response is returned as the error.
+func (c *apiClient) request(method, endpoint string, body interface{}) ([]byte, error) {
+	var reqBody []byte
+	if body != nil {
+		var err error
+		reqBody, err = json.Marshal(body)
+		if err != nil {
+			return nil, err
+		}
+	}
+	req, err := http.NewRequest(method, c.baseUrl+endpoint, bytes.NewReader(reqBody))
+	if err != nil {
+		return nil, err
+	}
+	if c.token != "" {
+		req.Header.Set("Authorization", "Bearer "+c.token)
+	} else if c.username != "" && c.password != "" {
+		req.SetBasicAuth(c.username, c.password)
+	} else if c.cookie != nil {
+		req.AddCookie(c.cookie)
+	}
+	resp, err := c.client.Do(req)
+	if err != nil {
+		if !c.listening() {
+			return nil, daemonNotRunningError
+		}
+		return nil, err
+	}
+	defer resp.Body.Close()
+	respBody, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		var apiErr struct {
+			Reason string `json:"reason"`
+		}
+		if json.Unmarshal(respBody, &apiErr) == nil && apiErr.Reason != "" {
+			return nil, errors.New(apiErr.Reason)
+		}
+		return nil, fmt.Errorf("%s %s returned %s", method, endpoint, resp.Status)
+	}
+	return respBody, nil
+}
+
+// Is anything accepting connections at the API address?
+func (c *apiClient) listening() bool {
+	conn, err := net.DialTimeout("tcp", c.address, time.Second)
+	if err != nil {
+		return false
+	}
+	conn.Close()
+	return true
+}
@@ -0,0 +1,24 @@
+package core
+
+import (
+	"io"
+	"io/ioutil"
+	"os"
+
+	"github.com/OpenBazaar/openbazaar-go/repo"
+)
+
+// Write an encrypted backup of the node to w. The datastore is snapshotted first so
+// the backup is consistent while the node keeps running.
+func (n *OpenBazaarNode) WriteBackup(w io.Writer, password string) error {
+	dir, err := ioutil.TempDir(n.RepoPath, ".backup")
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(dir)
+	dbPath, err := n.Datastore.Backup(dir)
+	if err != nil {
+		return err
+	}
+	return repo.WriteBackup(w, n.RepoPath, dbPath, password)
+}
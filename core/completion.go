@@ -17,8 +17,6 @@ import (
 	"github.com/OpenBazaar/openbazaar-go/pb"
 	"github.com/OpenBazaar/spvwallet"
 	"github.com/btcsuite/btcd/btcec"
-	"github.com/btcsuite/btcd/txscript"
-	"github.com/btcsuite/btcutil"
 	hd "github.com/btcsuite/btcutil/hdkeychain"
 	"github.com/golang/protobuf/ptypes/timestamp"
 )
@@ -112,8 +110,12 @@ func (n *OpenBazaarNode) CompleteOrder(orderRatings *OrderRatings, contract *pb.
 		oc.Ratings = append(oc.Ratings, rating)
 	}
 
-	// Payout order if moderated
-	if contract.BuyerOrder.Payment.Method == pb.Order_Payment_MODERATED {
+	// Payout order if moderated, unless a partial refund already paid out the escrow
+	if contract.BuyerOrder.Payment.Method == pb.Order_Payment_MODERATED && !n.PayoutSettledByRefund(contract) {
+		payout := n.GetFulfillmentPayout(contract)
+		if payout == nil {
+			return errors.New("Vendor has not sent a payout for the order")
+		}
 		var ins []spvwallet.TransactionInput
 		var outValue int64
 		for _, r := range records {
@@ -128,17 +130,10 @@ func (n *OpenBazaarNode) CompleteOrder(orderRatings *OrderRatings, contract *pb.
 			}
 		}
 
-		payoutAddress, err := btcutil.DecodeAddress(contract.VendorOrderFulfillment[0].Payout.PayoutAddress, n.Wallet.Params())
-		if err != nil {
-			return err
-		}
-		var output spvwallet.TransactionOutput
-		outputScript, err := txscript.PayToAddrScript(payoutAddress)
+		outputs, err := n.EscrowOutputs(contract, payout.PayoutAddress, outValue, len(ins), payout.PayoutFeePerByte)
 		if err != nil {
 			return err
 		}
-		output.ScriptPubKey = outputScript
-		output.Value = outValue
 
 		chaincode, err := hex.DecodeString(contract.BuyerOrder.Payment.Chaincode)
 		if err != nil {
@@ -171,7 +166,7 @@ func (n *OpenBazaarNode) CompleteOrder(orderRatings *OrderRatings, contract *pb.
 			return err
 		}
 
-		buyerSignatures, err := n.Wallet.CreateMultisigSignature(ins, []spvwallet.TransactionOutput{output}, buyerKey, redeemScript, contract.VendorOrderFulfillment[0].Payout.PayoutFeePerByte)
+		buyerSignatures, err := n.Wallet.CreateMultisigSignature(ins, outputs, buyerKey, redeemScript, payout.PayoutFeePerByte)
 		if err != nil {
 			return err
 		}
@@ -184,11 +179,11 @@ func (n *OpenBazaarNode) CompleteOrder(orderRatings *OrderRatings, contract *pb.
 		}
 		oc.PayoutSigs = pbSigs
 		var vendorSignatures []spvwallet.Signature
-		for _, s := range contract.VendorOrderFulfillment[0].Payout.Sigs {
+		for _, s := range payout.Sigs {
 			sig := spvwallet.Signature{InputIndex: s.InputIndex, Signature: s.Signature}
 			vendorSignatures = append(vendorSignatures, sig)
 		}
-		err = n.Wallet.Multisign(ins, []spvwallet.TransactionOutput{output}, buyerSignatures, vendorSignatures, redeemScript, contract.VendorOrderFulfillment[0].Payout.PayoutFeePerByte)
+		err = n.Wallet.Multisign(ins, outputs, buyerSignatures, vendorSignatures, redeemScript, payout.PayoutFeePerByte)
 		if err != nil {
 			return err
 		}
@@ -240,13 +235,37 @@ func (n *OpenBazaarNode) SignOrderCompletion(contract *pb.RicardianContract) (*p
 	return contract, nil
 }
 
-func (n *OpenBazaarNode) ValidateOrderCompletion(contract *pb.RicardianContract) error {
+func (n *OpenBazaarNode) ValidateOrderCompletion(contract *pb.RicardianContract, records []*spvwallet.TransactionRecord) error {
 	if err := verifySignaturesOnOrderCompletion(contract); err != nil {
 		return err
 	}
 
-	// TODO: validate bitcoin signatures of moderated
-
+	if contract.BuyerOrder.Payment.Method == pb.Order_Payment_MODERATED && !n.PayoutSettledByRefund(contract) {
+		payout := n.GetFulfillmentPayout(contract)
+		if payout == nil {
+			return errors.New("Contract does not contain a payout for the order completion")
+		}
+		ins, outValue, err := escrowInputs(records)
+		if err != nil {
+			return err
+		}
+		outputs, err := n.EscrowOutputs(contract, payout.PayoutAddress, outValue, len(ins), payout.PayoutFeePerByte)
+		if err != nil {
+			return err
+		}
+		buyerKey, err := n.escrowPublicKey(contract.BuyerOrder.BuyerID.Pubkeys.Bitcoin, contract.BuyerOrder.Payment.Chaincode)
+		if err != nil {
+			return err
+		}
+		redeemScript, err := hex.DecodeString(contract.BuyerOrder.Payment.RedeemScript)
+		if err != nil {
+			return err
+		}
+		err = verifyEscrowSignatures(ins, outputs, contract.BuyerOrderCompletion.PayoutSigs, buyerKey, redeemScript, payout.PayoutFeePerByte)
+		if err != nil {
+			return err
+		}
+	}
 	return nil
 }
 
@@ -2,6 +2,7 @@ package core
 
 import (
 	bstk "github.com/OpenBazaar/go-blockstackclient"
+	"github.com/OpenBazaar/openbazaar-go/api/webhooks"
 	"github.com/OpenBazaar/openbazaar-go/bitcoin"
 	"github.com/OpenBazaar/openbazaar-go/ipfs"
 	"github.com/OpenBazaar/openbazaar-go/net"
@@ -11,20 +12,21 @@ import (
 	sto "github.com/OpenBazaar/openbazaar-go/storage"
 	"github.com/ipfs/go-ipfs/commands"
 	"github.com/ipfs/go-ipfs/core"
+	lockfile "github.com/ipfs/go-ipfs/repo/fsrepo/lock"
 	"github.com/ipfs/go-ipfs/routing/dht"
 	"github.com/op/go-logging"
 	"golang.org/x/net/context"
 	"gx/ipfs/QmRBqJF7hb8ZSpRcMwUt8hNhydWcxGEhtk81HKq6oUwKvs/go-libp2p-peer"
 	"net/url"
-	"path"
+	"os"
+	"path/filepath"
+	"sync"
 )
 
 var log = logging.MustGetLogger("core")
 
 var Node *OpenBazaarNode
 
-var inflightPublishRequests int
-
 type OpenBazaarNode struct {
 	// Context for issuing IPFS commands
 	Context commands.Context
@@ -55,12 +57,24 @@ type OpenBazaarNode struct {
 	// Storage for our outgoing messages
 	MessageStorage sto.OfflineMessagingStorage
 
+	// Forward secret sessions used to encrypt offline messages
+	Sessions *net.SessionCipher
+
 	// A service that periodically checks the dht for outstanding messages
 	MessageRetriever *ret.MessageRetriever
 
 	// A service that periodically republishes active pointers
 	PointerRepublisher *rep.PointerRepublisher
 
+	// The node directory as a DAG which is patched as files change
+	RootDirectory *ipfs.DirectoryTree
+
+	// Publishes the node directory to IPNS as it changes
+	PublishManager *PublishManager
+
+	// A service that delivers notifications to the webhook endpoints in the config
+	Webhooks *webhooks.Dispatcher
+
 	// Used to resolve blockchainIDs to OpenBazaar IDs
 	Resolver *bstk.BlockstackClient
 
@@ -69,42 +83,62 @@ type OpenBazaarNode struct {
 
 	// An optional gateway URL where we can crosspost data to ensure persistence
 	CrosspostGateways []*url.URL
+
+	// Pushes the node directory to the crosspost gateways after each publish
+	Crossposter *Crossposter
+
+	// Moderators we have recently resolved
+	moderatorCache moderatorCache
+
+	// Per order locks held while a sale is fulfilled or refunded
+	orderLocksMtx sync.Mutex
+	orderLocks    map[string]*orderLock
+
+	// Funded sales which may have digital goods to deliver once the payment confirms
+	digitalDeliveryLock sync.Mutex
+	awaitingDelivery    map[string]bool
+	deliveryQueued      chan struct{}
 }
 
-// Unpin the current node repo, re-add it, then publish to IPNS
+// Add the changes made to the node repo and queue it to be published to IPNS
 func (n *OpenBazaarNode) SeedNode() error {
-	hash, aerr := ipfs.AddDirectory(n.Context, path.Join(n.RepoPath, "root"))
+	hash, aerr := n.RootDirectory.Update(n.Context)
 	if aerr != nil {
 		return aerr
 	}
-	go n.publish(hash)
+	n.PublishManager.Queue(hash)
 	return nil
 }
 
-func (n *OpenBazaarNode) publish(hash string) {
-	if inflightPublishRequests == 0 {
-		n.Broadcast <- []byte(`{"status": "publishing"}`)
+// Stop the background services, then close the wallet, datastore and IPFS node and
+// release the repo lock. The services and wallet write to the datastore so they are
+// stopped before it is closed. The lock is removed last so anything waiting on it knows
+// the node is fully down.
+func (n *OpenBazaarNode) Shutdown() {
+	if n.MessageRetriever != nil {
+		n.MessageRetriever.Stop()
 	}
-	var err, perr error
-	inflightPublishRequests++
-	_, err = ipfs.Publish(n.Context, hash)
-	if hash != n.RootHash {
-		perr = ipfs.UnPinDir(n.Context, n.RootHash)
-		n.RootHash = hash
+	if n.Crossposter != nil {
+		n.Crossposter.Stop()
 	}
-	inflightPublishRequests--
-	if inflightPublishRequests == 0 {
-		if err != nil || perr != nil {
-			log.Error(err, perr)
-			n.Broadcast <- []byte(`{"status": "error publishing"}`)
-		} else {
-			n.Broadcast <- []byte(`{"status": "publish complete"}`)
-		}
+	if n.PublishManager != nil {
+		n.PublishManager.Stop()
+	}
+	if n.Webhooks != nil {
+		n.Webhooks.Stop()
 	}
+	if n.Wallet != nil {
+		n.Wallet.Close()
+	}
+	n.Datastore.Close()
+	n.IpfsNode.Close()
+	os.Remove(filepath.Join(n.RepoPath, lockfile.LockFile))
 }
 
-/* This is a placeholder until the libsignal is operational.
-   For now we will just encrypt outgoing offline messages with the long lived identity key. */
+/* Encrypt an offline message with our session with the peer, starting one from their
+   prekey bundle if need be. Peers running older versions don't publish a bundle, so if
+   their directory has none the message is encrypted with their long lived identity key
+   instead. Any other failure to get the bundle is returned rather than falling back. */
 func (n *OpenBazaarNode) EncryptMessage(peerId peer.ID, message []byte) (ct []byte, rerr error) {
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
@@ -113,6 +147,18 @@ func (n *OpenBazaarNode) EncryptMessage(peerId peer.ID, message []byte) (ct []by
 		log.Errorf("Failed to find public key for %s", peerId.Pretty())
 		return nil, err
 	}
+	if n.Sessions != nil {
+		noBundle := false
+		ciphertext, err := n.Sessions.Encrypt(pubKey, message, func() (*net.PrekeyBundle, error) {
+			bundle, err := n.GetPrekeyBundle(peerId)
+			noBundle = err == ErrNoPrekeyBundle
+			return bundle, err
+		})
+		if !noBundle {
+			return ciphertext, err
+		}
+		log.Debugf("No prekey bundle for %s, encrypting with its identity key", peerId.Pretty())
+	}
 	ciphertext, err := net.Encrypt(pubKey, message)
 	if err != nil {
 		return nil, err
@@ -0,0 +1,435 @@
+package core
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/OpenBazaar/openbazaar-go/ipfs"
+)
+
+const (
+	crosspostInterval       = 30 * time.Second
+	crosspostInitialBackoff = 30 * time.Second
+	crosspostMaxBackoff     = time.Hour
+
+	// After this many failures in a row the gateway's copy is rebuilt from scratch in
+	// case it has lost the blocks we were patching
+	maxCrosspostFailures = 3
+
+	// Writable gateways build up our directory starting from an empty one
+	emptyDirectoryHash = "QmUNLLsPACCz1vLxQVkXqqLX5R1X345qqfHbsf67hvA3Nn"
+
+	// The gateway only creates directories on the way to a file, so empty directories
+	// are made by putting this file into them and deleting it again
+	crosspostPlaceholder = ".crosspost"
+)
+
+// Returned when the gateway answers a DELETE with 405 Method Not Allowed or 501 Not
+// Implemented. Such gateways are rebuilt with PUTs only from then on.
+var errDeleteUnsupported = errors.New("gateway does not support deleting paths")
+
+// A request to the gateway which got an unexpected response
+type gatewayStatusError struct {
+	method string
+	path   string
+	status string
+	code   int
+}
+
+func (e *gatewayStatusError) Error() string {
+	return fmt.Sprintf("%s %s: %s", e.method, e.path, e.status)
+}
+
+type CrosspostStatus struct {
+	Gateway string `json:"gateway"`
+
+	// The root hash of the gateway's copy of our directory and the one it should have
+	Root   string `json:"root,omitempty"`
+	Target string `json:"target,omitempty"`
+	Synced bool   `json:"synced"`
+
+	// The gateway can't delete paths so its copy is rebuilt with PUTs whenever anything
+	// is changed or removed. Empty directories can't be made this way and are left out.
+	PutOnly bool `json:"putOnly"`
+
+	// The number of changed paths which have not been pushed yet
+	Pending int `json:"pending"`
+
+	Failures    int        `json:"failures"`
+	LastError   string     `json:"lastError,omitempty"`
+	LastSync    *time.Time `json:"lastSync,omitempty"`
+	NextAttempt *time.Time `json:"nextAttempt,omitempty"`
+}
+
+// Pushes the node directory to the writable gateways in the config so our listings,
+// profile and ratings stay online while the node is not. The gateway's copy is patched
+// with the writable gateway API one path at a time, so only the files which have
+// changed since the gateway last acknowledged them are sent. Gateways which can't delete
+// paths have their copy rebuilt from an empty directory instead. The acknowledged hashes
+// are saved to the datastore and failed gateways are retried with exponential backoff.
+type Crossposter struct {
+	node     *OpenBazaarNode
+	gateways []*url.URL
+	client   *http.Client
+	wake     chan struct{}
+
+	stop     chan struct{}
+	stopped  chan struct{}
+	stopOnce sync.Once
+
+	lock   sync.Mutex
+	status map[string]*CrosspostStatus
+}
+
+func NewCrossposter(node *OpenBazaarNode, gateways []*url.URL) *Crossposter {
+	c := &Crossposter{
+		node:     node,
+		gateways: gateways,
+		client:   &http.Client{Timeout: 5 * time.Minute},
+		wake:     make(chan struct{}, 1),
+		stop:     make(chan struct{}),
+		stopped:  make(chan struct{}),
+		status:   make(map[string]*CrosspostStatus),
+	}
+	for _, g := range gateways {
+		s := &CrosspostStatus{Gateway: g.String()}
+		if acked, err := node.Datastore.Crossposts().Get(g.String()); err == nil {
+			s.Root = acked[""]
+		}
+		if putOnly, err := node.Datastore.Crossposts().PutOnly(g.String()); err == nil {
+			s.PutOnly = putOnly
+		}
+		c.status[g.String()] = s
+	}
+	return c
+}
+
+func (c *Crossposter) Run() {
+	defer close(c.stopped)
+	t := time.NewTicker(crosspostInterval)
+	defer t.Stop()
+	for {
+		select {
+		case <-t.C:
+		case <-c.wake:
+		case <-c.stop:
+			return
+		}
+		c.syncDue()
+	}
+}
+
+// Stop crossposting and wait for a sync in progress to finish
+func (c *Crossposter) Stop() {
+	c.stopOnce.Do(func() { close(c.stop) })
+	<-c.stopped
+}
+
+// Push the latest changes to the gateways which are not waiting to retry
+func (c *Crossposter) Wake() {
+	select {
+	case c.wake <- struct{}{}:
+	default:
+	}
+}
+
+// Return the sync status of each gateway in the order they appear in the config
+func (c *Crossposter) Status() []CrosspostStatus {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	ret := []CrosspostStatus{}
+	for _, g := range c.gateways {
+		s := *c.status[g.String()]
+		if s.LastSync != nil {
+			t := *s.LastSync
+			s.LastSync = &t
+		}
+		if s.NextAttempt != nil {
+			t := *s.NextAttempt
+			s.NextAttempt = &t
+		}
+		ret = append(ret, s)
+	}
+	return ret
+}
+
+func (c *Crossposter) syncDue() {
+	target, paths := c.node.RootDirectory.Snapshot()
+	if target == "" {
+		return
+	}
+	var files map[string]string
+	var putOnlyTarget string
+	for _, g := range c.gateways {
+		c.lock.Lock()
+		s := c.status[g.String()]
+		putOnly := s.PutOnly
+		c.lock.Unlock()
+		gatewayTarget, gatewayPaths := target, paths
+		if putOnly {
+			if files == nil {
+				files = withoutEmptyDirectories(paths)
+				var err error
+				putOnlyTarget, err = ipfs.BuildDirectory(c.node.Context, files)
+				if err != nil {
+					log.Error(err)
+					files = nil
+					continue
+				}
+			}
+			gatewayTarget, gatewayPaths = putOnlyTarget, files
+		}
+
+		c.lock.Lock()
+		s.Target = gatewayTarget
+		s.Synced = s.Root == gatewayTarget
+		due := !s.Synced && (s.NextAttempt == nil || time.Now().After(*s.NextAttempt))
+		c.lock.Unlock()
+		if !due {
+			continue
+		}
+		err := c.sync(g, gatewayTarget, gatewayPaths, putOnly)
+
+		c.lock.Lock()
+		if err == errDeleteUnsupported {
+			log.Warningf("Crossposting to %s with PUTs only: %s", g.String(), err)
+			s.PutOnly = true
+			c.lock.Unlock()
+			if err := c.node.Datastore.Crossposts().SetPutOnly(g.String(), true); err != nil {
+				log.Error(err)
+			}
+			c.Wake()
+			continue
+		}
+		if err != nil {
+			log.Warningf("Crossposting to %s failed: %s", g.String(), err)
+			s.Failures++
+			s.LastError = err.Error()
+			next := time.Now().Add(crosspostBackoff(s.Failures))
+			s.NextAttempt = &next
+			if s.Failures%maxCrosspostFailures == 0 {
+				if err := c.node.Datastore.Crossposts().Clear(g.String()); err != nil {
+					log.Error(err)
+				}
+				s.Root = ""
+			}
+		} else {
+			now := time.Now()
+			s.Synced = true
+			s.Failures = 0
+			s.LastError = ""
+			s.LastSync = &now
+			s.NextAttempt = nil
+		}
+		c.lock.Unlock()
+	}
+}
+
+// Patch the gateway's copy of the directory until it matches the target root. If the
+// gateway can't delete paths any change other than an addition rebuilds its copy from
+// an empty directory.
+func (c *Crossposter) sync(g *url.URL, target string, paths map[string]string, putOnly bool) error {
+	store := c.node.Datastore.Crossposts()
+	gateway := g.String()
+	acked, err := store.Get(gateway)
+	if err != nil {
+		return err
+	}
+	root := acked[""]
+
+	// Anything which has been deleted or changed is removed first. The gateway sets
+	// the data of an existing file rather than replacing it, so changed files can't
+	// simply be put again. Parents sort before their children so anything inside a
+	// removed directory goes with it.
+	var removed, added []string
+	for p, hash := range acked {
+		if p == "" {
+			continue
+		}
+		if current, ok := paths[p]; !ok || current != hash {
+			removed = append(removed, p)
+		}
+	}
+	if putOnly && len(removed) > 0 {
+		if err := store.Clear(gateway); err != nil {
+			return err
+		}
+		acked = map[string]string{}
+		root = ""
+		removed = nil
+	}
+	if root == "" {
+		root = emptyDirectoryHash
+	}
+	sort.Strings(removed)
+	for i := 0; i < len(removed); i++ {
+		if i > 0 && strings.HasPrefix(removed[i], removed[i-1]+"/") {
+			removed = append(removed[:i], removed[i+1:]...)
+			i--
+		}
+	}
+	for p, hash := range paths {
+		if current, ok := acked[p]; !ok || current != hash || underAny(p, removed) {
+			added = append(added, p)
+		}
+	}
+	sort.Strings(added)
+	c.setPending(gateway, len(removed)+len(added))
+
+	// Save the new root after each change so a failure part way through resumes from it
+	setRoot := func(newRoot string) error {
+		root = newRoot
+		c.lock.Lock()
+		s := c.status[gateway]
+		s.Root = root
+		s.Pending--
+		c.lock.Unlock()
+		return store.Put(gateway, "", root)
+	}
+
+	for _, p := range removed {
+		newRoot, err := c.delete(g, root, p)
+		if err != nil {
+			return err
+		}
+		if err := store.Delete(gateway, p); err != nil {
+			return err
+		}
+		if err := setRoot(newRoot); err != nil {
+			return err
+		}
+	}
+	for _, p := range added {
+		hash := paths[p]
+		newRoot := root
+		if hash != "" {
+			f, err := os.Open(path.Join(c.node.RepoPath, "root", p))
+			if err != nil {
+				return err
+			}
+			newRoot, err = c.patch("PUT", g, root, p, f)
+			f.Close()
+			if err != nil {
+				return err
+			}
+		} else if !hasChildren(p, paths) {
+			placeholder := path.Join(p, crosspostPlaceholder)
+			withPlaceholder, err := c.patch("PUT", g, root, placeholder, strings.NewReader(""))
+			if err != nil {
+				return err
+			}
+			newRoot, err = c.delete(g, withPlaceholder, placeholder)
+			if err != nil {
+				return err
+			}
+		}
+		if err := store.Put(gateway, p, hash); err != nil {
+			return err
+		}
+		if err := setRoot(newRoot); err != nil {
+			return err
+		}
+	}
+
+	// The files may have changed on disk since the snapshot was taken, in which case the
+	// copy won't match and is rebuilt on the next attempt
+	if root != target {
+		if err := store.Clear(gateway); err != nil {
+			return err
+		}
+		c.lock.Lock()
+		c.status[gateway].Root = ""
+		c.lock.Unlock()
+		return fmt.Errorf("gateway root %s does not match %s", root, target)
+	}
+	return nil
+}
+
+// Make a change to the gateway's copy of the directory and return its new root hash
+func (c *Crossposter) patch(method string, g *url.URL, root, p string, body io.Reader) (string, error) {
+	u := g.String() + "ipfs/" + root + (&url.URL{Path: p}).EscapedPath()
+	req, err := http.NewRequest(method, u, body)
+	if err != nil {
+		return "", err
+	}
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return "", err
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusCreated {
+		return "", &gatewayStatusError{method, p, resp.Status, resp.StatusCode}
+	}
+	hash := resp.Header.Get("IPFS-Hash")
+	if hash == "" {
+		return "", errors.New("gateway did not return the new root hash")
+	}
+	return hash, nil
+}
+
+// Delete a path from the gateway's copy of the directory. Only a gateway which says it
+// doesn't allow or implement DELETE is switched to PUTs. Any other failure may be
+// transient so it is retried with the usual backoff.
+func (c *Crossposter) delete(g *url.URL, root, p string) (string, error) {
+	newRoot, err := c.patch("DELETE", g, root, p, nil)
+	if e, ok := err.(*gatewayStatusError); ok && (e.code == http.StatusMethodNotAllowed || e.code == http.StatusNotImplemented) {
+		return "", errDeleteUnsupported
+	}
+	return newRoot, err
+}
+
+func (c *Crossposter) setPending(gateway string, pending int) {
+	c.lock.Lock()
+	c.status[gateway].Pending = pending
+	c.lock.Unlock()
+}
+
+// The delay before the next attempt after the given number of failures
+func crosspostBackoff(failures int) time.Duration {
+	delay := crosspostInitialBackoff
+	for i := 1; i < failures && delay < crosspostMaxBackoff; i++ {
+		delay *= 2
+	}
+	if delay > crosspostMaxBackoff {
+		delay = crosspostMaxBackoff
+	}
+	return delay
+}
+
+func underAny(p string, dirs []string) bool {
+	for _, d := range dirs {
+		if strings.HasPrefix(p, d+"/") {
+			return true
+		}
+	}
+	return false
+}
+
+func hasChildren(dir string, paths map[string]string) bool {
+	for p := range paths {
+		if strings.HasPrefix(p, dir+"/") {
+			return true
+		}
+	}
+	return false
+}
+
+// Drop the directories which have nothing beneath them
+func withoutEmptyDirectories(paths map[string]string) map[string]string {
+	ret := make(map[string]string)
+	for p, hash := range paths {
+		if hash != "" || hasChildren(p, paths) {
+			ret[p] = hash
+		}
+	}
+	return ret
+}
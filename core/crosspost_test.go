@@ -0,0 +1,53 @@
+package core
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+)
+
+func TestCrosspostDelete(t *testing.T) {
+	tests := []struct {
+		status int
+		err    error
+	}{
+		{http.StatusCreated, nil},
+		{http.StatusMethodNotAllowed, errDeleteUnsupported},
+		{http.StatusNotImplemented, errDeleteUnsupported},
+	}
+	for _, test := range tests {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("IPFS-Hash", "QmNewRoot")
+			w.WriteHeader(test.status)
+		}))
+		g, _ := url.Parse(server.URL + "/")
+		c := &Crossposter{client: http.DefaultClient}
+		_, err := c.delete(g, emptyDirectoryHash, "/listings/slug.json")
+		if err != test.err {
+			t.Errorf("Status %d: got error %v, want %v", test.status, err, test.err)
+		}
+		server.Close()
+	}
+
+	// Other failures may be transient so the gateway isn't switched to PUTs
+	for _, status := range []int{http.StatusInternalServerError, http.StatusBadGateway, http.StatusForbidden} {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(status)
+		}))
+		g, _ := url.Parse(server.URL + "/")
+		c := &Crossposter{client: http.DefaultClient}
+		_, err := c.delete(g, emptyDirectoryHash, "/listings/slug.json")
+		if err == nil || err == errDeleteUnsupported {
+			t.Errorf("Status %d: got error %v, want a retryable error", status, err)
+		}
+		server.Close()
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	g, _ := url.Parse(server.URL + "/")
+	server.Close()
+	c := &Crossposter{client: http.DefaultClient}
+	if _, err := c.delete(g, emptyDirectoryHash, "/listings/slug.json"); err == nil || err == errDeleteUnsupported {
+		t.Errorf("Unreachable gateway: got error %v, want a retryable error", err)
+	}
+}
@@ -0,0 +1,393 @@
+package core
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/json"
+	"errors"
+	"io"
+	"io/ioutil"
+	"os"
+	"path"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/OpenBazaar/openbazaar-go/ipfs"
+	"github.com/OpenBazaar/openbazaar-go/pb"
+	"github.com/OpenBazaar/spvwallet"
+	"github.com/btcsuite/btcutil/base58"
+)
+
+// Number of confirmations the payment for an order needs before digital goods are delivered automatically
+const DigitalDeliveryConfirmations = 1
+
+// How often the chain tip is checked for new blocks which may confirm payments for orders awaiting delivery
+const DigitalDeliveryPollInterval = 30 * time.Second
+
+// A file attached to a digital good listing. It is stored encrypted in the repo outside
+// of the root directory so it is never published.
+type DigitalFile struct {
+	Filename string `json:"filename"`
+	Data     []byte `json:"data"`
+}
+
+// Summary of the digital goods attached to a listing
+type DigitalAsset struct {
+	Slug        string `json:"slug"`
+	Filename    string `json:"filename"`
+	LicenseKeys int    `json:"licenseKeys"`
+}
+
+// The subset of the wallet database used to look up the height at which a payment confirmed
+type utxoStore interface {
+	Utxos() spvwallet.Utxos
+	Stxos() spvwallet.Stxos
+}
+
+func (n *OpenBazaarNode) digitalFilePath(slug string) string {
+	return path.Join(n.RepoPath, "digital", slug)
+}
+
+// Attach a file to a digital good listing. It replaces any file already attached.
+func (n *OpenBazaarNode) SetDigitalFile(slug string, file DigitalFile) error {
+	if slug == "" || path.Base(slug) != slug {
+		return errors.New("Invalid listing slug")
+	}
+	ser, err := json.Marshal(file)
+	if err != nil {
+		return err
+	}
+	key, err := n.digitalStorageKey()
+	if err != nil {
+		return err
+	}
+	ciphertext, err := encryptDigitalPayload(key, ser)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(path.Join(n.RepoPath, "digital"), os.ModePerm); err != nil {
+		return err
+	}
+	return ioutil.WriteFile(n.digitalFilePath(slug), ciphertext, 0600)
+}
+
+// Load and decrypt the file attached to a listing
+func (n *OpenBazaarNode) GetDigitalFile(slug string) (*DigitalFile, error) {
+	ciphertext, err := ioutil.ReadFile(n.digitalFilePath(slug))
+	if err != nil {
+		return nil, err
+	}
+	key, err := n.digitalStorageKey()
+	if err != nil {
+		return nil, err
+	}
+	plaintext, err := decryptDigitalPayload(key, ciphertext)
+	if err != nil {
+		return nil, err
+	}
+	file := new(DigitalFile)
+	if err := json.Unmarshal(plaintext, file); err != nil {
+		return nil, err
+	}
+	return file, nil
+}
+
+// Add license keys to the pool for a listing. Each key is delivered to exactly one order.
+func (n *OpenBazaarNode) AddLicenseKeys(slug string, keys []string) error {
+	if len(keys) == 0 {
+		return errors.New("No license keys given")
+	}
+	for _, k := range keys {
+		if k == "" {
+			return errors.New("License keys must not be empty")
+		}
+	}
+	return n.Datastore.LicenseKeys().Put(slug, keys)
+}
+
+// Return what is attached to the listing for automatic delivery
+func (n *OpenBazaarNode) GetDigitalAsset(slug string) (*DigitalAsset, error) {
+	asset := &DigitalAsset{Slug: slug}
+	file, err := n.GetDigitalFile(slug)
+	if err != nil && !os.IsNotExist(err) {
+		return nil, err
+	}
+	if file != nil {
+		asset.Filename = file.Filename
+	}
+	count, err := n.Datastore.LicenseKeys().Count(slug)
+	if err != nil {
+		return nil, err
+	}
+	asset.LicenseKeys = count
+	return asset, nil
+}
+
+// Remove the file and any unused license keys from the listing. Keys already delivered are kept.
+func (n *OpenBazaarNode) DeleteDigitalAsset(slug string) error {
+	if err := os.Remove(n.digitalFilePath(slug)); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return n.Datastore.LicenseKeys().DeleteUnused(slug)
+}
+
+// Queue a funded sale for automatic delivery. It is called by the transaction listener so it never blocks.
+func (n *OpenBazaarNode) QueueDigitalDelivery(orderId string) {
+	n.digitalDeliveryLock.Lock()
+	n.initDigitalDelivery()
+	n.awaitingDelivery[orderId] = true
+	queued := n.deliveryQueued
+	n.digitalDeliveryLock.Unlock()
+	select {
+	case queued <- struct{}{}:
+	default:
+	}
+}
+
+func (n *OpenBazaarNode) initDigitalDelivery() {
+	if n.awaitingDelivery == nil {
+		n.awaitingDelivery = make(map[string]bool)
+		n.deliveryQueued = make(chan struct{}, 1)
+	}
+}
+
+// Deliver digital goods for queued sales. Sales are tried as soon as they are queued and again each
+// time a new block arrives until their payment has enough confirmations. On start up every sale is
+// queued once so orders funded while the node was offline are picked up.
+func (n *OpenBazaarNode) RunDigitalDelivery() {
+	n.digitalDeliveryLock.Lock()
+	n.initDigitalDelivery()
+	queued := n.deliveryQueued
+	n.digitalDeliveryLock.Unlock()
+
+	orderIds, err := n.Datastore.Sales().GetAll()
+	if err != nil {
+		log.Error(err)
+	}
+	for _, orderId := range orderIds {
+		n.QueueDigitalDelivery(orderId)
+	}
+
+	tick := time.NewTicker(DigitalDeliveryPollInterval)
+	defer tick.Stop()
+	tip := n.Wallet.ChainTip()
+	for {
+		select {
+		case <-queued:
+		case <-tick.C:
+			if height := n.Wallet.ChainTip(); height != tip {
+				tip = height
+			} else {
+				continue
+			}
+		}
+		n.deliverQueuedDigitalGoods()
+	}
+}
+
+func (n *OpenBazaarNode) deliverQueuedDigitalGoods() {
+	n.digitalDeliveryLock.Lock()
+	var orderIds []string
+	for orderId := range n.awaitingDelivery {
+		orderIds = append(orderIds, orderId)
+	}
+	n.digitalDeliveryLock.Unlock()
+	for _, orderId := range orderIds {
+		awaiting, err := n.DeliverDigitalGoods(orderId)
+		if err != nil {
+			log.Errorf("Error delivering digital goods for order %s: %s", orderId, err.Error())
+			continue
+		}
+		if !awaiting {
+			n.digitalDeliveryLock.Lock()
+			delete(n.awaitingDelivery, orderId)
+			n.digitalDeliveryLock.Unlock()
+		}
+	}
+}
+
+// Fulfill each digital good in the order which has a file or license key attached. Nothing is
+// delivered until the payment has DigitalDeliveryConfirmations confirmations and until then
+// true is returned. The order is locked while it is delivered so it can't be fulfilled twice.
+func (n *OpenBazaarNode) DeliverDigitalGoods(orderId string) (bool, error) {
+	unlock := n.LockOrder(orderId)
+	defer unlock()
+	contract, state, funded, records, _, err := n.Datastore.Sales().GetByOrderId(orderId)
+	if err != nil {
+		return false, err
+	}
+	if !funded || (state != pb.OrderState_FUNDED && state != pb.OrderState_PARTIALLY_REFUNDED && state != pb.OrderState_PARTIALLY_FULFILLED) {
+		return false, nil
+	}
+	var pending []*pb.Listing
+	for _, listing := range contract.VendorListings {
+		if listing.Metadata.ContractType != pb.Listing_Metadata_DIGITAL_GOOD || checkFulfillmentSlug(listing.Slug, contract) != nil {
+			continue
+		}
+		asset, err := n.GetDigitalAsset(listing.Slug)
+		if err != nil {
+			return false, err
+		}
+		if asset.Filename == "" && asset.LicenseKeys == 0 {
+			continue
+		}
+		pending = append(pending, listing)
+	}
+	if len(pending) == 0 {
+		return false, nil
+	}
+	confirmations, err := n.fundingConfirmations(records)
+	if err != nil {
+		return false, err
+	}
+	if confirmations < DigitalDeliveryConfirmations {
+		return true, nil
+	}
+	remaining, err := remainingQuantities(contract)
+	if err != nil {
+		return false, err
+	}
+	for _, listing := range pending {
+		delivery, err := n.buildDigitalDelivery(listing.Slug, orderId, remaining[listing.Slug])
+		if err != nil {
+			return false, err
+		}
+		fulfillment := &pb.OrderFulfillment{
+			OrderId:         orderId,
+			Slug:            listing.Slug,
+			DigitalDelivery: []*pb.OrderFulfillment_DigitalDelivery{delivery},
+		}
+		if err := n.FulfillOrder(fulfillment, contract, records); err != nil {
+			return false, err
+		}
+		log.Noticef("Delivered %s for order %s", listing.Slug, orderId)
+	}
+	return false, nil
+}
+
+// Encrypt the listing's payload with a fresh key for this order and add it to IPFS. The file
+// holds a random nonce followed by the AES-256-GCM ciphertext of the payload. The password in the
+// delivery is the base58 encoded key and only ever travels inside the encrypted fulfillment message.
+// Listings sold by license key get a distinct key for each of the quantity ordered, one per line.
+func (n *OpenBazaarNode) buildDigitalDelivery(slug, orderId string, quantity uint32) (*pb.OrderFulfillment_DigitalDelivery, error) {
+	file, err := n.GetDigitalFile(slug)
+	if os.IsNotExist(err) {
+		licenseKeys, err := n.Datastore.LicenseKeys().Assign(slug, orderId, int(quantity))
+		if err != nil {
+			return nil, err
+		}
+		file = &DigitalFile{Filename: "license.txt", Data: []byte(strings.Join(licenseKeys, "\n"))}
+	} else if err != nil {
+		return nil, err
+	}
+	ser, err := json.Marshal(file)
+	if err != nil {
+		return nil, err
+	}
+	key := make([]byte, 32)
+	if _, err := rand.Read(key); err != nil {
+		return nil, err
+	}
+	ciphertext, err := encryptDigitalPayload(key, ser)
+	if err != nil {
+		return nil, err
+	}
+	if err := os.MkdirAll(path.Join(n.RepoPath, "digital"), os.ModePerm); err != nil {
+		return nil, err
+	}
+	tmpPath := path.Join(n.RepoPath, "digital", "."+orderId+"-"+slug)
+	if err := ioutil.WriteFile(tmpPath, ciphertext, 0600); err != nil {
+		return nil, err
+	}
+	defer os.Remove(tmpPath)
+	hash, err := ipfs.AddFile(n.Context, tmpPath)
+	if err != nil {
+		return nil, err
+	}
+	return &pb.OrderFulfillment_DigitalDelivery{Url: "/ipfs/" + hash, Password: base58.Encode(key)}, nil
+}
+
+// The lowest number of confirmations among the payments into the order
+func (n *OpenBazaarNode) fundingConfirmations(records []*spvwallet.TransactionRecord) (uint32, error) {
+	store, ok := n.Datastore.(utxoStore)
+	if !ok {
+		return 0, errors.New("Datastore does not track transaction heights")
+	}
+	heights := make(map[string]int32)
+	utxos, err := store.Utxos().GetAll()
+	if err != nil {
+		return 0, err
+	}
+	for _, u := range utxos {
+		heights[u.Op.String()] = u.AtHeight
+	}
+	stxos, err := store.Stxos().GetAll()
+	if err != nil {
+		return 0, err
+	}
+	for _, s := range stxos {
+		heights[s.Utxo.Op.String()] = s.Utxo.AtHeight
+	}
+	tip := n.Wallet.ChainTip()
+	var confirmations uint32
+	first := true
+	for _, r := range records {
+		if r.Value <= 0 {
+			continue
+		}
+		var c uint32
+		height := heights[r.Txid+":"+strconv.Itoa(int(r.Index))]
+		if height > 0 && uint32(height) <= tip {
+			c = tip - uint32(height) + 1
+		}
+		if first || c < confirmations {
+			confirmations = c
+			first = false
+		}
+	}
+	return confirmations, nil
+}
+
+// Key used to encrypt digital goods at rest. It is derived from the node's identity key.
+func (n *OpenBazaarNode) digitalStorageKey() ([]byte, error) {
+	keyBytes, err := n.IpfsNode.PrivateKey.Bytes()
+	if err != nil {
+		return nil, err
+	}
+	h := sha256.Sum256(append([]byte("OpenBazaar digital goods"), keyBytes...))
+	return h[:], nil
+}
+
+func encryptDigitalPayload(key, plaintext []byte) ([]byte, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	gcm, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, err
+	}
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		return nil, err
+	}
+	return gcm.Seal(nonce, nonce, plaintext, nil), nil
+}
+
+func decryptDigitalPayload(key, ciphertext []byte) ([]byte, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	gcm, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, err
+	}
+	if len(ciphertext) < gcm.NonceSize() {
+		return nil, errors.New("Digital payload is too short")
+	}
+	return gcm.Open(nil, ciphertext[:gcm.NonceSize()], ciphertext[gcm.NonceSize():], nil)
+}
@@ -0,0 +1,249 @@
+package core
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+
+	"github.com/OpenBazaar/openbazaar-go/pb"
+	"github.com/OpenBazaar/spvwallet"
+	"github.com/btcsuite/btcd/btcec"
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/btcsuite/btcd/txscript"
+	"github.com/btcsuite/btcd/wire"
+	"github.com/btcsuite/btcutil"
+	hd "github.com/btcsuite/btcutil/hdkeychain"
+	"github.com/btcsuite/btcutil/txsort"
+	"github.com/btcsuite/btcwallet/wallet/txrules"
+)
+
+// Collect the unspent outputs paying into the escrow address along with their total value
+func escrowInputs(records []*spvwallet.TransactionRecord) ([]spvwallet.TransactionInput, int64, error) {
+	var ins []spvwallet.TransactionInput
+	var value int64
+	for _, r := range records {
+		if !r.Spent && r.Value > 0 {
+			outpointHash, err := hex.DecodeString(r.Txid)
+			if err != nil {
+				return nil, 0, err
+			}
+			value += r.Value
+			ins = append(ins, spvwallet.TransactionInput{OutpointIndex: r.Index, OutpointHash: outpointHash})
+		}
+	}
+	return ins, value, nil
+}
+
+// Build the outputs for a payout from the moderated escrow. The moderator's fee that was locked
+// into the order at purchase time is paid to the moderator's fee address and the remainder of the
+// value is paid to the given address. The inputs and feePerByte must be those the payout will be
+// signed with as the moderator's output also pays its share of the transaction fee.
+func (n *OpenBazaarNode) EscrowOutputs(contract *pb.RicardianContract, payoutAddress string, value int64, inputs int, feePerByte uint64) ([]spvwallet.TransactionOutput, error) {
+	addr, err := btcutil.DecodeAddress(payoutAddress, n.Wallet.Params())
+	if err != nil {
+		return nil, err
+	}
+	payoutScript, err := txscript.PayToAddrScript(addr)
+	if err != nil {
+		return nil, err
+	}
+	fee := int64(contract.BuyerOrder.Payment.ModeratorFee)
+	if fee == 0 {
+		return []spvwallet.TransactionOutput{{ScriptPubKey: payoutScript, Value: value}}, nil
+	}
+	moderatorAddr, err := n.moderatorFeeAddress(contract)
+	if err != nil {
+		return nil, err
+	}
+	moderatorScript, err := txscript.PayToAddrScript(moderatorAddr)
+	if err != nil {
+		return nil, err
+	}
+	return splitEscrowPayout(value, fee, payoutScript, moderatorScript, inputs, feePerByte)
+}
+
+// Split the escrowed value between the payout and the moderator's fee. If the moderator's output
+// would be dust once its share of the transaction fee is taken out it is dropped and the whole
+// value goes to the payout, as a transaction with a dust output would not be relayed.
+func splitEscrowPayout(value, fee int64, payoutScript, moderatorScript []byte, inputs int, feePerByte uint64) ([]spvwallet.TransactionOutput, error) {
+	if fee >= value {
+		return nil, errors.New("Moderator fee exceeds the value held in escrow")
+	}
+	outs := []spvwallet.TransactionOutput{
+		{ScriptPubKey: payoutScript, Value: value - fee},
+		{ScriptPubKey: moderatorScript, Value: fee},
+	}
+	moderatorValue := fee - escrowFeePerOutput(inputs, outs, feePerByte)
+	if moderatorValue <= 0 || txrules.IsDustAmount(btcutil.Amount(moderatorValue), len(moderatorScript), txrules.DefaultRelayFeePerKb) {
+		return []spvwallet.TransactionOutput{{ScriptPubKey: payoutScript, Value: value}}, nil
+	}
+	return outs, nil
+}
+
+// The wallet splits the transaction fee evenly between the outputs of an escrow payout
+func escrowFeePerOutput(inputs int, outs []spvwallet.TransactionOutput, feePerByte uint64) int64 {
+	var txOuts []*wire.TxOut
+	for _, out := range outs {
+		txOuts = append(txOuts, wire.NewTxOut(out.Value, out.ScriptPubKey))
+	}
+	estimatedSize := spvwallet.EstimateSerializeSize(inputs, txOuts, false)
+	return int64(estimatedSize * int(feePerByte) / len(txOuts))
+}
+
+// Build the outputs for a partial refund from the moderated escrow. The refund amount is paid to the
+// buyer and the remainder is sent back to the escrow address so the rest of the order can carry on
+// to fulfillment and completion. The moderator is paid from the final payout so no fee is taken here.
+func (n *OpenBazaarNode) PartialRefundOutputs(contract *pb.RicardianContract, amount, value int64) ([]spvwallet.TransactionOutput, error) {
+	if amount <= 0 || amount >= value {
+		return nil, errors.New("Partial refund must be less than the value held in escrow")
+	}
+	if value-amount <= int64(contract.BuyerOrder.Payment.ModeratorFee) {
+		return nil, errors.New("Partial refund would leave too little in escrow to pay the moderator")
+	}
+	refundAddr, err := btcutil.DecodeAddress(contract.BuyerOrder.RefundAddress, n.Wallet.Params())
+	if err != nil {
+		return nil, err
+	}
+	refundScript, err := txscript.PayToAddrScript(refundAddr)
+	if err != nil {
+		return nil, err
+	}
+	escrowAddr, err := btcutil.DecodeAddress(contract.BuyerOrder.Payment.Address, n.Wallet.Params())
+	if err != nil {
+		return nil, err
+	}
+	escrowScript, err := txscript.PayToAddrScript(escrowAddr)
+	if err != nil {
+		return nil, err
+	}
+	outs := []spvwallet.TransactionOutput{
+		{ScriptPubKey: refundScript, Value: amount},
+		{ScriptPubKey: escrowScript, Value: value - amount},
+	}
+	return outs, nil
+}
+
+// Build the outputs for a partial refund which settles the rest of the order. Once nothing is left to
+// fulfill the value left after the refund is paid out to the vendor and moderator in the same
+// transaction. It can't go back into escrow as the vendor can't sign a payout spending an escrow
+// output which doesn't exist until the buyer has signed the refund.
+func (n *OpenBazaarNode) SettlingRefundOutputs(contract *pb.RicardianContract, amount, value int64, payoutAddress string, inputs int, feePerByte uint64) ([]spvwallet.TransactionOutput, error) {
+	if amount <= 0 || amount >= value {
+		return nil, errors.New("Partial refund must be less than the value held in escrow")
+	}
+	refundAddr, err := btcutil.DecodeAddress(contract.BuyerOrder.RefundAddress, n.Wallet.Params())
+	if err != nil {
+		return nil, err
+	}
+	refundScript, err := txscript.PayToAddrScript(refundAddr)
+	if err != nil {
+		return nil, err
+	}
+	payouts, err := n.EscrowOutputs(contract, payoutAddress, value-amount, inputs, feePerByte)
+	if err != nil {
+		return nil, err
+	}
+	return append([]spvwallet.TransactionOutput{{ScriptPubKey: refundScript, Value: amount}}, payouts...), nil
+}
+
+// The moderator's fee is paid to the wallet address the moderator published, which was locked into
+// the order at purchase time. The moderator's escrow key can't be used as it is derived with the
+// order's chaincode which the moderator only learns if there is a dispute.
+func (n *OpenBazaarNode) moderatorFeeAddress(contract *pb.RicardianContract) (btcutil.Address, error) {
+	if contract.BuyerOrder.Payment.ModeratorAddress == "" {
+		return nil, errors.New("Order does not have a moderator fee address")
+	}
+	return btcutil.DecodeAddress(contract.BuyerOrder.Payment.ModeratorAddress, n.Wallet.Params())
+}
+
+// Derive the escrow public key for the given master public key and order chaincode
+func (n *OpenBazaarNode) escrowPublicKey(masterPubKey []byte, chaincode string) (*btcec.PublicKey, error) {
+	cc, err := hex.DecodeString(chaincode)
+	if err != nil {
+		return nil, err
+	}
+	childKey, err := n.escrowChildKey(masterPubKey, cc)
+	if err != nil {
+		return nil, err
+	}
+	return childKey.ECPubKey()
+}
+
+// Derive the first child of a master public key using the order's chaincode
+func (n *OpenBazaarNode) escrowChildKey(masterPubKey []byte, chaincode []byte) (*hd.ExtendedKey, error) {
+	parentFP := []byte{0x00, 0x00, 0x00, 0x00}
+	hdKey := hd.NewExtendedKey(
+		n.Wallet.Params().HDPublicKeyID[:],
+		masterPubKey,
+		chaincode,
+		parentFP,
+		0,
+		0,
+		false)
+	return hdKey.Child(0)
+}
+
+// Check that the signatures commit to a transaction spending the given inputs to the given outputs.
+// The transaction is rebuilt the same way the wallet builds it when signing (fee split evenly between
+// the outputs, then BIP 69 sorted) so this will fail if the counterparty signed different outputs.
+func verifyEscrowSignatures(ins []spvwallet.TransactionInput, outs []spvwallet.TransactionOutput, sigs []*pb.BitcoinSignature, pubkey *btcec.PublicKey, redeemScript []byte, feePerByte uint64) error {
+	if len(ins) == 0 {
+		return errors.New("No escrowed funds to pay out")
+	}
+	if len(sigs) != len(ins) {
+		return errors.New("Escrow signatures do not cover all inputs")
+	}
+	tx := new(wire.MsgTx)
+	for _, in := range ins {
+		ch, err := chainhash.NewHashFromStr(hex.EncodeToString(in.OutpointHash))
+		if err != nil {
+			return err
+		}
+		tx.TxIn = append(tx.TxIn, wire.NewTxIn(wire.NewOutPoint(ch, in.OutpointIndex), []byte{}))
+	}
+	feePerOutput := escrowFeePerOutput(len(ins), outs, feePerByte)
+	for _, out := range outs {
+		tx.TxOut = append(tx.TxOut, wire.NewTxOut(out.Value-feePerOutput, out.ScriptPubKey))
+	}
+	txsort.InPlaceSort(tx)
+
+	for _, s := range sigs {
+		if int(s.InputIndex) >= len(tx.TxIn) || len(s.Signature) == 0 {
+			return errors.New("Escrow signature references an invalid input")
+		}
+		// Strip the sighash type appended to the signature
+		sig, err := btcec.ParseDERSignature(s.Signature[:len(s.Signature)-1], btcec.S256())
+		if err != nil {
+			return err
+		}
+		hash, err := calcSignatureHash(tx, int(s.InputIndex), redeemScript)
+		if err != nil {
+			return err
+		}
+		if !sig.Verify(hash, pubkey) {
+			return errors.New("Escrow signature does not match the expected payout")
+		}
+	}
+	return nil
+}
+
+// Signature hash of input idx for SIGHASH_ALL. Our redeem scripts never contain OP_CODESEPARATOR.
+func calcSignatureHash(tx *wire.MsgTx, idx int, redeemScript []byte) ([]byte, error) {
+	txCopy := tx.Copy()
+	for i := range txCopy.TxIn {
+		if i == idx {
+			txCopy.TxIn[i].SignatureScript = redeemScript
+		} else {
+			txCopy.TxIn[i].SignatureScript = nil
+		}
+	}
+	var buf bytes.Buffer
+	if err := txCopy.Serialize(&buf); err != nil {
+		return nil, err
+	}
+	if err := binary.Write(&buf, binary.LittleEndian, uint32(txscript.SigHashAll)); err != nil {
+		return nil, err
+	}
+	return chainhash.DoubleHashB(buf.Bytes()), nil
+}
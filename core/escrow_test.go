@@ -0,0 +1,152 @@
+package core
+
+import (
+	"testing"
+
+	"github.com/OpenBazaar/openbazaar-go/pb"
+	"github.com/OpenBazaar/spvwallet"
+	"github.com/btcsuite/btcd/chaincfg"
+	"github.com/btcsuite/btcd/txscript"
+	"github.com/btcsuite/btcutil"
+)
+
+func testPayToPubKeyHash(t *testing.T, b byte) []byte {
+	hash := make([]byte, 20)
+	hash[0] = b
+	addr, err := btcutil.NewAddressPubKeyHash(hash, &chaincfg.MainNetParams)
+	if err != nil {
+		t.Fatal(err)
+	}
+	script, err := txscript.PayToAddrScript(addr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return script
+}
+
+func TestEscrowFeePerOutput(t *testing.T) {
+	script := testPayToPubKeyHash(t, 1)
+	outs := []spvwallet.TransactionOutput{{ScriptPubKey: script, Value: 1}, {ScriptPubKey: script, Value: 1}}
+	// 10 bytes of overhead, one P2PKH input and two P2PKH outputs
+	if fee := escrowFeePerOutput(1, outs, 10); fee != 1135 {
+		t.Errorf("Expected 1135 got %d", fee)
+	}
+	if fee := escrowFeePerOutput(1, outs, 0); fee != 0 {
+		t.Errorf("Expected 0 got %d", fee)
+	}
+}
+
+func TestSplitEscrowPayout(t *testing.T) {
+	payoutScript := testPayToPubKeyHash(t, 1)
+	moderatorScript := testPayToPubKeyHash(t, 2)
+	tests := []struct {
+		value     int64
+		fee       int64
+		payout    int64
+		moderator int64
+	}{
+		// The moderator's output covers its share of the transaction fee with plenty left over
+		{1000000, 50000, 950000, 50000},
+		// Left with 600 satoshi after its 1135 satoshi share of the transaction fee
+		{1000000, 1735, 998265, 1735},
+		// Left with 545 satoshi which is dust
+		{1000000, 1680, 1000000, 0},
+		// The fee doesn't cover its share of the transaction fee
+		{1000000, 1000, 1000000, 0},
+		{1000000, 1, 1000000, 0},
+	}
+	for _, test := range tests {
+		outs, err := splitEscrowPayout(test.value, test.fee, payoutScript, moderatorScript, 1, 10)
+		if err != nil {
+			t.Errorf("Fee %d: %s", test.fee, err)
+			continue
+		}
+		if test.moderator == 0 {
+			if len(outs) != 1 {
+				t.Errorf("Fee %d: expected the moderator output to be dropped", test.fee)
+				continue
+			}
+		} else {
+			if len(outs) != 2 {
+				t.Errorf("Fee %d: expected a moderator output", test.fee)
+				continue
+			}
+			if string(outs[1].ScriptPubKey) != string(moderatorScript) || outs[1].Value != test.moderator {
+				t.Errorf("Fee %d: expected the moderator to be paid %d got %d", test.fee, test.moderator, outs[1].Value)
+			}
+		}
+		if string(outs[0].ScriptPubKey) != string(payoutScript) || outs[0].Value != test.payout {
+			t.Errorf("Fee %d: expected a payout of %d got %d", test.fee, test.payout, outs[0].Value)
+		}
+	}
+}
+
+func TestSplitEscrowPayoutFeeExceedsValue(t *testing.T) {
+	payoutScript := testPayToPubKeyHash(t, 1)
+	moderatorScript := testPayToPubKeyHash(t, 2)
+	for _, fee := range []int64{100000, 100001} {
+		if _, err := splitEscrowPayout(100000, fee, payoutScript, moderatorScript, 1, 10); err == nil {
+			t.Errorf("Fee %d: expected an error for a fee not less than the value in escrow", fee)
+		}
+	}
+}
+
+func TestModeratorFeeWithinTolerance(t *testing.T) {
+	tests := []struct {
+		expected uint64
+		fee      uint64
+		ok       bool
+	}{
+		{10000, 10000, true},
+		{10000, 10500, true},
+		{10000, 9500, true},
+		{10000, 10501, false},
+		{10000, 9499, false},
+		{10000, 0, false},
+		{0, 0, true},
+		{0, 1, false},
+	}
+	for _, test := range tests {
+		if ok := moderatorFeeWithinTolerance(test.expected, test.fee); ok != test.ok {
+			t.Errorf("Expected fee %d, order fee %d: expected %t got %t", test.expected, test.fee, test.ok, ok)
+		}
+	}
+}
+
+func TestGetModeratorFeePercentage(t *testing.T) {
+	n := new(OpenBazaarNode)
+	tests := []struct {
+		total      uint64
+		percentage float32
+		fee        uint64
+		err        bool
+	}{
+		{100000, 1, 1000, false},
+		{100000, 2.5, 2500, false},
+		{333, 10, 33, false},
+		{100000, 0, 0, false},
+		{100000, 100, 0, true},
+		{100000, 101, 0, true},
+		{100000, -1, 0, true},
+	}
+	for _, test := range tests {
+		moderator := &pb.Moderator{Fee: &pb.Moderator_Fee{FeeType: pb.Moderator_Fee_PERCENTAGE, Percentage: test.percentage}}
+		fee, err := n.GetModeratorFee(test.total, moderator)
+		if test.err {
+			if err == nil {
+				t.Errorf("%v%% of %d: expected an error", test.percentage, test.total)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%v%% of %d: %s", test.percentage, test.total, err)
+			continue
+		}
+		if fee != test.fee {
+			t.Errorf("%v%% of %d: expected %d got %d", test.percentage, test.total, test.fee, fee)
+		}
+	}
+	if _, err := n.GetModeratorFee(100000, &pb.Moderator{}); err == nil {
+		t.Error("Expected an error for a moderator without a fee")
+	}
+}
@@ -8,15 +8,22 @@ import (
 
 	"github.com/OpenBazaar/openbazaar-go/pb"
 	"github.com/OpenBazaar/spvwallet"
-	"github.com/btcsuite/btcd/txscript"
 	"github.com/btcsuite/btcutil"
 	hd "github.com/btcsuite/btcutil/hdkeychain"
 	"github.com/golang/protobuf/proto"
 )
 
+// Fulfill one listing in the order. Orders containing several listings may be fulfilled one listing at
+// a time. For moderated orders the vendor's payout signatures are only sent with the final fulfillment.
 func (n *OpenBazaarNode) FulfillOrder(fulfillment *pb.OrderFulfillment, contract *pb.RicardianContract, records []*spvwallet.TransactionRecord) error {
+	if err := checkFulfillmentSlug(fulfillment.Slug, contract); err != nil {
+		return err
+	}
+	unfulfilled := n.UnfulfilledSlugs(contract)
+	isLast := len(unfulfilled) == 1 && unfulfilled[0] == fulfillment.Slug
+
 	rc := new(pb.RicardianContract)
-	if contract.BuyerOrder.Payment.Method == pb.Order_Payment_MODERATED {
+	if contract.BuyerOrder.Payment.Method == pb.Order_Payment_MODERATED && isLast {
 		payout := new(pb.OrderFulfillment_Payout)
 		payout.PayoutAddress = n.Wallet.CurrentAddress(spvwallet.EXTERNAL).EncodeAddress()
 		payout.PayoutFeePerByte = n.Wallet.GetFeePerByte(spvwallet.NORMAL)
@@ -34,18 +41,10 @@ func (n *OpenBazaarNode) FulfillOrder(fulfillment *pb.OrderFulfillment, contract
 			}
 		}
 
-		refundAddress, err := btcutil.DecodeAddress(contract.BuyerOrder.RefundAddress, n.Wallet.Params())
+		outputs, err := n.EscrowOutputs(contract, payout.PayoutAddress, outValue, len(ins), payout.PayoutFeePerByte)
 		if err != nil {
 			return err
 		}
-		var output spvwallet.TransactionOutput
-
-		outputScript, err := txscript.PayToAddrScript(refundAddress)
-		if err != nil {
-			return err
-		}
-		output.ScriptPubKey = outputScript
-		output.Value = outValue
 
 		chaincode, err := hex.DecodeString(contract.BuyerOrder.Payment.Chaincode)
 		if err != nil {
@@ -75,7 +74,7 @@ func (n *OpenBazaarNode) FulfillOrder(fulfillment *pb.OrderFulfillment, contract
 		}
 		redeemScript, err := hex.DecodeString(contract.BuyerOrder.Payment.RedeemScript)
 
-		signatures, err := n.Wallet.CreateMultisigSignature(ins, []spvwallet.TransactionOutput{output}, vendorKey, redeemScript, payout.PayoutFeePerByte)
+		signatures, err := n.Wallet.CreateMultisigSignature(ins, outputs, vendorKey, redeemScript, payout.PayoutFeePerByte)
 		if err != nil {
 			return err
 		}
@@ -129,10 +128,67 @@ func (n *OpenBazaarNode) FulfillOrder(fulfillment *pb.OrderFulfillment, contract
 			contract.Signatures = append(contract.Signatures, sig)
 		}
 	}
-	n.Datastore.Sales().Put(contract.VendorOrderConfirmation.OrderID, *contract, pb.OrderState_FULFILLED, false)
+	n.Datastore.Sales().Put(contract.VendorOrderConfirmation.OrderID, *contract, n.PartialOrderState(contract), false)
 	return nil
 }
 
+// Make sure the slug is in the order and has not already been fulfilled
+func checkFulfillmentSlug(slug string, contract *pb.RicardianContract) error {
+	found := false
+	for _, listing := range contract.VendorListings {
+		if listing.Slug == slug {
+			found = true
+			break
+		}
+	}
+	if !found {
+		return errors.New("Fulfilled listing is not in the order")
+	}
+	for _, fulfil := range contract.VendorOrderFulfillment {
+		if fulfil.Slug == slug {
+			return errors.New("Listing has already been fulfilled")
+		}
+	}
+	remaining, err := remainingQuantities(contract)
+	if err != nil {
+		return err
+	}
+	if remaining[slug] == 0 {
+		return errors.New("Listing has been refunded")
+	}
+	return nil
+}
+
+// The quantity of each listing in the order which has not been refunded, keyed by slug
+func remainingQuantities(contract *pb.RicardianContract) (map[string]uint32, error) {
+	remaining := make(map[string]uint32)
+	if contract.Refund != nil {
+		return remaining, nil
+	}
+	refunded := make(map[string]uint32)
+	for _, refund := range contract.PartialRefunds {
+		for _, item := range refund.Items {
+			refunded[item.ListingHash] += item.Quantity
+		}
+	}
+	for _, listing := range contract.VendorListings {
+		hash, err := listingHash(listing)
+		if err != nil {
+			return nil, err
+		}
+		var ordered uint32
+		for _, item := range contract.BuyerOrder.Items {
+			if item.ListingHash == hash {
+				ordered += item.Quantity
+			}
+		}
+		if ordered > refunded[hash] {
+			remaining[listing.Slug] = ordered - refunded[hash]
+		}
+	}
+	return remaining, nil
+}
+
 func (n *OpenBazaarNode) SignOrderFulfillment(contract *pb.RicardianContract) (*pb.RicardianContract, error) {
 	serializedOrderFulfil, err := proto.Marshal(contract.VendorOrderFulfillment[0])
 	if err != nil {
@@ -152,10 +208,16 @@ func (n *OpenBazaarNode) SignOrderFulfillment(contract *pb.RicardianContract) (*
 	return contract, nil
 }
 
-func (n *OpenBazaarNode) ValidateOrderFulfillment(fulfillment *pb.OrderFulfillment, contract *pb.RicardianContract) error {
+// Validate a fulfillment from the vendor. The fulfillment must already be appended to the contract.
+func (n *OpenBazaarNode) ValidateOrderFulfillment(fulfillment *pb.OrderFulfillment, contract *pb.RicardianContract, records []*spvwallet.TransactionRecord) error {
 	if err := verifySignaturesOnOrderFulfilment(contract); err != nil {
 		return err
 	}
+	for _, fulfil := range contract.VendorOrderFulfillment[:len(contract.VendorOrderFulfillment)-1] {
+		if fulfil.Slug == fulfillment.Slug {
+			return errors.New("Listing has already been fulfilled")
+		}
+	}
 
 	slugExists := func(a string, list []string) bool {
 		for _, b := range list {
@@ -199,7 +261,8 @@ func (n *OpenBazaarNode) ValidateOrderFulfillment(fulfillment *pb.OrderFulfillme
 		return errors.New("Failed to verify signature on rating keys")
 	}
 
-	if contract.BuyerOrder.Payment.Method == pb.Order_Payment_MODERATED {
+	// The payout is sent with the fulfillment of the last listing in the order
+	if contract.BuyerOrder.Payment.Method == pb.Order_Payment_MODERATED && n.IsFulfilled(contract) {
 		if fulfillment.Payout == nil {
 			return errors.New("Payout object for multisig is nil")
 		}
@@ -207,6 +270,28 @@ func (n *OpenBazaarNode) ValidateOrderFulfillment(fulfillment *pb.OrderFulfillme
 		if err != nil {
 			return errors.New("Invalid payout address")
 		}
+
+		// Make sure the vendor's signatures pay the moderator the fee locked into the order
+		ins, outValue, err := escrowInputs(records)
+		if err != nil {
+			return err
+		}
+		outputs, err := n.EscrowOutputs(contract, fulfillment.Payout.PayoutAddress, outValue, len(ins), fulfillment.Payout.PayoutFeePerByte)
+		if err != nil {
+			return err
+		}
+		vendorKey, err := n.escrowPublicKey(contract.VendorListings[0].VendorID.Pubkeys.Bitcoin, contract.BuyerOrder.Payment.Chaincode)
+		if err != nil {
+			return err
+		}
+		redeemScript, err := hex.DecodeString(contract.BuyerOrder.Payment.RedeemScript)
+		if err != nil {
+			return err
+		}
+		err = verifyEscrowSignatures(ins, outputs, fulfillment.Payout.Sigs, vendorKey, redeemScript, fulfillment.Payout.PayoutFeePerByte)
+		if err != nil {
+			return err
+		}
 	}
 	if n.IsFulfilled(contract) {
 		var listingSlugs []string
@@ -236,11 +321,21 @@ func (n *OpenBazaarNode) ValidateOrderFulfillment(fulfillment *pb.OrderFulfillme
 }
 
 func verifySignaturesOnOrderFulfilment(contract *pb.RicardianContract) error {
-	for _, fulfil := range contract.VendorOrderFulfillment {
+	// Each fulfillment is signed separately and the signatures are stored in the same order
+	var fulfilSigs []*pb.Signature
+	for _, sig := range contract.Signatures {
+		if sig.Section == pb.Signature_ORDER_FULFILLMENT {
+			fulfilSigs = append(fulfilSigs, sig)
+		}
+	}
+	if len(fulfilSigs) != len(contract.VendorOrderFulfillment) {
+		return errors.New("Contract does not contain a signature for each order fulfilment")
+	}
+	for i, fulfil := range contract.VendorOrderFulfillment {
 		if err := verifyMessageSignature(
 			fulfil,
 			contract.VendorListings[0].VendorID.Pubkeys.Guid,
-			contract.Signatures,
+			[]*pb.Signature{fulfilSigs[i]},
 			pb.Signature_ORDER_FULFILLMENT,
 			contract.VendorListings[0].VendorID.Guid,
 		); err != nil {
@@ -259,9 +354,74 @@ func verifySignaturesOnOrderFulfilment(contract *pb.RicardianContract) error {
 	return nil
 }
 
+// Returns true once every listing in the order has been fulfilled
 func (n *OpenBazaarNode) IsFulfilled(contract *pb.RicardianContract) bool {
-	if len(contract.VendorOrderFulfillment) < len(contract.VendorListings) {
+	return len(n.UnfulfilledSlugs(contract)) == 0
+}
+
+// Returns the slugs of the listings in the order which have not yet been fulfilled. Listings whose
+// items have all been refunded don't need to be fulfilled.
+func (n *OpenBazaarNode) UnfulfilledSlugs(contract *pb.RicardianContract) []string {
+	fulfilled := make(map[string]bool)
+	for _, fulfil := range contract.VendorOrderFulfillment {
+		fulfilled[fulfil.Slug] = true
+	}
+	remaining, err := remainingQuantities(contract)
+	if err != nil {
+		log.Error(err)
+	}
+	var slugs []string
+	for _, listing := range contract.VendorListings {
+		if fulfilled[listing.Slug] || (remaining != nil && remaining[listing.Slug] == 0) {
+			continue
+		}
+		slugs = append(slugs, listing.Slug)
+	}
+	return slugs
+}
+
+// The state of an order which has been partly fulfilled or refunded. The order is FULFILLED once
+// every item which hasn't been refunded is fulfilled. Until then a partial refund takes precedence
+// over a partial fulfillment so the refund isn't hidden.
+func (n *OpenBazaarNode) PartialOrderState(contract *pb.RicardianContract) pb.OrderState {
+	switch {
+	case len(contract.VendorOrderFulfillment) > 0 && n.IsFulfilled(contract):
+		return pb.OrderState_FULFILLED
+	case len(contract.PartialRefunds) > 0:
+		return pb.OrderState_PARTIALLY_REFUNDED
+	default:
+		return pb.OrderState_PARTIALLY_FULFILLED
+	}
+}
+
+// Returns true if refunding the given items leaves nothing to fulfill in a moderated order which has
+// already been partly fulfilled. Such a refund pays out the vendor along with the buyer.
+func (n *OpenBazaarNode) RefundSettlesOrder(contract *pb.RicardianContract, refund *pb.Refund) bool {
+	if contract.BuyerOrder.Payment.Method != pb.Order_Payment_MODERATED || len(contract.VendorOrderFulfillment) == 0 {
 		return false
 	}
-	return true
+	after := *contract
+	after.PartialRefunds = append(append([]*pb.Refund{}, contract.PartialRefunds...), refund)
+	return n.IsFulfilled(&after)
+}
+
+// Returns true if the escrow was paid out by a partial refund, in which case completing the order
+// doesn't spend from escrow
+func (n *OpenBazaarNode) PayoutSettledByRefund(contract *pb.RicardianContract) bool {
+	for _, refund := range contract.PartialRefunds {
+		if refund.Payout != nil {
+			return true
+		}
+	}
+	return false
+}
+
+// Returns the vendor's payout for a moderated order. It is attached to the final fulfillment.
+func (n *OpenBazaarNode) GetFulfillmentPayout(contract *pb.RicardianContract) *pb.OrderFulfillment_Payout {
+	for i := len(contract.VendorOrderFulfillment) - 1; i >= 0; i-- {
+		if contract.VendorOrderFulfillment[i].Payout != nil {
+			return contract.VendorOrderFulfillment[i].Payout
+		}
+	}
+	return nil
 }
@@ -0,0 +1,183 @@
+package core
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/OpenBazaar/openbazaar-go/pb"
+)
+
+// An order for two of listing a and one of listing b
+func testFulfillmentContract(t *testing.T, method pb.Order_Payment_Method) (*pb.RicardianContract, string, string) {
+	listingA := &pb.Listing{Slug: "a"}
+	listingB := &pb.Listing{Slug: "b"}
+	hashA, err := listingHash(listingA)
+	if err != nil {
+		t.Fatal(err)
+	}
+	hashB, err := listingHash(listingB)
+	if err != nil {
+		t.Fatal(err)
+	}
+	contract := &pb.RicardianContract{
+		VendorListings: []*pb.Listing{listingA, listingB},
+		BuyerOrder: &pb.Order{
+			Items: []*pb.Order_Item{
+				{ListingHash: hashA, Quantity: 2},
+				{ListingHash: hashB, Quantity: 1},
+			},
+			Payment: &pb.Order_Payment{Method: method},
+		},
+	}
+	return contract, hashA, hashB
+}
+
+func TestRemainingQuantities(t *testing.T) {
+	contract, hashA, hashB := testFulfillmentContract(t, pb.Order_Payment_MODERATED)
+	remaining, err := remainingQuantities(contract)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(remaining, map[string]uint32{"a": 2, "b": 1}) {
+		t.Errorf("Unexpected quantities before refunds %v", remaining)
+	}
+
+	contract.PartialRefunds = []*pb.Refund{
+		{Amount: 1, Items: []*pb.Refund_Item{{ListingHash: hashA, Quantity: 1}}},
+		{Amount: 1, Items: []*pb.Refund_Item{{ListingHash: hashB, Quantity: 1}}},
+	}
+	remaining, err = remainingQuantities(contract)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(remaining, map[string]uint32{"a": 1}) {
+		t.Errorf("Unexpected quantities after partial refunds %v", remaining)
+	}
+
+	contract.Refund = &pb.Refund{}
+	remaining, err = remainingQuantities(contract)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(remaining) != 0 {
+		t.Errorf("Expected nothing to remain after a full refund got %v", remaining)
+	}
+}
+
+func TestUnfulfilledSlugs(t *testing.T) {
+	n := new(OpenBazaarNode)
+	contract, _, hashB := testFulfillmentContract(t, pb.Order_Payment_MODERATED)
+	if slugs := n.UnfulfilledSlugs(contract); !reflect.DeepEqual(slugs, []string{"a", "b"}) {
+		t.Errorf("Expected both listings to be unfulfilled got %v", slugs)
+	}
+	contract.VendorOrderFulfillment = []*pb.OrderFulfillment{{Slug: "a"}}
+	if slugs := n.UnfulfilledSlugs(contract); !reflect.DeepEqual(slugs, []string{"b"}) {
+		t.Errorf("Expected listing b to be unfulfilled got %v", slugs)
+	}
+	contract.PartialRefunds = []*pb.Refund{{Amount: 1, Items: []*pb.Refund_Item{{ListingHash: hashB, Quantity: 1}}}}
+	if slugs := n.UnfulfilledSlugs(contract); len(slugs) != 0 {
+		t.Errorf("Expected the refunded listing to be skipped got %v", slugs)
+	}
+	if !n.IsFulfilled(contract) {
+		t.Error("Expected the order to be fulfilled")
+	}
+}
+
+func TestPartialOrderState(t *testing.T) {
+	n := new(OpenBazaarNode)
+	contract, hashA, hashB := testFulfillmentContract(t, pb.Order_Payment_MODERATED)
+	refundA := &pb.Refund{Amount: 1, Items: []*pb.Refund_Item{{ListingHash: hashA, Quantity: 1}}}
+	refundB := &pb.Refund{Amount: 1, Items: []*pb.Refund_Item{{ListingHash: hashB, Quantity: 1}}}
+	tests := []struct {
+		fulfilled []string
+		refunds   []*pb.Refund
+		state     pb.OrderState
+	}{
+		{[]string{"a"}, nil, pb.OrderState_PARTIALLY_FULFILLED},
+		{[]string{"a", "b"}, nil, pb.OrderState_FULFILLED},
+		{nil, []*pb.Refund{refundA}, pb.OrderState_PARTIALLY_REFUNDED},
+		{[]string{"a"}, []*pb.Refund{refundA}, pb.OrderState_PARTIALLY_REFUNDED},
+		// Refunding the last unfulfilled listing leaves the order fulfilled
+		{[]string{"a"}, []*pb.Refund{refundB}, pb.OrderState_FULFILLED},
+		{[]string{"a"}, []*pb.Refund{refundA, refundB}, pb.OrderState_FULFILLED},
+	}
+	for i, test := range tests {
+		contract.VendorOrderFulfillment = nil
+		for _, slug := range test.fulfilled {
+			contract.VendorOrderFulfillment = append(contract.VendorOrderFulfillment, &pb.OrderFulfillment{Slug: slug})
+		}
+		contract.PartialRefunds = test.refunds
+		if state := n.PartialOrderState(contract); state != test.state {
+			t.Errorf("Test %d: expected %s got %s", i, test.state, state)
+		}
+	}
+}
+
+func TestRefundSettlesOrder(t *testing.T) {
+	n := new(OpenBazaarNode)
+	contract, hashA, hashB := testFulfillmentContract(t, pb.Order_Payment_MODERATED)
+	refundB := &pb.Refund{Amount: 1, Items: []*pb.Refund_Item{{ListingHash: hashB, Quantity: 1}}}
+	if n.RefundSettlesOrder(contract, refundB) {
+		t.Error("Refund settled an order with nothing fulfilled")
+	}
+	contract.VendorOrderFulfillment = []*pb.OrderFulfillment{{Slug: "a"}}
+	if !n.RefundSettlesOrder(contract, refundB) {
+		t.Error("Refunding the last unfulfilled listing did not settle the order")
+	}
+	if len(contract.PartialRefunds) != 0 {
+		t.Error("Checking the refund modified the contract")
+	}
+	refundA := &pb.Refund{Amount: 1, Items: []*pb.Refund_Item{{ListingHash: hashA, Quantity: 1}}}
+	if n.RefundSettlesOrder(contract, refundA) {
+		t.Error("Refund settled an order with a listing left to fulfill")
+	}
+
+	direct, _, hashB := testFulfillmentContract(t, pb.Order_Payment_DIRECT)
+	direct.VendorOrderFulfillment = []*pb.OrderFulfillment{{Slug: "a"}}
+	if n.RefundSettlesOrder(direct, &pb.Refund{Amount: 1, Items: []*pb.Refund_Item{{ListingHash: hashB, Quantity: 1}}}) {
+		t.Error("Refund of a direct order paid out from escrow")
+	}
+
+	if n.PayoutSettledByRefund(contract) {
+		t.Error("Payout settled without a refund")
+	}
+	contract.PartialRefunds = []*pb.Refund{{Amount: 1, Payout: &pb.OrderFulfillment_Payout{}}}
+	if !n.PayoutSettledByRefund(contract) {
+		t.Error("Expected the payout to be settled by the refund")
+	}
+}
+
+func TestLockOrder(t *testing.T) {
+	n := new(OpenBazaarNode)
+	unlock := n.LockOrder("order")
+
+	// A different order is not blocked
+	n.LockOrder("other")()
+
+	locked := make(chan struct{})
+	go func() {
+		unlock := n.LockOrder("order")
+		close(locked)
+		unlock()
+	}()
+	select {
+	case <-locked:
+		t.Fatal("Order was locked twice")
+	case <-time.After(50 * time.Millisecond):
+	}
+	unlock()
+	select {
+	case <-locked:
+	case <-time.After(5 * time.Second):
+		t.Fatal("Order was not unlocked")
+	}
+
+	// Wait for the goroutine to release the lock
+	n.LockOrder("order")()
+	n.orderLocksMtx.Lock()
+	defer n.orderLocksMtx.Unlock()
+	if len(n.orderLocks) != 0 {
+		t.Errorf("%d order locks left after unlocking", len(n.orderLocks))
+	}
+}
@@ -1,20 +1,36 @@
 package core
 
 import (
+	"container/list"
 	"crypto/sha256"
 	"errors"
 	"github.com/OpenBazaar/jsonpb"
 	"github.com/OpenBazaar/openbazaar-go/ipfs"
 	"github.com/OpenBazaar/openbazaar-go/pb"
+	"github.com/OpenBazaar/spvwallet"
+	"github.com/btcsuite/btcd/btcec"
+	ipfspath "github.com/ipfs/go-ipfs/path"
 	"golang.org/x/net/context"
 	multihash "gx/ipfs/QmYf7ng2hG5XBtJA3tN34DQ2GUN5HNksEw1rLDkmr6vGku/go-multihash"
 	ma "gx/ipfs/QmYzDkkgAEmrcNzFCiYo6L1dTX4EAG1gZkbtdbd9trL4vd/go-multiaddr"
 	"os"
 	"path"
+	"strings"
+	"sync"
+	"time"
 )
 
 var ModeratorPointerID multihash.Multihash
 
+// How long a resolved moderator is kept before its files are fetched again even if its root hasn't changed
+const ModeratorCacheTTL = time.Hour
+
+// Moderators which failed to resolve or verify are retried after this long
+const ModeratorNegativeCacheTTL = 10 * time.Minute
+
+// The most moderators kept in the cache. The least recently used are evicted beyond this.
+const ModeratorCacheSize = 1000
+
 func init() {
 	modHash := sha256.Sum256([]byte("moderators"))
 	encoded, err := multihash.Encode(modHash[:], multihash.SHA2_256)
@@ -43,6 +59,9 @@ func (n *OpenBazaarNode) SetSelfAsModerator(moderator *pb.Moderator) error {
 	}
 	moderator.PubKey = mPubKey.SerializeCompressed()
 
+	// Moderator fees are paid to a wallet address so they can be spent without the order's chaincode
+	moderator.FeeAddress = n.Wallet.CurrentAddress(spvwallet.EXTERNAL).EncodeAddress()
+
 	// Save to file
 	modPath := path.Join(n.RepoPath, "root", "moderation")
 	m := jsonpb.Marshaler{
@@ -131,3 +150,244 @@ func (n *OpenBazaarNode) RemoveSelfAsModerator() error {
 	}
 	return nil
 }
+
+// Fetch and parse the moderation file published by the given peer
+func (n *OpenBazaarNode) GetModerator(peerId string) (*pb.Moderator, error) {
+	ipnsPath := ipfspath.FromString(peerId + "/moderation")
+	moderatorBytes, err := ipfs.ResolveThenCat(n.Context, ipnsPath)
+	if err != nil {
+		return nil, err
+	}
+	moderatorInfo := new(pb.Moderator)
+	err = jsonpb.UnmarshalString(string(moderatorBytes), moderatorInfo)
+	if err != nil {
+		return nil, err
+	}
+	return moderatorInfo, nil
+}
+
+// Calculate the fee in satoshi the moderator charges for an order with the given total
+func (n *OpenBazaarNode) GetModeratorFee(total uint64, moderator *pb.Moderator) (uint64, error) {
+	if moderator.Fee == nil {
+		return 0, errors.New("Moderator has not set a fee")
+	}
+	var fee uint64
+	switch moderator.Fee.FeeType {
+	case pb.Moderator_Fee_FIXED, pb.Moderator_Fee_FIXED_PLUS_PERCENTAGE:
+		if moderator.Fee.FixedFee == nil {
+			return 0, errors.New("Fixed fee must be set when using a fixed fee type")
+		}
+		satoshis, err := n.getPriceInSatoshi(moderator.Fee.FixedFee.CurrencyCode, moderator.Fee.FixedFee.Amount)
+		if err != nil {
+			return 0, err
+		}
+		fee = satoshis
+	}
+	switch moderator.Fee.FeeType {
+	case pb.Moderator_Fee_PERCENTAGE, pb.Moderator_Fee_FIXED_PLUS_PERCENTAGE:
+		if moderator.Fee.Percentage < 0 || moderator.Fee.Percentage > 100 {
+			return 0, errors.New("Moderator fee percentage out of range")
+		}
+		fee += uint64(float64(total) * (float64(moderator.Fee.Percentage) / 100))
+	}
+	if fee >= total {
+		return 0, errors.New("Moderator fee exceeds the order total")
+	}
+	return fee, nil
+}
+
+type moderatorCacheEntry struct {
+	moderator *pb.ModeratorRespApi
+	hash      string
+	err       error
+	expires   time.Time
+}
+
+// Resolved moderators by peer ID. Entries are dropped once they expire and the least recently
+// used are evicted when there are more than size, ModeratorCacheSize if zero.
+type moderatorCache struct {
+	sync.Mutex
+	size    int
+	entries map[string]*list.Element
+	order   *list.List // Most recently used first
+}
+
+type moderatorCacheItem struct {
+	peerId string
+	entry  moderatorCacheEntry
+}
+
+// Return the unexpired entry for a peer
+func (c *moderatorCache) get(peerId string) (moderatorCacheEntry, bool) {
+	c.Lock()
+	defer c.Unlock()
+	el, ok := c.entries[peerId]
+	if !ok {
+		return moderatorCacheEntry{}, false
+	}
+	item := el.Value.(*moderatorCacheItem)
+	if !time.Now().Before(item.entry.expires) {
+		c.order.Remove(el)
+		delete(c.entries, peerId)
+		return moderatorCacheEntry{}, false
+	}
+	c.order.MoveToFront(el)
+	return item.entry, true
+}
+
+func (c *moderatorCache) put(peerId string, entry moderatorCacheEntry) {
+	c.Lock()
+	defer c.Unlock()
+	if c.entries == nil {
+		c.entries = make(map[string]*list.Element)
+		c.order = list.New()
+	}
+	if el, ok := c.entries[peerId]; ok {
+		el.Value.(*moderatorCacheItem).entry = entry
+		c.order.MoveToFront(el)
+		return
+	}
+	c.entries[peerId] = c.order.PushFront(&moderatorCacheItem{peerId, entry})
+	size := c.size
+	if size == 0 {
+		size = ModeratorCacheSize
+	}
+	for c.order.Len() > size {
+		el := c.order.Back()
+		c.order.Remove(el)
+		delete(c.entries, el.Value.(*moderatorCacheItem).peerId)
+	}
+}
+
+// Criteria used to select moderators. Zero values match everything.
+type ModeratorFilter struct {
+	// The moderator must speak at least one of these languages
+	Languages []string
+
+	// The moderator must use one of these fee types
+	FeeTypes []pb.Moderator_Fee_FeeType
+
+	// The fixed portion of the fee must be priced in this currency
+	Currency string
+
+	// Maximum fixed fee in satoshi
+	MaxFixedFee uint64
+
+	// Maximum percentage fee
+	MaxPercentage float32
+}
+
+// Resolve the given peer's profile and moderation file and verify they describe an active moderator.
+// The peer's root is resolved on every lookup so moderators who have gone offline or stopped moderating
+// since they were cached are dropped. The files are only fetched again when the root has changed.
+// Failures are cached so peers which don't resolve are not retried on every lookup.
+func (n *OpenBazaarNode) ResolveModerator(peerId string) (*pb.ModeratorRespApi, error) {
+	entry, fresh := n.moderatorCache.get(peerId)
+	if fresh && entry.err != nil {
+		return nil, entry.err
+	}
+
+	hash, err := ipfs.Resolve(n.Context, peerId)
+	var mod *pb.ModeratorRespApi
+	if err == nil {
+		if fresh && entry.hash == hash {
+			return entry.moderator, nil
+		}
+		mod, err = n.fetchModerator(peerId, hash)
+	}
+	entry = moderatorCacheEntry{moderator: mod, hash: hash, err: err, expires: time.Now().Add(ModeratorCacheTTL)}
+	if err != nil {
+		entry.expires = time.Now().Add(ModeratorNegativeCacheTTL)
+	}
+	n.moderatorCache.put(peerId, entry)
+	return mod, err
+}
+
+// Fetch the profile and moderation file from the given root so both come from the same snapshot of the node
+func (n *OpenBazaarNode) fetchModerator(peerId, hash string) (*pb.ModeratorRespApi, error) {
+	profileBytes, err := ipfs.Cat(n.Context, path.Join(hash, "profile"))
+	if err != nil {
+		return nil, err
+	}
+	profile := new(pb.Profile)
+	if err := jsonpb.UnmarshalString(string(profileBytes), profile); err != nil {
+		return nil, err
+	}
+	if !profile.Moderator {
+		return nil, errors.New("Peer is no longer a moderator")
+	}
+	moderatorBytes, err := ipfs.Cat(n.Context, path.Join(hash, "moderation"))
+	if err != nil {
+		return nil, err
+	}
+	moderator := new(pb.Moderator)
+	if err := jsonpb.UnmarshalString(string(moderatorBytes), moderator); err != nil {
+		return nil, err
+	}
+	if err := validateModerator(moderator); err != nil {
+		return nil, err
+	}
+	return &pb.ModeratorRespApi{PeerId: peerId, Profile: profile, Moderator: moderator}, nil
+}
+
+func validateModerator(moderator *pb.Moderator) error {
+	if moderator.Fee == nil {
+		return errors.New("Moderator must have a fee set")
+	}
+	switch moderator.Fee.FeeType {
+	case pb.Moderator_Fee_FIXED, pb.Moderator_Fee_FIXED_PLUS_PERCENTAGE:
+		if moderator.Fee.FixedFee == nil {
+			return errors.New("Fixed fee must be set when using a fixed fee type")
+		}
+	}
+	if moderator.Fee.Percentage < 0 || moderator.Fee.Percentage > 100 {
+		return errors.New("Moderator fee percentage out of range")
+	}
+	if _, err := btcec.ParsePubKey(moderator.PubKey, btcec.S256()); err != nil {
+		return errors.New("Moderator has an invalid escrow public key")
+	}
+	return nil
+}
+
+// Returns true if the moderator meets all of the filter's criteria
+func (n *OpenBazaarNode) MatchModerator(moderator *pb.Moderator, filter ModeratorFilter) bool {
+	if len(filter.Languages) > 0 {
+		found := false
+		for _, want := range filter.Languages {
+			for _, l := range moderator.Languages {
+				if strings.EqualFold(want, l) {
+					found = true
+				}
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	if len(filter.FeeTypes) > 0 {
+		found := false
+		for _, t := range filter.FeeTypes {
+			if t == moderator.Fee.FeeType {
+				found = true
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	usesFixed := moderator.Fee.FeeType == pb.Moderator_Fee_FIXED || moderator.Fee.FeeType == pb.Moderator_Fee_FIXED_PLUS_PERCENTAGE
+	usesPercentage := moderator.Fee.FeeType == pb.Moderator_Fee_PERCENTAGE || moderator.Fee.FeeType == pb.Moderator_Fee_FIXED_PLUS_PERCENTAGE
+	if usesFixed && filter.Currency != "" && !strings.EqualFold(filter.Currency, moderator.Fee.FixedFee.CurrencyCode) {
+		return false
+	}
+	if usesFixed && filter.MaxFixedFee > 0 {
+		satoshis, err := n.getPriceInSatoshi(moderator.Fee.FixedFee.CurrencyCode, moderator.Fee.FixedFee.Amount)
+		if err != nil || satoshis > filter.MaxFixedFee {
+			return false
+		}
+	}
+	if usesPercentage && filter.MaxPercentage > 0 && moderator.Fee.Percentage > filter.MaxPercentage {
+		return false
+	}
+	return true
+}
@@ -0,0 +1,122 @@
+package core
+
+import (
+	"errors"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/OpenBazaar/openbazaar-go/bitcoin"
+	"github.com/OpenBazaar/openbazaar-go/pb"
+)
+
+type testWallet struct {
+	bitcoin.BitcoinWallet
+}
+
+func (w *testWallet) CurrencyCode() string {
+	return "BTC"
+}
+
+// One dollar buys 1000 satoshi
+type testExchangeRates struct {
+	bitcoin.ExchangeRates
+}
+
+func (r *testExchangeRates) GetExchangeRate(currencyCode string) (float64, error) {
+	if currencyCode != "USD" {
+		return 0, errors.New("Unknown currency")
+	}
+	return 100000, nil
+}
+
+func (r *testExchangeRates) UnitsPerCoin() int {
+	return 100000000
+}
+
+func TestModeratorCacheEvictsLeastRecentlyUsed(t *testing.T) {
+	c := moderatorCache{size: 3}
+	entry := moderatorCacheEntry{hash: "Qm", expires: time.Now().Add(time.Hour)}
+	for i := 0; i < 3; i++ {
+		c.put(strconv.Itoa(i), entry)
+	}
+	// Using 0 makes 1 the least recently used
+	if _, ok := c.get("0"); !ok {
+		t.Fatal("Entry missing before the cache is full")
+	}
+	c.put("3", entry)
+	if _, ok := c.get("1"); ok {
+		t.Error("Least recently used entry was not evicted")
+	}
+	for _, peerId := range []string{"0", "2", "3"} {
+		if _, ok := c.get(peerId); !ok {
+			t.Errorf("Entry %s was evicted", peerId)
+		}
+	}
+	if len(c.entries) != 3 || c.order.Len() != 3 {
+		t.Errorf("Cache holds %d entries, expected 3", len(c.entries))
+	}
+}
+
+func TestModeratorCacheExpires(t *testing.T) {
+	var c moderatorCache
+	c.put("expired", moderatorCacheEntry{expires: time.Now().Add(-time.Second)})
+	c.put("fresh", moderatorCacheEntry{expires: time.Now().Add(time.Hour)})
+	if _, ok := c.get("expired"); ok {
+		t.Error("Returned an expired entry")
+	}
+	if _, ok := c.entries["expired"]; ok {
+		t.Error("Expired entry was not removed")
+	}
+	if _, ok := c.get("fresh"); !ok {
+		t.Error("Fresh entry missing")
+	}
+	// Replacing an entry doesn't add another
+	c.put("fresh", moderatorCacheEntry{hash: "Qm", expires: time.Now().Add(time.Hour)})
+	if entry, ok := c.get("fresh"); !ok || entry.hash != "Qm" || c.order.Len() != 1 {
+		t.Error("Entry was not replaced")
+	}
+}
+
+func TestMatchModerator(t *testing.T) {
+	n := &OpenBazaarNode{Wallet: new(testWallet), ExchangeRates: new(testExchangeRates)}
+	fixed := &pb.Moderator{
+		Languages: []string{"English", "German"},
+		Fee:       &pb.Moderator_Fee{FeeType: pb.Moderator_Fee_FIXED, FixedFee: &pb.Moderator_Price{CurrencyCode: "USD", Amount: 500}},
+	}
+	percentage := &pb.Moderator{
+		Languages: []string{"Spanish"},
+		Fee:       &pb.Moderator_Fee{FeeType: pb.Moderator_Fee_PERCENTAGE, Percentage: 5},
+	}
+	both := &pb.Moderator{
+		Fee: &pb.Moderator_Fee{FeeType: pb.Moderator_Fee_FIXED_PLUS_PERCENTAGE, Percentage: 1, FixedFee: &pb.Moderator_Price{CurrencyCode: "BTC", Amount: 2000}},
+	}
+	tests := []struct {
+		name      string
+		moderator *pb.Moderator
+		filter    ModeratorFilter
+		match     bool
+	}{
+		{"empty filter", fixed, ModeratorFilter{}, true},
+		{"language in any case", fixed, ModeratorFilter{Languages: []string{"french", "german"}}, true},
+		{"language not spoken", percentage, ModeratorFilter{Languages: []string{"English"}}, false},
+		{"fee type", percentage, ModeratorFilter{FeeTypes: []pb.Moderator_Fee_FeeType{pb.Moderator_Fee_FIXED, pb.Moderator_Fee_PERCENTAGE}}, true},
+		{"other fee type", fixed, ModeratorFilter{FeeTypes: []pb.Moderator_Fee_FeeType{pb.Moderator_Fee_PERCENTAGE}}, false},
+		{"currency", fixed, ModeratorFilter{Currency: "usd"}, true},
+		{"other currency", fixed, ModeratorFilter{Currency: "EUR"}, false},
+		{"currency ignored without a fixed fee", percentage, ModeratorFilter{Currency: "EUR"}, true},
+		// $5 is 5000 satoshi
+		{"fixed fee under the maximum", fixed, ModeratorFilter{MaxFixedFee: 5000}, true},
+		{"fixed fee over the maximum", fixed, ModeratorFilter{MaxFixedFee: 4999}, false},
+		{"fixed fee in the wallet currency", both, ModeratorFilter{MaxFixedFee: 1999}, false},
+		{"percentage under the maximum", percentage, ModeratorFilter{MaxPercentage: 5}, true},
+		{"percentage over the maximum", percentage, ModeratorFilter{MaxPercentage: 4.5}, false},
+		{"both fees under the maximums", both, ModeratorFilter{MaxFixedFee: 2000, MaxPercentage: 1}, true},
+		{"percentage ignored without a percentage fee", fixed, ModeratorFilter{MaxPercentage: 1}, true},
+	}
+	for _, test := range tests {
+		if match := n.MatchModerator(test.moderator, test.filter); match != test.match {
+			t.Errorf("%s: expected %t, got %t", test.name, test.match, match)
+		}
+	}
+}
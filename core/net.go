@@ -1,6 +1,8 @@
 package core
 
 import (
+	"errors"
+
 	peer "gx/ipfs/QmRBqJF7hb8ZSpRcMwUt8hNhydWcxGEhtk81HKq6oUwKvs/go-libp2p-peer"
 	multihash "gx/ipfs/QmYf7ng2hG5XBtJA3tN34DQ2GUN5HNksEw1rLDkmr6vGku/go-multihash"
 
@@ -53,10 +55,13 @@ func (n *OpenBazaarNode) SendOfflineMessage(p peer.ID, m *pb.Message) error {
 	}
 	if m.MessageType != pb.Message_OFFLINE_ACK {
 		pointer.Purpose = ipfs.MESSAGE
-		err = n.Datastore.Pointers().Put(pointer)
-		if err != nil {
-			return err
-		}
+	} else {
+		pointer.Purpose = ipfs.ACK
+	}
+	pointer.Recipient = p
+	err = n.Datastore.Pointers().Put(pointer)
+	if err != nil {
+		return err
 	}
 	return nil
 }
@@ -297,3 +302,25 @@ func (n *OpenBazaarNode) SendOrderCompletion(peerId string, completionMessage *p
 	}
 	return nil
 }
+
+func (n *OpenBazaarNode) SendContractRequest(peerId string) ([]*pb.RicardianContract, error) {
+	p, err := peer.IDB58Decode(peerId)
+	if err != nil {
+		return nil, err
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	m := pb.Message{MessageType: pb.Message_CONTRACT_REQUEST}
+	resp, err := n.Service.SendRequest(ctx, p, &m)
+	if err != nil {
+		return nil, err
+	}
+	if resp.MessageType == pb.Message_ERROR {
+		return nil, errors.New(string(resp.Payload.Value))
+	}
+	list := new(pb.ContractList)
+	if err := ptypes.UnmarshalAny(resp.Payload, list); err != nil {
+		return nil, err
+	}
+	return list.Contracts, nil
+}
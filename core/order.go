@@ -1,7 +1,6 @@
 package core
 
 import (
-	"crypto/rand"
 	"crypto/sha256"
 	"encoding/hex"
 	"errors"
@@ -12,6 +11,7 @@ import (
 	mh "gx/ipfs/QmYf7ng2hG5XBtJA3tN34DQ2GUN5HNksEw1rLDkmr6vGku/go-multihash"
 	"path"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/OpenBazaar/jsonpb"
@@ -21,10 +21,10 @@ import (
 	"github.com/btcsuite/btcd/chaincfg/chainhash"
 	"github.com/btcsuite/btcd/txscript"
 	"github.com/btcsuite/btcd/wire"
+	"github.com/btcsuite/btcutil"
 	hd "github.com/btcsuite/btcutil/hdkeychain"
 	"github.com/golang/protobuf/ptypes"
 	"github.com/golang/protobuf/ptypes/timestamp"
-	ipfspath "github.com/ipfs/go-ipfs/path"
 )
 
 type option struct {
@@ -256,26 +256,31 @@ func (n *OpenBazaarNode) Purchase(data *PurchaseData) (orderId string, paymentAd
 		payment := new(pb.Order_Payment)
 		payment.Method = pb.Order_Payment_MODERATED
 		payment.Moderator = data.Moderator
-		ipnsPath := ipfspath.FromString(data.Moderator + "/moderation")
-		moderatorBytes, err := ipfs.ResolveThenCat(n.Context, ipnsPath)
+		moderatorInfo, err := n.GetModerator(data.Moderator)
 		if err != nil {
 			return "", "", 0, false, err
 		}
-		moderatorInfo := new(pb.Moderator)
-		err = jsonpb.UnmarshalString(string(moderatorBytes), moderatorInfo)
+		total, err := n.CalculateOrderTotal(contract)
 		if err != nil {
 			return "", "", 0, false, err
 		}
-		total, err := n.CalculateOrderTotal(contract)
+		payment.Amount = total
+
+		// Lock the moderator's currently published fee into the contract
+		payment.ModeratorFee, err = n.GetModeratorFee(total, moderatorInfo)
 		if err != nil {
 			return "", "", 0, false, err
 		}
-		payment.Amount = total
+		if payment.ModeratorFee > 0 {
+			if _, err := btcutil.DecodeAddress(moderatorInfo.FeeAddress, n.Wallet.Params()); err != nil {
+				return "", "", 0, false, errors.New("Moderator does not publish a valid fee address")
+			}
+			payment.ModeratorAddress = moderatorInfo.FeeAddress
+		}
 
 		/* Generate a payment address using the first child key derived from the buyers's,
-		   vendors's and moderator's masterPubKey and a random chaincode. */
-		chaincode := make([]byte, 32)
-		_, err = rand.Read(chaincode)
+		   vendors's and moderator's masterPubKey and the order's chaincode. */
+		chaincode, err := n.newOrderChaincode()
 		if err != nil {
 			return "", "", 0, false, err
 		}
@@ -421,9 +426,8 @@ func (n *OpenBazaarNode) Purchase(data *PurchaseData) (orderId string, paymentAd
 			payment.Method = pb.Order_Payment_DIRECT
 
 			/* Generate a payment address using the first child key derived from the buyer's
-			   and vendors's masterPubKeys and a random chaincode. */
-			chaincode := make([]byte, 32)
-			_, err := rand.Read(chaincode)
+			   and vendors's masterPubKeys and the order's chaincode. */
+			chaincode, err := n.newOrderChaincode()
 			if err != nil {
 				return "", "", 0, false, err
 			}
@@ -594,6 +598,38 @@ func (n *OpenBazaarNode) CancelOfflineOrder(contract *pb.RicardianContract, reco
 	return nil
 }
 
+type orderLock struct {
+	sync.Mutex
+	waiters int
+}
+
+// Lock the order until the returned function is called. Callers hold it from reading the
+// order state until the fulfillment or refund is saved so two requests can't both act on it.
+func (n *OpenBazaarNode) LockOrder(orderId string) (unlock func()) {
+	n.orderLocksMtx.Lock()
+	if n.orderLocks == nil {
+		n.orderLocks = make(map[string]*orderLock)
+	}
+	l, ok := n.orderLocks[orderId]
+	if !ok {
+		l = new(orderLock)
+		n.orderLocks[orderId] = l
+	}
+	l.waiters++
+	n.orderLocksMtx.Unlock()
+
+	l.Lock()
+	return func() {
+		l.Unlock()
+		n.orderLocksMtx.Lock()
+		l.waiters--
+		if l.waiters == 0 {
+			delete(n.orderLocks, orderId)
+		}
+		n.orderLocksMtx.Unlock()
+	}
+}
+
 func (n *OpenBazaarNode) CalcOrderId(order *pb.Order) (string, error) {
 	ser, err := proto.Marshal(order)
 	if err != nil {
@@ -611,6 +647,24 @@ func (n *OpenBazaarNode) CalcOrderId(order *pb.Order) (string, error) {
 	return multihash.B58String(), nil
 }
 
+// The hash order items use to refer to a listing in the contract
+func listingHash(listing *pb.Listing) (string, error) {
+	ser, err := proto.Marshal(listing)
+	if err != nil {
+		return "", err
+	}
+	h := sha256.Sum256(ser)
+	encoded, err := mh.Encode(h[:], mh.SHA2_256)
+	if err != nil {
+		return "", err
+	}
+	multihash, err := mh.Cast(encoded)
+	if err != nil {
+		return "", err
+	}
+	return multihash.B58String(), nil
+}
+
 func (n *OpenBazaarNode) CalculateOrderTotal(contract *pb.RicardianContract) (uint64, error) {
 	if n.ExchangeRates != nil {
 		n.ExchangeRates.GetLatestRate("") // Refresh the exchange rates
@@ -959,6 +1013,9 @@ func (n *OpenBazaarNode) ValidateOrder(contract *pb.RicardianContract) error {
 		if err != nil {
 			return errors.New("Invalid moderator")
 		}
+		if contract.BuyerOrder.Payment.ModeratorFee >= contract.BuyerOrder.Payment.Amount {
+			return errors.New("Moderator fee exceeds the order total")
+		}
 	}
 
 	// Validate that the hash of the items in the contract match claimed hash in the order
@@ -1184,13 +1241,7 @@ func (n *OpenBazaarNode) ValidateDirectPaymentAddress(order *pb.Order) error {
 }
 
 func (n *OpenBazaarNode) ValidateModeratedPaymentAddress(order *pb.Order) error {
-	ipnsPath := ipfspath.FromString(order.Payment.Moderator + "/moderation")
-	moderatorBytes, err := ipfs.ResolveThenCat(n.Context, ipnsPath)
-	if err != nil {
-		return err
-	}
-	moderatorInfo := new(pb.Moderator)
-	err = jsonpb.UnmarshalString(string(moderatorBytes), moderatorInfo)
+	moderatorInfo, err := n.GetModerator(order.Payment.Moderator)
 	if err != nil {
 		return err
 	}
@@ -1253,6 +1304,36 @@ func (n *OpenBazaarNode) ValidateModeratedPaymentAddress(order *pb.Order) error
 	return nil
 }
 
+// How far the moderator fee in an order may be from the fee the vendor calculates, as a fraction of it.
+// Fixed fees are priced in the moderator's currency so the buyer and vendor may see different exchange rates.
+const ModeratorFeeTolerance = 0.05
+
+func (n *OpenBazaarNode) ValidateModeratorFee(order *pb.Order) error {
+	moderatorInfo, err := n.GetModerator(order.Payment.Moderator)
+	if err != nil {
+		return err
+	}
+	fee, err := n.GetModeratorFee(order.Payment.Amount, moderatorInfo)
+	if err != nil {
+		return err
+	}
+	if !moderatorFeeWithinTolerance(fee, order.Payment.ModeratorFee) {
+		return errors.New("Moderator fee in order does not match the moderator's published fee")
+	}
+	if order.Payment.ModeratorFee > 0 && order.Payment.ModeratorAddress != moderatorInfo.FeeAddress {
+		return errors.New("Moderator fee address in order does not match the moderator's published address")
+	}
+	return nil
+}
+
+func moderatorFeeWithinTolerance(expected, fee uint64) bool {
+	diff := expected - fee
+	if fee > expected {
+		diff = fee - expected
+	}
+	return float64(diff) <= float64(expected)*ModeratorFeeTolerance
+}
+
 func (n *OpenBazaarNode) SignOrder(contract *pb.RicardianContract) (*pb.RicardianContract, error) {
 	serializedOrder, err := proto.Marshal(contract.BuyerOrder)
 	if err != nil {
@@ -0,0 +1,284 @@
+package core
+
+import (
+	"encoding/json"
+	"sync"
+	"time"
+
+	"github.com/OpenBazaar/openbazaar-go/ipfs"
+	"github.com/OpenBazaar/openbazaar-go/metrics"
+)
+
+const (
+	// Changes made within this long of each other are published together
+	publishWindow = 3 * time.Second
+
+	maxPublishAttempts = 3
+	publishRetryDelay  = 30 * time.Second
+)
+
+const (
+	PublishIdle       = "idle"
+	PublishWaiting    = "waiting"
+	PublishPublishing = "publishing"
+)
+
+// The outcome of the last publish
+type PublishResult struct {
+	Hash     string    `json:"hash"`
+	Started  time.Time `json:"started"`
+	Finished time.Time `json:"finished"`
+	Attempts int       `json:"attempts"`
+	Error    string    `json:"error,omitempty"`
+}
+
+type PublishStatus struct {
+	State string `json:"state"`
+
+	// The number of changes waiting to be published
+	Queued int `json:"queued"`
+
+	// The root hash being published and the one which will be published next
+	Publishing string `json:"publishing,omitempty"`
+	Pending    string `json:"pending,omitempty"`
+
+	LastPublished *time.Time     `json:"lastPublished,omitempty"`
+	LastResult    *PublishResult `json:"lastResult,omitempty"`
+}
+
+// Publishes the node directory to IPNS. Changes are queued as they are made and
+// published together once none have been made for publishWindow. Only one publish
+// runs at a time and a failed publish is retried unless a newer change supersedes it.
+// Directory hashes which are replaced are unpinned once something newer is published.
+type PublishManager struct {
+	node *OpenBazaarNode
+	lock sync.Mutex
+	wake chan struct{}
+
+	stop     chan struct{}
+	stopped  chan struct{}
+	stopOnce sync.Once
+
+	state         string
+	current       string
+	pending       string
+	queued        int
+	lastPublished time.Time
+	lastResult    *PublishResult
+
+	// Hashes which have been added but not published or unpinned
+	unpublished map[string]bool
+}
+
+func NewPublishManager(node *OpenBazaarNode) *PublishManager {
+	return &PublishManager{
+		node:        node,
+		wake:        make(chan struct{}, 1),
+		stop:        make(chan struct{}),
+		stopped:     make(chan struct{}),
+		state:       PublishIdle,
+		unpublished: make(map[string]bool),
+	}
+}
+
+func (p *PublishManager) Run() {
+	defer close(p.stopped)
+	for {
+		select {
+		case <-p.wake:
+		case <-p.stop:
+			return
+		}
+		// Wait until the changes stop coming in
+		for {
+			p.lock.Lock()
+			queued := p.queued
+			p.lock.Unlock()
+			time.Sleep(publishWindow)
+			p.lock.Lock()
+			done := p.queued == queued
+			p.lock.Unlock()
+			if done {
+				break
+			}
+		}
+		p.publish()
+	}
+}
+
+// Stop publishing and wait for a publish in progress to finish. A failed publish is not
+// retried after this is called.
+func (p *PublishManager) Stop() {
+	p.stopOnce.Do(func() { close(p.stop) })
+	<-p.stopped
+}
+
+// Queue the root hash of the node directory to be published
+func (p *PublishManager) Queue(hash string) {
+	p.lock.Lock()
+	p.pending = hash
+	p.queued++
+	p.unpublished[hash] = true
+	if p.state == PublishIdle {
+		p.state = PublishWaiting
+	}
+	p.lock.Unlock()
+	p.broadcast("publish queued")
+	select {
+	case p.wake <- struct{}{}:
+	default:
+	}
+}
+
+func (p *PublishManager) Status() PublishStatus {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	s := PublishStatus{
+		State:      p.state,
+		Queued:     p.queued,
+		Publishing: p.current,
+		Pending:    p.pending,
+	}
+	if !p.lastPublished.IsZero() {
+		t := p.lastPublished
+		s.LastPublished = &t
+	}
+	if p.lastResult != nil {
+		r := *p.lastResult
+		s.LastResult = &r
+	}
+	return s
+}
+
+func (p *PublishManager) publish() {
+	p.lock.Lock()
+	hash := p.pending
+	p.current = hash
+	p.pending = ""
+	p.queued = 0
+	p.state = PublishPublishing
+	p.lock.Unlock()
+	if hash == "" {
+		p.setState(PublishIdle)
+		return
+	}
+	p.broadcast("publishing")
+
+	result := &PublishResult{Hash: hash, Started: time.Now()}
+	var err error
+	for {
+		result.Attempts++
+		start := time.Now()
+		_, err = ipfs.Publish(p.node.Context, hash)
+		metrics.PublishDuration.Observe(time.Since(start).Seconds())
+		if err == nil {
+			break
+		}
+		metrics.PublishFailures.Inc()
+		log.Errorf("Publishing %s failed on attempt %d: %s", hash, result.Attempts, err)
+		if result.Attempts >= maxPublishAttempts || p.superseded() {
+			break
+		}
+		stopping := false
+		select {
+		case <-time.After(publishRetryDelay * time.Duration(result.Attempts)):
+		case <-p.stop:
+			stopping = true
+		}
+		if stopping || p.superseded() {
+			break
+		}
+	}
+	result.Finished = time.Now()
+
+	if err == nil {
+		p.unpinReplaced(hash)
+		if p.node.Crossposter != nil {
+			p.node.Crossposter.Wake()
+		}
+	} else {
+		result.Error = err.Error()
+	}
+
+	p.lock.Lock()
+	p.current = ""
+	p.lastResult = result
+	if err == nil {
+		p.lastPublished = result.Finished
+	}
+	if p.pending != "" {
+		p.state = PublishWaiting
+	} else {
+		p.state = PublishIdle
+	}
+	p.lock.Unlock()
+
+	if err != nil {
+		p.broadcast("error publishing")
+	} else {
+		p.broadcast("publish complete")
+	}
+}
+
+// Has a newer change been queued since the current publish started?
+func (p *PublishManager) superseded() bool {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	return p.pending != ""
+}
+
+// Unpin the previously published directory and any added since which were never published
+func (p *PublishManager) unpinReplaced(hash string) {
+	p.lock.Lock()
+	var replaced []string
+	for h := range p.unpublished {
+		if h != hash && h != p.pending {
+			replaced = append(replaced, h)
+			delete(p.unpublished, h)
+		}
+	}
+	delete(p.unpublished, hash)
+	previous := p.node.RootHash
+	p.node.RootHash = hash
+	p.lock.Unlock()
+
+	if previous != "" && previous != hash && !contains(replaced, previous) {
+		replaced = append(replaced, previous)
+	}
+	for _, h := range replaced {
+		if err := ipfs.UnPinDir(p.node.Context, h); err != nil {
+			log.Errorf("Error unpinning %s: %s", h, err)
+		}
+	}
+}
+
+func (p *PublishManager) setState(state string) {
+	p.lock.Lock()
+	p.state = state
+	p.lock.Unlock()
+}
+
+// Tell the websocket clients. The status is one of the strings the UI already shows
+// and the details follow in publisher.
+func (p *PublishManager) broadcast(status string) {
+	if p.node.Broadcast == nil {
+		return
+	}
+	b, err := json.MarshalIndent(struct {
+		Status    string        `json:"status"`
+		Publisher PublishStatus `json:"publisher"`
+	}{status, p.Status()}, "", "    ")
+	if err != nil {
+		return
+	}
+	p.node.Broadcast <- b
+}
+
+func contains(list []string, s string) bool {
+	for _, l := range list {
+		if l == s {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,511 @@
+package core
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+
+	"github.com/OpenBazaar/jsonpb"
+	"github.com/OpenBazaar/openbazaar-go/ipfs"
+	"github.com/OpenBazaar/openbazaar-go/pb"
+	"github.com/btcsuite/btcd/txscript"
+	"github.com/btcsuite/btcutil"
+	hd "github.com/btcsuite/btcutil/hdkeychain"
+	ipfspath "github.com/ipfs/go-ipfs/path"
+)
+
+// An order rebuilt from the copy of the contract held by the counterparty
+type RecoveredOrder struct {
+	OrderId        string `json:"orderId"`
+	Type           string `json:"type"`
+	PeerId         string `json:"peerId"`
+	State          string `json:"state"`
+	PaymentAddress string `json:"paymentAddress"`
+	CanSign        bool   `json:"canSign"`
+	RatingKeys     bool   `json:"ratingKeys"`
+}
+
+// Ask each peer for the contracts it holds with us and rebuild the purchase and sale records we
+// no longer have. Our escrow and rating keys are re-derived from the wallet's master key and the
+// escrow addresses are watched before the chain is rescanned, so funds held in escrow show up in
+// the rebuilt records and can be co-signed again. The escrow addresses for the chaincodes we could
+// have used with the vendors and moderators we know of are watched as well. If no peers are given
+// the peers we follow and our followers are asked.
+func (n *OpenBazaarNode) RecoverOrders(peers []string) ([]RecoveredOrder, error) {
+	if len(peers) == 0 {
+		peers = n.recoveryPeers()
+	}
+	returned := make(map[string][]*pb.RicardianContract)
+	var contracts []*pb.RicardianContract
+	for _, p := range peers {
+		c, err := n.SendContractRequest(p)
+		if err != nil {
+			log.Warningf("Could not request contracts from %s: %s", p, err.Error())
+			continue
+		}
+		returned[p] = c
+		contracts = append(contracts, c...)
+	}
+	chaincodes, end, err := n.scanChaincodes(contracts)
+	if err != nil {
+		return nil, err
+	}
+	var recovered []RecoveredOrder
+	for _, p := range peers {
+		for _, contract := range returned[p] {
+			order, err := n.recoverOrder(p, contract, chaincodes)
+			if err != nil {
+				log.Warningf("Ignoring contract from %s: %s", p, err.Error())
+				continue
+			}
+			if order != nil {
+				log.Noticef("Recovered %s %s from %s", order.Type, order.OrderId, p)
+				recovered = append(recovered, *order)
+			}
+		}
+	}
+	watched, err := n.scanEscrowAddresses(contracts, chaincodes, end)
+	if err != nil {
+		log.Warningf("Could not scan for escrow addresses: %s", err.Error())
+	}
+	if len(recovered) > 0 || watched > 0 {
+		n.Wallet.ReSyncBlockchain(0)
+	}
+	return recovered, nil
+}
+
+// Return the purchases and sales we hold with the given peer
+func (n *OpenBazaarNode) ContractsWithPeer(peerId string) ([]*pb.RicardianContract, error) {
+	var contracts []*pb.RicardianContract
+	purchases, err := n.Datastore.Purchases().GetAll()
+	if err != nil {
+		return nil, err
+	}
+	for _, orderId := range purchases {
+		contract, _, _, _, _, err := n.Datastore.Purchases().GetByOrderId(orderId)
+		if err != nil || len(contract.VendorListings) == 0 {
+			continue
+		}
+		if contract.VendorListings[0].VendorID.Guid == peerId {
+			contracts = append(contracts, contract)
+		}
+	}
+	sales, err := n.Datastore.Sales().GetAll()
+	if err != nil {
+		return nil, err
+	}
+	for _, orderId := range sales {
+		contract, _, _, _, _, err := n.Datastore.Sales().GetByOrderId(orderId)
+		if err != nil || contract.BuyerOrder == nil {
+			continue
+		}
+		if contract.BuyerOrder.BuyerID.Guid == peerId {
+			contracts = append(contracts, contract)
+		}
+	}
+	return contracts, nil
+}
+
+// How many chaincode indices in a row are scanned past the last one known to be in use
+const chaincodeGapLimit = 20
+
+// Order chaincodes are derived from the wallet's master private key and a sequential index so,
+// like the rating keys, they can be re-derived from the mnemonic by enumerating the indices.
+func (n *OpenBazaarNode) orderChaincode(index uint32) ([]byte, error) {
+	key, err := n.Wallet.MasterPrivateKey().ECPrivKey()
+	if err != nil {
+		return nil, err
+	}
+	i := make([]byte, 4)
+	binary.BigEndian.PutUint32(i, index)
+	mac := hmac.New(sha256.New, key.Serialize())
+	mac.Write([]byte("OpenBazaar order chaincode"))
+	mac.Write(i)
+	return mac.Sum(nil), nil
+}
+
+// Allocate the chaincode for a new order. The index is recorded as used before the chaincode is
+// returned so no two orders share an escrow address.
+func (n *OpenBazaarNode) newOrderChaincode() ([]byte, error) {
+	index, err := n.Datastore.OrderChaincodes().Allocate()
+	if err != nil {
+		return nil, err
+	}
+	return n.orderChaincode(index)
+}
+
+// The purchases we hold which have an order and payment
+func (n *OpenBazaarNode) purchaseContracts() ([]*pb.RicardianContract, error) {
+	purchases, err := n.Datastore.Purchases().GetAll()
+	if err != nil {
+		return nil, err
+	}
+	var contracts []*pb.RicardianContract
+	for _, orderId := range purchases {
+		contract, _, _, _, _, err := n.Datastore.Purchases().GetByOrderId(orderId)
+		if err != nil || contract.BuyerOrder == nil || contract.BuyerOrder.Payment == nil || len(contract.VendorListings) == 0 {
+			continue
+		}
+		contracts = append(contracts, contract)
+	}
+	return contracts, nil
+}
+
+// Find the index of each chaincode in use by the purchases we hold or the contracts returned by our
+// peers. Chaincodes are derived in order until chaincodeGapLimit in a row match none of them, and
+// at least up to the next index we would allocate. The scan is sized by what is found rather than
+// by the allocation counter so it still reaches the chaincodes in use when the database was lost.
+// Returns the indices by hex encoded chaincode and the index the scan stopped at.
+func (n *OpenBazaarNode) scanChaincodes(contracts []*pb.RicardianContract) (map[string]uint32, uint32, error) {
+	purchases, err := n.purchaseContracts()
+	if err != nil {
+		return nil, 0, err
+	}
+	known := make(map[string]bool)
+	for _, contract := range append(purchases, contracts...) {
+		if contract.BuyerOrder != nil && contract.BuyerOrder.Payment != nil && contract.BuyerOrder.Payment.Chaincode != "" {
+			known[contract.BuyerOrder.Payment.Chaincode] = true
+		}
+	}
+	next, err := n.Datastore.OrderChaincodes().Next()
+	if err != nil {
+		return nil, 0, err
+	}
+	indices := make(map[string]uint32)
+	end := next + chaincodeGapLimit
+	for i := uint32(0); i < end; i++ {
+		chaincode, err := n.orderChaincode(i)
+		if err != nil {
+			return nil, 0, err
+		}
+		if cc := hex.EncodeToString(chaincode); known[cc] {
+			indices[cc] = i
+			if i+1+chaincodeGapLimit > end {
+				end = i + 1 + chaincodeGapLimit
+			}
+		}
+	}
+	return indices, end, nil
+}
+
+// Return the wallet key a peer signs its orders with. It is taken from the peer's moderation file
+// if it moderates, in which case moderator is true, or else from its first listing.
+func (n *OpenBazaarNode) peerBitcoinKey(peerId string) (key []byte, moderator bool, err error) {
+	if m, err := n.GetModerator(peerId); err == nil {
+		return m.PubKey, true, nil
+	}
+	indexBytes, err := ipfs.ResolveThenCat(n.Context, ipfspath.FromString(peerId+"/listings/index.json"))
+	if err != nil {
+		return nil, false, err
+	}
+	var index []listingData
+	if err := json.Unmarshal(indexBytes, &index); err != nil {
+		return nil, false, err
+	}
+	if len(index) == 0 {
+		return nil, false, errors.New("Peer has no listings")
+	}
+	listingBytes, err := ipfs.Cat(n.Context, index[0].Hash)
+	if err != nil {
+		return nil, false, err
+	}
+	listing := new(pb.RicardianContract)
+	if err := jsonpb.UnmarshalString(string(listingBytes), listing); err != nil {
+		return nil, false, err
+	}
+	if len(listing.VendorListings) == 0 || listing.VendorListings[0].VendorID == nil || listing.VendorListings[0].VendorID.Guid != peerId ||
+		listing.VendorListings[0].VendorID.Pubkeys == nil {
+		return nil, false, errors.New("Listing was not made by the peer")
+	}
+	return listing.VendorListings[0].VendorID.Pubkeys.Bitcoin, false, nil
+}
+
+// Watch the escrow addresses we would have made with each vendor and moderator we know of, for
+// every chaincode index up to end which isn't used by an order we know of. Vendors and moderators
+// come from the purchases we hold, the contracts our peers returned and the peers we follow. Funds
+// escrowed for purchases whose contracts are lost then show up when the chain is rescanned. Only
+// the buyer picks the chaincode so sales can't be found this way. Returns the number of scripts watched.
+func (n *OpenBazaarNode) scanEscrowAddresses(contracts []*pb.RicardianContract, chaincodes map[string]uint32, end uint32) (int, error) {
+	purchases, err := n.purchaseContracts()
+	if err != nil {
+		return 0, err
+	}
+	ourId := n.IpfsNode.Identity.Pretty()
+	vendors := make(map[string][]byte)
+	moderators := make(map[string][]byte)
+	for _, contract := range append(purchases, contracts...) {
+		if contract.BuyerOrder == nil || contract.BuyerOrder.Payment == nil || len(contract.VendorListings) == 0 {
+			continue
+		}
+		vendor := contract.VendorListings[0].VendorID
+		if vendor != nil && vendor.Guid != ourId && vendor.Pubkeys != nil {
+			vendors[vendor.Guid] = vendor.Pubkeys.Bitcoin
+		}
+		if m := contract.BuyerOrder.Payment.Moderator; m != "" && m != ourId {
+			moderators[m] = nil
+		}
+	}
+	following, err := n.Datastore.Following().Get("", -1)
+	if err != nil {
+		return 0, err
+	}
+	for _, p := range following {
+		key, moderator, err := n.peerBitcoinKey(p)
+		if err != nil {
+			log.Debugf("Not scanning escrow addresses with %s: %s", p, err.Error())
+			continue
+		}
+		vendors[p] = key
+		if moderator {
+			moderators[p] = key
+		}
+	}
+	for m, key := range moderators {
+		if key != nil {
+			continue
+		}
+		if moderator, err := n.GetModerator(m); err != nil {
+			log.Warningf("Not scanning escrow addresses with moderator %s: %s", m, err.Error())
+		} else {
+			moderators[m] = moderator.PubKey
+		}
+	}
+	if len(vendors) == 0 {
+		return 0, nil
+	}
+	masterPubKey, err := n.Wallet.MasterPublicKey().ECPubKey()
+	if err != nil {
+		return 0, err
+	}
+	watched := 0
+	for i := uint32(0); i < end; i++ {
+		chaincode, err := n.orderChaincode(i)
+		if err != nil {
+			return watched, err
+		}
+		if _, ok := chaincodes[hex.EncodeToString(chaincode)]; ok {
+			continue
+		}
+		buyerKey, err := n.escrowChildKey(masterPubKey.SerializeCompressed(), chaincode)
+		if err != nil {
+			return watched, err
+		}
+		for _, v := range vendors {
+			vendorKey, err := n.escrowChildKey(v, chaincode)
+			if err != nil {
+				continue
+			}
+			keySets := [][]hd.ExtendedKey{{*buyerKey, *vendorKey}}
+			for _, m := range moderators {
+				if m == nil {
+					continue
+				}
+				moderatorKey, err := n.escrowChildKey(m, chaincode)
+				if err != nil {
+					continue
+				}
+				keySets = append(keySets, []hd.ExtendedKey{*buyerKey, *vendorKey, *moderatorKey})
+			}
+			for _, keys := range keySets {
+				// Direct payments are 1 of 2 and moderated payments 2 of 3
+				addr, _, err := n.Wallet.GenerateMultisigScript(keys, len(keys)-1)
+				if err != nil {
+					return watched, err
+				}
+				script, err := txscript.PayToAddrScript(addr)
+				if err != nil {
+					return watched, err
+				}
+				if err := n.Wallet.AddWatchedScript(script); err != nil {
+					return watched, err
+				}
+				watched++
+			}
+		}
+	}
+	return watched, nil
+}
+
+func (n *OpenBazaarNode) recoveryPeers() []string {
+	seen := make(map[string]bool)
+	var peers []string
+	following, _ := n.Datastore.Following().Get("", -1)
+	followers, _ := n.Datastore.Followers().Get("", -1)
+	for _, p := range append(following, followers...) {
+		if !seen[p] {
+			seen[p] = true
+			peers = append(peers, p)
+		}
+	}
+	return peers
+}
+
+// Check a contract returned by a peer and store it if it is an order between us which we don't have
+func (n *OpenBazaarNode) recoverOrder(peerId string, contract *pb.RicardianContract, chaincodes map[string]uint32) (*RecoveredOrder, error) {
+	if contract.BuyerOrder == nil || contract.BuyerOrder.Payment == nil || contract.BuyerOrder.BuyerID == nil ||
+		contract.BuyerOrder.Timestamp == nil || len(contract.VendorListings) == 0 || contract.VendorListings[0].VendorID == nil {
+		return nil, errors.New("Contract is missing the order")
+	}
+	if err := verifySignaturesOnOrder(contract); err != nil {
+		return nil, err
+	}
+	ourId := n.IpfsNode.Identity.Pretty()
+	buyer := contract.BuyerOrder.BuyerID
+	vendor := contract.VendorListings[0].VendorID
+	order := &RecoveredOrder{PeerId: peerId, PaymentAddress: contract.BuyerOrder.Payment.Address}
+	var ourBitcoinKey []byte
+	if buyer.Guid == ourId && vendor.Guid == peerId {
+		order.Type = "purchase"
+		ourBitcoinKey = buyer.Pubkeys.Bitcoin
+	} else if vendor.Guid == ourId && buyer.Guid == peerId {
+		if contract.VendorOrderConfirmation != nil {
+			if err := verifySignaturesOnOrderConfirmation(contract); err != nil {
+				return nil, err
+			}
+		}
+		order.Type = "sale"
+		ourBitcoinKey = vendor.Pubkeys.Bitcoin
+	} else {
+		return nil, errors.New("Contract is not an order between us and the peer")
+	}
+	masterPubKey, err := n.Wallet.MasterPublicKey().ECPubKey()
+	if err != nil {
+		return nil, err
+	}
+	if !bytes.Equal(ourBitcoinKey, masterPubKey.SerializeCompressed()) {
+		return nil, errors.New("Contract was made with a different wallet")
+	}
+
+	orderId, err := n.CalcOrderId(contract.BuyerOrder)
+	if err != nil {
+		return nil, err
+	}
+	order.OrderId = orderId
+	if order.Type == "purchase" {
+		if _, _, _, _, _, err := n.Datastore.Purchases().GetByOrderId(orderId); err == nil {
+			return nil, nil
+		}
+	} else {
+		if _, _, _, _, _, err := n.Datastore.Sales().GetByOrderId(orderId); err == nil {
+			return nil, nil
+		}
+	}
+
+	order.CanSign, err = n.canSignEscrow(contract, ourBitcoinKey)
+	if err != nil {
+		return nil, err
+	}
+	if order.Type == "purchase" {
+		order.RatingKeys = n.ratingKeysMatch(contract)
+		if index, ok := chaincodes[contract.BuyerOrder.Payment.Chaincode]; ok {
+			if err := n.Datastore.OrderChaincodes().Reserve(index); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	addr, err := btcutil.DecodeAddress(contract.BuyerOrder.Payment.Address, n.Wallet.Params())
+	if err != nil {
+		return nil, err
+	}
+	script, err := txscript.PayToAddrScript(addr)
+	if err != nil {
+		return nil, err
+	}
+	if err := n.Wallet.AddWatchedScript(script); err != nil {
+		return nil, err
+	}
+
+	state := n.recoveredOrderState(contract)
+	order.State = state.String()
+	// Anything past confirmation has been funded. Earlier orders are marked funded by the rescan.
+	funded := state != pb.OrderState_PENDING && state != pb.OrderState_CONFIRMED
+	if order.Type == "purchase" {
+		if err := n.Datastore.Purchases().Put(orderId, *contract, state, true); err != nil {
+			return nil, err
+		}
+		if err := n.Datastore.Purchases().UpdateFunding(orderId, funded, nil); err != nil {
+			return nil, err
+		}
+	} else {
+		if err := n.Datastore.Sales().Put(orderId, *contract, state, true); err != nil {
+			return nil, err
+		}
+		if err := n.Datastore.Sales().UpdateFunding(orderId, funded, nil); err != nil {
+			return nil, err
+		}
+	}
+	return order, nil
+}
+
+// Check the payment address commits to the redeem script and that our key derived from the
+// order chaincode is one of the keys which can sign for it
+func (n *OpenBazaarNode) canSignEscrow(contract *pb.RicardianContract, masterPubKey []byte) (bool, error) {
+	payment := contract.BuyerOrder.Payment
+	if payment.RedeemScript == "" || payment.Chaincode == "" {
+		return false, nil
+	}
+	redeemScript, err := hex.DecodeString(payment.RedeemScript)
+	if err != nil {
+		return false, err
+	}
+	scriptAddr, err := btcutil.NewAddressScriptHash(redeemScript, n.Wallet.Params())
+	if err != nil {
+		return false, err
+	}
+	if scriptAddr.EncodeAddress() != payment.Address {
+		return false, errors.New("Payment address does not match the redeem script")
+	}
+	escrowKey, err := n.escrowPublicKey(masterPubKey, payment.Chaincode)
+	if err != nil {
+		return false, err
+	}
+	_, addrs, _, err := txscript.ExtractPkScriptAddrs(redeemScript, n.Wallet.Params())
+	if err != nil {
+		return false, err
+	}
+	for _, a := range addrs {
+		if pk, ok := a.(*btcutil.AddressPubKey); ok && bytes.Equal(pk.ScriptAddress(), escrowKey.SerializeCompressed()) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// Check the rating keys in the order are the ones we derive from the order timestamp
+func (n *OpenBazaarNode) ratingKeysMatch(contract *pb.RicardianContract) bool {
+	ratingKey, err := n.Wallet.MasterPublicKey().Child(uint32(contract.BuyerOrder.Timestamp.Seconds))
+	if err != nil {
+		return false
+	}
+	ecRatingKey, err := ratingKey.ECPubKey()
+	if err != nil {
+		return false
+	}
+	for _, key := range contract.BuyerOrder.RatingKeys {
+		if !bytes.Equal(key, ecRatingKey.SerializeCompressed()) {
+			return false
+		}
+	}
+	return len(contract.BuyerOrder.RatingKeys) > 0
+}
+
+// Work out how far an order got from the sections in the contract
+func (n *OpenBazaarNode) recoveredOrderState(contract *pb.RicardianContract) pb.OrderState {
+	switch {
+	case contract.Refund != nil:
+		return pb.OrderState_REFUNDED
+	case contract.BuyerOrderCompletion != nil:
+		return pb.OrderState_COMPLETE
+	case len(contract.VendorOrderFulfillment) > 0 || len(contract.PartialRefunds) > 0:
+		return n.PartialOrderState(contract)
+	case contract.VendorOrderConfirmation != nil:
+		return pb.OrderState_CONFIRMED
+	default:
+		return pb.OrderState_PENDING
+	}
+}
@@ -1,8 +1,10 @@
 package core
 
 import (
+	"bytes"
 	"encoding/hex"
 	"errors"
+	"fmt"
 
 	"github.com/OpenBazaar/openbazaar-go/pb"
 	"github.com/OpenBazaar/spvwallet"
@@ -12,40 +14,52 @@ import (
 	"github.com/golang/protobuf/proto"
 )
 
-func (n *OpenBazaarNode) RefundOrder(contract *pb.RicardianContract, records []*spvwallet.TransactionRecord) error {
-	refundMsg := new(pb.Refund)
+// Refund the order. If refund.Amount is zero or covers everything left in the order the whole order is
+// refunded, otherwise only the given amount is returned to the buyer and the order carries on. Any
+// items listed in the refund are recorded as refunded. If that leaves nothing to fulfill in a moderated
+// order the vendor is paid out from escrow by the same transaction.
+func (n *OpenBazaarNode) RefundOrder(contract *pb.RicardianContract, records []*spvwallet.TransactionRecord, refund *pb.Refund) error {
 	orderId, err := n.CalcOrderId(contract.BuyerOrder)
 	if err != nil {
 		return err
 	}
-	refundMsg.OrderID = orderId
+	if err := validateRefundItems(contract, refund.Items); err != nil {
+		return err
+	}
+	refundMsg := &pb.Refund{
+		OrderID: orderId,
+		Memo:    refund.Memo,
+		Items:   refund.Items,
+	}
+	remaining := refundableValue(contract, records)
+	if refund.Amount > uint64(remaining) {
+		return errors.New("Refund amount exceeds the value left in the order")
+	}
+	partial := refund.Amount > 0 && refund.Amount < uint64(remaining)
+	if partial {
+		refundMsg.Amount = refund.Amount
+	}
 	if contract.BuyerOrder.Payment.Method == pb.Order_Payment_MODERATED {
-		var ins []spvwallet.TransactionInput
-		var outValue int64
-		for _, r := range records {
-			if !r.Spent && r.Value > 0 {
-				outpointHash, err := hex.DecodeString(r.Txid)
-				if err != nil {
-					return err
-				}
-				outValue += r.Value
-				in := spvwallet.TransactionInput{OutpointIndex: r.Index, OutpointHash: outpointHash}
-				ins = append(ins, in)
-			}
-		}
-
-		refundAddress, err := btcutil.DecodeAddress(contract.BuyerOrder.RefundAddress, n.Wallet.Params())
+		ins, outValue, err := escrowInputs(records)
 		if err != nil {
 			return err
 		}
-		var output spvwallet.TransactionOutput
 
-		outputScript, err := txscript.PayToAddrScript(refundAddress)
+		var outputs []spvwallet.TransactionOutput
+		if partial && n.RefundSettlesOrder(contract, refundMsg) {
+			refundMsg.Payout = &pb.OrderFulfillment_Payout{
+				PayoutAddress:    n.Wallet.CurrentAddress(spvwallet.EXTERNAL).EncodeAddress(),
+				PayoutFeePerByte: contract.BuyerOrder.RefundFee,
+			}
+			outputs, err = n.SettlingRefundOutputs(contract, int64(refundMsg.Amount), outValue, refundMsg.Payout.PayoutAddress, len(ins), contract.BuyerOrder.RefundFee)
+		} else if partial {
+			outputs, err = n.PartialRefundOutputs(contract, int64(refundMsg.Amount), outValue)
+		} else {
+			outputs, err = n.EscrowOutputs(contract, contract.BuyerOrder.RefundAddress, outValue, len(ins), contract.BuyerOrder.RefundFee)
+		}
 		if err != nil {
 			return err
 		}
-		output.ScriptPubKey = outputScript
-		output.Value = outValue
 
 		chaincode, err := hex.DecodeString(contract.BuyerOrder.Payment.Chaincode)
 		if err != nil {
@@ -75,7 +89,7 @@ func (n *OpenBazaarNode) RefundOrder(contract *pb.RicardianContract, records []*
 		}
 		redeemScript, err := hex.DecodeString(contract.BuyerOrder.Payment.RedeemScript)
 
-		signatures, err := n.Wallet.CreateMultisigSignature(ins, []spvwallet.TransactionOutput{output}, vendorKey, redeemScript, contract.BuyerOrder.RefundFee)
+		signatures, err := n.Wallet.CreateMultisigSignature(ins, outputs, vendorKey, redeemScript, contract.BuyerOrder.RefundFee)
 		if err != nil {
 			return err
 		}
@@ -86,12 +100,9 @@ func (n *OpenBazaarNode) RefundOrder(contract *pb.RicardianContract, records []*
 		}
 		refundMsg.Sigs = sigs
 	} else {
-		var outValue int64
-		for _, r := range records {
-			log.Notice(r.Value)
-			if r.Value > 0 {
-				outValue += r.Value
-			}
+		outValue := remaining
+		if partial {
+			outValue = int64(refundMsg.Amount)
 		}
 		refundAddr, err := btcutil.DecodeAddress(contract.BuyerOrder.RefundAddress, n.Wallet.Params())
 		if err != nil {
@@ -102,13 +113,135 @@ func (n *OpenBazaarNode) RefundOrder(contract *pb.RicardianContract, records []*
 			return err
 		}
 	}
-	contract.Refund = refundMsg
-	contract, err = n.SignRefund(contract)
+	rc := new(pb.RicardianContract)
+	rc.Refund = refundMsg
+	rc, err = n.SignRefund(rc)
+	if err != nil {
+		return err
+	}
+	n.SendRefund(contract.BuyerOrder.BuyerID.Guid, rc)
+
+	for _, sig := range rc.Signatures {
+		if sig.Section == pb.Signature_REFUND {
+			contract.Signatures = append(contract.Signatures, sig)
+		}
+	}
+	if partial {
+		contract.PartialRefunds = append(contract.PartialRefunds, refundMsg)
+		n.Datastore.Sales().Put(orderId, *contract, n.PartialOrderState(contract), true)
+	} else {
+		contract.Refund = refundMsg
+		n.Datastore.Sales().Put(orderId, *contract, pb.OrderState_REFUNDED, true)
+	}
+	return nil
+}
+
+// The value which can still be refunded to the buyer. For moderated orders this is whatever is left
+// in escrow. For direct orders it is the amount paid minus any partial refunds already sent.
+func refundableValue(contract *pb.RicardianContract, records []*spvwallet.TransactionRecord) int64 {
+	var value int64
+	if contract.BuyerOrder.Payment.Method == pb.Order_Payment_MODERATED {
+		for _, r := range records {
+			if !r.Spent && r.Value > 0 {
+				value += r.Value
+			}
+		}
+		return value
+	}
+	for _, r := range records {
+		if r.Value > 0 {
+			value += r.Value
+		}
+	}
+	for _, refund := range contract.PartialRefunds {
+		value -= int64(refund.Amount)
+	}
+	return value
+}
+
+// Make sure the refunded items are in the order and are not refunded more times than they were ordered
+func validateRefundItems(contract *pb.RicardianContract, items []*pb.Refund_Item) error {
+	refunded := make(map[string]uint32)
+	for _, refund := range contract.PartialRefunds {
+		for _, item := range refund.Items {
+			refunded[item.ListingHash] += item.Quantity
+		}
+	}
+	for _, item := range items {
+		if item.Quantity == 0 {
+			return errors.New("Refunded item quantity must be greater than zero")
+		}
+		var ordered uint32
+		for _, orderItem := range contract.BuyerOrder.Items {
+			if orderItem.ListingHash == item.ListingHash {
+				ordered += orderItem.Quantity
+			}
+		}
+		if ordered == 0 {
+			return fmt.Errorf("Refunded item %s is not in the order", item.ListingHash)
+		}
+		refunded[item.ListingHash] += item.Quantity
+		if refunded[item.ListingHash] > ordered {
+			return fmt.Errorf("Refunded quantity of item %s exceeds the quantity ordered", item.ListingHash)
+		}
+	}
+	return nil
+}
+
+// Check a refund received from the vendor before the buyer signs or records it. The refunded items
+// must be in the order and a partial refund must leave something in the order.
+func (n *OpenBazaarNode) ValidateRefund(contract *pb.RicardianContract, records []*spvwallet.TransactionRecord, refund *pb.Refund) error {
+	if contract.Refund != nil {
+		return errors.New("Order has already been refunded")
+	}
+	if err := validateRefundItems(contract, refund.Items); err != nil {
+		return err
+	}
+	remaining := refundableValue(contract, records)
+	if remaining <= 0 {
+		return errors.New("Nothing is left in the order to refund")
+	}
+	if refund.Amount >= uint64(remaining) {
+		return errors.New("Partial refund amount must be less than the value left in the order")
+	}
+	return nil
+}
+
+// Check the outputs of a refund from escrow spend no more than the escrowed value and pay the buyer
+// at least the amount refunded. A full refund pays the buyer everything except the moderator's fee.
+func (n *OpenBazaarNode) ValidateRefundOutputs(contract *pb.RicardianContract, refund *pb.Refund, outputs []spvwallet.TransactionOutput, value int64) error {
+	refundAddr, err := btcutil.DecodeAddress(contract.BuyerOrder.RefundAddress, n.Wallet.Params())
+	if err != nil {
+		return err
+	}
+	refundScript, err := txscript.PayToAddrScript(refundAddr)
 	if err != nil {
 		return err
 	}
-	n.SendRefund(contract.BuyerOrder.BuyerID.Guid, contract)
-	n.Datastore.Sales().Put(orderId, *contract, pb.OrderState_REFUNDED, true)
+	minRefund := int64(refund.Amount)
+	if refund.Amount == 0 {
+		minRefund = value - int64(contract.BuyerOrder.Payment.ModeratorFee)
+	}
+	return checkRefundOutputs(outputs, value, refundScript, minRefund)
+}
+
+func checkRefundOutputs(outputs []spvwallet.TransactionOutput, value int64, refundScript []byte, minRefund int64) error {
+	var total, refunded int64
+	for _, out := range outputs {
+		if out.Value <= 0 {
+			return errors.New("Refund has an output with no value")
+		}
+		total += out.Value
+		if bytes.Equal(out.ScriptPubKey, refundScript) {
+			refunded += out.Value
+		}
+	}
+	if total > value {
+		return errors.New("Refund spends more than the value held in escrow")
+	}
+	if refunded < minRefund {
+		return errors.New("Refund pays the buyer less than the amount refunded")
+	}
 	return nil
 }
 
@@ -131,11 +264,12 @@ func (n *OpenBazaarNode) SignRefund(contract *pb.RicardianContract) (*pb.Ricardi
 	return contract, nil
 }
 
-func (n *OpenBazaarNode) VerifySignaturesOnRefund(contract *pb.RicardianContract) error {
+// Verify the vendor's signature on the refund in rc against the vendor ID in the order's contract
+func (n *OpenBazaarNode) VerifySignaturesOnRefund(rc *pb.RicardianContract, contract *pb.RicardianContract) error {
 	if err := verifyMessageSignature(
-		contract.Refund,
+		rc.Refund,
 		contract.VendorListings[0].VendorID.Pubkeys.Guid,
-		contract.Signatures,
+		rc.Signatures,
 		pb.Signature_REFUND,
 		contract.VendorListings[0].VendorID.Guid,
 	); err != nil {
@@ -0,0 +1,113 @@
+package core
+
+import (
+	"testing"
+
+	"github.com/OpenBazaar/openbazaar-go/pb"
+	"github.com/OpenBazaar/spvwallet"
+)
+
+func TestValidateRefundItems(t *testing.T) {
+	contract, hashA, hashB := testFulfillmentContract(t, pb.Order_Payment_MODERATED)
+	contract.PartialRefunds = []*pb.Refund{{Items: []*pb.Refund_Item{{ListingHash: hashA, Quantity: 1}}}}
+	tests := []struct {
+		name  string
+		items []*pb.Refund_Item
+		valid bool
+	}{
+		{"no items", nil, true},
+		{"rest of a", []*pb.Refund_Item{{ListingHash: hashA, Quantity: 1}}, true},
+		{"all of b", []*pb.Refund_Item{{ListingHash: hashB, Quantity: 1}}, true},
+		{"more of a than is left", []*pb.Refund_Item{{ListingHash: hashA, Quantity: 2}}, false},
+		{"a twice", []*pb.Refund_Item{{ListingHash: hashA, Quantity: 1}, {ListingHash: hashA, Quantity: 1}}, false},
+		{"more of b than ordered", []*pb.Refund_Item{{ListingHash: hashB, Quantity: 2}}, false},
+		{"zero quantity", []*pb.Refund_Item{{ListingHash: hashB, Quantity: 0}}, false},
+		{"not in the order", []*pb.Refund_Item{{ListingHash: "QmOther", Quantity: 1}}, false},
+	}
+	for _, test := range tests {
+		err := validateRefundItems(contract, test.items)
+		if test.valid && err != nil {
+			t.Errorf("%s: %s", test.name, err)
+		} else if !test.valid && err == nil {
+			t.Errorf("%s: expected an error", test.name)
+		}
+	}
+}
+
+func TestRefundableValue(t *testing.T) {
+	records := []*spvwallet.TransactionRecord{
+		{Txid: "a", Value: 60000},
+		{Txid: "b", Value: 40000, Spent: true},
+		{Txid: "c", Value: -40000},
+		{Txid: "c", Value: 30000},
+	}
+	moderated, _, _ := testFulfillmentContract(t, pb.Order_Payment_MODERATED)
+	moderated.PartialRefunds = []*pb.Refund{{Amount: 10000}}
+	// Whatever is left unspent in escrow
+	if value := refundableValue(moderated, records); value != 90000 {
+		t.Errorf("Expected 90000 refundable from escrow, got %d", value)
+	}
+	direct, _, _ := testFulfillmentContract(t, pb.Order_Payment_DIRECT)
+	direct.PartialRefunds = []*pb.Refund{{Amount: 10000}, {Amount: 5000}}
+	// Everything paid less what has already been refunded
+	if value := refundableValue(direct, records); value != 115000 {
+		t.Errorf("Expected 115000 refundable from a direct order, got %d", value)
+	}
+}
+
+func TestValidateRefund(t *testing.T) {
+	contract, hashA, _ := testFulfillmentContract(t, pb.Order_Payment_MODERATED)
+	records := []*spvwallet.TransactionRecord{{Txid: "a", Value: 100000}}
+	n := new(OpenBazaarNode)
+	tests := []struct {
+		name   string
+		refund *pb.Refund
+		valid  bool
+	}{
+		{"full refund", &pb.Refund{}, true},
+		{"partial refund", &pb.Refund{Amount: 50000, Items: []*pb.Refund_Item{{ListingHash: hashA, Quantity: 1}}}, true},
+		{"partial refund of everything", &pb.Refund{Amount: 100000}, false},
+		{"partial refund of more than escrowed", &pb.Refund{Amount: 200000}, false},
+		{"invalid items", &pb.Refund{Amount: 50000, Items: []*pb.Refund_Item{{ListingHash: hashA, Quantity: 3}}}, false},
+	}
+	for _, test := range tests {
+		err := n.ValidateRefund(contract, records, test.refund)
+		if test.valid && err != nil {
+			t.Errorf("%s: %s", test.name, err)
+		} else if !test.valid && err == nil {
+			t.Errorf("%s: expected an error", test.name)
+		}
+	}
+	if err := n.ValidateRefund(contract, []*spvwallet.TransactionRecord{{Txid: "a", Value: 100000, Spent: true}}, &pb.Refund{}); err == nil {
+		t.Error("Expected an error refunding an order with nothing left in escrow")
+	}
+	contract.Refund = &pb.Refund{}
+	if err := n.ValidateRefund(contract, records, &pb.Refund{}); err == nil {
+		t.Error("Expected an error refunding an order twice")
+	}
+}
+
+func TestCheckRefundOutputs(t *testing.T) {
+	refundScript := testPayToPubKeyHash(t, 1)
+	otherScript := testPayToPubKeyHash(t, 2)
+	tests := []struct {
+		name    string
+		outputs []spvwallet.TransactionOutput
+		valid   bool
+	}{
+		{"refund and change", []spvwallet.TransactionOutput{{ScriptPubKey: refundScript, Value: 40000}, {ScriptPubKey: otherScript, Value: 60000}}, true},
+		{"refund less than escrowed", []spvwallet.TransactionOutput{{ScriptPubKey: refundScript, Value: 40000}}, true},
+		{"spends more than escrowed", []spvwallet.TransactionOutput{{ScriptPubKey: refundScript, Value: 40000}, {ScriptPubKey: otherScript, Value: 60001}}, false},
+		{"refund too small", []spvwallet.TransactionOutput{{ScriptPubKey: refundScript, Value: 39999}, {ScriptPubKey: otherScript, Value: 60000}}, false},
+		{"refund to someone else", []spvwallet.TransactionOutput{{ScriptPubKey: otherScript, Value: 100000}}, false},
+		{"output with no value", []spvwallet.TransactionOutput{{ScriptPubKey: refundScript, Value: 40000}, {ScriptPubKey: otherScript, Value: 0}}, false},
+	}
+	for _, test := range tests {
+		err := checkRefundOutputs(test.outputs, 100000, refundScript, 40000)
+		if test.valid && err != nil {
+			t.Errorf("%s: %s", test.name, err)
+		} else if !test.valid && err == nil {
+			t.Errorf("%s: expected an error", test.name)
+		}
+	}
+}
@@ -0,0 +1,102 @@
+package core
+
+import (
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"path"
+	"time"
+
+	"github.com/OpenBazaar/openbazaar-go/ipfs"
+	"github.com/OpenBazaar/openbazaar-go/net"
+	"github.com/OpenBazaar/openbazaar-go/repo"
+	ipfspath "github.com/ipfs/go-ipfs/path"
+	peer "gx/ipfs/QmRBqJF7hb8ZSpRcMwUt8hNhydWcxGEhtk81HKq6oUwKvs/go-libp2p-peer"
+)
+
+const (
+	// How often the signed prekey in our bundle is replaced
+	PrekeyRotationInterval = 7 * 24 * time.Hour
+
+	// How often to check whether the prekey is due to be replaced
+	prekeyCheckInterval = 24 * time.Hour
+
+	// Offline messages expire after 30 days and a peer can keep starting sessions with a
+	// replaced prekey until it gives up waiting for a reply, so replaced prekeys are kept
+	// for both before being deleted. Deleting them is what makes the first messages of a
+	// session unreadable to anyone who later gets hold of our keys.
+	prekeyRetention = 30*24*time.Hour + net.MaxPendingSessionAge
+)
+
+// Replace the signed prekey if it is due, delete the replaced ones we no longer need and
+// write our prekey bundle to the root directory. Returns true if the prekey was replaced.
+func (n *OpenBazaarNode) UpdatePrekeys() (bool, error) {
+	store := n.Datastore.Prekeys()
+	id, prekey, created, err := store.GetLatest()
+	if err != nil && err != repo.ErrPrekeyNotFound {
+		return false, err
+	}
+	rotated := err == repo.ErrPrekeyNotFound || time.Since(created) > PrekeyRotationInterval
+	if rotated {
+		prekey, err = net.GeneratePrekey()
+		if err != nil {
+			return false, err
+		}
+		id++
+		if err := store.Put(id, prekey, time.Now()); err != nil {
+			return false, err
+		}
+	}
+	if err := store.DeleteSuperseded(time.Now().Add(-prekeyRetention)); err != nil {
+		return false, err
+	}
+
+	bundle, err := n.Sessions.PrekeyBundle(id, prekey)
+	if err != nil {
+		return false, err
+	}
+	b, err := json.MarshalIndent(bundle, "", "    ")
+	if err != nil {
+		return false, err
+	}
+	if err := ioutil.WriteFile(path.Join(n.RepoPath, "root", "prekeys"), b, 0644); err != nil {
+		return false, err
+	}
+	return rotated, nil
+}
+
+// Replace the signed prekey as it comes due and republish the bundle
+func (n *OpenBazaarNode) RunPrekeyRotation() {
+	tick := time.NewTicker(prekeyCheckInterval)
+	defer tick.Stop()
+	for range tick.C {
+		rotated, err := n.UpdatePrekeys()
+		if err != nil {
+			log.Errorf("Error rotating prekey: %s", err.Error())
+			continue
+		}
+		if rotated {
+			if err := n.SeedNode(); err != nil {
+				log.Error(err)
+			}
+		}
+	}
+}
+
+// Returned when a peer's directory has no prekey bundle, as with peers running older versions
+var ErrNoPrekeyBundle = errors.New("peer has not published a prekey bundle")
+
+// Fetch the prekey bundle published by the given peer. The caller must verify it.
+func (n *OpenBazaarNode) GetPrekeyBundle(peerId peer.ID) (*net.PrekeyBundle, error) {
+	b, err := ipfs.ResolveThenCat(n.Context, ipfspath.FromString(peerId.Pretty()+"/prekeys"))
+	if ipfs.IsNoLink(err) {
+		return nil, ErrNoPrekeyBundle
+	} else if err != nil {
+		return nil, err
+	}
+	bundle := new(net.PrekeyBundle)
+	if err := json.Unmarshal(b, bundle); err != nil {
+		return nil, err
+	}
+	return bundle, nil
+}
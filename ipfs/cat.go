@@ -4,6 +4,7 @@ import (
 	"github.com/ipfs/go-ipfs/commands"
 	"github.com/ipfs/go-ipfs/path"
 	"io"
+	"strings"
 	"time"
 )
 
@@ -32,6 +33,12 @@ func Cat(ctx commands.Context, hash string) ([]byte, error) {
 	return b, nil
 }
 
+// Returns true if the error says the path doesn't exist in the directory it was resolved
+// in. Errors from commands only keep their message so the message is what is checked.
+func IsNoLink(err error) bool {
+	return err != nil && strings.HasPrefix(err.Error(), "no link named ")
+}
+
 func ResolveThenCat(ctx commands.Context, ipnsPath path.Path) ([]byte, error) {
 	var ret []byte
 	hash, err := Resolve(ctx, ipnsPath.Segments()[0])
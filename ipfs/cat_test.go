@@ -0,0 +1,34 @@
+package ipfs
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/ipfs/go-ipfs/commands"
+	"github.com/ipfs/go-ipfs/path"
+	mh "gx/ipfs/QmYf7ng2hG5XBtJA3tN34DQ2GUN5HNksEw1rLDkmr6vGku/go-multihash"
+)
+
+func TestIsNoLink(t *testing.T) {
+	node, err := mh.FromB58String("QmS3Gtxkc5mdTycRquLfdeZJt2udY2RZ49SCy81abZjHGj")
+	if err != nil {
+		t.Fatal(err)
+	}
+	noLink := path.ErrNoLink{Name: "prekeys", Node: node}
+	tests := []struct {
+		err    error
+		noLink bool
+	}{
+		{noLink, true},
+		// Commands return the error as a message, which is what Cat passes on
+		{&commands.Error{Message: noLink.Error(), Code: commands.ErrNormal}, true},
+		{&commands.Error{Message: "context deadline exceeded", Code: commands.ErrNormal}, false},
+		{errors.New("routing: not found"), false},
+		{nil, false},
+	}
+	for _, test := range tests {
+		if IsNoLink(test.err) != test.noLink {
+			t.Errorf("IsNoLink(%v) = %t", test.err, !test.noLink)
+		}
+	}
+}
@@ -34,6 +34,10 @@ const (
 	MODERATOR Purpose = 2
 	TAG       Purpose = 3
 	CHANNEL   Purpose = 4
+
+	// An offline ack. These are saved so the stored ack can be deleted when the
+	// pointer expires but unlike messages they are never republished.
+	ACK Purpose = 5
 )
 
 /* A pointer is a custom provider inserted into the DHT which points to a location of a file.
@@ -46,6 +50,9 @@ type Pointer struct {
 	Value     ps.PeerInfo
 	Purpose   Purpose
 	Timestamp time.Time
+
+	// The peer a message or ack was left for. Only that peer can acknowledge it.
+	Recipient peer.ID
 }
 
 func PublishPointer(node *core.IpfsNode, ctx context.Context, mhKey multihash.Multihash, prefixLen int, addr ma.Multiaddr) (Pointer, error) {
@@ -0,0 +1,281 @@
+package ipfs
+
+import (
+	"os"
+	gopath "path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	key "github.com/ipfs/go-ipfs/blocks/key"
+	"github.com/ipfs/go-ipfs/commands"
+	"github.com/ipfs/go-ipfs/importer"
+	"github.com/ipfs/go-ipfs/importer/chunk"
+	dag "github.com/ipfs/go-ipfs/merkledag"
+	dagutils "github.com/ipfs/go-ipfs/merkledag/utils"
+	"github.com/ipfs/go-ipfs/mfs"
+	"github.com/ipfs/go-ipfs/unixfs"
+	uio "github.com/ipfs/go-ipfs/unixfs/io"
+)
+
+// Keeps a unixfs DAG in step with a directory on disk. Each update only hashes the
+// files which have been added or changed since the last one and patches them into
+// the DAG, so the cost of an update depends on the size of the change rather than
+// the size of the directory. The hashes match those from AddDirectory.
+type DirectoryTree struct {
+	path     string
+	lock     sync.Mutex
+	root     *mfs.Root
+	rootHash string
+	files    map[string]treeEntry
+}
+
+// What a path looked like when it was last added. Directories have no hash.
+type treeEntry struct {
+	size    int64
+	modTime time.Time
+	mode    os.FileMode
+	hash    string
+	info    os.FileInfo
+	racy    bool
+}
+
+// The coarsest modification time resolution of the filesystems we expect to run on. A file
+// modified within this long of an update may be changed again without its size or time
+// changing so it is hashed again on the next update.
+const modTimeResolution = 2 * time.Second
+
+// Whether the file may have changed since it was added. Besides the size, time and mode the
+// file must be the same one on disk, so a file replaced by a rename is always hashed again.
+func (e treeEntry) changed(info os.FileInfo) bool {
+	return e.racy || e.size != info.Size() || !e.modTime.Equal(info.ModTime()) || e.mode != info.Mode() || !os.SameFile(e.info, info)
+}
+
+func NewDirectoryTree(fpath string) *DirectoryTree {
+	return &DirectoryTree{path: fpath}
+}
+
+// Patch the changes made to the directory since the last update into the DAG, pin
+// it and return its hash. The first update adds the whole directory. The previous
+// root is left pinned; unpinning it releases only the blocks which are no longer
+// referenced since the new root shares the rest.
+func (t *DirectoryTree) Update(ctx commands.Context) (string, error) {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+	n, err := ctx.ConstructNode()
+	if err != nil {
+		return "", err
+	}
+	defer n.Blockstore.PinLock().Unlock()
+
+	if t.root == nil {
+		root, err := mfs.NewRoot(n.Context(), n.DAG, unixfs.EmptyDirNode(), nil)
+		if err != nil {
+			return "", err
+		}
+		t.root = root
+		t.files = make(map[string]treeEntry)
+	}
+
+	start := time.Now()
+	seen := make(map[string]bool)
+	err = filepath.Walk(t.path, func(fpath string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(t.path, fpath)
+		if err != nil {
+			return err
+		}
+		if rel == "." {
+			return nil
+		}
+		// Hidden files are skipped by ipfs add
+		if name := info.Name(); strings.HasPrefix(name, ".") && len(name) > 1 {
+			if info.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		rel = "/" + filepath.ToSlash(rel)
+		seen[rel] = true
+
+		entry := treeEntry{
+			size:    info.Size(),
+			modTime: info.ModTime(),
+			mode:    info.Mode(),
+			info:    info,
+			racy:    !info.ModTime().Before(start.Add(-modTimeResolution)),
+		}
+		prev, ok := t.files[rel]
+		if info.IsDir() {
+			if ok && prev.mode.IsDir() {
+				return nil
+			}
+			if ok {
+				if err := t.remove(rel); err != nil {
+					return err
+				}
+			}
+			if err := mfs.Mkdir(t.root, rel, true, false); err != nil {
+				return err
+			}
+			t.files[rel] = entry
+			return nil
+		}
+		if ok && !prev.changed(info) {
+			return nil
+		}
+		nd, err := importPath(n.DAG, fpath, info)
+		if err != nil {
+			return err
+		}
+		k, err := nd.Key()
+		if err != nil {
+			return err
+		}
+		entry.hash = k.B58String()
+		if ok {
+			if err := t.remove(rel); err != nil {
+				return err
+			}
+		}
+		if err := mfs.PutNode(t.root, rel, nd); err != nil {
+			return err
+		}
+		t.files[rel] = entry
+		return nil
+	})
+	if err != nil {
+		return "", err
+	}
+
+	// Remove whatever has been deleted. Parents sort before their children so
+	// anything inside a deleted directory goes with it.
+	var deleted []string
+	for rel := range t.files {
+		if !seen[rel] {
+			deleted = append(deleted, rel)
+		}
+	}
+	sort.Strings(deleted)
+	for _, rel := range deleted {
+		if _, ok := t.files[rel]; !ok {
+			continue
+		}
+		if err := t.remove(rel); err != nil {
+			return "", err
+		}
+	}
+
+	if err := t.root.Flush(); err != nil {
+		return "", err
+	}
+	nd, err := t.root.GetValue().GetNode()
+	if err != nil {
+		return "", err
+	}
+	if err := n.Pinning.Pin(n.Context(), nd, true); err != nil {
+		return "", err
+	}
+	if err := n.Pinning.Flush(); err != nil {
+		return "", err
+	}
+	k, err := nd.Key()
+	if err != nil {
+		return "", err
+	}
+	t.rootHash = k.B58String()
+	return t.rootHash, nil
+}
+
+// Return the root hash from the last update along with the hash of each file in it
+// by path. Directories are included with an empty hash. The root hash is empty if
+// the tree has not been updated yet.
+func (t *DirectoryTree) Snapshot() (string, map[string]string) {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+	paths := make(map[string]string)
+	for rel, entry := range t.files {
+		paths[rel] = entry.hash
+	}
+	return t.rootHash, paths
+}
+
+// Return the hash of a directory built the way a writable gateway builds one when each
+// file, given by path and hash, is put into an empty directory. Directories are only made
+// on the way to a file so empty ones are left out.
+func BuildDirectory(ctx commands.Context, files map[string]string) (string, error) {
+	n, err := ctx.ConstructNode()
+	if err != nil {
+		return "", err
+	}
+	e := dagutils.NewDagEditor(uio.NewEmptyDirectory(), n.DAG)
+	for p, hash := range files {
+		if hash == "" {
+			continue
+		}
+		nd, err := n.DAG.Get(n.Context(), key.B58KeyDecode(hash))
+		if err != nil {
+			return "", err
+		}
+		if err := e.InsertNodeAtPath(n.Context(), strings.TrimPrefix(p, "/"), nd, uio.NewEmptyDirectory); err != nil {
+			return "", err
+		}
+	}
+	nd, err := e.Finalize(n.DAG)
+	if err != nil {
+		return "", err
+	}
+	k, err := nd.Key()
+	if err != nil {
+		return "", err
+	}
+	return k.B58String(), nil
+}
+
+// Unlink a path and anything beneath it from the DAG
+func (t *DirectoryTree) remove(rel string) error {
+	dir, name := gopath.Split(rel)
+	parent, err := mfs.Lookup(t.root, dir)
+	if err != nil {
+		return err
+	}
+	d, ok := parent.(*mfs.Directory)
+	if !ok {
+		return os.ErrNotExist
+	}
+	for other := range t.files {
+		if other == rel || strings.HasPrefix(other, rel+"/") {
+			delete(t.files, other)
+		}
+	}
+	return d.Unlink(name)
+}
+
+// Build the DAG for a file or symlink the same way ipfs add does
+func importPath(ds dag.DAGService, fpath string, info os.FileInfo) (*dag.Node, error) {
+	if info.Mode()&os.ModeSymlink != 0 {
+		target, err := os.Readlink(fpath)
+		if err != nil {
+			return nil, err
+		}
+		data, err := unixfs.SymlinkData(target)
+		if err != nil {
+			return nil, err
+		}
+		nd := dag.NodeWithData(data)
+		if _, err := ds.Add(nd); err != nil {
+			return nil, err
+		}
+		return nd, nil
+	}
+	f, err := os.Open(fpath)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return importer.BuildDagFromReader(ds, chunk.DefaultSplitter(f))
+}
@@ -0,0 +1,189 @@
+package ipfs
+
+import (
+	"io/ioutil"
+	"os"
+	"path"
+	"testing"
+	"time"
+)
+
+func TestDirectoryTreeMatchesAdd(t *testing.T) {
+	ctx, err := MockCmdsCtx()
+	if err != nil {
+		t.Fatal(err)
+	}
+	hash, err := NewDirectoryTree(path.Join("./", "root")).Update(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if hash != "QmbuHqv8yQDwSsLvK4wGEBBXAYiqzXn23yqU9rh1tYwJSb" {
+		t.Error("Directory tree hash does not match ipfs add")
+	}
+}
+
+func TestDirectoryTreeUpdate(t *testing.T) {
+	ctx, err := MockCmdsCtx()
+	if err != nil {
+		t.Fatal(err)
+	}
+	dir := path.Join("./", "tree")
+	os.MkdirAll(path.Join(dir, "listings"), os.ModePerm)
+	defer os.RemoveAll(dir)
+	ioutil.WriteFile(path.Join(dir, "profile"), []byte("profile"), 0644)
+	ioutil.WriteFile(path.Join(dir, "listings", "a.json"), []byte("listing a"), 0644)
+	ioutil.WriteFile(path.Join(dir, ".hidden"), []byte("hidden"), 0644)
+
+	// Each incremental update should give the same hash as adding the directory from scratch
+	tree := NewDirectoryTree(dir)
+	check := func(step string) {
+		hash, err := tree.Update(ctx)
+		if err != nil {
+			t.Fatalf("%s: %s", step, err)
+		}
+		expected, err := NewDirectoryTree(dir).Update(ctx)
+		if err != nil {
+			t.Fatalf("%s: %s", step, err)
+		}
+		if hash != expected {
+			t.Errorf("%s: expected %s, got %s", step, expected, hash)
+		}
+	}
+	check("initial add")
+
+	ioutil.WriteFile(path.Join(dir, "profile"), []byte("updated profile"), 0644)
+	os.MkdirAll(path.Join(dir, "images", "tiny"), os.ModePerm)
+	ioutil.WriteFile(path.Join(dir, "images", "tiny", "img"), []byte("image"), 0644)
+	check("changed and added files")
+
+	os.Remove(path.Join(dir, "listings", "a.json"))
+	os.RemoveAll(path.Join(dir, "images"))
+	check("deleted files")
+
+	os.Remove(path.Join(dir, "profile"))
+	os.MkdirAll(path.Join(dir, "profile"), os.ModePerm)
+	check("file replaced with directory")
+}
+
+func TestDirectoryTreeSameSizeAndTime(t *testing.T) {
+	ctx, err := MockCmdsCtx()
+	if err != nil {
+		t.Fatal(err)
+	}
+	dir := path.Join("./", "sametime")
+	os.MkdirAll(dir, os.ModePerm)
+	defer os.RemoveAll(dir)
+	file := path.Join(dir, "profile")
+	ioutil.WriteFile(file, []byte("profile a"), 0644)
+
+	tree := NewDirectoryTree(dir)
+	check := func(step string) {
+		hash, err := tree.Update(ctx)
+		if err != nil {
+			t.Fatalf("%s: %s", step, err)
+		}
+		expected, err := NewDirectoryTree(dir).Update(ctx)
+		if err != nil {
+			t.Fatalf("%s: %s", step, err)
+		}
+		if hash != expected {
+			t.Errorf("%s: expected %s, got %s", step, expected, hash)
+		}
+	}
+	check("initial add")
+
+	// Rewritten in place within the modification time resolution of the last update
+	info, err := os.Stat(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ioutil.WriteFile(file, []byte("profile b"), 0644)
+	os.Chtimes(file, info.ModTime(), info.ModTime())
+	check("rewritten with the same size and time")
+
+	// Replaced by a rename long after the last update
+	old := time.Now().Add(-time.Hour)
+	os.Chtimes(file, old, old)
+	check("old modification time")
+	tmp := path.Join(dir, ".profile.tmp")
+	ioutil.WriteFile(tmp, []byte("profile c"), 0644)
+	os.Chtimes(tmp, old, old)
+	os.Rename(tmp, file)
+	check("replaced with the same size and time")
+}
+
+func TestDirectoryTreeSnapshot(t *testing.T) {
+	ctx, err := MockCmdsCtx()
+	if err != nil {
+		t.Fatal(err)
+	}
+	dir := path.Join("./", "snapshot")
+	os.MkdirAll(path.Join(dir, "listings"), os.ModePerm)
+	defer os.RemoveAll(dir)
+	ioutil.WriteFile(path.Join(dir, "profile"), []byte("profile"), 0644)
+
+	tree := NewDirectoryTree(dir)
+	if root, _ := tree.Snapshot(); root != "" {
+		t.Error("Returned a root before the first update")
+	}
+	hash, err := tree.Update(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	root, paths := tree.Snapshot()
+	if root != hash {
+		t.Errorf("Expected root %s, got %s", hash, root)
+	}
+	if len(paths) != 2 {
+		t.Errorf("Returned incorrect paths %v", paths)
+	}
+	if h, ok := paths["/listings"]; !ok || h != "" {
+		t.Error("Directory should be returned with an empty hash")
+	}
+	expected, err := AddFile(ctx, path.Join(dir, "profile"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if paths["/profile"] != expected {
+		t.Errorf("Expected file hash %s, got %s", expected, paths["/profile"])
+	}
+}
+
+func TestBuildDirectory(t *testing.T) {
+	ctx, err := MockCmdsCtx()
+	if err != nil {
+		t.Fatal(err)
+	}
+	dir := path.Join("./", "build")
+	os.MkdirAll(path.Join(dir, "listings"), os.ModePerm)
+	defer os.RemoveAll(dir)
+	ioutil.WriteFile(path.Join(dir, "profile"), []byte("profile"), 0644)
+	ioutil.WriteFile(path.Join(dir, "listings", "a.json"), []byte("listing a"), 0644)
+
+	tree := NewDirectoryTree(dir)
+	if _, err := tree.Update(ctx); err != nil {
+		t.Fatal(err)
+	}
+	root, paths := tree.Snapshot()
+	hash, err := BuildDirectory(ctx, paths)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if hash != root {
+		t.Errorf("Expected %s, got %s", root, hash)
+	}
+
+	// Empty directories are left out
+	os.MkdirAll(path.Join(dir, "ratings"), os.ModePerm)
+	if _, err := tree.Update(ctx); err != nil {
+		t.Fatal(err)
+	}
+	_, paths = tree.Snapshot()
+	hash, err = BuildDirectory(ctx, paths)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if hash != root {
+		t.Errorf("Expected %s, got %s", root, hash)
+	}
+}
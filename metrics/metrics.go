@@ -0,0 +1,63 @@
+package metrics
+
+import (
+	prometheus "gx/ipfs/QmdhsRK1EK2fvAz2i2SH5DEfkL6seDuyMYEsxKa9Braim3/client_golang/prometheus"
+)
+
+// Metrics recorded as things happen. They are registered with the default Prometheus
+// registry and are served at /metrics on the gateway if it is enabled in the config.
+// Values which can be read from the node at any time, such as the wallet balance,
+// are collected when scraped by the api package instead.
+var (
+	PublishDuration = prometheus.NewHistogram(prometheus.HistogramOpts{
+		Namespace: "openbazaar",
+		Subsystem: "publish",
+		Name:      "duration_seconds",
+		Help:      "Time taken to publish the node directory to IPNS",
+		Buckets:   []float64{1, 5, 10, 30, 60, 120, 300, 600},
+	})
+
+	PublishFailures = prometheus.NewCounter(prometheus.CounterOpts{
+		Namespace: "openbazaar",
+		Subsystem: "publish",
+		Name:      "failures_total",
+		Help:      "Number of failed publishes",
+	})
+
+	PendingPointers = prometheus.NewGauge(prometheus.GaugeOpts{
+		Namespace: "openbazaar",
+		Subsystem: "retriever",
+		Name:      "pending_pointers",
+		Help:      "Offline message pointers found in the DHT which are still being fetched",
+	})
+
+	RetrieverFetches = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "openbazaar",
+		Subsystem: "retriever",
+		Name:      "fetches_total",
+		Help:      "Offline messages fetched by the message retriever by transport and result",
+	}, []string{"transport", "result"})
+
+	HandlerDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: "openbazaar",
+		Subsystem: "service",
+		Name:      "handler_duration_seconds",
+		Help:      "Time taken to handle a message from another peer by message type",
+	}, []string{"type"})
+
+	APIRequestDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: "openbazaar",
+		Subsystem: "api",
+		Name:      "request_duration_seconds",
+		Help:      "Time taken to serve a JSON API request by route",
+	}, []string{"method", "route"})
+)
+
+func init() {
+	prometheus.MustRegister(PublishDuration)
+	prometheus.MustRegister(PublishFailures)
+	prometheus.MustRegister(PendingPointers)
+	prometheus.MustRegister(RetrieverFetches)
+	prometheus.MustRegister(HandlerDuration)
+	prometheus.MustRegister(APIRequestDuration)
+}
@@ -14,7 +14,8 @@ import (
 )
 
 const (
-	// The version of the encryption algorithm used. Currently only 1 is supported
+	// The version of the encryption algorithm which encrypts to the identity key. Messages
+	// encrypted with a session use SessionCiphertextVersion.
 	CiphertextVersion = 1
 
 	// Length of the serialized version in bytes
@@ -113,6 +114,8 @@ func Decrypt(privKey libp2p.PrivKey, ciphertext []byte) ([]byte, error) {
 	version := getCipherTextVersion(ciphertext)
 	if version == CiphertextVersion {
 		return decryptV1(privKey, ciphertext)
+	} else if version == SessionCiphertextVersion {
+		return nil, ErrSessionRequired
 	} else {
 		return nil, errors.New("Unknown ciphertext version")
 	}
@@ -3,23 +3,42 @@ package net
 import (
 	"github.com/OpenBazaar/openbazaar-go/ipfs"
 	"github.com/OpenBazaar/openbazaar-go/repo"
+	sto "github.com/OpenBazaar/openbazaar-go/storage"
 	"github.com/ipfs/go-ipfs/core"
+	"github.com/op/go-logging"
 	"golang.org/x/net/context"
+	"sync"
 	"time"
 )
 
+// Stored messages and acks are deleted once their pointers are this old
+const messageExpiry = time.Hour * 24 * 30
+
+var log = logging.MustGetLogger("repointer")
+
 type PointerRepublisher struct {
 	ipfsNode *core.IpfsNode
 	db       repo.Datastore
+	storage  sto.OfflineMessagingStorage
+	lastRun  time.Time
+	lock     sync.Mutex
 }
 
-func NewPointerRepublisher(node *core.IpfsNode, database repo.Datastore) *PointerRepublisher {
+func NewPointerRepublisher(node *core.IpfsNode, database repo.Datastore, storage sto.OfflineMessagingStorage) *PointerRepublisher {
 	return &PointerRepublisher{
 		ipfsNode: node,
 		db:       database,
+		storage:  storage,
 	}
 }
 
+// Return the time the pointers were last republished
+func (r *PointerRepublisher) LastRun() time.Time {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	return r.lastRun
+}
+
 func (r *PointerRepublisher) Run() {
 	tick := time.NewTicker(time.Hour * 24)
 	defer tick.Stop()
@@ -36,14 +55,22 @@ func (r *PointerRepublisher) republish() {
 	}
 	ctx := context.Background()
 	for _, p := range pointers {
-		if p.Purpose != ipfs.MESSAGE {
+		if p.Purpose != ipfs.MESSAGE && p.Purpose != ipfs.ACK {
 			ipfs.RePublishPointer(r.ipfsNode, ctx, p)
 		} else {
-			if time.Now().Sub(p.Timestamp) > time.Hour*24*30 {
+			if time.Now().Sub(p.Timestamp) > messageExpiry {
+				if r.storage != nil && len(p.Value.Addrs) > 0 {
+					if err := r.storage.Delete(p.Value.Addrs[0]); err != nil {
+						log.Errorf("Error deleting expired message %s: %s", p.Value.Addrs[0], err)
+					}
+				}
 				r.db.Pointers().Delete(p.Value.ID)
-			} else {
+			} else if p.Purpose == ipfs.MESSAGE {
 				ipfs.RePublishPointer(r.ipfsNode, ctx, p)
 			}
 		}
 	}
+	r.lock.Lock()
+	r.lastRun = time.Now()
+	r.lock.Unlock()
 }
@@ -1,7 +1,10 @@
 package net
 
 import (
+	"encoding/json"
+	"errors"
 	"github.com/OpenBazaar/openbazaar-go/ipfs"
+	"github.com/OpenBazaar/openbazaar-go/metrics"
 	"github.com/OpenBazaar/openbazaar-go/net"
 	"github.com/OpenBazaar/openbazaar-go/pb"
 	"github.com/OpenBazaar/openbazaar-go/repo"
@@ -23,6 +26,16 @@ import (
 
 var log = logging.MustGetLogger("retriever")
 
+// Orders in these states can still get messages from the other party
+var openOrderStates = []pb.OrderState{
+	pb.OrderState_PENDING,
+	pb.OrderState_CONFIRMED,
+	pb.OrderState_FUNDED,
+	pb.OrderState_PARTIALLY_FULFILLED,
+	pb.OrderState_FULFILLED,
+	pb.OrderState_DISPUTED,
+}
+
 type MessageRetriever struct {
 	db           repo.Datastore
 	node         *core.IpfsNode
@@ -30,33 +43,152 @@ type MessageRetriever struct {
 	service      net.NetworkService
 	prefixLen    int
 	sendAck      func(peerId string, pointerID peer.ID) error
+	sessions     *net.SessionCipher
+	config       repo.RetrieverConfig
+	broadcast    chan []byte
+	trigger      chan struct{}
+	stop         chan struct{}
+	stopped      chan struct{}
+	stopOnce     *sync.Once
 	messageQueue []pb.Envelope
 	queueLock    *sync.Mutex
+	status       RetrieverStatus
+	statusLock   *sync.Mutex
 	*sync.WaitGroup
 }
 
-func NewMessageRetriever(db repo.Datastore, ctx commands.Context, node *core.IpfsNode, service net.NetworkService, prefixLen int, sendAck func(peerId string, pointerID peer.ID) error) *MessageRetriever {
-	mr := MessageRetriever{db, node, ctx, service, prefixLen, sendAck, nil, new(sync.Mutex), new(sync.WaitGroup)}
+// The outcome of the last run of the message retriever
+type RetrieverStatus struct {
+	LastRun time.Time
+	Errors  []string
+}
+
+// Sent over the websocket as each run of the retriever progresses
+type RetrieverProgress struct {
+	State string `json:"state"` // started, fetching or finished
+
+	// The number of new messages found in the DHT and how many of those have been
+	// fetched or failed so far
+	Found   int `json:"found"`
+	Fetched int `json:"fetched"`
+	Failed  int `json:"failed"`
+
+	Errors  []string   `json:"errors,omitempty"`
+	NextRun *time.Time `json:"nextRun,omitempty"`
+}
+
+func NewMessageRetriever(db repo.Datastore, ctx commands.Context, node *core.IpfsNode, service net.NetworkService, prefixLen int, sendAck func(peerId string, pointerID peer.ID) error, sessions *net.SessionCipher, config repo.RetrieverConfig, broadcast chan []byte) *MessageRetriever {
+	mr := MessageRetriever{db, node, ctx, service, prefixLen, sendAck, sessions, config, broadcast, make(chan struct{}, 1), make(chan struct{}), make(chan struct{}), new(sync.Once), nil, new(sync.Mutex), RetrieverStatus{}, new(sync.Mutex), new(sync.WaitGroup)}
 	// Add one for initial wait at start up
 	mr.Add(1)
 	return &mr
 }
 
+// Check for messages straight away and then again after each interval. The runs never
+// overlap, and FetchNow starts the next one early.
 func (m *MessageRetriever) Run() {
-	tick := time.NewTicker(time.Hour)
-	defer tick.Stop()
-	go m.fetchPointers()
+	defer close(m.stopped)
 	for {
+		progress := m.fetchPointers()
+		interval := m.interval(progress.Found)
+		next := time.Now().Add(interval)
+		progress.State = "finished"
+		progress.NextRun = &next
+		m.sendProgress(progress)
+
+		timer := time.NewTimer(interval)
 		select {
-		case <-tick.C:
-			go m.fetchPointers()
+		case <-timer.C:
+		case <-m.trigger:
+			timer.Stop()
+		case <-m.stop:
+			timer.Stop()
+			return
 		}
 	}
 }
 
-func (m *MessageRetriever) fetchPointers() {
+// Stop checking for messages and wait for a run in progress to finish
+func (m *MessageRetriever) Stop() {
+	m.stopOnce.Do(func() { close(m.stop) })
+	<-m.stopped
+}
+
+// Check for messages now rather than waiting for the next run. If a run is in
+// progress another starts as soon as it finishes.
+func (m *MessageRetriever) FetchNow() {
+	select {
+	case m.trigger <- struct{}{}:
+	default:
+	}
+}
+
+// Check more often while there are open orders or messages are coming in, so replies
+// sent while we were offline are seen quickly, and back off when there's nothing going on
+func (m *MessageRetriever) interval(found int) time.Duration {
+	if found > 0 {
+		return m.config.ActiveInterval
+	}
+	purchases, err := m.db.Purchases().Count(openOrderStates...)
+	if err != nil {
+		log.Error(err)
+	}
+	sales, err := m.db.Sales().Count(openOrderStates...)
+	if err != nil {
+		log.Error(err)
+	}
+	if purchases+sales > 0 {
+		return m.config.ActiveInterval
+	}
+	return m.config.Interval
+}
+
+func (m *MessageRetriever) sendProgress(progress RetrieverProgress) {
+	if m.broadcast == nil {
+		return
+	}
+	b, err := json.MarshalIndent(struct {
+		MessageRetriever RetrieverProgress `json:"messageRetriever"`
+	}{progress}, "", "    ")
+	if err != nil {
+		return
+	}
+	m.broadcast <- b
+}
+
+// Return the time the retriever last finished checking for messages and the errors it hit
+func (m *MessageRetriever) Status() RetrieverStatus {
+	m.statusLock.Lock()
+	defer m.statusLock.Unlock()
+	return m.status
+}
+
+// Fetch the messages pointed to from the DHT and return how it went
+func (m *MessageRetriever) fetchPointers() RetrieverProgress {
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
+	progress := RetrieverProgress{State: "started"}
+	progressLock := new(sync.Mutex)
+	m.sendProgress(progress)
+	found := func() {
+		progressLock.Lock()
+		progress.Found++
+		progressLock.Unlock()
+	}
+	done := func(err error) {
+		progressLock.Lock()
+		if err != nil {
+			log.Errorf("Error retrieving offline message: %s", err.Error())
+			progress.Failed++
+			progress.Errors = append(progress.Errors, err.Error())
+		} else {
+			progress.Fetched++
+		}
+		progress.State = "fetching"
+		p := progress
+		progressLock.Unlock()
+		m.sendProgress(p)
+	}
 	wg := new(sync.WaitGroup)
 	wg.Add(1)
 	mh, _ := multihash.FromB58String(m.node.Identity.Pretty())
@@ -68,7 +200,9 @@ func (m *MessageRetriever) fetchPointers() {
 			// IPFS
 			if len(p.Addrs[0].Protocols()) == 1 && p.Addrs[0].Protocols()[0].Code == ma.P_IPFS {
 				wg.Add(1)
-				go m.fetchIPFS(p.ID, m.ctx, p.Addrs[0], wg)
+				found()
+				metrics.PendingPointers.Inc()
+				go m.fetchIPFS(p.ID, m.ctx, p.Addrs[0], wg, done)
 			}
 
 			// HTTPS
@@ -86,7 +220,9 @@ func (m *MessageRetriever) fetchPointers() {
 					continue
 				}
 				wg.Add(1)
-				go m.fetchHTTPS(p.ID, string(d.Digest), p.Addrs[0], wg)
+				found()
+				metrics.PendingPointers.Inc()
+				go m.fetchHTTPS(p.ID, string(d.Digest), p.Addrs[0], wg, done)
 			}
 		}
 	}
@@ -100,65 +236,79 @@ func (m *MessageRetriever) fetchPointers() {
 	}
 	m.messageQueue = []pb.Envelope{}
 
+	m.statusLock.Lock()
+	m.status = RetrieverStatus{time.Now(), progress.Errors}
+	m.statusLock.Unlock()
+
 	// For initial start up only
 	if m.WaitGroup != nil {
 		m.Done()
 		m.WaitGroup = nil
 	}
+	return progress
 }
 
-func (m *MessageRetriever) fetchIPFS(pid peer.ID, ctx commands.Context, addr ma.Multiaddr, wg *sync.WaitGroup) {
+func (m *MessageRetriever) fetchIPFS(pid peer.ID, ctx commands.Context, addr ma.Multiaddr, wg *sync.WaitGroup, done func(error)) {
 	defer wg.Done()
+	defer metrics.PendingPointers.Dec()
 	ciphertext, err := ipfs.Cat(ctx, addr.String())
 	if err != nil {
-		log.Errorf("Error retrieving offline message: %s", err.Error())
+		done(err)
+		metrics.RetrieverFetches.WithLabelValues("ipfs", "error").Inc()
 		return
 	}
+	metrics.RetrieverFetches.WithLabelValues("ipfs", "success").Inc()
 	m.attemptDecrypt(ciphertext, pid)
 	m.db.OfflineMessages().Put(addr.String())
+	done(nil)
 }
 
-func (m *MessageRetriever) fetchHTTPS(pid peer.ID, url string, addr ma.Multiaddr, wg *sync.WaitGroup) {
+func (m *MessageRetriever) fetchHTTPS(pid peer.ID, url string, addr ma.Multiaddr, wg *sync.WaitGroup, done func(error)) {
 	defer wg.Done()
+	defer metrics.PendingPointers.Dec()
 	resp, err := http.Get(url)
 	if err != nil {
-		log.Errorf("Error retrieving offline message: %s", err.Error())
+		done(err)
+		metrics.RetrieverFetches.WithLabelValues("https", "error").Inc()
 		return
 	}
 	ciphertext, err := ioutil.ReadAll(resp.Body)
 	if err != nil {
-		log.Errorf("Error retrieving offline message: %s", err.Error())
+		done(err)
+		metrics.RetrieverFetches.WithLabelValues("https", "error").Inc()
 		return
 	}
+	metrics.RetrieverFetches.WithLabelValues("https", "success").Inc()
 	m.attemptDecrypt(ciphertext, pid)
 	m.db.OfflineMessages().Put(addr.String())
+	done(nil)
 }
 
 func (m *MessageRetriever) attemptDecrypt(ciphertext []byte, pid peer.ID) {
-	// Decript and unmarshal plaintext
-	plaintext, err := net.Decrypt(m.node.PrivateKey, ciphertext)
-	if err != nil {
-		return
-	}
-
-	// Unmarshal plaintext
+	// Decrypt and unmarshal the plaintext then validate the signature. Session messages
+	// are checked against the session's peer before the session is updated.
 	env := pb.Envelope{}
-	err = proto.Unmarshal(plaintext, &env)
-	if err != nil {
-		return
-	}
-
-	// Validate the signature
-	ser, err := proto.Marshal(env.Message)
-	if err != nil {
-		return
-	}
-	pubkey, err := libp2p.UnmarshalPublicKey(env.Pubkey)
+	var pubkey libp2p.PubKey
+	_, err := m.sessions.Decrypt(ciphertext, func(plaintext []byte) (libp2p.PubKey, error) {
+		if err := proto.Unmarshal(plaintext, &env); err != nil {
+			return nil, err
+		}
+		ser, err := proto.Marshal(env.Message)
+		if err != nil {
+			return nil, err
+		}
+		pubkey, err = libp2p.UnmarshalPublicKey(env.Pubkey)
+		if err != nil {
+			return nil, err
+		}
+		valid, err := pubkey.Verify(ser, env.Signature)
+		if err != nil || !valid {
+			return nil, errors.New("Invalid envelope signature")
+		}
+		return pubkey, nil
+	})
 	if err != nil {
-		return
-	}
-	valid, err := pubkey.Verify(ser, env.Signature)
-	if err != nil || !valid {
+		log.Debugf("Could not decrypt offline message: %s", err.Error())
 		return
 	}
 
@@ -206,7 +356,9 @@ func (m *MessageRetriever) handleMessage(env pb.Envelope, id *peer.ID) {
 	}
 
 	// Dispatch handler
+	start := time.Now()
 	_, err := handler(*id, env.Message, true)
+	metrics.HandlerDuration.WithLabelValues(env.Message.MessageType.String()).Observe(time.Since(start).Seconds())
 	if err != nil {
 		log.Errorf("Handle message error: %s", err)
 		return
@@ -2,9 +2,11 @@ package service
 
 import (
 	"encoding/hex"
+	"errors"
 	"fmt"
 	"github.com/OpenBazaar/openbazaar-go/api/notifications"
 	"github.com/OpenBazaar/openbazaar-go/pb"
+	"github.com/OpenBazaar/openbazaar-go/repo"
 	"github.com/OpenBazaar/spvwallet"
 	"github.com/btcsuite/btcd/chaincfg/chainhash"
 	"github.com/btcsuite/btcd/txscript"
@@ -40,6 +42,8 @@ func (service *OpenBazaarService) HandlerForMsgType(t pb.Message_MessageType) fu
 		return service.handleOrderFulfillment
 	case pb.Message_ORDER_COMPLETION:
 		return service.handleOrderCompletion
+	case pb.Message_CONTRACT_REQUEST:
+		return service.handleContractRequest
 	default:
 		return nil
 	}
@@ -76,6 +80,24 @@ func (service *OpenBazaarService) handleOfflineAck(p peer.ID, pmes *pb.Message,
 	if err != nil {
 		return nil, err
 	}
+	pointer, err := service.datastore.Pointers().Get(pid)
+	if err == repo.ErrPointerNotFound {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+	// Only the peer the message was left for can say it has been received. Pointers saved
+	// before the recipient was recorded are left to expire.
+	if pointer.Recipient != p {
+		log.Warningf("Ignoring OFFLINE_ACK from %s for a message it was not sent", p.Pretty())
+		return nil, nil
+	}
+	// The message has been received so it no longer needs to be stored
+	if service.node.MessageStorage != nil && len(pointer.Value.Addrs) > 0 {
+		if err := service.node.MessageStorage.Delete(pointer.Value.Addrs[0]); err != nil {
+			log.Errorf("Error deleting acknowledged message %s: %s", pointer.Value.Addrs[0], err)
+		}
+	}
 	err = service.datastore.Pointers().Delete(pid)
 	if err != nil {
 		return nil, err
@@ -160,6 +182,10 @@ func (service *OpenBazaarService) handleOrder(peer peer.ID, pmes *pb.Message, op
 		if err != nil {
 			return errorResponse(err.Error()), err
 		}
+		err = service.node.ValidateModeratorFee(contract.BuyerOrder)
+		if err != nil {
+			return errorResponse(err.Error()), err
+		}
 		addr, err := btcutil.DecodeAddress(contract.BuyerOrder.Payment.Address, service.node.Wallet.Params())
 		if err != nil {
 			return errorResponse(err.Error()), err
@@ -349,17 +375,10 @@ func (service *OpenBazaarService) handleReject(p peer.ID, pmes *pb.Message, opti
 			}
 		}
 
-		refundAddress, err := btcutil.DecodeAddress(contract.BuyerOrder.RefundAddress, service.node.Wallet.Params())
-		if err != nil {
-			return nil, err
-		}
-		var output spvwallet.TransactionOutput
-		outputScript, err := txscript.PayToAddrScript(refundAddress)
+		outputs, err := service.node.EscrowOutputs(contract, contract.BuyerOrder.RefundAddress, outValue, len(ins), contract.BuyerOrder.RefundFee)
 		if err != nil {
 			return nil, err
 		}
-		output.ScriptPubKey = outputScript
-		output.Value = outValue
 
 		chaincode, err := hex.DecodeString(contract.BuyerOrder.Payment.Chaincode)
 		if err != nil {
@@ -389,7 +408,7 @@ func (service *OpenBazaarService) handleReject(p peer.ID, pmes *pb.Message, opti
 		}
 		redeemScript, err := hex.DecodeString(contract.BuyerOrder.Payment.RedeemScript)
 
-		buyerSignatures, err := service.node.Wallet.CreateMultisigSignature(ins, []spvwallet.TransactionOutput{output}, buyerKey, redeemScript, contract.BuyerOrder.RefundFee)
+		buyerSignatures, err := service.node.Wallet.CreateMultisigSignature(ins, outputs, buyerKey, redeemScript, contract.BuyerOrder.RefundFee)
 		if err != nil {
 			return nil, err
 		}
@@ -398,7 +417,7 @@ func (service *OpenBazaarService) handleReject(p peer.ID, pmes *pb.Message, opti
 			sig := spvwallet.Signature{InputIndex: s.InputIndex, Signature: s.Signature}
 			vendorSignatures = append(vendorSignatures, sig)
 		}
-		err = service.node.Wallet.Multisign(ins, []spvwallet.TransactionOutput{output}, buyerSignatures, vendorSignatures, redeemScript, contract.BuyerOrder.RefundFee)
+		err = service.node.Wallet.Multisign(ins, outputs, buyerSignatures, vendorSignatures, redeemScript, contract.BuyerOrder.RefundFee)
 		if err != nil {
 			return nil, err
 		}
@@ -422,8 +441,8 @@ func (service *OpenBazaarService) handleRefund(p peer.ID, pmes *pb.Message, opti
 		return nil, err
 	}
 
-	if err := service.node.VerifySignaturesOnRefund(rc); err != nil {
-		return nil, err
+	if rc.Refund == nil {
+		return nil, errors.New("Refund message is missing the refund")
 	}
 
 	// Load the order
@@ -432,6 +451,17 @@ func (service *OpenBazaarService) handleRefund(p peer.ID, pmes *pb.Message, opti
 		return nil, err
 	}
 
+	if err := service.node.VerifySignaturesOnRefund(rc, contract); err != nil {
+		return nil, err
+	}
+	if err := service.node.ValidateRefund(contract, records, rc.Refund); err != nil {
+		return nil, err
+	}
+
+	partial := rc.Refund.Amount > 0
+	if rc.Refund.Payout != nil && !(partial && service.node.RefundSettlesOrder(contract, rc.Refund)) {
+		return nil, errors.New("Refund pays out the vendor before the order is settled")
+	}
 	if contract.BuyerOrder.Payment.Method == pb.Order_Payment_MODERATED {
 		var ins []spvwallet.TransactionInput
 		var outValue int64
@@ -447,17 +477,20 @@ func (service *OpenBazaarService) handleRefund(p peer.ID, pmes *pb.Message, opti
 			}
 		}
 
-		refundAddress, err := btcutil.DecodeAddress(contract.BuyerOrder.RefundAddress, service.node.Wallet.Params())
+		var outputs []spvwallet.TransactionOutput
+		if rc.Refund.Payout != nil {
+			outputs, err = service.node.SettlingRefundOutputs(contract, int64(rc.Refund.Amount), outValue, rc.Refund.Payout.PayoutAddress, len(ins), contract.BuyerOrder.RefundFee)
+		} else if partial {
+			outputs, err = service.node.PartialRefundOutputs(contract, int64(rc.Refund.Amount), outValue)
+		} else {
+			outputs, err = service.node.EscrowOutputs(contract, contract.BuyerOrder.RefundAddress, outValue, len(ins), contract.BuyerOrder.RefundFee)
+		}
 		if err != nil {
 			return nil, err
 		}
-		var output spvwallet.TransactionOutput
-		outputScript, err := txscript.PayToAddrScript(refundAddress)
-		if err != nil {
+		if err := service.node.ValidateRefundOutputs(contract, rc.Refund, outputs, outValue); err != nil {
 			return nil, err
 		}
-		output.ScriptPubKey = outputScript
-		output.Value = outValue
 
 		chaincode, err := hex.DecodeString(contract.BuyerOrder.Payment.Chaincode)
 		if err != nil {
@@ -490,7 +523,7 @@ func (service *OpenBazaarService) handleRefund(p peer.ID, pmes *pb.Message, opti
 			return nil, err
 		}
 
-		buyerSignatures, err := service.node.Wallet.CreateMultisigSignature(ins, []spvwallet.TransactionOutput{output}, buyerKey, redeemScript, contract.BuyerOrder.RefundFee)
+		buyerSignatures, err := service.node.Wallet.CreateMultisigSignature(ins, outputs, buyerKey, redeemScript, contract.BuyerOrder.RefundFee)
 		if err != nil {
 			return nil, err
 		}
@@ -499,23 +532,28 @@ func (service *OpenBazaarService) handleRefund(p peer.ID, pmes *pb.Message, opti
 			sig := spvwallet.Signature{InputIndex: s.InputIndex, Signature: s.Signature}
 			vendorSignatures = append(vendorSignatures, sig)
 		}
-		err = service.node.Wallet.Multisign(ins, []spvwallet.TransactionOutput{output}, buyerSignatures, vendorSignatures, redeemScript, contract.BuyerOrder.RefundFee)
+		err = service.node.Wallet.Multisign(ins, outputs, buyerSignatures, vendorSignatures, redeemScript, contract.BuyerOrder.RefundFee)
 		if err != nil {
 			return nil, err
 		}
 	}
-	contract.Refund = rc.Refund
-	for _, sig := range contract.Signatures {
+	for _, sig := range rc.Signatures {
 		if sig.Section == pb.Signature_REFUND {
 			contract.Signatures = append(contract.Signatures, sig)
 		}
 	}
 
-	// Set message state to refunded
-	service.datastore.Purchases().Put(contract.Refund.OrderID, *contract, pb.OrderState_REFUNDED, false)
+	if partial {
+		contract.PartialRefunds = append(contract.PartialRefunds, rc.Refund)
+		service.datastore.Purchases().Put(rc.Refund.OrderID, *contract, service.node.PartialOrderState(contract), false)
+	} else {
+		// Set message state to refunded
+		contract.Refund = rc.Refund
+		service.datastore.Purchases().Put(rc.Refund.OrderID, *contract, pb.OrderState_REFUNDED, false)
+	}
 
 	// Send notification to websocket
-	n := notifications.Serialize(notifications.RefundNotification{contract.Refund.OrderID})
+	n := notifications.Serialize(notifications.RefundNotification{rc.Refund.OrderID, rc.Refund.Amount})
 	service.broadcast <- n
 
 	return nil, nil
@@ -530,8 +568,12 @@ func (service *OpenBazaarService) handleOrderFulfillment(p peer.ID, pmes *pb.Mes
 		return nil, err
 	}
 
+	if len(rc.VendorOrderFulfillment) == 0 {
+		return nil, errors.New("Fulfillment message is missing the fulfillment")
+	}
+
 	// Load the order
-	contract, _, _, _, _, err := service.datastore.Purchases().GetByOrderId(rc.VendorOrderFulfillment[0].OrderId)
+	contract, _, _, records, _, err := service.datastore.Purchases().GetByOrderId(rc.VendorOrderFulfillment[0].OrderId)
 	if err != nil {
 		return nil, err
 	}
@@ -543,17 +585,15 @@ func (service *OpenBazaarService) handleOrderFulfillment(p peer.ID, pmes *pb.Mes
 		}
 	}
 
-	if err := service.node.ValidateOrderFulfillment(rc.VendorOrderFulfillment[0], contract); err != nil {
+	if err := service.node.ValidateOrderFulfillment(rc.VendorOrderFulfillment[0], contract, records); err != nil {
 		return nil, err
 	}
 
-	// Set message state to fulfilled if all listings have a matching fulfillment message
-	if service.node.IsFulfilled(contract) {
-		service.datastore.Purchases().Put(rc.VendorOrderFulfillment[0].OrderId, *contract, pb.OrderState_FULFILLED, false)
-	}
+	// The order is fulfilled once every listing which hasn't been refunded has a fulfillment message
+	service.datastore.Purchases().Put(rc.VendorOrderFulfillment[0].OrderId, *contract, service.node.PartialOrderState(contract), false)
 
 	// Send notification to websocket
-	n := notifications.Serialize(notifications.FulfillmentNotification{rc.VendorOrderFulfillment[0].OrderId})
+	n := notifications.Serialize(notifications.FulfillmentNotification{rc.VendorOrderFulfillment[0].OrderId, rc.VendorOrderFulfillment[0].Slug})
 	service.broadcast <- n
 
 	return nil, nil
@@ -581,11 +621,16 @@ func (service *OpenBazaarService) handleOrderCompletion(p peer.ID, pmes *pb.Mess
 		}
 	}
 
-	if err := service.node.ValidateOrderCompletion(contract); err != nil {
+	if err := service.node.ValidateOrderCompletion(contract, records); err != nil {
 		return nil, err
 	}
 
-	if contract.BuyerOrder.Payment.Method == pb.Order_Payment_MODERATED {
+	// The escrow has already been paid out if a partial refund settled the order
+	if contract.BuyerOrder.Payment.Method == pb.Order_Payment_MODERATED && !service.node.PayoutSettledByRefund(contract) {
+		payout := service.node.GetFulfillmentPayout(contract)
+		if payout == nil {
+			return nil, errors.New("Order does not contain a payout")
+		}
 		var ins []spvwallet.TransactionInput
 		var outValue int64
 		for _, r := range records {
@@ -600,17 +645,10 @@ func (service *OpenBazaarService) handleOrderCompletion(p peer.ID, pmes *pb.Mess
 			}
 		}
 
-		payoutAddress, err := btcutil.DecodeAddress(contract.VendorOrderFulfillment[0].Payout.PayoutAddress, service.node.Wallet.Params())
-		if err != nil {
-			return nil, err
-		}
-		var output spvwallet.TransactionOutput
-		outputScript, err := txscript.PayToAddrScript(payoutAddress)
+		outputs, err := service.node.EscrowOutputs(contract, payout.PayoutAddress, outValue, len(ins), payout.PayoutFeePerByte)
 		if err != nil {
 			return nil, err
 		}
-		output.ScriptPubKey = outputScript
-		output.Value = outValue
 
 		redeemScript, err := hex.DecodeString(contract.BuyerOrder.Payment.RedeemScript)
 		if err != nil {
@@ -618,7 +656,7 @@ func (service *OpenBazaarService) handleOrderCompletion(p peer.ID, pmes *pb.Mess
 		}
 
 		var vendorSignatures []spvwallet.Signature
-		for _, s := range contract.VendorOrderFulfillment[0].Payout.Sigs {
+		for _, s := range payout.Sigs {
 			sig := spvwallet.Signature{InputIndex: s.InputIndex, Signature: s.Signature}
 			vendorSignatures = append(vendorSignatures, sig)
 		}
@@ -628,7 +666,7 @@ func (service *OpenBazaarService) handleOrderCompletion(p peer.ID, pmes *pb.Mess
 			buyerSignatures = append(buyerSignatures, sig)
 		}
 
-		err = service.node.Wallet.Multisign(ins, []spvwallet.TransactionOutput{output}, buyerSignatures, vendorSignatures, redeemScript, contract.VendorOrderFulfillment[0].Payout.PayoutFeePerByte)
+		err = service.node.Wallet.Multisign(ins, outputs, buyerSignatures, vendorSignatures, redeemScript, payout.PayoutFeePerByte)
 		if err != nil {
 			return nil, err
 		}
@@ -648,3 +686,23 @@ func (service *OpenBazaarService) handleOrderCompletion(p peer.ID, pmes *pb.Mess
 
 	return nil, nil
 }
+
+func (service *OpenBazaarService) handleContractRequest(p peer.ID, pmes *pb.Message, options interface{}) (*pb.Message, error) {
+	log.Debugf("Received CONTRACT_REQUEST message from %s", p.Pretty())
+	if offline, _ := options.(bool); offline {
+		return nil, errors.New("Contract requests must be made directly")
+	}
+	contracts, err := service.node.ContractsWithPeer(p.Pretty())
+	if err != nil {
+		return nil, err
+	}
+	a, err := ptypes.MarshalAny(&pb.ContractList{Contracts: contracts})
+	if err != nil {
+		return nil, err
+	}
+	m := pb.Message{
+		MessageType: pb.Message_CONTRACT_REQUEST,
+		Payload:     a,
+	}
+	return &m, nil
+}
@@ -9,8 +9,10 @@ import (
 	protocol "gx/ipfs/QmVCe3SNMjkcPgnpFhZs719dheq6xE7gJwjzV7aWcUM4Ms/go-libp2p/p2p/protocol"
 	ggio "gx/ipfs/QmZ4Qi3GaRbjcx28Sme5eMH7RQjGkt8wHxt2a65oLaeFEV/gogo-protobuf/io"
 	"gx/ipfs/QmZy2y8t9zQH2a1b8q2ZSLKp17ATuJoCNxxyMFG5qFExpt/go-net/context"
+	"time"
 
 	"github.com/OpenBazaar/openbazaar-go/core"
+	"github.com/OpenBazaar/openbazaar-go/metrics"
 	"github.com/OpenBazaar/openbazaar-go/pb"
 	"github.com/OpenBazaar/openbazaar-go/repo"
 	"github.com/ipfs/go-ipfs/commands"
@@ -77,7 +79,9 @@ func (service *OpenBazaarService) handleNewMessage(s inet.Stream) {
 	}
 
 	// Dispatch handler
+	start := time.Now()
 	rpmes, err := handler(mPeer, pmes, nil)
+	metrics.HandlerDuration.WithLabelValues(pmes.MessageType.String()).Observe(time.Since(start).Seconds())
 	if err != nil {
 		log.Debugf("handle message error: %s", err)
 		return
@@ -0,0 +1,731 @@
+package net
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/elliptic"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"io"
+	"math/big"
+	"sync"
+	"time"
+
+	"github.com/OpenBazaar/openbazaar-go/repo"
+	"golang.org/x/crypto/hkdf"
+	peer "gx/ipfs/QmRBqJF7hb8ZSpRcMwUt8hNhydWcxGEhtk81HKq6oUwKvs/go-libp2p-peer"
+	libp2p "gx/ipfs/QmUWER4r4qMvaCnX5zREcfyiWN7cXN9g3a7fkRqNz8qWPP/go-libp2p-crypto"
+)
+
+const (
+	// The version of ciphertexts encrypted with a forward secret session
+	SessionCiphertextVersion = 2
+
+	// Length of the random ID which identifies a session in bytes
+	SessionIDBytes = 16
+
+	// Length of a serialized P-256 public key in bytes
+	SessionPublicKeyBytes = 65
+
+	// A session the peer hasn't replied to is started again after this long. Peers must
+	// keep replaced prekeys for at least this long after messages to them expire.
+	MaxPendingSessionAge = 7 * 24 * time.Hour
+
+	// The most message keys that will be skipped over in a single chain. This stops a
+	// peer from making us derive an unbounded number of keys.
+	maxSkippedKeys = 1000
+
+	// The most keys for skipped messages kept in a session. The oldest go first.
+	maxStoredSkippedKeys = 2000
+)
+
+var (
+	// A session ciphertext can only be decrypted with a SessionCipher
+	ErrSessionRequired = errors.New("Ciphertext was encrypted with a session")
+
+	// The session ciphertext is malformed
+	ErrInvalidSessionHeader = errors.New("Invalid session header")
+
+	// The message is for a session we don't have and doesn't say how to start it
+	ErrUnknownSession = errors.New("Unknown session")
+
+	// The prekey bundle was not signed by the peer's identity key
+	ErrInvalidPrekeyBundle = errors.New("Invalid prekey bundle signature")
+
+	// The message was not signed by the peer the session is with
+	ErrWrongSender = errors.New("Message was not sent by the session's peer")
+
+	// A key in a bundle or header is not a point on the curve
+	ErrInvalidPublicKey = errors.New("Invalid session public key")
+
+	// The message would need more keys skipped than we allow
+	ErrTooManySkipped = errors.New("Too many skipped messages")
+
+	curve = elliptic.P256()
+
+	// Domain separation for the signatures and key derivations used by sessions
+	prekeySigPrefix     = []byte("OpenBazaar Prekey")
+	sessionSigPrefix    = []byte("OpenBazaar Session")
+	identityKeyInfo     = []byte("OpenBazaar Session Identity")
+	x3dhInfo            = []byte("OpenBazaar X3DH")
+	ratchetInfo         = []byte("OpenBazaar Ratchet")
+	messageKeyInfo      = []byte("OpenBazaar Message Keys")
+	x3dhPadding         = bytes.Repeat([]byte{0xff}, 32)
+	chainKeyConstant    = []byte{0x02}
+	messageKeyConstant  = []byte{0x01}
+	sessionHeaderLength = CiphertextVersionBytes + SessionIDBytes + SessionPublicKeyBytes + 4 + 4 + 1
+	sessionInitLength   = SessionPublicKeyBytes*2 + 4
+)
+
+// Published in the node's root directory so peers can start a session with us while we
+// are offline. The identity key is a P-256 key derived from our libp2p identity key since
+// RSA can't be used for Diffie-Hellman. The signed prekey is replaced regularly and its
+// private key deleted a while later. There are no one-time prekeys since the bundle is
+// public and we can't hand out each of them only once.
+type PrekeyBundle struct {
+	IdentityKey  []byte `json:"identityKey"`
+	SignedPrekey []byte `json:"signedPrekey"`
+	PrekeyID     uint32 `json:"prekeyId"`
+
+	// Signature over the keys and ID by the libp2p identity key
+	Signature []byte `json:"signature"`
+}
+
+// Check the bundle was signed by the given identity key
+func (b *PrekeyBundle) Verify(pubKey libp2p.PubKey) error {
+	valid, err := pubKey.Verify(b.signedData(), b.Signature)
+	if err != nil || !valid {
+		return ErrInvalidPrekeyBundle
+	}
+	if !validPublicKey(b.IdentityKey) || !validPublicKey(b.SignedPrekey) {
+		return ErrInvalidPublicKey
+	}
+	return nil
+}
+
+func (b *PrekeyBundle) signedData() []byte {
+	id := make([]byte, 4)
+	binary.BigEndian.PutUint32(id, b.PrekeyID)
+	return concat(prekeySigPrefix, b.IdentityKey, b.SignedPrekey, id)
+}
+
+// Generate the private key for a new signed prekey
+func GeneratePrekey() ([]byte, error) {
+	priv, _, _, err := elliptic.GenerateKey(curve, rand.Reader)
+	return priv, err
+}
+
+// Encrypts messages to peers with sessions which give forward secrecy. A session is
+// started with an X3DH key agreement against the peer's prekey bundle and each message
+// after that is encrypted with a key from a Double Ratchet. Once the keys for a message
+// have been used they are deleted, so compromising the identity key or the database
+// later doesn't reveal it. The state of each session is saved to the datastore.
+type SessionCipher struct {
+	identity    libp2p.PrivKey
+	identityKey []byte
+	sessions    repo.Sessions
+	prekeys     repo.Prekeys
+	lock        sync.Mutex
+}
+
+func NewSessionCipher(identity libp2p.PrivKey, sessions repo.Sessions, prekeys repo.Prekeys) (*SessionCipher, error) {
+	identityKey, err := sessionIdentityKey(identity)
+	if err != nil {
+		return nil, err
+	}
+	return &SessionCipher{
+		identity:    identity,
+		identityKey: identityKey,
+		sessions:    sessions,
+		prekeys:     prekeys,
+	}, nil
+}
+
+// Return our prekey bundle for the given signed prekey
+func (c *SessionCipher) PrekeyBundle(id uint32, prekey []byte) (*PrekeyBundle, error) {
+	b := &PrekeyBundle{
+		IdentityKey:  ecPublic(c.identityKey),
+		SignedPrekey: ecPublic(prekey),
+		PrekeyID:     id,
+	}
+	sig, err := c.identity.Sign(b.signedData())
+	if err != nil {
+		return nil, err
+	}
+	b.Signature = sig
+	return b, nil
+}
+
+// Encrypt a message for the peer with the given identity key using our latest session
+// with them. If there isn't one the bundle function is called for their prekey bundle
+// to start one, and any error it returns is returned unchanged.
+func (c *SessionCipher) Encrypt(pubKey libp2p.PubKey, plaintext []byte, bundle func() (*PrekeyBundle, error)) ([]byte, error) {
+	pid, err := peer.IDFromPublicKey(pubKey)
+	if err != nil {
+		return nil, err
+	}
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	var id []byte
+	var state *sessionState
+	sid, b, err := c.sessions.GetByPeer(pid.Pretty())
+	if err != nil && err != repo.ErrSessionNotFound {
+		return nil, err
+	} else if err == nil {
+		id, err = hex.DecodeString(sid)
+		if err != nil {
+			return nil, err
+		}
+		state = new(sessionState)
+		if err := json.Unmarshal(b, state); err != nil {
+			return nil, err
+		}
+		// The prekey it was started with may be gone by now
+		if state.Init != nil && time.Since(state.Init.Created) > MaxPendingSessionAge {
+			state = nil
+		}
+	}
+	if state == nil {
+		pkb, err := bundle()
+		if err != nil {
+			return nil, err
+		}
+		if err := pkb.Verify(pubKey); err != nil {
+			return nil, err
+		}
+		id, state, err = c.initiate(pkb)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	h := sessionHeader{
+		SessionID: id,
+		Ratchet:   ecPublic(state.SendRatchet),
+		PrevSent:  state.PrevSent,
+		N:         state.Sent,
+		Init:      state.Init,
+	}
+	mk := state.nextSendKey()
+	payload := plaintext
+	if state.Init != nil {
+		// The signature binding the session to our identity goes inside the ciphertext so
+		// the message doesn't reveal who sent it
+		sigLen := make([]byte, 2)
+		binary.BigEndian.PutUint16(sigLen, uint16(len(state.Init.Signature)))
+		payload = concat(sigLen, state.Init.Signature, plaintext)
+	}
+	hb := h.marshal()
+	ciphertext, err := sealMessage(mk, payload, concat(state.AD, hb))
+	if err != nil {
+		return nil, err
+	}
+	if err := c.save(id, pid.Pretty(), state); err != nil {
+		return nil, err
+	}
+	return append(hb, ciphertext...), nil
+}
+
+// Decrypt a message encrypted with either a session or the identity key. The sender
+// function must return the public key which signed the plaintext or an error if it
+// isn't validly signed. Session messages are only accepted from the peer the session
+// is with, and a new session is only saved once the sender has been checked against
+// the identity key which started it.
+func (c *SessionCipher) Decrypt(ciphertext []byte, sender func(plaintext []byte) (libp2p.PubKey, error)) ([]byte, error) {
+	if len(ciphertext) < CiphertextVersionBytes {
+		return nil, ErrShortCiphertext
+	}
+	if getCipherTextVersion(ciphertext) != SessionCiphertextVersion {
+		plaintext, err := Decrypt(c.identity, ciphertext)
+		if err != nil {
+			return nil, err
+		}
+		if _, err := sender(plaintext); err != nil {
+			return nil, err
+		}
+		return plaintext, nil
+	}
+	h, hb, err := parseSessionHeader(ciphertext)
+	if err != nil {
+		return nil, err
+	}
+	sid := hex.EncodeToString(h.SessionID)
+
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	var state *sessionState
+	peerID, b, err := c.sessions.Get(sid)
+	if err == repo.ErrSessionNotFound {
+		if h.Init == nil {
+			return nil, ErrUnknownSession
+		}
+		state, err = c.respond(h.Init)
+		if err != nil {
+			return nil, err
+		}
+	} else if err != nil {
+		return nil, err
+	} else {
+		state = new(sessionState)
+		if err := json.Unmarshal(b, state); err != nil {
+			return nil, err
+		}
+	}
+
+	// The state is only saved once the message has been authenticated so a bad message
+	// can't advance the ratchet
+	mk, err := state.receiveKey(h)
+	if err != nil {
+		return nil, err
+	}
+	plaintext, err := openMessage(mk, ciphertext[len(hb):], concat(state.AD, hb))
+	if err != nil {
+		return nil, err
+	}
+	var sig []byte
+	if h.Init != nil {
+		if len(plaintext) < 2 || len(plaintext) < 2+int(binary.BigEndian.Uint16(plaintext)) {
+			return nil, ErrInvalidSessionHeader
+		}
+		sigLen := 2 + int(binary.BigEndian.Uint16(plaintext))
+		sig = plaintext[2:sigLen]
+		plaintext = plaintext[sigLen:]
+	}
+	pubKey, err := sender(plaintext)
+	if err != nil {
+		return nil, err
+	}
+	pid, err := peer.IDFromPublicKey(pubKey)
+	if err != nil {
+		return nil, err
+	}
+	if peerID == "" {
+		valid, err := pubKey.Verify(initSigData(h.SessionID, h.Init.IdentityKey, ecPublic(c.identityKey), h.Init.EphemeralKey), sig)
+		if err != nil || !valid {
+			return nil, ErrWrongSender
+		}
+		peerID = pid.Pretty()
+	} else if pid.Pretty() != peerID {
+		return nil, ErrWrongSender
+	}
+
+	// The peer has the session now so there's no need to keep telling them how to start it
+	state.Init = nil
+	if err := c.save(h.SessionID, peerID, state); err != nil {
+		return nil, err
+	}
+	return plaintext, nil
+}
+
+// Start a session with the X3DH key agreement against a peer's prekey bundle
+func (c *SessionCipher) initiate(b *PrekeyBundle) ([]byte, *sessionState, error) {
+	ephemeral, err := GeneratePrekey()
+	if err != nil {
+		return nil, nil, err
+	}
+	dh1, err := ecdh(c.identityKey, b.SignedPrekey)
+	if err != nil {
+		return nil, nil, err
+	}
+	dh2, err := ecdh(ephemeral, b.IdentityKey)
+	if err != nil {
+		return nil, nil, err
+	}
+	dh3, err := ecdh(ephemeral, b.SignedPrekey)
+	if err != nil {
+		return nil, nil, err
+	}
+	sk, err := x3dh(dh1, dh2, dh3)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	id := make([]byte, SessionIDBytes)
+	if _, err := rand.Read(id); err != nil {
+		return nil, nil, err
+	}
+	identityPub := ecPublic(c.identityKey)
+	ephemeralPub := ecPublic(ephemeral)
+	sig, err := c.identity.Sign(initSigData(id, identityPub, b.IdentityKey, ephemeralPub))
+	if err != nil {
+		return nil, nil, err
+	}
+
+	ratchet, err := GeneratePrekey()
+	if err != nil {
+		return nil, nil, err
+	}
+	dh, err := ecdh(ratchet, b.SignedPrekey)
+	if err != nil {
+		return nil, nil, err
+	}
+	rk, ck, err := kdfRootKey(sk, dh)
+	if err != nil {
+		return nil, nil, err
+	}
+	return id, &sessionState{
+		AD:             concat(identityPub, b.IdentityKey),
+		RootKey:        rk,
+		SendChain:      ck,
+		SendRatchet:    ratchet,
+		ReceiveRatchet: b.SignedPrekey,
+		Init: &sessionInit{
+			IdentityKey:  identityPub,
+			EphemeralKey: ephemeralPub,
+			PrekeyID:     b.PrekeyID,
+			Signature:    sig,
+			Created:      time.Now(),
+		},
+	}, nil
+}
+
+// Accept a session started by a peer against one of our signed prekeys
+func (c *SessionCipher) respond(init *sessionInit) (*sessionState, error) {
+	prekey, err := c.prekeys.Get(init.PrekeyID)
+	if err != nil {
+		return nil, err
+	}
+	dh1, err := ecdh(prekey, init.IdentityKey)
+	if err != nil {
+		return nil, err
+	}
+	dh2, err := ecdh(c.identityKey, init.EphemeralKey)
+	if err != nil {
+		return nil, err
+	}
+	dh3, err := ecdh(prekey, init.EphemeralKey)
+	if err != nil {
+		return nil, err
+	}
+	sk, err := x3dh(dh1, dh2, dh3)
+	if err != nil {
+		return nil, err
+	}
+	return &sessionState{
+		AD:          concat(init.IdentityKey, ecPublic(c.identityKey)),
+		RootKey:     sk,
+		SendRatchet: prekey,
+	}, nil
+}
+
+func (c *SessionCipher) save(id []byte, peerID string, state *sessionState) error {
+	b, err := json.Marshal(state)
+	if err != nil {
+		return err
+	}
+	return c.sessions.Put(hex.EncodeToString(id), peerID, b)
+}
+
+// The Double Ratchet state of a session
+type sessionState struct {
+	// Associated data from the key agreement which is authenticated with every message
+	AD []byte
+
+	RootKey      []byte
+	SendChain    []byte
+	ReceiveChain []byte
+
+	// Our private ratchet key and the peer's public one
+	SendRatchet    []byte
+	ReceiveRatchet []byte
+
+	// Message numbers in the current chains and the length of the previous send chain
+	Sent     uint32
+	Received uint32
+	PrevSent uint32
+
+	// Keys for messages which haven't arrived yet in the order they were skipped
+	Skipped []skippedKey
+
+	// Sent with each message until the peer replies so they can start the session from any of them
+	Init *sessionInit
+}
+
+type skippedKey struct {
+	Ratchet []byte
+	N       uint32
+	Key     []byte
+}
+
+type sessionInit struct {
+	IdentityKey  []byte
+	EphemeralKey []byte
+	PrekeyID     uint32
+
+	// Not part of the header. The signature is sent inside the ciphertext.
+	Signature []byte
+	Created   time.Time
+}
+
+// Step the sending chain and return the message key
+func (s *sessionState) nextSendKey() []byte {
+	var mk []byte
+	s.SendChain, mk = kdfChainKey(s.SendChain)
+	s.Sent++
+	return mk
+}
+
+// Return the message key for a received header, stepping the ratchet if the peer has
+func (s *sessionState) receiveKey(h *sessionHeader) ([]byte, error) {
+	for i, k := range s.Skipped {
+		if k.N == h.N && bytes.Equal(k.Ratchet, h.Ratchet) {
+			s.Skipped = append(s.Skipped[:i], s.Skipped[i+1:]...)
+			return k.Key, nil
+		}
+	}
+	if !bytes.Equal(h.Ratchet, s.ReceiveRatchet) {
+		if err := s.skip(h.PrevSent); err != nil {
+			return nil, err
+		}
+		if err := s.ratchet(h.Ratchet); err != nil {
+			return nil, err
+		}
+	}
+	if err := s.skip(h.N); err != nil {
+		return nil, err
+	}
+	var mk []byte
+	s.ReceiveChain, mk = kdfChainKey(s.ReceiveChain)
+	s.Received++
+	return mk, nil
+}
+
+// Save the keys for the messages in the receiving chain before the given one
+func (s *sessionState) skip(until uint32) error {
+	if s.ReceiveChain == nil {
+		return nil
+	}
+	if until > s.Received+maxSkippedKeys {
+		return ErrTooManySkipped
+	}
+	for s.Received < until {
+		var mk []byte
+		s.ReceiveChain, mk = kdfChainKey(s.ReceiveChain)
+		s.Skipped = append(s.Skipped, skippedKey{s.ReceiveRatchet, s.Received, mk})
+		s.Received++
+	}
+	if len(s.Skipped) > maxStoredSkippedKeys {
+		s.Skipped = s.Skipped[len(s.Skipped)-maxStoredSkippedKeys:]
+	}
+	return nil
+}
+
+// Take the peer's new ratchet key and replace ours
+func (s *sessionState) ratchet(peerRatchet []byte) error {
+	s.PrevSent = s.Sent
+	s.Sent = 0
+	s.Received = 0
+	s.ReceiveRatchet = peerRatchet
+	dh, err := ecdh(s.SendRatchet, peerRatchet)
+	if err != nil {
+		return err
+	}
+	s.RootKey, s.ReceiveChain, err = kdfRootKey(s.RootKey, dh)
+	if err != nil {
+		return err
+	}
+	s.SendRatchet, err = GeneratePrekey()
+	if err != nil {
+		return err
+	}
+	dh, err = ecdh(s.SendRatchet, peerRatchet)
+	if err != nil {
+		return err
+	}
+	s.RootKey, s.SendChain, err = kdfRootKey(s.RootKey, dh)
+	return err
+}
+
+// The header sent in the clear before each session message. It's authenticated as
+// associated data. The layout is:
+// version (4) | session ID (16) | ratchet key (65) | previous chain length (4) | message number (4) |
+// flags (1) | [identity key (65) | ephemeral key (65) | prekey ID (4)]
+type sessionHeader struct {
+	SessionID []byte
+	Ratchet   []byte
+	PrevSent  uint32
+	N         uint32
+	Init      *sessionInit
+}
+
+func (h *sessionHeader) marshal() []byte {
+	b := make([]byte, sessionHeaderLength, sessionHeaderLength+sessionInitLength)
+	binary.BigEndian.PutUint32(b, uint32(SessionCiphertextVersion))
+	off := CiphertextVersionBytes
+	off += copy(b[off:], h.SessionID)
+	off += copy(b[off:], h.Ratchet)
+	binary.BigEndian.PutUint32(b[off:], h.PrevSent)
+	binary.BigEndian.PutUint32(b[off+4:], h.N)
+	if h.Init != nil {
+		b[off+8] = 1
+		prekeyID := make([]byte, 4)
+		binary.BigEndian.PutUint32(prekeyID, h.Init.PrekeyID)
+		b = append(b, concat(h.Init.IdentityKey, h.Init.EphemeralKey, prekeyID)...)
+	}
+	return b
+}
+
+// Parse the header of a session ciphertext and return it along with its raw bytes
+func parseSessionHeader(ciphertext []byte) (*sessionHeader, []byte, error) {
+	if len(ciphertext) < sessionHeaderLength {
+		return nil, nil, ErrShortCiphertext
+	}
+	h := new(sessionHeader)
+	off := CiphertextVersionBytes
+	h.SessionID = ciphertext[off : off+SessionIDBytes]
+	off += SessionIDBytes
+	h.Ratchet = ciphertext[off : off+SessionPublicKeyBytes]
+	off += SessionPublicKeyBytes
+	h.PrevSent = binary.BigEndian.Uint32(ciphertext[off:])
+	h.N = binary.BigEndian.Uint32(ciphertext[off+4:])
+	flags := ciphertext[off+8]
+	if flags > 1 {
+		return nil, nil, ErrInvalidSessionHeader
+	}
+	if !validPublicKey(h.Ratchet) {
+		return nil, nil, ErrInvalidPublicKey
+	}
+	if flags == 0 {
+		return h, ciphertext[:sessionHeaderLength], nil
+	}
+	if len(ciphertext) < sessionHeaderLength+sessionInitLength {
+		return nil, nil, ErrShortCiphertext
+	}
+	off = sessionHeaderLength
+	h.Init = &sessionInit{
+		IdentityKey:  ciphertext[off : off+SessionPublicKeyBytes],
+		EphemeralKey: ciphertext[off+SessionPublicKeyBytes : off+2*SessionPublicKeyBytes],
+		PrekeyID:     binary.BigEndian.Uint32(ciphertext[off+2*SessionPublicKeyBytes:]),
+	}
+	return h, ciphertext[:sessionHeaderLength+sessionInitLength], nil
+}
+
+func initSigData(id, initiatorKey, responderKey, ephemeralKey []byte) []byte {
+	return concat(sessionSigPrefix, id, initiatorKey, responderKey, ephemeralKey)
+}
+
+// Derive our session identity key from the libp2p identity key so it doesn't need to
+// be stored and is restored along with the identity
+func sessionIdentityKey(identity libp2p.PrivKey) ([]byte, error) {
+	b, err := identity.Bytes()
+	if err != nil {
+		return nil, err
+	}
+	r := hkdf.New(sha256.New, b, Salt, identityKeyInfo)
+	n := curve.Params().N
+	for {
+		k := make([]byte, 32)
+		if _, err := io.ReadFull(r, k); err != nil {
+			return nil, err
+		}
+		if v := new(big.Int).SetBytes(k); v.Sign() > 0 && v.Cmp(n) < 0 {
+			return k, nil
+		}
+	}
+}
+
+// Derive the shared secret from the three Diffie-Hellman outputs of the key agreement
+func x3dh(dh1, dh2, dh3 []byte) ([]byte, error) {
+	sk := make([]byte, 32)
+	r := hkdf.New(sha256.New, concat(x3dhPadding, dh1, dh2, dh3), make([]byte, 32), x3dhInfo)
+	if _, err := io.ReadFull(r, sk); err != nil {
+		return nil, err
+	}
+	return sk, nil
+}
+
+// Mix a Diffie-Hellman output into the root key and return the new root and chain keys
+func kdfRootKey(rk, dh []byte) ([]byte, []byte, error) {
+	out := make([]byte, 64)
+	r := hkdf.New(sha256.New, dh, rk, ratchetInfo)
+	if _, err := io.ReadFull(r, out); err != nil {
+		return nil, nil, err
+	}
+	return out[:32], out[32:], nil
+}
+
+// Return the next chain key and the message key for this step
+func kdfChainKey(ck []byte) ([]byte, []byte) {
+	mac := hmac.New(sha256.New, ck)
+	mac.Write(chainKeyConstant)
+	next := mac.Sum(nil)
+	mac = hmac.New(sha256.New, ck)
+	mac.Write(messageKeyConstant)
+	return next, mac.Sum(nil)
+}
+
+// Each message key is only used once so the nonce is derived along with the AES key
+func messageCipher(mk []byte) (cipher.AEAD, []byte, error) {
+	out := make([]byte, AESKeyBytes+12)
+	r := hkdf.New(sha256.New, mk, nil, messageKeyInfo)
+	if _, err := io.ReadFull(r, out); err != nil {
+		return nil, nil, err
+	}
+	block, err := aes.NewCipher(out[:AESKeyBytes])
+	if err != nil {
+		return nil, nil, err
+	}
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, nil, err
+	}
+	return aead, out[AESKeyBytes:], nil
+}
+
+func sealMessage(mk, plaintext, ad []byte) ([]byte, error) {
+	aead, nonce, err := messageCipher(mk)
+	if err != nil {
+		return nil, err
+	}
+	return aead.Seal(nil, nonce, plaintext, ad), nil
+}
+
+func openMessage(mk, ciphertext, ad []byte) ([]byte, error) {
+	aead, nonce, err := messageCipher(mk)
+	if err != nil {
+		return nil, err
+	}
+	plaintext, err := aead.Open(nil, nonce, ciphertext, ad)
+	if err != nil {
+		return nil, ErrInvalidHmac
+	}
+	return plaintext, nil
+}
+
+func ecPublic(priv []byte) []byte {
+	x, y := curve.ScalarBaseMult(priv)
+	return elliptic.Marshal(curve, x, y)
+}
+
+func validPublicKey(pub []byte) bool {
+	x, _ := elliptic.Unmarshal(curve, pub)
+	return x != nil
+}
+
+func ecdh(priv, pub []byte) ([]byte, error) {
+	x, y := elliptic.Unmarshal(curve, pub)
+	if x == nil {
+		return nil, ErrInvalidPublicKey
+	}
+	x, _ = curve.ScalarMult(x, y, priv)
+	secret := make([]byte, 32)
+	xb := x.Bytes()
+	copy(secret[32-len(xb):], xb)
+	return secret, nil
+}
+
+func concat(parts ...[]byte) []byte {
+	var b []byte
+	for _, p := range parts {
+		b = append(b, p...)
+	}
+	return b
+}
@@ -0,0 +1,289 @@
+package net
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/OpenBazaar/openbazaar-go/repo"
+	libp2p "gx/ipfs/QmUWER4r4qMvaCnX5zREcfyiWN7cXN9g3a7fkRqNz8qWPP/go-libp2p-crypto"
+)
+
+type memorySession struct {
+	peerID string
+	state  []byte
+}
+
+type memorySessions struct {
+	sessions map[string]memorySession
+	order    []string
+}
+
+func (m *memorySessions) Put(id, peerID string, state []byte) error {
+	m.sessions[id] = memorySession{peerID, state}
+	m.order = append(m.order, id)
+	return nil
+}
+
+func (m *memorySessions) Get(id string) (string, []byte, error) {
+	s, ok := m.sessions[id]
+	if !ok {
+		return "", nil, repo.ErrSessionNotFound
+	}
+	return s.peerID, s.state, nil
+}
+
+func (m *memorySessions) GetByPeer(peerID string) (string, []byte, error) {
+	for i := len(m.order) - 1; i >= 0; i-- {
+		if s := m.sessions[m.order[i]]; s.peerID == peerID {
+			return m.order[i], s.state, nil
+		}
+	}
+	return "", nil, repo.ErrSessionNotFound
+}
+
+type memoryPrekeys map[uint32][]byte
+
+func (m memoryPrekeys) Put(id uint32, private []byte, created time.Time) error {
+	m[id] = private
+	return nil
+}
+
+func (m memoryPrekeys) Get(id uint32) ([]byte, error) {
+	private, ok := m[id]
+	if !ok {
+		return nil, repo.ErrPrekeyNotFound
+	}
+	return private, nil
+}
+
+func (m memoryPrekeys) GetLatest() (uint32, []byte, time.Time, error) {
+	return 0, nil, time.Time{}, errors.New("Not implemented")
+}
+
+func (m memoryPrekeys) DeleteSuperseded(before time.Time) error {
+	return nil
+}
+
+type sessionPeer struct {
+	priv    libp2p.PrivKey
+	pub     libp2p.PubKey
+	cipher  *SessionCipher
+	prekeys memoryPrekeys
+	bundle  *PrekeyBundle
+}
+
+func newSessionPeer(t *testing.T) *sessionPeer {
+	priv, pub, err := libp2p.GenerateKeyPair(libp2p.RSA, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	p := &sessionPeer{priv: priv, pub: pub, prekeys: make(memoryPrekeys)}
+	p.cipher, err = NewSessionCipher(priv, &memorySessions{sessions: make(map[string]memorySession)}, p.prekeys)
+	if err != nil {
+		t.Fatal(err)
+	}
+	prekey, err := GeneratePrekey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	p.prekeys.Put(1, prekey, time.Now())
+	p.bundle, err = p.cipher.PrekeyBundle(1, prekey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return p
+}
+
+// Send a message and return the ciphertext
+func (p *sessionPeer) send(t *testing.T, to *sessionPeer, plaintext string) []byte {
+	ciphertext, err := p.cipher.Encrypt(to.pub, []byte(plaintext), func() (*PrekeyBundle, error) {
+		return to.bundle, nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return ciphertext
+}
+
+// Receive a message which the sender function says was signed by from
+func (p *sessionPeer) receive(from *sessionPeer, ciphertext []byte) (string, error) {
+	plaintext, err := p.cipher.Decrypt(ciphertext, func([]byte) (libp2p.PubKey, error) {
+		return from.pub, nil
+	})
+	return string(plaintext), err
+}
+
+func TestSessionEncrypt(t *testing.T) {
+	alice := newSessionPeer(t)
+	bob := newSessionPeer(t)
+
+	// Messages may arrive in any order and before Bob has replied
+	first := alice.send(t, bob, "one")
+	second := alice.send(t, bob, "two")
+	third := alice.send(t, bob, "three")
+	for _, m := range []struct {
+		ciphertext []byte
+		plaintext  string
+	}{{second, "two"}, {first, "one"}, {third, "three"}} {
+		plaintext, err := bob.receive(alice, m.ciphertext)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if plaintext != m.plaintext {
+			t.Errorf("Expected %s, got %s", m.plaintext, plaintext)
+		}
+	}
+
+	// Each message key is deleted once it has been used
+	if _, err := bob.receive(alice, first); err == nil {
+		t.Error("Decrypted a message twice")
+	}
+
+	// Bob replies in the same session, after which Alice stops sending the session init
+	reply := bob.send(t, alice, "reply")
+	if plaintext, err := alice.receive(bob, reply); err != nil || plaintext != "reply" {
+		t.Errorf("Failed to decrypt reply: %v", err)
+	}
+	next := alice.send(t, bob, "four")
+	if len(next) >= len(third) {
+		t.Error("Session init was sent after the peer replied")
+	}
+	if plaintext, err := bob.receive(alice, next); err != nil || plaintext != "four" {
+		t.Errorf("Failed to decrypt message after reply: %v", err)
+	}
+}
+
+func TestSessionDeletedPrekey(t *testing.T) {
+	alice := newSessionPeer(t)
+	bob := newSessionPeer(t)
+	ciphertext := alice.send(t, bob, "hello")
+
+	// Once the prekey is gone nothing sent before the session started can be read,
+	// even with the identity key
+	delete(bob.prekeys, 1)
+	if _, err := bob.receive(alice, ciphertext); err != repo.ErrPrekeyNotFound {
+		t.Errorf("Expected ErrPrekeyNotFound, got %v", err)
+	}
+	if _, err := Decrypt(bob.priv, ciphertext); err != ErrSessionRequired {
+		t.Errorf("Expected ErrSessionRequired, got %v", err)
+	}
+}
+
+func TestSessionRestartsPendingSession(t *testing.T) {
+	alice := newSessionPeer(t)
+	bob := newSessionPeer(t)
+	first := alice.send(t, bob, "one")
+
+	// Age the session Alice started, then rotate Bob's prekey and delete the old one
+	sessions := alice.cipher.sessions.(*memorySessions)
+	id := sessions.order[len(sessions.order)-1]
+	var state sessionState
+	json.Unmarshal(sessions.sessions[id].state, &state)
+	state.Init.Created = time.Now().Add(-MaxPendingSessionAge - time.Hour)
+	b, _ := json.Marshal(&state)
+	sessions.Put(id, sessions.sessions[id].peerID, b)
+
+	prekey, _ := GeneratePrekey()
+	bob.prekeys.Put(2, prekey, time.Now())
+	bob.bundle, _ = bob.cipher.PrekeyBundle(2, prekey)
+	delete(bob.prekeys, 1)
+
+	second := alice.send(t, bob, "two")
+	if bytes.Equal(first[4:4+SessionIDBytes], second[4:4+SessionIDBytes]) {
+		t.Error("Pending session was not restarted")
+	}
+	if plaintext, err := bob.receive(alice, second); err != nil || plaintext != "two" {
+		t.Errorf("Failed to decrypt message in new session: %v", err)
+	}
+}
+
+func TestSessionWrongSender(t *testing.T) {
+	alice := newSessionPeer(t)
+	bob := newSessionPeer(t)
+	mallory := newSessionPeer(t)
+
+	// A session started by Mallory can't be claimed by a message signed by Alice
+	ciphertext := mallory.send(t, bob, "hello")
+	if _, err := bob.receive(alice, ciphertext); err != ErrWrongSender {
+		t.Errorf("Expected ErrWrongSender, got %v", err)
+	}
+
+	// Nor can a message signed by Mallory be accepted in a session with Alice
+	first := alice.send(t, bob, "one")
+	second := alice.send(t, bob, "two")
+	if _, err := bob.receive(alice, first); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := bob.receive(mallory, second); err != ErrWrongSender {
+		t.Errorf("Expected ErrWrongSender, got %v", err)
+	}
+
+	// The rejected message didn't change the session
+	if plaintext, err := bob.receive(alice, second); err != nil || plaintext != "two" {
+		t.Errorf("Failed to decrypt message: %v", err)
+	}
+}
+
+func TestSessionTamperedCiphertext(t *testing.T) {
+	alice := newSessionPeer(t)
+	bob := newSessionPeer(t)
+	ciphertext := alice.send(t, bob, "hello")
+	tampered := make([]byte, len(ciphertext))
+	copy(tampered, ciphertext)
+	tampered[len(tampered)-1] ^= 0x01
+	if _, err := bob.receive(alice, tampered); err != ErrInvalidHmac {
+		t.Errorf("Expected ErrInvalidHmac, got %v", err)
+	}
+	if plaintext, err := bob.receive(alice, ciphertext); err != nil || plaintext != "hello" {
+		t.Errorf("Failed to decrypt message: %v", err)
+	}
+}
+
+func TestPrekeyBundleVerify(t *testing.T) {
+	alice := newSessionPeer(t)
+	bob := newSessionPeer(t)
+	if err := bob.bundle.Verify(bob.pub); err != nil {
+		t.Error(err)
+	}
+	if err := bob.bundle.Verify(alice.pub); err != ErrInvalidPrekeyBundle {
+		t.Error("Accepted a bundle signed by another key")
+	}
+	tampered := *bob.bundle
+	tampered.SignedPrekey = alice.bundle.SignedPrekey
+	if err := tampered.Verify(bob.pub); err != ErrInvalidPrekeyBundle {
+		t.Error("Accepted a bundle with a replaced prekey")
+	}
+	_, err := alice.cipher.Encrypt(bob.pub, []byte("hello"), func() (*PrekeyBundle, error) {
+		return &tampered, nil
+	})
+	if err != ErrInvalidPrekeyBundle {
+		t.Error("Started a session with an invalid bundle")
+	}
+}
+
+func TestSessionCipherDecryptsIdentityCiphertext(t *testing.T) {
+	priv, pub, err := libp2p.GenerateKeyPair(libp2p.RSA, 4096)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cipher, err := NewSessionCipher(priv, &memorySessions{sessions: make(map[string]memorySession)}, make(memoryPrekeys))
+	if err != nil {
+		t.Fatal(err)
+	}
+	ciphertext, err := Encrypt(pub, []byte("hello"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	plaintext, err := cipher.Decrypt(ciphertext, func([]byte) (libp2p.PubKey, error) {
+		return pub, nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(plaintext, []byte("hello")) {
+		t.Error("Result plaintext doesn't match original plaintext")
+	}
+}
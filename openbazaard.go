@@ -1,6 +1,7 @@
 package main
 
 import (
+	"encoding/json"
 	"errors"
 	"fmt"
 	ipfslogging "gx/ipfs/QmNQynaz7qfriSUJkiEZUrm2Wen1u3Kj9goZzWtrPyu7XR/go-log"
@@ -21,10 +22,12 @@ import (
 	"crypto/rand"
 	bstk "github.com/OpenBazaar/go-blockstackclient"
 	"github.com/OpenBazaar/openbazaar-go/api"
+	"github.com/OpenBazaar/openbazaar-go/api/webhooks"
 	"github.com/OpenBazaar/openbazaar-go/bitcoin"
 	"github.com/OpenBazaar/openbazaar-go/bitcoin/bitcoind"
 	"github.com/OpenBazaar/openbazaar-go/bitcoin/exchange"
 	lis "github.com/OpenBazaar/openbazaar-go/bitcoin/listeners"
+	"github.com/OpenBazaar/openbazaar-go/bitcoin/spv"
 	"github.com/OpenBazaar/openbazaar-go/core"
 	"github.com/OpenBazaar/openbazaar-go/ipfs"
 	obnet "github.com/OpenBazaar/openbazaar-go/net"
@@ -35,6 +38,7 @@ import (
 	"github.com/OpenBazaar/openbazaar-go/repo/db"
 	sto "github.com/OpenBazaar/openbazaar-go/storage"
 	"github.com/OpenBazaar/openbazaar-go/storage/dropbox"
+	"github.com/OpenBazaar/openbazaar-go/storage/s3"
 	"github.com/OpenBazaar/openbazaar-go/storage/selfhosted"
 	"github.com/OpenBazaar/spvwallet"
 	"github.com/btcsuite/btcd/chaincfg"
@@ -54,10 +58,15 @@ import (
 	"github.com/mitchellh/go-homedir"
 	"github.com/natefinch/lumberjack"
 	"github.com/op/go-logging"
+	"golang.org/x/crypto/ssh/terminal"
 	"io/ioutil"
 	"net/http"
 	"net/url"
+	"os/exec"
 	"strings"
+	"sync"
+	"syscall"
+	"time"
 )
 
 var (
@@ -86,6 +95,7 @@ type Init struct {
 }
 type Start struct {
 	Password             string   `short:"p" long:"password" description:"the encryption password if the database is encrypted"`
+	PasswordFile         string   `long:"passwordfile" description:"read the encryption password from this file, or from standard input if it is -"`
 	Testnet              bool     `short:"t" long:"testnet" description:"use the test network"`
 	Regtest              bool     `short:"r" long:"regtest" description:"run in regression test mode"`
 	LogLevel             string   `short:"l" long:"loglevel" description:"set the logging level [debug, info, notice, warning, error, critical]"`
@@ -94,12 +104,42 @@ type Start struct {
 	DataDir              string   `short:"d" long:"datadir" description:"specify the data directory to be used"`
 	DisableWallet        bool     `long:"disablewallet" description:"disable the wallet functionality of the node"`
 	DisableExchangeRates bool     `long:"disableexchangerates" description:"disable the exchange rate service to prevent api queries"`
-	Storage              string   `long:"storage" description:"set the outgoing message storage option [self-hosted, dropbox] default=self-hosted"`
+	Storage              string   `long:"storage" description:"set the outgoing message storage option [self-hosted, dropbox, s3] default=self-hosted"`
+	Recover              bool     `long:"recover" description:"rebuild orders missing from the database by requesting the contracts from the peers we follow and our followers"`
+}
+type Stop struct {
+	DataDir string `short:"d" long:"datadir" description:"specify the data directory to be used"`
+	Testnet bool   `short:"t" long:"testnet" description:"use the test network"`
+}
+type Restart struct {
+	DataDir      string `short:"d" long:"datadir" description:"specify the data directory to be used"`
+	Testnet      bool   `short:"t" long:"testnet" description:"use the test network"`
+	PasswordFile string `long:"passwordfile" description:"read the encryption password from this file if the database is encrypted"`
+	Detach       bool   `long:"detach" description:"exit once the server is up, appending its output to logs/daemon.log in the data directory"`
+}
+type EncryptDatabase struct {
+	DataDir             string `short:"d" long:"datadir" description:"specify the data directory to be used"`
+	Testnet             bool   `short:"t" long:"testnet" description:"use the test network"`
+	PasswordFile        string `long:"passwordfile" description:"read the new password from this file instead of prompting for it"`
+	CurrentPasswordFile string `long:"currentpasswordfile" description:"change the password of an encrypted database, reading the current password from this file"`
+}
+type DecryptDatabase struct {
+	DataDir      string `short:"d" long:"datadir" description:"specify the data directory to be used"`
+	Testnet      bool   `short:"t" long:"testnet" description:"use the test network"`
+	PasswordFile string `long:"passwordfile" description:"read the password from this file instead of prompting for it"`
+}
+type Backup struct {
+	DataDir      string `short:"d" long:"datadir" description:"specify the data directory to be used"`
+	Testnet      bool   `short:"t" long:"testnet" description:"use the test network"`
+	Output       string `short:"o" long:"output" description:"the file to write the backup to" required:"true"`
+	PasswordFile string `long:"passwordfile" description:"read the backup password from this file instead of prompting for it"`
+}
+type Restore struct {
+	DataDir      string `short:"d" long:"datadir" description:"specify the data directory to be used"`
+	Testnet      bool   `short:"t" long:"testnet" description:"use the test network"`
+	Input        string `short:"i" long:"input" description:"the backup file to restore from" required:"true"`
+	PasswordFile string `long:"passwordfile" description:"read the backup password from this file instead of prompting for it"`
 }
-type Stop struct{}
-type Restart struct{}
-type EncryptDatabase struct{}
-type DecryptDatabase struct{}
 
 var initRepo Init
 var startServer Start
@@ -107,22 +147,28 @@ var stopServer Stop
 var restartServer Restart
 var encryptDatabase EncryptDatabase
 var decryptDatabase DecryptDatabase
+var backupRepo Backup
+var restoreRepo Restore
 
 var parser = flags.NewParser(nil, flags.Default)
 
 func main() {
 	c := make(chan os.Signal, 1)
-	signal.Notify(c, os.Interrupt)
+	signal.Notify(c, os.Interrupt, syscall.SIGTERM)
 	go func() {
 		for sig := range c {
+			if daemon := restartedDaemon(); daemon != nil {
+				// The restart command stands in for the daemon it started so signals
+				// from a supervisor or the terminal are passed on
+				if err := daemon.Signal(sig); err != nil {
+					daemon.Kill()
+				}
+				continue
+			}
 			log.Noticef("Received %s\n", sig)
 			log.Info("OpenBazaar Server shutting down...")
 			if core.Node != nil {
-				core.Node.Datastore.Close()
-				repoLockFile := filepath.Join(core.Node.RepoPath, lockfile.LockFile)
-				os.Remove(repoLockFile)
-				core.Node.Wallet.Close()
-				core.Node.IpfsNode.Close()
+				core.Node.Shutdown()
 			}
 			os.Exit(1)
 		}
@@ -142,7 +188,9 @@ func main() {
 		&stopServer)
 	parser.AddCommand("restart",
 		"restart the server",
-		"The restart command shuts down the server and restarts",
+		"The restart command shuts down the server and starts it again with the options it was started with. "+
+			"Further options for the new server can be given after --, e.g. openbazaard restart -- --tor. "+
+			"The new server runs in the foreground with its output on this terminal unless --detach is given.",
 		&restartServer)
 	parser.AddCommand("encryptdatabase",
 		"encrypt your database",
@@ -152,18 +200,438 @@ func main() {
 		"decrypt your database",
 		"This command decrypts the database containing your bitcoin private keys, identity key, and contracts.\n [Warning] doing so may put your bitcoins at risk.",
 		&decryptDatabase)
+	parser.AddCommand("backup",
+		"back up the node",
+		"This command writes an encrypted backup of the database, config and published data. The daemon must not be running. Use the /ob/backup API to back up a running node.",
+		&backupRepo)
+	parser.AddCommand("restore",
+		"restore the node from a backup",
+		"This command verifies a backup and rebuilds the repo from it. The repo must not already exist.",
+		&restoreRepo)
+	addClientCommands(parser)
 
 	if _, err := parser.Parse(); err != nil {
 		os.Exit(1)
 	}
 }
 
+func (x *Stop) Execute(args []string) error {
+	repoPath, err := getRepoPath(x.Testnet)
+	if err != nil {
+		return err
+	}
+	if x.DataDir != "" {
+		repoPath = x.DataDir
+	}
+	client, err := newAPIClient(repoPath)
+	if err != nil {
+		return err
+	}
+	if err := shutdownDaemon(client, repoPath); err != nil {
+		return err
+	}
+	fmt.Println("OpenBazaar server stopped")
+	return nil
+}
+
+func (x *Restart) Execute(args []string) error {
+	repoPath, err := getRepoPath(x.Testnet)
+	if err != nil {
+		return err
+	}
+	if x.DataDir != "" {
+		repoPath = x.DataDir
+	}
+	client, err := newAPIClient(repoPath)
+	if err != nil {
+		return err
+	}
+	started, err := loadStartCommand(repoPath)
+	if err != nil {
+		// Started by a version which didn't save its command line
+		started = &startCommand{Executable: os.Args[0], Args: []string{"start"}}
+		if x.DataDir != "" {
+			started.Args = append(started.Args, "--datadir", x.DataDir)
+		}
+		if x.Testnet {
+			started.Args = append(started.Args, "--testnet")
+		}
+	}
+	startArgs := append(started.Args, args...)
+	var password string
+	if x.PasswordFile != "" {
+		password, err = readPasswordFile(x.PasswordFile)
+		if err != nil {
+			return err
+		}
+		// Passed on standard input so it never shows up in the process list
+		startArgs = append(startArgs, "--passwordfile", "-")
+	}
+	if err := shutdownDaemon(client, repoPath); err != nil {
+		return err
+	}
+	cmd := exec.Command(started.Executable, startArgs...)
+	if password != "" {
+		cmd.Stdin = strings.NewReader(password + "\n")
+	}
+	if x.Detach {
+		out, err := os.OpenFile(path.Join(repoPath, "logs", "daemon.log"), os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0600)
+		if err != nil {
+			return err
+		}
+		defer out.Close()
+		cmd.Stdout = out
+		cmd.Stderr = out
+	} else {
+		cmd.Stdout = os.Stdout
+		cmd.Stderr = os.Stderr
+	}
+	if err := cmd.Start(); err != nil {
+		return err
+	}
+	setRestartedDaemon(cmd.Process)
+	exited := make(chan error, 1)
+	go func() {
+		exited <- cmd.Wait()
+	}()
+	timeout := time.After(daemonStartTimeout)
+	for {
+		select {
+		case err := <-exited:
+			return fmt.Errorf("the daemon exited while starting up: %v", err)
+		case <-timeout:
+			return fmt.Errorf("timed out waiting for the daemon (pid %d) to start", cmd.Process.Pid)
+		case <-time.After(250 * time.Millisecond):
+			if !client.listening() {
+				continue
+			}
+			fmt.Printf("OpenBazaar server restarted with pid %d\n", cmd.Process.Pid)
+			if x.Detach {
+				return nil
+			}
+			// Stay in the foreground until the daemon exits, the same as the start command
+			return <-exited
+		}
+	}
+}
+
+var (
+	restartedDaemonLock sync.Mutex
+	restartedDaemonProc *os.Process
+)
+
+// The daemon started by the restart command, if any
+func restartedDaemon() *os.Process {
+	restartedDaemonLock.Lock()
+	defer restartedDaemonLock.Unlock()
+	return restartedDaemonProc
+}
+
+func setRestartedDaemon(p *os.Process) {
+	restartedDaemonLock.Lock()
+	restartedDaemonProc = p
+	restartedDaemonLock.Unlock()
+}
+
+// The command line the daemon was started with. It is saved in the repo so the restart
+// command can start the daemon the same way. The database password is left out.
+type startCommand struct {
+	Executable string   `json:"executable"`
+	Args       []string `json:"args"`
+}
+
+const startCommandFile = "start_command.json"
+
+func saveStartCommand(repoPath string) error {
+	executable, err := exec.LookPath(os.Args[0])
+	if err != nil {
+		return err
+	}
+	executable, err = filepath.Abs(executable)
+	if err != nil {
+		return err
+	}
+	b, err := json.MarshalIndent(startCommand{executable, withoutPassword(os.Args[1:])}, "", "    ")
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(path.Join(repoPath, startCommandFile), b, 0600)
+}
+
+func loadStartCommand(repoPath string) (*startCommand, error) {
+	b, err := ioutil.ReadFile(path.Join(repoPath, startCommandFile))
+	if err != nil {
+		return nil, err
+	}
+	started := new(startCommand)
+	if err := json.Unmarshal(b, started); err != nil {
+		return nil, err
+	}
+	if started.Executable == "" || len(started.Args) == 0 {
+		return nil, errors.New("invalid start command")
+	}
+	return started, nil
+}
+
+// Remove the -p/--password option and its value from the start command's arguments
+func withoutPassword(args []string) []string {
+	var ret []string
+	for i := 0; i < len(args); i++ {
+		a := args[i]
+		switch {
+		case a == "--":
+			return append(ret, args[i:]...)
+		case a == "--password":
+			i++
+		case strings.HasPrefix(a, "--password="):
+		case len(a) > 1 && a[0] == '-' && a[1] != '-':
+			// Short options can be combined, as in -tp secret or -tpsecret. The
+			// value of an option which takes one runs to the end of the argument.
+			kept := a
+			for j := 1; j < len(a); j++ {
+				if a[j] == 'p' {
+					kept = a[:j]
+					if j == len(a)-1 {
+						i++
+					}
+					break
+				}
+				if strings.IndexByte("lad", a[j]) >= 0 {
+					break
+				}
+			}
+			if kept != "-" {
+				ret = append(ret, kept)
+			}
+		default:
+			ret = append(ret, a)
+		}
+	}
+	return ret
+}
+
+const (
+	daemonStopTimeout  = time.Minute
+	daemonStartTimeout = 2 * time.Minute
+)
+
+// Ask the daemon to shut down and wait for it to release the repo and stop listening
+func shutdownDaemon(client *apiClient, repoPath string) error {
+	if _, err := client.request("POST", "/ob/shutdown", nil); err != nil {
+		return err
+	}
+	deadline := time.Now().Add(daemonStopTimeout)
+	for {
+		locked, err := lockfile.Locked(repoPath)
+		if err != nil {
+			return err
+		}
+		if !locked && !client.listening() {
+			return nil
+		}
+		if time.Now().After(deadline) {
+			return errors.New("timed out waiting for the daemon to exit")
+		}
+		time.Sleep(250 * time.Millisecond)
+	}
+}
+
 func (x *EncryptDatabase) Execute(args []string) error {
-	return db.Encrypt()
+	sqliteDB, err := openDatabase(x.DataDir, x.Testnet)
+	if err != nil {
+		return err
+	}
+	defer sqliteDB.Close()
+	if x.CurrentPasswordFile != "" {
+		currentPassword, err := readPasswordFile(x.CurrentPasswordFile)
+		if err != nil {
+			return err
+		}
+		newPassword, err := readNewPassword(x.PasswordFile)
+		if err != nil {
+			return err
+		}
+		if err := sqliteDB.ChangePassword(currentPassword, newPassword); err != nil {
+			return err
+		}
+		fmt.Println("Success! You must now run openbazaard start with the new password.")
+		return nil
+	}
+	password, err := readNewPassword(x.PasswordFile)
+	if err != nil {
+		return err
+	}
+	if err := sqliteDB.Encrypt(password); err != nil {
+		return err
+	}
+	fmt.Println("Success! You must now run openbazaard start with the --password flag.")
+	return nil
 }
 
 func (x *DecryptDatabase) Execute(args []string) error {
-	return db.Decrypt()
+	sqliteDB, err := openDatabase(x.DataDir, x.Testnet)
+	if err != nil {
+		return err
+	}
+	defer sqliteDB.Close()
+	var password string
+	if x.PasswordFile != "" {
+		password, err = readPasswordFile(x.PasswordFile)
+		if err != nil {
+			return err
+		}
+	} else {
+		fmt.Print("Enter your password: ")
+		bytePassword, _ := terminal.ReadPassword(int(syscall.Stdin))
+		fmt.Println("")
+		password = string(bytePassword)
+	}
+	if err := sqliteDB.Decrypt(password); err != nil {
+		return err
+	}
+	fmt.Println("Success!")
+	return nil
+}
+
+func (x *Backup) Execute(args []string) error {
+	repoPath, err := getRepoPath(x.Testnet)
+	if err != nil {
+		return err
+	}
+	if x.DataDir != "" {
+		repoPath = x.DataDir
+	}
+	if _, err := os.Stat(filepath.Join(repoPath, lockfile.LockFile)); !os.IsNotExist(err) {
+		return errors.New("Cannot back up the repo while the daemon is running. Use the /ob/backup API instead.")
+	}
+	dbName := "mainnet.db"
+	if x.Testnet {
+		dbName = "testnet.db"
+	}
+	dbPath := path.Join(repoPath, "datastore", dbName)
+	if _, err := os.Stat(dbPath); os.IsNotExist(err) {
+		return errors.New("Database does not exist. You may need to run the daemon at least once to initialize it.")
+	}
+	password, err := readNewPassword(x.PasswordFile)
+	if err != nil {
+		return err
+	}
+	f, err := os.OpenFile(x.Output, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0600)
+	if err != nil {
+		return err
+	}
+	if err := repo.WriteBackup(f, repoPath, dbPath, password); err != nil {
+		f.Close()
+		os.Remove(x.Output)
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+	fmt.Printf("Backed up %s to %s\n", repoPath, x.Output)
+	return nil
+}
+
+func (x *Restore) Execute(args []string) error {
+	repoPath, err := getRepoPath(x.Testnet)
+	if err != nil {
+		return err
+	}
+	if x.DataDir != "" {
+		repoPath = x.DataDir
+	}
+	var password string
+	if x.PasswordFile != "" {
+		password, err = readPasswordFile(x.PasswordFile)
+		if err != nil {
+			return err
+		}
+	} else {
+		fmt.Print("Enter the backup password: ")
+		bytePassword, _ := terminal.ReadPassword(int(syscall.Stdin))
+		fmt.Println("")
+		password = string(bytePassword)
+	}
+	f, err := os.Open(x.Input)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	manifest, err := repo.RestoreBackup(f, repoPath, password, x.Testnet)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("Restored %s from the backup made %s to %s\n", manifest.PeerID, manifest.Created.Format(time.RFC1123), repoPath)
+	return nil
+}
+
+// Open the database of a repo which is not in use by a running daemon
+func openDatabase(dataDir string, testnet bool) (*db.SQLiteDatastore, error) {
+	repoPath, err := getRepoPath(testnet)
+	if err != nil {
+		return nil, err
+	}
+	if dataDir != "" {
+		repoPath = dataDir
+	}
+	if _, err := os.Stat(filepath.Join(repoPath, lockfile.LockFile)); !os.IsNotExist(err) {
+		return nil, errors.New("Cannot change the database encryption while the daemon is running")
+	}
+	if _, err := os.Stat(filepath.Join(repoPath, "datastore")); os.IsNotExist(err) {
+		return nil, errors.New("Database does not exist. You may need to run the daemon at least once to initialize it.")
+	}
+	return db.Create(repoPath, "", testnet)
+}
+
+// Read a password from the first line of a file, or of standard input if the filename is -
+func readPasswordFile(filename string) (string, error) {
+	var b []byte
+	var err error
+	if filename == "-" {
+		b, err = ioutil.ReadAll(os.Stdin)
+	} else {
+		b, err = ioutil.ReadFile(filename)
+	}
+	if err != nil {
+		return "", err
+	}
+	password := strings.SplitN(string(b), "\n", 2)[0]
+	return strings.TrimRight(password, "\r"), nil
+}
+
+// Read a new password from a file or prompt for it twice on the terminal
+func readNewPassword(filename string) (string, error) {
+	if filename != "" {
+		password, err := readPasswordFile(filename)
+		if err != nil {
+			return "", err
+		}
+		if len(password) < 8 {
+			return "", errors.New("The password must be at least 8 characters")
+		}
+		return password, nil
+	}
+	var password string
+	for {
+		fmt.Print("Enter a veerrrry strong password: ")
+		bytePassword, _ := terminal.ReadPassword(int(syscall.Stdin))
+		fmt.Println("")
+		password = string(bytePassword)
+		if len(password) >= 8 {
+			break
+		}
+		fmt.Println("You call that a password? Try again.")
+	}
+	for {
+		fmt.Print("Confirm your password: ")
+		bytePassword, _ := terminal.ReadPassword(int(syscall.Stdin))
+		fmt.Println("")
+		if string(bytePassword) == password {
+			break
+		}
+		fmt.Println("Passwords do not match. Try again.")
+	}
+	return password, nil
 }
 
 func (x *Init) Execute(args []string) error {
@@ -224,6 +692,13 @@ func (x *Start) Execute(args []string) error {
 		repoPath = x.DataDir
 	}
 
+	if x.PasswordFile != "" {
+		x.Password, err = readPasswordFile(x.PasswordFile)
+		if err != nil {
+			return err
+		}
+	}
+
 	repoLockFile := filepath.Join(repoPath, lockfile.LockFile)
 	os.Remove(repoLockFile)
 
@@ -231,6 +706,9 @@ func (x *Start) Execute(args []string) error {
 	if err != nil && err != repo.ErrRepoExists {
 		return err
 	}
+	if err := saveStartCommand(repoPath); err != nil {
+		log.Error("Error saving the start command:", err)
+	}
 
 	// Logging
 	w := &lumberjack.Logger{
@@ -403,7 +881,8 @@ func (x *Start) Execute(args []string) error {
 	ml := logging.MultiLogger(bitcoinFileFormatter)
 	var wallet bitcoin.BitcoinWallet
 	if strings.ToLower(walletCfg.Type) == "spvwallet" {
-		wallet = spvwallet.NewSPVWallet(mn, &params, uint64(walletCfg.MaxFee), uint64(walletCfg.LowFeeDefault), uint64(walletCfg.MediumFeeDefault), uint64(walletCfg.HighFeeDefault), walletCfg.FeeAPI, repoPath, sqliteDB, "OpenBazaar", walletCfg.TrustedPeer, ml)
+		spvWallet := spvwallet.NewSPVWallet(mn, &params, uint64(walletCfg.MaxFee), uint64(walletCfg.LowFeeDefault), uint64(walletCfg.MediumFeeDefault), uint64(walletCfg.HighFeeDefault), walletCfg.FeeAPI, repoPath, sqliteDB, "OpenBazaar", walletCfg.TrustedPeer, ml)
+		wallet = spv.NewWallet(spvWallet, &params, walletCfg.TrustedPeer)
 	} else if strings.ToLower(walletCfg.Type) == "bitcoind" {
 		if walletCfg.Binary == "" {
 			return errors.New("The path to the bitcoind binary must be specified in the config file when using bitcoind")
@@ -471,12 +950,30 @@ func (x *Start) Execute(args []string) error {
 			log.Error(err)
 			return err
 		}
+	} else if x.Storage == "s3" {
+		s3Config, err := repo.GetS3Config(path.Join(repoPath, "config"))
+		if err != nil {
+			log.Error(err)
+			return err
+		}
+		storage, err = s3.NewS3Storage(*s3Config)
+		if err != nil {
+			log.Error(err)
+			return err
+		}
 	} else {
 		err = errors.New("Invalid storage option")
 		log.Error(err)
 		return err
 	}
 
+	// Offline message retrieval
+	retrieverConfig, err := repo.GetRetrieverConfig(path.Join(repoPath, "config"))
+	if err != nil {
+		log.Error(err)
+		return err
+	}
+
 	// Resolver
 	resolverUrl, err := repo.GetResolverUrl(path.Join(repoPath, "config"))
 	if err != nil {
@@ -489,6 +986,18 @@ func (x *Start) Execute(args []string) error {
 		exchangeRates = exchange.NewBitcoinPriceFetcher()
 	}
 
+	// Webhooks
+	hooks, err := repo.GetWebhooks(path.Join(repoPath, "config"))
+	if err != nil {
+		log.Error(err)
+		return err
+	}
+	var dispatcher *webhooks.Dispatcher
+	if len(hooks) > 0 {
+		dispatcher = webhooks.NewDispatcher(sqliteDB, hooks)
+		go dispatcher.Run()
+	}
+
 	// OpenBazaar node setup
 	core.Node = &core.OpenBazaarNode{
 		Context:           ctx,
@@ -501,6 +1010,24 @@ func (x *Start) Execute(args []string) error {
 		Resolver:          bstk.NewBlockStackClient(resolverUrl),
 		ExchangeRates:     exchangeRates,
 		CrosspostGateways: gatewayUrls,
+		Webhooks:          dispatcher,
+	}
+	core.Node.Sessions, err = obnet.NewSessionCipher(nd.PrivateKey, sqliteDB.Sessions(), sqliteDB.Prekeys())
+	if err != nil {
+		log.Error(err)
+		return err
+	}
+	if _, err := core.Node.UpdatePrekeys(); err != nil {
+		log.Error(err)
+		return err
+	}
+	go core.Node.RunPrekeyRotation()
+	core.Node.RootDirectory = ipfs.NewDirectoryTree(path.Join(repoPath, "root"))
+	core.Node.PublishManager = core.NewPublishManager(core.Node)
+	go core.Node.PublishManager.Run()
+	if len(gatewayUrls) > 0 {
+		core.Node.Crossposter = core.NewCrossposter(core.Node, gatewayUrls)
+		go core.Node.Crossposter.Run()
 	}
 
 	var gwErrc <-chan error
@@ -523,20 +1050,31 @@ func (x *Start) Execute(args []string) error {
 		if b == true {
 			OBService := service.SetupOpenBazaarService(core.Node, ctx, sqliteDB)
 			core.Node.Service = OBService
-			MR := ret.NewMessageRetriever(sqliteDB, ctx, nd, OBService, 16, core.Node.SendOfflineAck)
+			MR := ret.NewMessageRetriever(sqliteDB, ctx, nd, OBService, 16, core.Node.SendOfflineAck, core.Node.Sessions, *retrieverConfig, core.Node.Broadcast)
 			go MR.Run()
 			core.Node.MessageRetriever = MR
-			PR := rep.NewPointerRepublisher(nd, sqliteDB)
+			PR := rep.NewPointerRepublisher(nd, sqliteDB, storage)
 			go PR.Run()
 			core.Node.PointerRepublisher = PR
 			if !x.DisableWallet {
 				MR.Wait()
-				TL := lis.NewTransactionListener(core.Node.Datastore, core.Node.Broadcast, core.Node.Wallet.Params())
+				TL := lis.NewTransactionListener(core.Node.Datastore, core.Node.Broadcast, core.Node.Wallet.Params(), core.Node.QueueDigitalDelivery)
 				wallet.AddTransactionListener(TL.OnTransactionReceived)
+				go core.Node.RunDigitalDelivery()
 				log.Info("Starting bitcoin wallet...")
 				go wallet.Start()
 			}
 			core.Node.SeedNode()
+			if x.Recover && !x.DisableWallet {
+				go func() {
+					recovered, err := core.Node.RecoverOrders(nil)
+					if err != nil {
+						log.Error("Order recovery failed: ", err)
+						return
+					}
+					log.Noticef("Recovered %d orders", len(recovered))
+				}()
+			}
 		}
 		break
 	}
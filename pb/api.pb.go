@@ -13,7 +13,9 @@ It has these top-level messages:
 	ListingRespApi
 	Inventory
 	OrderRespApi
+	FulfillmentStatus
 	TransactionRecord
+	ModeratorRespApi
 */
 package pb
 
@@ -94,6 +96,7 @@ type OrderRespApi struct {
 	Read         bool                 `protobuf:"varint,3,opt,name=read" json:"read,omitempty"`
 	Funded       bool                 `protobuf:"varint,4,opt,name=funded" json:"funded,omitempty"`
 	Transactions []*TransactionRecord `protobuf:"bytes,5,rep,name=transactions" json:"transactions,omitempty"`
+	Fulfillment  []*FulfillmentStatus `protobuf:"bytes,6,rep,name=fulfillment" json:"fulfillment,omitempty"`
 }
 
 func (m *OrderRespApi) Reset()                    { *m = OrderRespApi{} }
@@ -115,6 +118,24 @@ func (m *OrderRespApi) GetTransactions() []*TransactionRecord {
 	return nil
 }
 
+func (m *OrderRespApi) GetFulfillment() []*FulfillmentStatus {
+	if m != nil {
+		return m.Fulfillment
+	}
+	return nil
+}
+
+type FulfillmentStatus struct {
+	Slug      string `protobuf:"bytes,1,opt,name=slug" json:"slug,omitempty"`
+	Title     string `protobuf:"bytes,2,opt,name=title" json:"title,omitempty"`
+	Fulfilled bool   `protobuf:"varint,3,opt,name=fulfilled" json:"fulfilled,omitempty"`
+}
+
+func (m *FulfillmentStatus) Reset()                    { *m = FulfillmentStatus{} }
+func (m *FulfillmentStatus) String() string            { return proto.CompactTextString(m) }
+func (*FulfillmentStatus) ProtoMessage()               {}
+func (*FulfillmentStatus) Descriptor() ([]byte, []int) { return fileDescriptor5, []int{4} }
+
 type TransactionRecord struct {
 	Txid  string `protobuf:"bytes,1,opt,name=txid" json:"txid,omitempty"`
 	Value int64  `protobuf:"varint,2,opt,name=value" json:"value,omitempty"`
@@ -123,35 +144,70 @@ type TransactionRecord struct {
 func (m *TransactionRecord) Reset()                    { *m = TransactionRecord{} }
 func (m *TransactionRecord) String() string            { return proto.CompactTextString(m) }
 func (*TransactionRecord) ProtoMessage()               {}
-func (*TransactionRecord) Descriptor() ([]byte, []int) { return fileDescriptor5, []int{4} }
+func (*TransactionRecord) Descriptor() ([]byte, []int) { return fileDescriptor5, []int{5} }
+
+type ModeratorRespApi struct {
+	PeerId    string     `protobuf:"bytes,1,opt,name=peerId" json:"peerId,omitempty"`
+	Profile   *Profile   `protobuf:"bytes,2,opt,name=profile" json:"profile,omitempty"`
+	Moderator *Moderator `protobuf:"bytes,3,opt,name=moderator" json:"moderator,omitempty"`
+}
+
+func (m *ModeratorRespApi) Reset()                    { *m = ModeratorRespApi{} }
+func (m *ModeratorRespApi) String() string            { return proto.CompactTextString(m) }
+func (*ModeratorRespApi) ProtoMessage()               {}
+func (*ModeratorRespApi) Descriptor() ([]byte, []int) { return fileDescriptor5, []int{6} }
+
+func (m *ModeratorRespApi) GetProfile() *Profile {
+	if m != nil {
+		return m.Profile
+	}
+	return nil
+}
+
+func (m *ModeratorRespApi) GetModerator() *Moderator {
+	if m != nil {
+		return m.Moderator
+	}
+	return nil
+}
 
 func init() {
 	proto.RegisterType((*ListingReqApi)(nil), "ListingReqApi")
 	proto.RegisterType((*ListingRespApi)(nil), "ListingRespApi")
 	proto.RegisterType((*Inventory)(nil), "Inventory")
 	proto.RegisterType((*OrderRespApi)(nil), "OrderRespApi")
+	proto.RegisterType((*FulfillmentStatus)(nil), "FulfillmentStatus")
 	proto.RegisterType((*TransactionRecord)(nil), "TransactionRecord")
+	proto.RegisterType((*ModeratorRespApi)(nil), "ModeratorRespApi")
 }
 
 var fileDescriptor5 = []byte{
-	// 303 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x09, 0x6e, 0x88, 0x02, 0xff, 0x9c, 0x52, 0x4d, 0x4b, 0xc4, 0x30,
-	0x14, 0xa4, 0xb6, 0x5d, 0xdb, 0xb7, 0x75, 0xc5, 0x20, 0x52, 0x3c, 0xad, 0x3d, 0xf5, 0x94, 0xc3,
-	0x8a, 0xde, 0x3c, 0x88, 0x27, 0x41, 0x10, 0xa2, 0x57, 0x0f, 0xb1, 0x89, 0x12, 0x59, 0x93, 0x9a,
-	0xa4, 0x8b, 0xfe, 0x40, 0xff, 0x97, 0x49, 0xfa, 0xb1, 0x88, 0x27, 0xf7, 0xf6, 0xde, 0xcc, 0x30,
-	0x33, 0x7d, 0x0d, 0xe4, 0xb4, 0x15, 0xb8, 0xd5, 0xca, 0xaa, 0xd3, 0xc3, 0x46, 0x49, 0xab, 0x69,
-	0x63, 0xcd, 0x00, 0x14, 0x4a, 0x33, 0xae, 0x87, 0xad, 0x7a, 0x82, 0x83, 0x3b, 0x61, 0xac, 0x90,
-	0xaf, 0x84, 0x7f, 0x5c, 0xb7, 0x02, 0x55, 0xb0, 0xbf, 0xee, 0x81, 0x32, 0x5a, 0x46, 0xf5, 0x7c,
-	0x95, 0xe1, 0x51, 0x30, 0x12, 0xa8, 0x86, 0x5c, 0xc8, 0x0d, 0x97, 0x56, 0xe9, 0xaf, 0x72, 0x6f,
-	0x19, 0x3b, 0x15, 0xe0, 0xdb, 0x11, 0x21, 0x5b, 0xb2, 0x7a, 0x83, 0xc5, 0x64, 0x6f, 0x5a, 0xef,
-	0x8f, 0x21, 0x1b, 0x1b, 0x0d, 0x01, 0x08, 0x13, 0xd1, 0x50, 0xcd, 0x04, 0x95, 0x37, 0x03, 0x43,
-	0x26, 0xcd, 0x3f, 0xb2, 0x2e, 0x20, 0x9f, 0x70, 0x84, 0x20, 0x11, 0x96, 0xbf, 0x87, 0x88, 0x9c,
-	0x84, 0x19, 0x1d, 0x43, 0xda, 0xa8, 0x4e, 0x5a, 0x67, 0x13, 0xd5, 0x09, 0xe9, 0x97, 0xea, 0x3b,
-	0x82, 0xe2, 0xde, 0x9f, 0x64, 0xd7, 0x86, 0x67, 0x90, 0x1a, 0x4b, 0x2d, 0x0f, 0xb6, 0x8b, 0xd5,
-	0x1c, 0x07, 0xb7, 0x07, 0x0f, 0x91, 0x9e, 0xf1, 0x6d, 0x34, 0xa7, 0xac, 0x8c, 0x9d, 0x22, 0x23,
-	0x61, 0x46, 0x27, 0x30, 0x7b, 0xe9, 0x24, 0xe3, 0xac, 0x4c, 0x02, 0x3a, 0x6c, 0xe8, 0x12, 0x0a,
-	0xe7, 0x2b, 0x8d, 0xb3, 0x16, 0x4a, 0x9a, 0x32, 0x0d, 0xdf, 0x8c, 0xf0, 0xe3, 0x16, 0x24, 0xbc,
-	0x71, 0x3f, 0x91, 0xfc, 0xd2, 0x55, 0x57, 0x70, 0xf4, 0x47, 0xe2, 0x83, 0xed, 0xa7, 0x60, 0xe3,
-	0x19, 0xfc, 0xec, 0xcf, 0xb0, 0xa1, 0xeb, 0xae, 0xef, 0x1b, 0x93, 0x7e, 0x79, 0x9e, 0x85, 0xf7,
-	0x70, 0xfe, 0x13, 0x00, 0x00, 0xff, 0xff, 0x00, 0xbb, 0xdc, 0x5f, 0x3b, 0x02, 0x00, 0x00,
+	// 419 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x09, 0x6e, 0x88, 0x02, 0xff, 0x9c, 0x53, 0x3d, 0x8f, 0xd4, 0x30,
+	0x10, 0x55, 0xf6, 0x8b, 0xcd, 0xec, 0xde, 0xc1, 0x59, 0xe8, 0x64, 0x21, 0x8a, 0x25, 0x55, 0xaa,
+	0x14, 0xcb, 0x47, 0x47, 0x81, 0x90, 0x90, 0x4e, 0x02, 0x81, 0x0c, 0x1d, 0xa2, 0x30, 0xb1, 0xf7,
+	0x64, 0xe4, 0xb5, 0x83, 0x3d, 0x39, 0x1d, 0xbf, 0x9d, 0x06, 0xf9, 0x6b, 0x03, 0xba, 0x8a, 0xeb,
+	0xe6, 0xbd, 0x19, 0xbf, 0x79, 0x76, 0x5e, 0xa0, 0xe6, 0x83, 0xea, 0x06, 0x67, 0xd1, 0x3e, 0x79,
+	0xd8, 0x5b, 0x83, 0x8e, 0xf7, 0xe8, 0x33, 0xb1, 0xb5, 0x4e, 0x48, 0x57, 0xd0, 0xd9, 0xe0, 0xec,
+	0x41, 0x69, 0x59, 0xa6, 0x8f, 0x56, 0x48, 0xc7, 0xd1, 0xba, 0x44, 0x34, 0xdf, 0xe0, 0xec, 0xbd,
+	0xf2, 0xa8, 0xcc, 0x35, 0x93, 0x3f, 0xdf, 0x0c, 0x8a, 0x34, 0xf0, 0x40, 0x27, 0x82, 0x56, 0xbb,
+	0xaa, 0xdd, 0xec, 0xd7, 0x5d, 0x19, 0x28, 0x0d, 0xd2, 0x42, 0xad, 0xcc, 0x8d, 0x34, 0x68, 0xdd,
+	0x2f, 0x3a, 0xdb, 0xcd, 0xdb, 0xcd, 0x1e, 0xba, 0xab, 0xc2, 0xb0, 0xa9, 0xd9, 0xfc, 0x80, 0xf3,
+	0x93, 0xbc, 0x1f, 0x82, 0x7e, 0x07, 0xeb, 0xe2, 0x38, 0x2f, 0x20, 0x1d, 0x53, 0x3d, 0x77, 0x42,
+	0x71, 0xf3, 0x36, 0x77, 0xd8, 0x69, 0xe6, 0x3f, 0x76, 0xbd, 0x84, 0xfa, 0xc4, 0x13, 0x02, 0x0b,
+	0x85, 0xf2, 0x18, 0x57, 0xd4, 0x2c, 0xd6, 0xe4, 0x31, 0x2c, 0x7b, 0x3b, 0x1a, 0xa4, 0xb3, 0x5d,
+	0xd5, 0x2e, 0x58, 0x02, 0xcd, 0xef, 0x0a, 0xb6, 0x1f, 0xc3, 0x93, 0xdd, 0xd7, 0xe1, 0x33, 0x58,
+	0x7a, 0xe4, 0x28, 0xa3, 0xec, 0xf9, 0x7e, 0xd3, 0x45, 0xb5, 0xcf, 0x81, 0x62, 0xa9, 0x13, 0xdc,
+	0x38, 0xc9, 0x05, 0x9d, 0xef, 0xaa, 0x76, 0xcd, 0x62, 0x4d, 0x2e, 0x61, 0x75, 0x18, 0x8d, 0x90,
+	0x82, 0x2e, 0x22, 0x9b, 0x11, 0x79, 0x05, 0x5b, 0x74, 0xdc, 0x78, 0xde, 0xa3, 0xb2, 0xc6, 0xd3,
+	0x65, 0xbc, 0x33, 0xe9, 0xbe, 0x4c, 0x24, 0x93, 0xbd, 0x75, 0x82, 0xfd, 0x33, 0x47, 0x5e, 0xc0,
+	0xe6, 0x30, 0xea, 0x83, 0xd2, 0xfa, 0x28, 0x0d, 0xd2, 0x55, 0x3e, 0xf6, 0x6e, 0xe2, 0x82, 0xa5,
+	0xd1, 0xb3, 0xbf, 0xc7, 0x9a, 0xaf, 0x70, 0x71, 0x67, 0x22, 0xd8, 0xf5, 0x7a, 0xbc, 0x2e, 0x8f,
+	0x17, 0xea, 0xf0, 0x78, 0xa8, 0x50, 0xa7, 0x5b, 0xd6, 0x2c, 0x01, 0xf2, 0x14, 0xea, 0xac, 0x26,
+	0xcb, 0xed, 0x26, 0xa2, 0x79, 0x0d, 0x17, 0x77, 0x5c, 0x07, 0x71, 0xbc, 0x55, 0xa2, 0x88, 0x87,
+	0x3a, 0x88, 0xdf, 0x70, 0x3d, 0x26, 0xf1, 0x39, 0x4b, 0xa0, 0xb9, 0x85, 0x47, 0x1f, 0x4a, 0x5c,
+	0xcb, 0xc7, 0xb9, 0x84, 0xd5, 0x20, 0xa5, 0xbb, 0x2a, 0xe7, 0x33, 0x0a, 0xb1, 0xcd, 0x49, 0xa7,
+	0xb3, 0x1c, 0xdb, 0x4f, 0x09, 0xb3, 0xd2, 0x08, 0x51, 0x3a, 0xc5, 0x3f, 0x9a, 0x0d, 0x51, 0x9a,
+	0x36, 0x4c, 0xcd, 0xef, 0xab, 0xf8, 0x73, 0x3c, 0xff, 0x33, 0x00, 0xdf, 0x84, 0x7c, 0xbe, 0x68,
+	0x03, 0x00, 0x00,
 }
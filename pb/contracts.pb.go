@@ -23,6 +23,7 @@ It has these top-level messages:
 	Refund
 	ID
 	Signature
+	ContractList
 */
 package pb
 
@@ -220,6 +221,7 @@ type RicardianContract struct {
 	DisputeResolution       *DisputeResolution  `protobuf:"bytes,7,opt,name=disputeResolution" json:"disputeResolution,omitempty"`
 	Refund                  *Refund             `protobuf:"bytes,8,opt,name=refund" json:"refund,omitempty"`
 	Signatures              []*Signature        `protobuf:"bytes,9,rep,name=signatures" json:"signatures,omitempty"`
+	PartialRefunds          []*Refund           `protobuf:"bytes,10,rep,name=partialRefunds" json:"partialRefunds,omitempty"`
 }
 
 func (m *RicardianContract) Reset()                    { *m = RicardianContract{} }
@@ -290,6 +292,13 @@ func (m *RicardianContract) GetSignatures() []*Signature {
 	return nil
 }
 
+func (m *RicardianContract) GetPartialRefunds() []*Refund {
+	if m != nil {
+		return m.PartialRefunds
+	}
+	return nil
+}
+
 type Listing struct {
 	Slug               string                    `protobuf:"bytes,1,opt,name=slug" json:"slug,omitempty"`
 	VendorID           *ID                       `protobuf:"bytes,2,opt,name=vendorID" json:"vendorID,omitempty"`
@@ -772,13 +781,15 @@ func (*Order_Item_ShippingOption) ProtoMessage()               {}
 func (*Order_Item_ShippingOption) Descriptor() ([]byte, []int) { return fileDescriptor1, []int{2, 1, 1} }
 
 type Order_Payment struct {
-	Method       Order_Payment_Method `protobuf:"varint,1,opt,name=method,enum=Order_Payment_Method" json:"method,omitempty"`
-	Moderator    string               `protobuf:"bytes,2,opt,name=moderator" json:"moderator,omitempty"`
-	Amount       uint64               `protobuf:"varint,3,opt,name=amount" json:"amount,omitempty"`
-	ExchangeRate uint64               `protobuf:"varint,4,opt,name=exchangeRate" json:"exchangeRate,omitempty"`
-	Chaincode    string               `protobuf:"bytes,6,opt,name=chaincode" json:"chaincode,omitempty"`
-	Address      string               `protobuf:"bytes,7,opt,name=address" json:"address,omitempty"`
-	RedeemScript string               `protobuf:"bytes,8,opt,name=redeemScript" json:"redeemScript,omitempty"`
+	Method           Order_Payment_Method `protobuf:"varint,1,opt,name=method,enum=Order_Payment_Method" json:"method,omitempty"`
+	Moderator        string               `protobuf:"bytes,2,opt,name=moderator" json:"moderator,omitempty"`
+	Amount           uint64               `protobuf:"varint,3,opt,name=amount" json:"amount,omitempty"`
+	ExchangeRate     uint64               `protobuf:"varint,4,opt,name=exchangeRate" json:"exchangeRate,omitempty"`
+	Chaincode        string               `protobuf:"bytes,6,opt,name=chaincode" json:"chaincode,omitempty"`
+	Address          string               `protobuf:"bytes,7,opt,name=address" json:"address,omitempty"`
+	RedeemScript     string               `protobuf:"bytes,8,opt,name=redeemScript" json:"redeemScript,omitempty"`
+	ModeratorFee     uint64               `protobuf:"varint,9,opt,name=moderatorFee" json:"moderatorFee,omitempty"`
+	ModeratorAddress string               `protobuf:"bytes,10,opt,name=moderatorAddress" json:"moderatorAddress,omitempty"`
 }
 
 func (m *Order_Payment) Reset()                    { *m = Order_Payment{} }
@@ -1069,9 +1080,12 @@ func (*DisputeResolution) ProtoMessage()               {}
 func (*DisputeResolution) Descriptor() ([]byte, []int) { return fileDescriptor1, []int{10} }
 
 type Refund struct {
-	OrderID string              `protobuf:"bytes,1,opt,name=orderID" json:"orderID,omitempty"`
-	Sigs    []*BitcoinSignature `protobuf:"bytes,2,rep,name=sigs" json:"sigs,omitempty"`
-	Memo    string              `protobuf:"bytes,3,opt,name=memo" json:"memo,omitempty"`
+	OrderID string                   `protobuf:"bytes,1,opt,name=orderID" json:"orderID,omitempty"`
+	Sigs    []*BitcoinSignature      `protobuf:"bytes,2,rep,name=sigs" json:"sigs,omitempty"`
+	Memo    string                   `protobuf:"bytes,3,opt,name=memo" json:"memo,omitempty"`
+	Amount  uint64                   `protobuf:"varint,4,opt,name=amount" json:"amount,omitempty"`
+	Items   []*Refund_Item           `protobuf:"bytes,5,rep,name=items" json:"items,omitempty"`
+	Payout  *OrderFulfillment_Payout `protobuf:"bytes,6,opt,name=payout" json:"payout,omitempty"`
 }
 
 func (m *Refund) Reset()                    { *m = Refund{} }
@@ -1086,6 +1100,30 @@ func (m *Refund) GetSigs() []*BitcoinSignature {
 	return nil
 }
 
+func (m *Refund) GetItems() []*Refund_Item {
+	if m != nil {
+		return m.Items
+	}
+	return nil
+}
+
+func (m *Refund) GetPayout() *OrderFulfillment_Payout {
+	if m != nil {
+		return m.Payout
+	}
+	return nil
+}
+
+type Refund_Item struct {
+	ListingHash string `protobuf:"bytes,1,opt,name=listingHash" json:"listingHash,omitempty"`
+	Quantity    uint32 `protobuf:"varint,2,opt,name=quantity" json:"quantity,omitempty"`
+}
+
+func (m *Refund_Item) Reset()                    { *m = Refund_Item{} }
+func (m *Refund_Item) String() string            { return proto.CompactTextString(m) }
+func (*Refund_Item) ProtoMessage()               {}
+func (*Refund_Item) Descriptor() ([]byte, []int) { return fileDescriptor1, []int{11, 0} }
+
 type ID struct {
 	Guid         string      `protobuf:"bytes,1,opt,name=guid" json:"guid,omitempty"`
 	BlockchainID string      `protobuf:"bytes,2,opt,name=blockchainID" json:"blockchainID,omitempty"`
@@ -1125,6 +1163,22 @@ func (m *Signature) String() string            { return proto.CompactTextString(
 func (*Signature) ProtoMessage()               {}
 func (*Signature) Descriptor() ([]byte, []int) { return fileDescriptor1, []int{13} }
 
+type ContractList struct {
+	Contracts []*RicardianContract `protobuf:"bytes,1,rep,name=contracts" json:"contracts,omitempty"`
+}
+
+func (m *ContractList) Reset()                    { *m = ContractList{} }
+func (m *ContractList) String() string            { return proto.CompactTextString(m) }
+func (*ContractList) ProtoMessage()               {}
+func (*ContractList) Descriptor() ([]byte, []int) { return fileDescriptor1, []int{14} }
+
+func (m *ContractList) GetContracts() []*RicardianContract {
+	if m != nil {
+		return m.Contracts
+	}
+	return nil
+}
+
 func init() {
 	proto.RegisterType((*RicardianContract)(nil), "RicardianContract")
 	proto.RegisterType((*Listing)(nil), "Listing")
@@ -1160,9 +1214,11 @@ func init() {
 	proto.RegisterType((*Dispute)(nil), "Dispute")
 	proto.RegisterType((*DisputeResolution)(nil), "DisputeResolution")
 	proto.RegisterType((*Refund)(nil), "Refund")
+	proto.RegisterType((*Refund_Item)(nil), "Refund.Item")
 	proto.RegisterType((*ID)(nil), "ID")
 	proto.RegisterType((*ID_Pubkeys)(nil), "ID.Pubkeys")
 	proto.RegisterType((*Signature)(nil), "Signature")
+	proto.RegisterType((*ContractList)(nil), "ContractList")
 	proto.RegisterEnum("Listing_Metadata_ContractType", Listing_Metadata_ContractType_name, Listing_Metadata_ContractType_value)
 	proto.RegisterEnum("Listing_Metadata_Format", Listing_Metadata_Format_name, Listing_Metadata_Format_value)
 	proto.RegisterEnum("Listing_ShippingOption_ShippingType", Listing_ShippingOption_ShippingType_name, Listing_ShippingOption_ShippingType_value)
@@ -1172,176 +1228,182 @@ func init() {
 }
 
 var fileDescriptor1 = []byte{
-	// 2735 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x09, 0x6e, 0x88, 0x02, 0xff, 0xa4, 0x59, 0x4b, 0x93, 0x1b, 0x49,
-	0xf1, 0x5f, 0x3d, 0x46, 0x8f, 0x9a, 0x97, 0xa6, 0x6c, 0xaf, 0xf5, 0xef, 0x3f, 0xfb, 0x52, 0xd8,
-	0x1b, 0xde, 0x65, 0xe9, 0x65, 0xcd, 0x81, 0xe5, 0x11, 0x80, 0x46, 0xd2, 0xd8, 0x8d, 0x67, 0x24,
-	0x6d, 0x49, 0xb3, 0xcb, 0x1e, 0x88, 0x89, 0x1e, 0x75, 0x8d, 0xa6, 0xb1, 0xa4, 0xd6, 0x76, 0xb7,
-	0x6c, 0xcf, 0x91, 0x03, 0x17, 0x2e, 0x44, 0x10, 0x44, 0xec, 0x8d, 0x33, 0x77, 0xae, 0x7c, 0x02,
-	0x8e, 0x44, 0x70, 0x22, 0x20, 0x38, 0x71, 0x20, 0x08, 0xbe, 0x00, 0x07, 0xc8, 0xcc, 0xaa, 0x7e,
-	0x4a, 0x33, 0x36, 0xc1, 0x69, 0x3a, 0x1f, 0x55, 0xaa, 0xca, 0xcc, 0xfa, 0xe5, 0xaf, 0x6a, 0xd8,
-	0xfe, 0xc4, 0x5b, 0x84, 0xbe, 0x3d, 0x09, 0x03, 0x73, 0xe9, 0x7b, 0xa1, 0x67, 0xf0, 0x89, 0xb7,
-	0x02, 0xcd, 0xd5, 0xc4, 0x73, 0x64, 0xa4, 0x7b, 0x6b, 0xea, 0x79, 0xd3, 0x99, 0xfc, 0x90, 0xa4,
-	0xf3, 0xd5, 0xc5, 0x87, 0xa1, 0x3b, 0x97, 0x41, 0x68, 0xcf, 0x97, 0xca, 0xa1, 0xf5, 0xef, 0x12,
-	0x3b, 0x10, 0xee, 0xc4, 0xf6, 0x1d, 0xd7, 0x5e, 0x74, 0xf4, 0x8c, 0xfc, 0xeb, 0x6c, 0xef, 0x99,
-	0x5c, 0x38, 0x9e, 0x7f, 0xec, 0x06, 0xa1, 0xbb, 0x98, 0x06, 0xcd, 0xc2, 0xdb, 0xa5, 0x07, 0xdb,
-	0x0f, 0x6b, 0xa6, 0x56, 0x88, 0x9c, 0x9d, 0xbf, 0xcb, 0xd8, 0xf9, 0xea, 0x4a, 0xfa, 0x03, 0xdf,
-	0x91, 0x7e, 0xb3, 0xf8, 0x76, 0x01, 0xbc, 0x2b, 0x26, 0x49, 0x22, 0x65, 0xe1, 0xc7, 0xec, 0xae,
-	0x1a, 0x49, 0x22, 0xfc, 0xe0, 0x85, 0xeb, 0xcf, 0xed, 0xd0, 0xf5, 0x16, 0xcd, 0x12, 0x0d, 0xe2,
-	0xe6, 0x9a, 0x45, 0x5c, 0x37, 0x84, 0x5b, 0xec, 0xf5, 0x94, 0xe9, 0x68, 0x35, 0xbb, 0x70, 0x67,
-	0xb3, 0xb9, 0x5c, 0x84, 0xcd, 0x32, 0xad, 0xf7, 0xc0, 0xcc, 0x1b, 0xc4, 0x35, 0x03, 0x78, 0x97,
-	0xdd, 0x4e, 0x96, 0xd9, 0xf1, 0xe6, 0xcb, 0x99, 0xa4, 0x55, 0x6d, 0xd1, 0xaa, 0x1a, 0x66, 0x4e,
-	0x2f, 0x36, 0x7a, 0xf3, 0x16, 0xab, 0x3a, 0x6e, 0xb0, 0x5c, 0x85, 0xb2, 0x59, 0xa1, 0x81, 0x35,
-	0xb3, 0xab, 0x64, 0x11, 0x19, 0xf8, 0x0f, 0xd8, 0x81, 0xfe, 0x14, 0x32, 0xf0, 0x66, 0x2b, 0xfa,
-	0x99, 0xaa, 0xde, 0x7c, 0x37, 0x6f, 0x11, 0xeb, 0xce, 0xfc, 0x2d, 0x56, 0xf1, 0xe5, 0xc5, 0x6a,
-	0xe1, 0x34, 0x6b, 0x34, 0xac, 0x6a, 0x0a, 0x12, 0x85, 0x56, 0xf3, 0xf7, 0x19, 0x0b, 0xdc, 0xe9,
-	0xc2, 0x0e, 0x57, 0xbe, 0x0c, 0x9a, 0x75, 0x8a, 0x05, 0x33, 0x47, 0x91, 0x4a, 0xa4, 0xac, 0xad,
-	0x9f, 0xdd, 0x61, 0x55, 0x9d, 0x46, 0xce, 0x59, 0x39, 0x98, 0xad, 0xa6, 0x90, 0xed, 0xc2, 0x83,
-	0xba, 0xa0, 0x6f, 0xf8, 0xb1, 0x9a, 0x0a, 0x99, 0xd5, 0xd5, 0x79, 0x2d, 0x99, 0x56, 0x57, 0xc4,
-	0x4a, 0xfe, 0x35, 0x56, 0x9b, 0xcb, 0xd0, 0x76, 0xec, 0xd0, 0xd6, 0x39, 0x3c, 0x88, 0xca, 0xc4,
-	0x3c, 0xd1, 0x06, 0x11, 0xbb, 0xf0, 0x77, 0x58, 0xd9, 0x0d, 0xe5, 0x1c, 0x32, 0x84, 0xae, 0xbb,
-	0xb1, 0xab, 0x05, 0x4a, 0x41, 0x26, 0xde, 0x66, 0xfb, 0xc1, 0xa5, 0xbb, 0x5c, 0x82, 0x7a, 0xb0,
-	0xc4, 0x1d, 0x07, 0x90, 0x06, 0xdc, 0xc3, 0xdd, 0xd8, 0x7b, 0x94, 0xb1, 0x8b, 0xbc, 0x3f, 0x24,
-	0x62, 0x2b, 0xb4, 0x5f, 0xc0, 0xe6, 0x2b, 0x34, 0x70, 0x27, 0x1e, 0x38, 0xb6, 0x5f, 0x08, 0x65,
-	0xe2, 0xef, 0xb1, 0x2a, 0x1c, 0x99, 0x25, 0x4e, 0x5f, 0x25, 0xaf, 0xfd, 0xd8, 0xab, 0x43, 0x7a,
-	0x11, 0xd9, 0xf9, 0x9b, 0x8c, 0xcd, 0xe1, 0x58, 0xf9, 0x76, 0xe8, 0xf9, 0x01, 0x44, 0xbd, 0x04,
-	0xe1, 0x49, 0x69, 0xb8, 0xc9, 0x78, 0x28, 0xfd, 0x79, 0xd0, 0x5e, 0x38, 0x50, 0xa0, 0x8e, 0xab,
-	0x16, 0x5d, 0xa7, 0x30, 0x6e, 0xb0, 0xc0, 0xf2, 0x76, 0x54, 0xaa, 0x86, 0xde, 0xcc, 0x9d, 0x5c,
-	0x35, 0x19, 0x79, 0x66, 0x74, 0xc6, 0xaf, 0x4b, 0xac, 0x16, 0xc5, 0x8f, 0x37, 0x59, 0xf5, 0x99,
-	0xf4, 0x03, 0x2c, 0x15, 0x4c, 0xce, 0xae, 0x88, 0x44, 0x7e, 0xc8, 0x76, 0x22, 0x24, 0x18, 0x5f,
-	0x2d, 0x25, 0xe5, 0x68, 0xef, 0xe1, 0x9b, 0x6b, 0x29, 0x30, 0x3b, 0x29, 0x2f, 0x91, 0x19, 0x03,
-	0xe7, 0xbd, 0x72, 0xe1, 0xe1, 0xa1, 0xa2, 0x04, 0xee, 0x3d, 0x6c, 0xae, 0x8f, 0x3e, 0x22, 0xbb,
-	0xd0, 0x7e, 0xfc, 0x21, 0xab, 0xc8, 0x17, 0x4b, 0xd7, 0xbf, 0xd2, 0x79, 0x34, 0x4c, 0x85, 0x34,
-	0x66, 0x84, 0x34, 0xe6, 0x38, 0x42, 0x1a, 0xa1, 0x3d, 0xa1, 0x2a, 0x1b, 0xf6, 0x64, 0x22, 0x97,
-	0xa1, 0x74, 0x3a, 0x2b, 0xdf, 0x97, 0x0b, 0xd8, 0xf8, 0x16, 0x6d, 0x7c, 0x4d, 0xcf, 0x1f, 0xb0,
-	0xfd, 0xa5, 0xef, 0x4e, 0x60, 0x09, 0xb1, 0x6b, 0x85, 0x5c, 0xf3, 0xea, 0xd6, 0x90, 0xed, 0xa4,
-	0x77, 0xc6, 0x0f, 0xd8, 0xee, 0xf0, 0xf1, 0xe7, 0x23, 0xab, 0xd3, 0x3e, 0x3e, 0x7b, 0x34, 0x18,
-	0x74, 0x1b, 0xaf, 0xf1, 0x06, 0xdb, 0xe9, 0x5a, 0x8f, 0xac, 0x71, 0xa4, 0x29, 0xf0, 0x6d, 0x56,
-	0x1d, 0xf5, 0xc4, 0xa7, 0x56, 0xa7, 0xd7, 0x28, 0xf2, 0x3d, 0xc6, 0x3a, 0x62, 0xf0, 0x59, 0xf7,
-	0xec, 0xe8, 0xb4, 0xdf, 0x6d, 0x94, 0x5a, 0xef, 0xb2, 0x8a, 0xda, 0x2d, 0xdf, 0x67, 0xdb, 0x47,
-	0xd6, 0x8f, 0x7a, 0xdd, 0xb3, 0xa1, 0x40, 0xd7, 0xd7, 0x70, 0x5c, 0xfb, 0xb4, 0x33, 0xb6, 0x06,
-	0xfd, 0x46, 0xc1, 0xf8, 0xe7, 0x16, 0x2b, 0x63, 0xd5, 0xf2, 0xdb, 0x50, 0x6c, 0x6e, 0x38, 0x93,
-	0xfa, 0xdc, 0x28, 0x81, 0xbf, 0xcd, 0xb6, 0x01, 0x88, 0x27, 0xbe, 0x4b, 0x25, 0x49, 0x79, 0xa9,
-	0x8b, 0xb4, 0x0a, 0x40, 0x73, 0x0f, 0xc2, 0x35, 0x91, 0x41, 0x00, 0x1b, 0xc2, 0x78, 0x51, 0xf8,
-	0xeb, 0x22, 0xa7, 0xc5, 0xf9, 0x71, 0xd7, 0x92, 0x62, 0x5d, 0x16, 0x4a, 0xc0, 0xc3, 0xba, 0x08,
-	0x2e, 0x9e, 0x53, 0x08, 0x6b, 0x82, 0xbe, 0x51, 0x17, 0xda, 0x53, 0x55, 0xf5, 0x70, 0x80, 0xf1,
-	0x9b, 0x7f, 0x95, 0x55, 0xdc, 0xb9, 0x3d, 0x95, 0x51, 0x95, 0xdf, 0xca, 0x1c, 0x39, 0xd3, 0x42,
-	0x9b, 0xd0, 0x2e, 0x58, 0xe8, 0x13, 0x3b, 0x94, 0x53, 0xcf, 0x77, 0x65, 0x5c, 0xe8, 0x89, 0x06,
-	0x42, 0x59, 0x0a, 0x9e, 0xae, 0x74, 0x65, 0xe3, 0x27, 0x2e, 0x6e, 0xea, 0xdb, 0xf3, 0x80, 0x6a,
-	0xb8, 0x28, 0x94, 0xc0, 0xbf, 0xc2, 0xea, 0x93, 0xa8, 0xdc, 0x9b, 0xdb, 0xe4, 0x9d, 0x28, 0xe0,
-	0xb8, 0x54, 0x3d, 0x7d, 0xb0, 0x77, 0x68, 0x4d, 0xb7, 0xb3, 0x6b, 0xd2, 0xa7, 0x3a, 0x72, 0x32,
-	0xfe, 0x5e, 0x60, 0x15, 0xa5, 0xa3, 0x5d, 0xdb, 0xf3, 0x28, 0xd4, 0xf4, 0xfd, 0x0a, 0x91, 0xfe,
-	0x16, 0x80, 0x98, 0xed, 0x43, 0x8b, 0x0b, 0x03, 0x88, 0x31, 0xfe, 0xe2, 0x1b, 0x9b, 0x7e, 0xd1,
-	0xfc, 0x54, 0x3b, 0x89, 0xd8, 0xdd, 0xf0, 0x58, 0x2d, 0xd2, 0x6e, 0xfc, 0xf1, 0xf7, 0xd8, 0x16,
-	0xc5, 0x4e, 0x83, 0xe3, 0xc6, 0xe8, 0x2a, 0x0f, 0x7e, 0x8f, 0xed, 0x52, 0xea, 0x4e, 0x3c, 0xc7,
-	0xbd, 0x70, 0xa1, 0x4f, 0x62, 0xba, 0x4b, 0x22, 0xab, 0x34, 0xbe, 0x2c, 0xb0, 0x2d, 0x1a, 0xc6,
-	0x0d, 0x56, 0x83, 0xfe, 0x24, 0x53, 0x3f, 0x19, 0xcb, 0x68, 0x83, 0x8c, 0x4c, 0xdd, 0x85, 0x3d,
-	0xd3, 0x1b, 0x8e, 0x65, 0x4c, 0xc9, 0xcc, 0xf6, 0xa7, 0x51, 0x39, 0x29, 0x81, 0xbf, 0xce, 0x2a,
-	0x73, 0xe9, 0xb8, 0x2b, 0x05, 0xbd, 0x75, 0xa1, 0x25, 0xf4, 0x0e, 0xe6, 0xf6, 0x6c, 0xa6, 0xcf,
-	0xa2, 0x12, 0xa8, 0x92, 0xdc, 0x45, 0x74, 0xea, 0xe8, 0xdb, 0xf8, 0x6d, 0x85, 0xed, 0x65, 0x81,
-	0x77, 0x63, 0x44, 0x3e, 0x86, 0xa1, 0x09, 0x12, 0xdd, 0xbb, 0x06, 0xb3, 0x63, 0x91, 0xf0, 0x88,
-	0x46, 0xc0, 0x81, 0xa8, 0xfa, 0x72, 0x4a, 0x75, 0x81, 0x59, 0xda, 0x03, 0xdc, 0xee, 0x28, 0x52,
-	0xd3, 0x01, 0xac, 0x15, 0x91, 0x91, 0x3f, 0x61, 0xbb, 0x11, 0xe0, 0x8b, 0xd5, 0x0c, 0x0a, 0x55,
-	0x81, 0xd0, 0xfd, 0x97, 0xfd, 0x14, 0x39, 0x8b, 0xec, 0x58, 0xfe, 0x1d, 0x56, 0x0b, 0xa4, 0xff,
-	0x0c, 0x52, 0x10, 0xb5, 0x99, 0xb7, 0xae, 0x9d, 0x47, 0xf9, 0x89, 0x78, 0x80, 0x61, 0x03, 0x90,
-	0xa8, 0xef, 0x8d, 0xa1, 0x88, 0x4f, 0x6e, 0x31, 0x7d, 0x72, 0x3f, 0x60, 0x07, 0x00, 0x8d, 0x50,
-	0x13, 0x80, 0x78, 0x5d, 0x39, 0x73, 0x01, 0xca, 0xaf, 0x74, 0xae, 0xd6, 0x0d, 0xc6, 0xcf, 0x4b,
-	0x6c, 0x37, 0xb3, 0x01, 0xfe, 0x43, 0x56, 0xf3, 0xe1, 0x83, 0xe0, 0xbe, 0x40, 0x41, 0x36, 0x5f,
-	0x69, 0xe7, 0xa6, 0xd0, 0xa3, 0x44, 0x3c, 0x1e, 0xd8, 0xc8, 0x96, 0x4f, 0x21, 0x2c, 0xd2, 0xd6,
-	0xdf, 0x7f, 0xf5, 0x89, 0x84, 0x1a, 0x68, 0x8c, 0x59, 0x19, 0x45, 0xac, 0xc8, 0xb9, 0xbb, 0x10,
-	0xf6, 0x62, 0x2a, 0x75, 0x8f, 0x8a, 0x65, 0xb2, 0x41, 0xe3, 0x25, 0x5b, 0x51, 0xdb, 0xb4, 0x9c,
-	0xc4, 0xa8, 0x94, 0x8a, 0x51, 0xeb, 0x57, 0x05, 0x56, 0x8b, 0x96, 0xcb, 0xef, 0xb0, 0x83, 0x4f,
-	0x4e, 0xdb, 0xfd, 0xb1, 0x35, 0xfe, 0xfc, 0xac, 0x6b, 0x8d, 0x3a, 0x83, 0xd3, 0xfe, 0x18, 0xd0,
-	0xf8, 0xff, 0xd9, 0xdd, 0xa3, 0xe3, 0xf6, 0xf8, 0xec, 0xa8, 0xd7, 0x3b, 0x8b, 0xed, 0xa2, 0xdd,
-	0x7f, 0xd4, 0x03, 0x88, 0xff, 0x3f, 0x76, 0x27, 0x36, 0x7e, 0xd6, 0xb3, 0x1e, 0x3d, 0x1e, 0x6b,
-	0x53, 0x11, 0x4d, 0x9d, 0xc1, 0xc9, 0xa1, 0xd5, 0x07, 0x64, 0x1f, 0x3d, 0xb6, 0x86, 0x43, 0xab,
-	0xff, 0xe8, 0xac, 0xdd, 0x05, 0xec, 0x07, 0xfc, 0x33, 0xd6, 0x4d, 0xa3, 0xd3, 0xc3, 0xb1, 0x68,
-	0x77, 0xc6, 0x8d, 0x72, 0xeb, 0x23, 0xb6, 0x93, 0xae, 0x5b, 0x6c, 0x2d, 0xc7, 0x03, 0x6c, 0x35,
-	0x43, 0xab, 0xf3, 0xe4, 0x74, 0x08, 0x8b, 0xca, 0xf5, 0x8c, 0x82, 0xf1, 0x8b, 0x02, 0x2b, 0x01,
-	0xeb, 0xc0, 0x16, 0x0e, 0xbc, 0x23, 0x4e, 0x5a, 0x5d, 0x44, 0x22, 0xd4, 0x03, 0x83, 0x4f, 0xa1,
-	0x2b, 0xbf, 0xb8, 0xa1, 0xf2, 0x53, 0x76, 0x44, 0x3b, 0x90, 0xa2, 0x55, 0x50, 0xd4, 0x6a, 0x22,
-	0xad, 0x42, 0x10, 0x5f, 0x4a, 0x7f, 0x02, 0xb4, 0x16, 0x71, 0xa9, 0x4c, 0xb8, 0x9c, 0xd2, 0x18,
-	0xbf, 0x04, 0x38, 0x55, 0x0c, 0xe7, 0x9a, 0xd6, 0x05, 0xa5, 0x7c, 0x69, 0x07, 0x97, 0x1a, 0x58,
-	0xe8, 0x1b, 0xba, 0xf7, 0xbe, 0x9e, 0x02, 0x38, 0x2a, 0x5d, 0x35, 0xe8, 0xa7, 0x8b, 0x8f, 0x5f,
-	0x13, 0x79, 0x03, 0x9c, 0x63, 0x85, 0x69, 0xb1, 0x27, 0x35, 0x2e, 0xf0, 0xcc, 0xaa, 0x0f, 0x19,
-	0xab, 0x39, 0xfa, 0xbb, 0xf5, 0xc7, 0x3a, 0xdb, 0x52, 0x77, 0x04, 0x80, 0x49, 0x45, 0x84, 0xda,
-	0x8e, 0x03, 0x0c, 0x35, 0xd0, 0x6b, 0xcb, 0x2a, 0xb1, 0xc3, 0x28, 0xc5, 0x91, 0x8c, 0x8e, 0x57,
-	0xa2, 0x80, 0xa6, 0x57, 0x0b, 0xd2, 0x11, 0x42, 0x72, 0x47, 0xb3, 0x27, 0x85, 0x1c, 0x3b, 0xf0,
-	0x37, 0x58, 0x95, 0xd8, 0x3c, 0x30, 0xdc, 0x72, 0xc2, 0x70, 0x23, 0x1d, 0xe0, 0x59, 0x3d, 0xbe,
-	0x36, 0xe9, 0xfb, 0xc0, 0x4d, 0x74, 0x27, 0x71, 0x06, 0xae, 0xbb, 0x85, 0x84, 0x36, 0x62, 0xa1,
-	0xdb, 0x7a, 0x09, 0x44, 0x75, 0x95, 0x05, 0x88, 0x4e, 0x75, 0x69, 0x5f, 0xd1, 0x9d, 0x45, 0xdd,
-	0x01, 0xf6, 0xb4, 0xd3, 0x50, 0x69, 0x45, 0x64, 0xc6, 0xac, 0x02, 0xdb, 0x84, 0xf5, 0x3e, 0x91,
-	0x57, 0xaa, 0x35, 0xef, 0x88, 0x94, 0x06, 0x28, 0xd9, 0x6d, 0x7b, 0x06, 0x5c, 0x13, 0x88, 0xbd,
-	0x44, 0x46, 0x04, 0x84, 0xc8, 0x5a, 0x5c, 0x78, 0xba, 0x57, 0x6f, 0xb4, 0x19, 0x7f, 0x80, 0x53,
-	0x16, 0x97, 0x0d, 0x34, 0x08, 0x0c, 0xc9, 0xd8, 0xd3, 0x01, 0xd7, 0x12, 0x16, 0xae, 0xad, 0x33,
-	0xa1, 0x0a, 0x22, 0x12, 0xb1, 0x4e, 0x26, 0x6e, 0x18, 0x61, 0x17, 0x7d, 0x53, 0x3b, 0x09, 0xe1,
-	0x67, 0x74, 0x97, 0x51, 0x02, 0x95, 0xa4, 0x07, 0x9f, 0x33, 0x2c, 0x67, 0xdd, 0x69, 0x52, 0x1a,
-	0x44, 0x7e, 0x7d, 0x7d, 0xa5, 0x8e, 0xb3, 0x86, 0xfc, 0xda, 0x88, 0xc4, 0x59, 0xff, 0x78, 0xdf,
-	0x0b, 0x89, 0xd2, 0x10, 0x71, 0x4e, 0xeb, 0x8c, 0xbf, 0x16, 0x35, 0x2f, 0x83, 0x93, 0x32, 0x53,
-	0x68, 0xf6, 0x18, 0xab, 0x59, 0xed, 0x2a, 0xad, 0x42, 0x5c, 0xfa, 0x62, 0x05, 0xad, 0x1d, 0x37,
-	0xa1, 0x71, 0x29, 0x92, 0xe1, 0x54, 0xc6, 0x24, 0x45, 0x51, 0x06, 0x9e, 0x4a, 0x5f, 0x9e, 0xa2,
-	0x00, 0x0d, 0xdf, 0xcb, 0xde, 0x41, 0x62, 0x62, 0x9c, 0x1a, 0x94, 0xbb, 0xb5, 0xe4, 0x46, 0x60,
-	0x38, 0xe7, 0x72, 0xee, 0xe9, 0xf0, 0xd0, 0x37, 0xee, 0x41, 0x5d, 0x42, 0x30, 0x0e, 0x11, 0xb1,
-	0x4b, 0xab, 0x8c, 0x87, 0x37, 0x72, 0x23, 0x48, 0xc7, 0x33, 0x7b, 0xb6, 0x92, 0x3a, 0x75, 0x4a,
-	0x30, 0xbe, 0xf7, 0x4a, 0x8d, 0x1c, 0x12, 0xaf, 0x1b, 0x5d, 0x94, 0x78, 0x2d, 0x1a, 0xbf, 0x29,
-	0xb2, 0xaa, 0x2e, 0x50, 0xb8, 0xff, 0x01, 0x93, 0x08, 0x2f, 0x3d, 0x47, 0xf7, 0xa2, 0x3b, 0xd9,
-	0x02, 0xc6, 0x2b, 0x04, 0x18, 0x85, 0x76, 0xc2, 0x73, 0x1b, 0x5f, 0x9c, 0xf4, 0xb4, 0x89, 0x02,
-	0x6b, 0xd0, 0x9e, 0xc7, 0xe0, 0x52, 0x16, 0x5a, 0xc2, 0xbc, 0xcb, 0x17, 0x93, 0x4b, 0x6c, 0x18,
-	0x22, 0x2a, 0xae, 0xb2, 0xc8, 0xe8, 0x88, 0x73, 0x5e, 0xda, 0xee, 0x02, 0x1f, 0x40, 0x34, 0x6f,
-	0x49, 0x14, 0xe9, 0x2a, 0xae, 0x66, 0xab, 0x98, 0x2e, 0x63, 0x8e, 0x94, 0xf3, 0x11, 0xf1, 0x45,
-	0xba, 0x54, 0xd3, 0x65, 0x2c, 0xd1, 0xb5, 0x3e, 0x66, 0x15, 0xb5, 0x0f, 0x7e, 0x8b, 0xed, 0x43,
-	0xab, 0x10, 0xbd, 0xd1, 0xe8, 0x4c, 0xf4, 0x3e, 0x39, 0xed, 0x8d, 0xb0, 0x13, 0x31, 0x56, 0xe9,
-	0x5a, 0xa2, 0x07, 0x2d, 0xa2, 0xc0, 0x77, 0x59, 0xfd, 0x64, 0xd0, 0xed, 0x89, 0xf6, 0xb8, 0xd7,
-	0x6d, 0x14, 0x5b, 0x7f, 0x2a, 0xb0, 0x83, 0xf5, 0x97, 0x0b, 0x58, 0x8d, 0x87, 0x4a, 0x80, 0x1c,
-	0xdd, 0x0c, 0xb4, 0x48, 0x97, 0x02, 0x15, 0xb9, 0x76, 0xe6, 0xd0, 0xe5, 0xb4, 0x78, 0x43, 0xf2,
-	0xe5, 0x17, 0x2b, 0x40, 0x1a, 0xe9, 0xb4, 0xd3, 0x21, 0xcb, 0xab, 0x31, 0x2e, 0x30, 0xd6, 0x5b,
-	0x85, 0x88, 0x94, 0x2a, 0x70, 0x89, 0x82, 0x7f, 0x97, 0x35, 0x14, 0x88, 0x8c, 0x92, 0x17, 0x03,
-	0x45, 0x83, 0x1a, 0xa6, 0xc8, 0x1a, 0xc4, 0x9a, 0x67, 0xab, 0xcf, 0xb6, 0xd5, 0x23, 0x8f, 0xfc,
-	0x89, 0x9c, 0x84, 0x37, 0x6c, 0xeb, 0x3e, 0x2b, 0x07, 0xee, 0x34, 0xa2, 0x19, 0x07, 0xe6, 0xa1,
-	0x1b, 0x4e, 0x3c, 0x77, 0x91, 0xcc, 0x4d, 0xe6, 0xd6, 0x3f, 0x0a, 0x6c, 0x3f, 0xf7, 0xab, 0x40,
-	0x51, 0x92, 0x07, 0x86, 0x02, 0x1d, 0xaa, 0x7b, 0xf9, 0x95, 0x99, 0x63, 0xdf, 0x5e, 0x04, 0x80,
-	0x6a, 0x10, 0xdb, 0x0d, 0x6f, 0x0e, 0x10, 0x81, 0xf8, 0xc5, 0x83, 0xc2, 0xb9, 0x23, 0x12, 0x85,
-	0x71, 0xc5, 0x6e, 0x6d, 0x18, 0x9e, 0x42, 0x8f, 0x51, 0xf2, 0x26, 0x92, 0x56, 0x51, 0x0b, 0x8a,
-	0xf0, 0x37, 0x9a, 0x36, 0x56, 0x60, 0x59, 0xc5, 0x75, 0x8d, 0x0e, 0x25, 0x72, 0xc8, 0xe8, 0xe0,
-	0xf2, 0xda, 0xc8, 0x07, 0x02, 0xa1, 0xd2, 0x5d, 0x2c, 0x57, 0x00, 0xd0, 0x8e, 0x7c, 0xa1, 0x99,
-	0x54, 0x4a, 0x73, 0xf3, 0x66, 0x5a, 0x7f, 0x2b, 0xb3, 0xc6, 0xda, 0xe3, 0x56, 0x9c, 0x16, 0x27,
-	0x9b, 0x16, 0x27, 0x7e, 0xf1, 0x29, 0xa6, 0x5e, 0x7c, 0xfa, 0xac, 0xb1, 0xbc, 0xbc, 0x0a, 0xdc,
-	0x89, 0x3d, 0x4b, 0xb1, 0x53, 0x4c, 0x5b, 0x6b, 0xed, 0x3d, 0xcd, 0x1c, 0xe6, 0x3c, 0xc5, 0xda,
-	0x58, 0x60, 0xeb, 0xfb, 0x0e, 0xdc, 0x4c, 0xc2, 0xd4, 0x74, 0xea, 0x79, 0xee, 0x9d, 0xf5, 0xe9,
-	0xba, 0x59, 0x47, 0x91, 0x1f, 0x89, 0x4f, 0x15, 0xaa, 0x76, 0x75, 0x27, 0x6e, 0x6e, 0x58, 0x12,
-	0xd9, 0x85, 0xf6, 0xe3, 0xdf, 0x86, 0x83, 0x92, 0xad, 0x16, 0xfd, 0x36, 0xb7, 0x5e, 0xdf, 0x79,
-	0x47, 0xe0, 0xb6, 0x8d, 0xfc, 0x06, 0x09, 0x15, 0x11, 0x3b, 0xe1, 0xfe, 0xa6, 0x83, 0xa9, 0x45,
-	0x3c, 0xba, 0xf8, 0x0e, 0xf1, 0x14, 0xa6, 0xe8, 0xaf, 0xe6, 0xe7, 0x32, 0xc2, 0xb7, 0x9c, 0xd6,
-	0xf8, 0x3e, 0xdb, 0xcf, 0xed, 0x13, 0xef, 0xd5, 0x2b, 0x7f, 0xa6, 0x27, 0xc4, 0x4f, 0x6c, 0x4d,
-	0x4b, 0x3b, 0x08, 0x9e, 0x43, 0xa6, 0xa2, 0x0b, 0x5e, 0x24, 0x1b, 0x3f, 0x05, 0x02, 0xa7, 0x76,
-	0x19, 0x9f, 0xab, 0xc2, 0x8d, 0xe7, 0x8a, 0xae, 0x9e, 0x34, 0x20, 0x0b, 0x2a, 0x59, 0x25, 0xbe,
-	0xd0, 0xc4, 0xc0, 0x30, 0x94, 0xfe, 0xe1, 0x55, 0x18, 0xb1, 0xf2, 0x35, 0x7d, 0xeb, 0x2f, 0x5b,
-	0x6c, 0x3f, 0xff, 0xfc, 0x79, 0x7d, 0x9d, 0x7d, 0x04, 0xfd, 0x9f, 0x66, 0x18, 0xdd, 0x08, 0x02,
-	0x29, 0x27, 0x18, 0x52, 0x55, 0xe9, 0x88, 0xfa, 0xef, 0xdd, 0xfc, 0x23, 0xac, 0xce, 0x9f, 0x88,
-	0xfc, 0x8c, 0xdf, 0x97, 0x59, 0x45, 0xe9, 0xa0, 0x1f, 0x6b, 0x6e, 0xd4, 0x4d, 0x60, 0xa3, 0x75,
-	0xcd, 0x04, 0xfa, 0x0f, 0x7a, 0x8a, 0xd4, 0xa8, 0x97, 0xc0, 0xc6, 0x9f, 0x4b, 0x8c, 0x89, 0x8c,
-	0x73, 0x02, 0x06, 0x85, 0x3c, 0x18, 0xbc, 0xf4, 0x15, 0x35, 0xc5, 0x41, 0x4b, 0x1b, 0x38, 0xe8,
-	0x7d, 0xb6, 0x1d, 0x03, 0x47, 0x96, 0xa6, 0xa6, 0xf5, 0xdc, 0x64, 0x75, 0x35, 0x23, 0x44, 0x30,
-	0x7e, 0xba, 0xce, 0x57, 0x79, 0xe2, 0x92, 0xc1, 0x28, 0x1c, 0x52, 0xc9, 0x61, 0x14, 0xfa, 0x64,
-	0xe8, 0x6f, 0xf5, 0xbf, 0xa1, 0xbf, 0x58, 0x0e, 0x50, 0xdd, 0xf8, 0xb6, 0x50, 0x53, 0x8f, 0x96,
-	0x5a, 0x44, 0x0b, 0xf0, 0xac, 0x19, 0xd2, 0xae, 0xba, 0xb2, 0x68, 0x31, 0xff, 0x96, 0xc3, 0xc8,
-	0x9a, 0x79, 0xcb, 0x81, 0x52, 0x76, 0xf4, 0xb1, 0x19, 0x2d, 0xa5, 0x74, 0xe8, 0x79, 0x69, 0x57,
-	0x64, 0x95, 0xd8, 0x1e, 0x27, 0xab, 0x20, 0xf4, 0xe6, 0xd2, 0xd7, 0x17, 0xf4, 0xe6, 0x0e, 0xf9,
-	0xe5, 0xd5, 0x48, 0x39, 0x7c, 0xf9, 0xcc, 0x95, 0xcf, 0x9b, 0xbb, 0x8a, 0xf6, 0x2a, 0xa9, 0x55,
-	0x67, 0x55, 0xfd, 0x1a, 0xdf, 0xba, 0xc5, 0x0e, 0xd6, 0x1e, 0xe6, 0x5b, 0x3f, 0x86, 0x5a, 0x53,
-	0xcf, 0xed, 0xff, 0x6b, 0xd7, 0x8b, 0x89, 0x5f, 0x29, 0x21, 0x7e, 0xad, 0xdf, 0x15, 0x58, 0x11,
-	0x66, 0x00, 0xd3, 0x74, 0xe5, 0x46, 0xe7, 0x89, 0xbe, 0x31, 0x6b, 0xe7, 0x33, 0x6f, 0xf2, 0x94,
-	0xc8, 0x8d, 0x2e, 0x28, 0x20, 0x2c, 0x69, 0x1d, 0xfc, 0x72, 0x75, 0xb9, 0x3a, 0x7f, 0x8a, 0x57,
-	0x05, 0x55, 0x4f, 0xdb, 0x50, 0x2c, 0xe6, 0x50, 0xa9, 0x44, 0x64, 0xc3, 0x66, 0x73, 0x1e, 0xaf,
-	0x89, 0xca, 0x0a, 0x2e, 0x15, 0x89, 0xc6, 0xf8, 0x26, 0xf0, 0x3c, 0xed, 0x9a, 0x5e, 0xc9, 0x8e,
-	0x5e, 0x09, 0xec, 0x5c, 0x3b, 0xeb, 0xf3, 0x11, 0x89, 0xad, 0x7f, 0x15, 0x58, 0x3d, 0xe9, 0x69,
-	0x1f, 0x20, 0x93, 0xa4, 0xf6, 0xaa, 0x49, 0x22, 0x4f, 0xfe, 0x1b, 0x61, 0x8e, 0x94, 0x45, 0x44,
-	0x2e, 0x88, 0xa3, 0xf1, 0x31, 0x43, 0xac, 0x09, 0xf4, 0xe4, 0x39, 0x6d, 0xeb, 0xcb, 0x02, 0xbe,
-	0xbe, 0xa8, 0x31, 0xdb, 0xac, 0x7a, 0x6c, 0x8d, 0xc6, 0x70, 0x5d, 0x07, 0x3a, 0x86, 0x57, 0x49,
-	0x01, 0x14, 0x0c, 0xd8, 0xd8, 0xeb, 0x8c, 0xd3, 0xe7, 0x59, 0x67, 0xd0, 0x3f, 0xb2, 0xc4, 0x49,
-	0x9b, 0x1e, 0x6f, 0x8b, 0xf8, 0xa4, 0xa0, 0xf4, 0x47, 0xa7, 0xc7, 0x47, 0xd6, 0xf1, 0xf1, 0x49,
-	0xaf, 0x3f, 0x86, 0xfb, 0xff, 0x6d, 0xe8, 0x9e, 0xda, 0xfd, 0x64, 0x78, 0xdc, 0x23, 0xe7, 0x32,
-	0x4e, 0xde, 0xb5, 0x46, 0xc3, 0xd3, 0x71, 0xaf, 0xb1, 0x85, 0x33, 0x6a, 0x01, 0x08, 0xe0, 0x68,
-	0x70, 0x7c, 0x4a, 0x4e, 0x15, 0xe4, 0x80, 0xa2, 0x47, 0x4f, 0xc8, 0xd5, 0xf3, 0x0a, 0x1d, 0x8c,
-	0x6f, 0xfc, 0x27, 0x00, 0x00, 0xff, 0xff, 0xf6, 0xb5, 0xb4, 0x27, 0xa5, 0x1b, 0x00, 0x00,
+	// 2821 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x09, 0x6e, 0x88, 0x02, 0xff, 0xac, 0x59, 0xcb, 0x93, 0x23, 0x47,
+	0xd1, 0x5f, 0xbd, 0xa5, 0x9c, 0x97, 0xa6, 0xf6, 0xd5, 0x5f, 0x7f, 0xb6, 0x77, 0xac, 0xd8, 0xdd,
+	0x58, 0xaf, 0xd7, 0x6d, 0x7b, 0x38, 0x60, 0x1e, 0x01, 0xab, 0x51, 0x6b, 0x76, 0x9b, 0x9d, 0xd1,
+	0xc8, 0x25, 0x8d, 0x8d, 0x4f, 0x13, 0x35, 0xdd, 0x35, 0x9a, 0x66, 0x5b, 0xdd, 0x72, 0x3f, 0xc6,
+	0x33, 0x47, 0x0e, 0x5c, 0xb8, 0x10, 0x10, 0x44, 0xf8, 0xc6, 0x8d, 0x3b, 0x11, 0x5c, 0xf9, 0x0b,
+	0x38, 0x72, 0x25, 0x20, 0x38, 0x71, 0x20, 0x08, 0xce, 0x44, 0x70, 0x21, 0xea, 0xd5, 0x2f, 0x69,
+	0x76, 0x97, 0x80, 0x5b, 0xe7, 0x2f, 0x33, 0x4b, 0x55, 0x99, 0x59, 0x99, 0x59, 0x29, 0xd8, 0xb2,
+	0x03, 0x3f, 0x0e, 0x89, 0x1d, 0x47, 0xc6, 0x22, 0x0c, 0xe2, 0x40, 0x47, 0x76, 0x90, 0xf8, 0x71,
+	0x78, 0x65, 0x07, 0x0e, 0x55, 0xd8, 0xbd, 0x59, 0x10, 0xcc, 0x3c, 0xfa, 0x21, 0xa7, 0x4e, 0x93,
+	0xb3, 0x0f, 0x63, 0x77, 0x4e, 0xa3, 0x98, 0xcc, 0x17, 0x42, 0xa0, 0xf7, 0x9b, 0x3a, 0x6c, 0x63,
+	0xd7, 0x26, 0xa1, 0xe3, 0x12, 0x7f, 0x20, 0x57, 0x44, 0x1f, 0xc1, 0xe6, 0x05, 0xf5, 0x9d, 0x20,
+	0x3c, 0x70, 0xa3, 0xd8, 0xf5, 0x67, 0x91, 0x56, 0xd9, 0xa9, 0x3d, 0x5a, 0xdb, 0x6d, 0x1b, 0x12,
+	0xc0, 0x25, 0x3e, 0x7a, 0x08, 0x70, 0x9a, 0x5c, 0xd1, 0xf0, 0x28, 0x74, 0x68, 0xa8, 0x55, 0x77,
+	0x2a, 0x8f, 0xd6, 0x76, 0x9b, 0x06, 0xa7, 0x70, 0x8e, 0x83, 0x0e, 0xe0, 0xae, 0xd0, 0xe4, 0xe4,
+	0x20, 0xf0, 0xcf, 0xdc, 0x70, 0x4e, 0x62, 0x37, 0xf0, 0xb5, 0x1a, 0x57, 0x42, 0xc6, 0x12, 0x07,
+	0x5f, 0xa7, 0x82, 0x2c, 0xb8, 0x93, 0x63, 0xed, 0x27, 0xde, 0x99, 0xeb, 0x79, 0x73, 0xea, 0xc7,
+	0x5a, 0x9d, 0xef, 0x77, 0xdb, 0x28, 0x33, 0xf0, 0x35, 0x0a, 0xc8, 0x84, 0x5b, 0xd9, 0x36, 0x07,
+	0xc1, 0x7c, 0xe1, 0x51, 0xbe, 0xab, 0x06, 0xdf, 0x55, 0xd7, 0x28, 0xe1, 0x78, 0xa5, 0x34, 0xea,
+	0x41, 0xcb, 0x71, 0xa3, 0x45, 0x12, 0x53, 0xad, 0xc9, 0x15, 0xdb, 0x86, 0x29, 0x68, 0xac, 0x18,
+	0xe8, 0x29, 0x6c, 0xcb, 0x4f, 0x4c, 0xa3, 0xc0, 0x4b, 0xf8, 0xcf, 0xb4, 0xe4, 0xe1, 0xcd, 0x32,
+	0x07, 0x2f, 0x0b, 0xa3, 0x7b, 0xd0, 0x0c, 0xe9, 0x59, 0xe2, 0x3b, 0x5a, 0x9b, 0xab, 0xb5, 0x0c,
+	0xcc, 0x49, 0x2c, 0x61, 0xf4, 0x18, 0x20, 0x72, 0x67, 0x3e, 0x89, 0x93, 0x90, 0x46, 0x5a, 0x87,
+	0xdb, 0x02, 0x8c, 0x89, 0x82, 0x70, 0x8e, 0x8b, 0x3e, 0x84, 0xcd, 0x05, 0x09, 0x63, 0x97, 0x78,
+	0x62, 0x91, 0x48, 0x83, 0x9d, 0x5a, 0x7e, 0xd1, 0x12, 0xbb, 0xf7, 0x93, 0xdb, 0xd0, 0x92, 0x7e,
+	0x47, 0x08, 0xea, 0x91, 0x97, 0xcc, 0xb4, 0xca, 0x4e, 0xe5, 0x51, 0x07, 0xf3, 0x6f, 0x74, 0x0f,
+	0xda, 0xc2, 0xc6, 0x96, 0x29, 0x03, 0xa1, 0x66, 0x58, 0x26, 0x4e, 0x41, 0xf4, 0x01, 0xb4, 0xe7,
+	0x34, 0x26, 0x0e, 0x89, 0x89, 0x74, 0xfa, 0xb6, 0x8a, 0x2b, 0xe3, 0x50, 0x32, 0x70, 0x2a, 0x82,
+	0xde, 0x85, 0xba, 0x1b, 0xd3, 0xb9, 0x56, 0xe7, 0xa2, 0x1b, 0xa9, 0xa8, 0x15, 0xd3, 0x39, 0xe6,
+	0x2c, 0xd4, 0x87, 0xad, 0xe8, 0xdc, 0x5d, 0x2c, 0x5c, 0x7f, 0x76, 0xb4, 0x60, 0x26, 0x8a, 0xb4,
+	0x06, 0x3f, 0xc4, 0xdd, 0x54, 0x7a, 0x52, 0xe0, 0xe3, 0xb2, 0x3c, 0xea, 0x41, 0x23, 0x26, 0x97,
+	0x34, 0xd2, 0x9a, 0x5c, 0x71, 0x3d, 0x55, 0x9c, 0x92, 0x4b, 0x2c, 0x58, 0xe8, 0x3d, 0x68, 0xd9,
+	0x41, 0xb2, 0x60, 0xcb, 0xb7, 0xb8, 0xd4, 0x56, 0x2a, 0x35, 0xe0, 0x38, 0x56, 0x7c, 0xf4, 0x0e,
+	0xc0, 0x3c, 0x70, 0x68, 0x48, 0xe2, 0x20, 0x8c, 0xb4, 0xf6, 0x4e, 0xed, 0x51, 0x07, 0xe7, 0x10,
+	0x64, 0x00, 0x8a, 0x69, 0x38, 0x8f, 0xfa, 0xbe, 0x33, 0x08, 0x7c, 0xc7, 0x15, 0x9b, 0xee, 0x70,
+	0x33, 0xae, 0xe0, 0xa0, 0x1e, 0xac, 0x0b, 0xdf, 0x8e, 0x03, 0xcf, 0xb5, 0xaf, 0x34, 0xe0, 0x92,
+	0x05, 0x4c, 0xff, 0x55, 0x0d, 0xda, 0xca, 0x7e, 0x48, 0x83, 0xd6, 0x05, 0x0d, 0x23, 0x16, 0x5b,
+	0xcc, 0x39, 0x1b, 0x58, 0x91, 0x68, 0x0f, 0xd6, 0x55, 0xea, 0x98, 0x5e, 0x2d, 0x28, 0xf7, 0xd1,
+	0xe6, 0xee, 0x3b, 0x4b, 0x2e, 0x30, 0x06, 0x39, 0x29, 0x5c, 0xd0, 0x41, 0x1f, 0x41, 0xf3, 0x2c,
+	0x60, 0xb7, 0x90, 0x3b, 0x70, 0x73, 0x57, 0x5b, 0xd6, 0xde, 0xe7, 0x7c, 0x2c, 0xe5, 0xd0, 0x2e,
+	0x34, 0xe9, 0xe5, 0xc2, 0x0d, 0xaf, 0xa4, 0x1f, 0x75, 0x43, 0xa4, 0x26, 0x43, 0xa5, 0x26, 0x63,
+	0xaa, 0x52, 0x13, 0x96, 0x92, 0xe8, 0x31, 0x74, 0x89, 0x6d, 0xd3, 0x45, 0x4c, 0x9d, 0x41, 0x12,
+	0x86, 0xd4, 0xb7, 0xaf, 0xf8, 0x7d, 0xec, 0xe0, 0x25, 0x1c, 0x3d, 0x82, 0xad, 0x45, 0xe8, 0xda,
+	0xae, 0x3f, 0x4b, 0x45, 0x9b, 0x5c, 0xb4, 0x0c, 0xf7, 0xc6, 0xb0, 0x9e, 0x3f, 0x19, 0xda, 0x86,
+	0x8d, 0xf1, 0xf3, 0x2f, 0x26, 0xd6, 0xa0, 0x7f, 0x70, 0xf2, 0xec, 0xe8, 0xc8, 0xec, 0xde, 0x40,
+	0x5d, 0x58, 0x37, 0xad, 0x67, 0xd6, 0x54, 0x21, 0x15, 0xb4, 0x06, 0xad, 0xc9, 0x10, 0x7f, 0x66,
+	0x0d, 0x86, 0xdd, 0x2a, 0xda, 0x04, 0x18, 0xe0, 0xa3, 0xcf, 0xcd, 0x93, 0xfd, 0xe3, 0x91, 0xd9,
+	0xad, 0xf5, 0x1e, 0x42, 0x53, 0x9c, 0x16, 0x6d, 0xc1, 0xda, 0xbe, 0xf5, 0xc3, 0xa1, 0x79, 0x32,
+	0xc6, 0x4c, 0xf4, 0x06, 0xd3, 0xeb, 0x1f, 0x0f, 0xa6, 0xd6, 0xd1, 0xa8, 0x5b, 0xd1, 0xff, 0xd1,
+	0x80, 0x3a, 0x8b, 0x5a, 0x74, 0x0b, 0x1a, 0xb1, 0x1b, 0x7b, 0x54, 0xde, 0x1b, 0x41, 0xa0, 0x1d,
+	0x58, 0x73, 0x68, 0x64, 0x87, 0x2e, 0x0f, 0x49, 0xee, 0x97, 0x0e, 0xce, 0x43, 0xe8, 0x21, 0x6c,
+	0x2e, 0xc2, 0xc0, 0xa6, 0x51, 0xe4, 0xfa, 0x33, 0x66, 0x2f, 0x6e, 0xfe, 0x0e, 0x2e, 0xa1, 0x6c,
+	0x7d, 0x76, 0x6a, 0xca, 0x6d, 0x5d, 0xc7, 0x82, 0x60, 0x97, 0xd5, 0x8f, 0xce, 0xbe, 0xe2, 0x26,
+	0x6c, 0x63, 0xfe, 0xcd, 0xb0, 0x98, 0xcc, 0x44, 0xd4, 0x77, 0x30, 0xff, 0x46, 0xef, 0x43, 0xd3,
+	0x9d, 0x93, 0x19, 0x55, 0x51, 0x7e, 0xb3, 0x70, 0xe5, 0x0c, 0x8b, 0xf1, 0xb0, 0x14, 0x61, 0x81,
+	0x6e, 0x93, 0x98, 0xce, 0x82, 0xd0, 0xa5, 0x69, 0xa0, 0x67, 0x08, 0xea, 0x42, 0x2d, 0x7a, 0x99,
+	0xc8, 0xc8, 0x66, 0x9f, 0x6c, 0x73, 0xb3, 0x90, 0xcc, 0x23, 0x1e, 0xc3, 0x55, 0x2c, 0x08, 0xf4,
+	0x16, 0x74, 0x6c, 0x15, 0xee, 0xda, 0x1a, 0x97, 0xce, 0x00, 0x64, 0x40, 0x2b, 0x90, 0x17, 0x7b,
+	0x9d, 0xef, 0xe9, 0x56, 0x71, 0x4f, 0xf2, 0x56, 0x2b, 0x21, 0xfd, 0x6f, 0x15, 0x68, 0x0a, 0x8c,
+	0x9f, 0x9a, 0xcc, 0x95, 0xa9, 0xf9, 0xf7, 0x1b, 0x58, 0xfa, 0x5b, 0xd0, 0xbe, 0x20, 0xa1, 0x4b,
+	0xfc, 0x38, 0xd2, 0x6a, 0xfc, 0x17, 0xdf, 0x5e, 0xf5, 0x8b, 0xc6, 0x67, 0x52, 0x08, 0xa7, 0xe2,
+	0x7a, 0x00, 0x6d, 0x85, 0xae, 0xfc, 0xf1, 0xf7, 0xa0, 0xc1, 0x6d, 0x27, 0x93, 0xe3, 0x4a, 0xeb,
+	0x0a, 0x09, 0x74, 0x1f, 0x36, 0xb8, 0xeb, 0x0e, 0x03, 0xc7, 0x3d, 0x73, 0x69, 0xc8, 0xdd, 0x5d,
+	0xc3, 0x45, 0x50, 0xff, 0xba, 0x02, 0x0d, 0xae, 0x86, 0x74, 0x68, 0x9f, 0xb9, 0x1e, 0xcd, 0xfd,
+	0x64, 0x4a, 0x33, 0x5e, 0x10, 0xba, 0x33, 0xd7, 0x27, 0x9e, 0x3c, 0x70, 0x4a, 0x33, 0x97, 0x78,
+	0x24, 0x9c, 0xa9, 0x70, 0x12, 0x04, 0xba, 0x03, 0xcd, 0x39, 0x75, 0xdc, 0x44, 0xa4, 0xde, 0x0e,
+	0x96, 0x14, 0x93, 0x8e, 0xe6, 0xc4, 0xf3, 0xe4, 0x5d, 0x14, 0x04, 0x8f, 0x24, 0xd7, 0x57, 0xb7,
+	0x8e, 0x7f, 0xeb, 0xbf, 0x6d, 0xc2, 0x66, 0x31, 0xf1, 0xae, 0xb4, 0xc8, 0x27, 0x50, 0x8f, 0xb3,
+	0x4c, 0x74, 0xff, 0x9a, 0x9c, 0x9d, 0x92, 0x3c, 0x1f, 0x71, 0x0d, 0xf4, 0x10, 0x5a, 0x21, 0x9d,
+	0xf1, 0xb8, 0x60, 0x5e, 0xda, 0xdc, 0x5d, 0x37, 0x06, 0xa2, 0x0b, 0x1a, 0x04, 0x0e, 0xc5, 0x8a,
+	0x89, 0x5e, 0xc0, 0x86, 0x4a, 0xf8, 0x38, 0xf1, 0x68, 0x24, 0x93, 0xd0, 0x83, 0xd7, 0xfd, 0x14,
+	0x17, 0xc6, 0x45, 0x5d, 0xf4, 0x1d, 0x68, 0x47, 0x34, 0xbc, 0x70, 0x6d, 0xaa, 0xca, 0xcc, 0xbd,
+	0x6b, 0xd7, 0x11, 0x72, 0x38, 0x55, 0xd0, 0x09, 0xb4, 0x24, 0xb8, 0xd2, 0x14, 0xe9, 0xcd, 0xad,
+	0xe6, 0x6f, 0xee, 0x13, 0xd8, 0xa6, 0x51, 0xec, 0xce, 0x49, 0x4c, 0x1d, 0x93, 0x7a, 0xee, 0x05,
+	0x0d, 0xaf, 0xa4, 0xaf, 0x96, 0x19, 0xfa, 0x4f, 0x6b, 0xb0, 0x51, 0x38, 0x00, 0xfa, 0x01, 0xb4,
+	0xc3, 0xc4, 0xa3, 0x3c, 0xdd, 0x57, 0xb8, 0x91, 0x8d, 0x37, 0x3a, 0xb9, 0x81, 0xa5, 0x16, 0x4e,
+	0xf5, 0xd1, 0x53, 0x68, 0x84, 0xdc, 0x84, 0x55, 0x7e, 0xf4, 0xc7, 0x6f, 0xbe, 0x10, 0x16, 0x8a,
+	0xfa, 0x14, 0xea, 0x8c, 0x64, 0x11, 0x39, 0x77, 0x7d, 0x4c, 0xfc, 0x19, 0x95, 0x35, 0x2a, 0xa5,
+	0x39, 0x8f, 0x5c, 0x0a, 0x5e, 0x55, 0xf2, 0x24, 0x9d, 0xd9, 0xa8, 0x96, 0xb3, 0x51, 0xef, 0x97,
+	0x15, 0x68, 0xab, 0xed, 0xa2, 0xdb, 0xb0, 0xfd, 0xe9, 0x71, 0x7f, 0x34, 0xb5, 0xa6, 0x5f, 0x9c,
+	0x98, 0xd6, 0x64, 0x70, 0x74, 0x3c, 0x9a, 0x76, 0x6f, 0xa0, 0xff, 0x87, 0xbb, 0xfb, 0x07, 0xfd,
+	0xe9, 0xc9, 0xfe, 0x70, 0x78, 0x92, 0xf2, 0x71, 0x7f, 0xf4, 0x6c, 0xd8, 0xad, 0xa0, 0xff, 0x83,
+	0xdb, 0x29, 0xf3, 0xf3, 0xa1, 0xf5, 0xec, 0xf9, 0x54, 0xb2, 0xaa, 0x8c, 0x35, 0x38, 0x3a, 0xdc,
+	0xb3, 0x46, 0x43, 0xf3, 0x64, 0xf2, 0xdc, 0x1a, 0x8f, 0xad, 0xd1, 0xb3, 0x93, 0xbe, 0x69, 0x76,
+	0x6b, 0xe8, 0x1d, 0xd0, 0x97, 0x59, 0x93, 0xe3, 0xbd, 0x29, 0xee, 0x0f, 0xa6, 0xdd, 0x7a, 0xef,
+	0x63, 0x58, 0xcf, 0xc7, 0x2d, 0x2b, 0x2d, 0x07, 0x47, 0xac, 0xd4, 0x8c, 0xad, 0xc1, 0x8b, 0xe3,
+	0x71, 0xf7, 0x46, 0xb9, 0x66, 0x54, 0xf4, 0x9f, 0x55, 0xa0, 0x36, 0x25, 0x97, 0xac, 0x84, 0xc7,
+	0xe4, 0x32, 0x75, 0x5a, 0x07, 0x2b, 0x12, 0x3d, 0x01, 0x88, 0xc9, 0x25, 0x96, 0x91, 0x5f, 0x5d,
+	0x11, 0xf9, 0x39, 0x3e, 0xcb, 0x76, 0x31, 0xb9, 0x54, 0xbb, 0xe0, 0x56, 0x6b, 0xe3, 0x3c, 0xc4,
+	0x92, 0xf8, 0x82, 0x86, 0x36, 0xf5, 0x63, 0x96, 0x97, 0xea, 0x3c, 0x2f, 0xe7, 0x10, 0xfd, 0x17,
+	0x15, 0x68, 0x8a, 0x0e, 0xe7, 0x9a, 0xd2, 0x85, 0xa0, 0x7e, 0x4e, 0xa2, 0x73, 0x99, 0x58, 0xf8,
+	0x37, 0x7a, 0x0c, 0x5b, 0x72, 0x09, 0xd3, 0x8d, 0xf8, 0xdb, 0x84, 0xff, 0x74, 0xf5, 0xf9, 0x0d,
+	0x5c, 0x66, 0xa0, 0x87, 0x32, 0xd1, 0xa5, 0x92, 0xbc, 0x70, 0x3d, 0xbf, 0x81, 0x8b, 0xf0, 0x1e,
+	0x40, 0xdb, 0x91, 0xdf, 0xbd, 0x5f, 0x03, 0x34, 0xc4, 0xa3, 0xe2, 0x3e, 0x6c, 0x88, 0x46, 0xa8,
+	0xef, 0x38, 0x21, 0x8d, 0x22, 0xb9, 0xb7, 0x22, 0xc8, 0x2a, 0x8c, 0x00, 0xf6, 0xa9, 0xba, 0x5e,
+	0x19, 0x80, 0xde, 0x87, 0x76, 0x94, 0xb7, 0x10, 0x6b, 0xee, 0xf8, 0xea, 0x59, 0x20, 0xa7, 0x02,
+	0xe8, 0x6d, 0x68, 0xf1, 0xf6, 0xdf, 0x32, 0xb5, 0x7a, 0xd6, 0xe1, 0x2a, 0x0c, 0x7d, 0x02, 0x9d,
+	0xf4, 0x9d, 0xa5, 0x35, 0x5e, 0xdb, 0xee, 0x64, 0xc2, 0xe8, 0x5d, 0x68, 0xb0, 0x86, 0x56, 0x75,
+	0xa1, 0x6b, 0x72, 0x0b, 0xbc, 0xd5, 0x15, 0x1c, 0xf4, 0x08, 0x5a, 0x0b, 0x72, 0xc5, 0x1f, 0x39,
+	0xe2, 0xd1, 0xb0, 0x29, 0x85, 0xc6, 0x02, 0xc5, 0x8a, 0xcd, 0xbc, 0x1a, 0x12, 0x76, 0x35, 0x5f,
+	0xd0, 0x2b, 0x51, 0x9a, 0xd7, 0x71, 0x0e, 0x41, 0xbb, 0x70, 0x8b, 0x78, 0x31, 0x0d, 0x7d, 0x12,
+	0x53, 0xd6, 0x11, 0x11, 0x3b, 0xb6, 0xfc, 0xb3, 0x40, 0xd6, 0xea, 0x95, 0x3c, 0xfd, 0x0f, 0x15,
+	0x68, 0xa7, 0x61, 0x73, 0x07, 0x9a, 0xcc, 0x24, 0xd3, 0x40, 0x1a, 0x5c, 0x52, 0x2c, 0x70, 0x89,
+	0xf4, 0x84, 0x08, 0x08, 0x45, 0xb2, 0x38, 0xb1, 0xdd, 0x58, 0xe5, 0x2e, 0xfe, 0xcd, 0xcb, 0x49,
+	0x4c, 0x62, 0x2a, 0xab, 0x8c, 0x20, 0x78, 0x48, 0x06, 0x51, 0x4c, 0x3c, 0x16, 0xce, 0xb2, 0xd2,
+	0xe4, 0x10, 0x96, 0xf9, 0xe5, 0x7b, 0x97, 0x57, 0x9c, 0xa5, 0xcc, 0x2f, 0x99, 0xac, 0x71, 0x96,
+	0x3f, 0x3e, 0x0a, 0x62, 0xde, 0xd2, 0xf0, 0xc6, 0x39, 0x8f, 0xe9, 0x7f, 0xa9, 0xca, 0xbe, 0x6c,
+	0x07, 0xd6, 0x3c, 0x91, 0xcd, 0x9e, 0xb3, 0x68, 0x16, 0xa7, 0xca, 0x43, 0x2c, 0x2f, 0x7d, 0x99,
+	0x10, 0x3f, 0x66, 0x87, 0x90, 0x79, 0x49, 0xd1, 0xe8, 0x49, 0xd6, 0xa4, 0x88, 0x96, 0x01, 0xe5,
+	0xdc, 0x57, 0x6e, 0x51, 0xd0, 0x1e, 0x6c, 0x16, 0xdf, 0x20, 0x69, 0x63, 0x9c, 0x53, 0x2a, 0xbd,
+	0x5a, 0x4a, 0x1a, 0xcc, 0x9c, 0x73, 0x3a, 0x0f, 0xa4, 0x79, 0xf8, 0x37, 0x3b, 0x83, 0x78, 0x84,
+	0x30, 0x3b, 0xa8, 0xc6, 0x2e, 0x0f, 0xe9, 0xbb, 0xaf, 0xec, 0x8d, 0x6e, 0x41, 0xe3, 0x82, 0x78,
+	0x09, 0x95, 0xae, 0x13, 0x84, 0xfe, 0xbd, 0x37, 0x2a, 0xe4, 0x1a, 0xb4, 0x64, 0xa1, 0x53, 0x8e,
+	0x97, 0xa4, 0xfe, 0xcf, 0x2a, 0xb4, 0x64, 0x80, 0xa2, 0x0f, 0x58, 0x5f, 0x11, 0x9f, 0x07, 0x8e,
+	0xac, 0x45, 0xb7, 0x8b, 0x01, 0xcc, 0x9e, 0x10, 0xe7, 0x81, 0x83, 0xa5, 0x10, 0xbb, 0xb7, 0xe9,
+	0xc3, 0x49, 0x2e, 0x9b, 0x01, 0x2c, 0x06, 0xc9, 0x3c, 0x4d, 0x2e, 0x75, 0x2c, 0x29, 0xe6, 0x77,
+	0x7a, 0x69, 0x9f, 0xb3, 0x82, 0x81, 0x55, 0x70, 0xd5, 0x71, 0x01, 0xe3, 0x3d, 0xe7, 0x39, 0x71,
+	0x7d, 0x36, 0x31, 0x91, 0x7d, 0x4b, 0x06, 0xe4, 0xa3, 0xb8, 0x55, 0x8c, 0x62, 0xfe, 0x18, 0x73,
+	0x28, 0x9d, 0x4f, 0x78, 0xbf, 0xa8, 0xb5, 0xd5, 0x63, 0x2c, 0xc3, 0x98, 0x4c, 0xba, 0x49, 0x96,
+	0x70, 0x3a, 0xe2, 0xf7, 0xf3, 0x18, 0x7b, 0xdf, 0xa4, 0xb4, 0x4a, 0x5d, 0xe2, 0x61, 0xb7, 0x84,
+	0xf7, 0x3e, 0x81, 0xa6, 0xb0, 0x0b, 0xba, 0x09, 0x5b, 0x7d, 0xd3, 0xc4, 0xc3, 0xc9, 0xe4, 0x04,
+	0x0f, 0x3f, 0x3d, 0x1e, 0x4e, 0x58, 0x65, 0x03, 0x68, 0x9a, 0x16, 0x1e, 0x0e, 0xa6, 0xdd, 0x0a,
+	0xda, 0x80, 0xce, 0xe1, 0x91, 0x39, 0xc4, 0xfd, 0xe9, 0xd0, 0xec, 0x56, 0x7b, 0x7f, 0xac, 0xc0,
+	0xf6, 0xf2, 0xe8, 0x44, 0x83, 0x56, 0xc0, 0x40, 0xcb, 0x54, 0xc5, 0x45, 0x92, 0xfc, 0x91, 0x21,
+	0x3c, 0xd1, 0x2f, 0x5c, 0xe2, 0x12, 0xca, 0x5e, 0x5c, 0x21, 0xfd, 0x32, 0xa1, 0x51, 0x4c, 0x9d,
+	0x7e, 0xde, 0x05, 0x65, 0x98, 0xd9, 0x79, 0x41, 0xae, 0x82, 0x24, 0xde, 0xa7, 0xca, 0x11, 0x19,
+	0x80, 0xbe, 0x0b, 0x5d, 0x91, 0x94, 0x26, 0xd9, 0xc8, 0x42, 0xb4, 0x55, 0x5d, 0x03, 0x17, 0x19,
+	0x78, 0x49, 0xb2, 0x37, 0x82, 0x35, 0x7e, 0x38, 0x4c, 0x7f, 0x44, 0xed, 0xf8, 0x15, 0xc7, 0x7a,
+	0x00, 0xf5, 0xc8, 0x9d, 0xa9, 0xb6, 0x65, 0xdb, 0xd8, 0x73, 0x63, 0x3b, 0x70, 0xfd, 0x6c, 0x6d,
+	0xce, 0xee, 0xfd, 0xbd, 0x02, 0x5b, 0xa5, 0x5f, 0x45, 0x4f, 0x73, 0x03, 0x8b, 0x0a, 0xbf, 0xa4,
+	0xf7, 0xcb, 0x3b, 0x33, 0xa6, 0x21, 0xf1, 0x23, 0x62, 0x33, 0xdb, 0xae, 0x98, 0x61, 0xbc, 0x05,
+	0x9d, 0x74, 0xe4, 0xc2, 0xcd, 0xb9, 0x8e, 0x33, 0x40, 0xbf, 0x82, 0x9b, 0x2b, 0xd4, 0x73, 0xd9,
+	0x68, 0x92, 0xcd, 0x58, 0xf2, 0x10, 0x2f, 0x69, 0x2a, 0x9f, 0xab, 0x65, 0x53, 0xa0, 0x10, 0x82,
+	0x4c, 0xa0, 0xc6, 0x05, 0x0a, 0x58, 0x6f, 0x0c, 0xdd, 0xb2, 0x21, 0x58, 0xea, 0x75, 0xfd, 0x45,
+	0x12, 0x5b, 0xbe, 0x43, 0x2f, 0x65, 0x67, 0x96, 0x43, 0x5e, 0x7d, 0x98, 0xde, 0x5f, 0xeb, 0xd0,
+	0x5d, 0x9a, 0xae, 0xa5, 0x6e, 0x71, 0x8a, 0x6e, 0x71, 0xd2, 0x09, 0x52, 0x35, 0x37, 0x41, 0x1a,
+	0x41, 0x77, 0x71, 0x7e, 0x15, 0xb9, 0x36, 0xf1, 0x72, 0xdd, 0x2e, 0x73, 0x5b, 0x6f, 0x69, 0xa0,
+	0x67, 0x8c, 0x4b, 0x92, 0x78, 0x49, 0x17, 0xbd, 0x80, 0x2d, 0xc7, 0x9d, 0xb9, 0x71, 0x6e, 0x39,
+	0x31, 0x1f, 0x7c, 0x77, 0x79, 0x39, 0xb3, 0x28, 0x88, 0xcb, 0x9a, 0x6c, 0xf4, 0x21, 0x62, 0x57,
+	0x56, 0x76, 0x6d, 0xc5, 0x96, 0x38, 0x1f, 0x4b, 0x39, 0xf4, 0x6d, 0xd8, 0x2a, 0x85, 0xad, 0x1c,
+	0x0e, 0x2e, 0xc7, 0x77, 0x59, 0x50, 0x9f, 0x42, 0xb7, 0x7c, 0x40, 0x9e, 0x65, 0x59, 0x2e, 0xa6,
+	0xa1, 0x32, 0xa6, 0x24, 0xd9, 0xd5, 0x65, 0x73, 0x8d, 0x97, 0xae, 0x3f, 0x1b, 0x25, 0xf3, 0x53,
+	0xaa, 0xf2, 0x65, 0x09, 0xd5, 0xbf, 0x0f, 0x5b, 0xa5, 0x73, 0xb2, 0x77, 0x7a, 0x12, 0x7a, 0x72,
+	0x41, 0xf6, 0xc9, 0x4a, 0xdd, 0x82, 0x44, 0xd1, 0x57, 0x41, 0xe8, 0xa8, 0x07, 0xa3, 0xa2, 0xf5,
+	0x1f, 0x57, 0xa0, 0x29, 0x4e, 0x99, 0xde, 0xab, 0xca, 0x2b, 0xef, 0x15, 0x7f, 0xca, 0x72, 0x85,
+	0x62, 0x52, 0x29, 0x82, 0x2c, 0x23, 0xa6, 0x89, 0x61, 0x4c, 0xc3, 0xbd, 0xab, 0x58, 0x75, 0xf9,
+	0x4b, 0x78, 0xef, 0xcf, 0x0d, 0xd8, 0x2a, 0xcf, 0x5f, 0xaf, 0x8f, 0xb3, 0x8f, 0x01, 0xc4, 0x0a,
+	0x93, 0x57, 0x26, 0x81, 0x9c, 0x10, 0xfa, 0x18, 0x5a, 0xc2, 0x1d, 0xaa, 0x9e, 0xdf, 0x2d, 0x4f,
+	0x81, 0xa5, 0xff, 0xb0, 0x92, 0xd3, 0x7f, 0x5f, 0x87, 0xa6, 0xc0, 0xd0, 0x9e, 0xea, 0xbe, 0xcc,
+	0x2c, 0x6d, 0xf4, 0xae, 0x59, 0xc0, 0xc0, 0xa9, 0x24, 0xce, 0x69, 0xbd, 0x26, 0x6d, 0xfc, 0xa9,
+	0x06, 0x80, 0x0b, 0xc2, 0x59, 0x32, 0xa8, 0x94, 0x93, 0xc1, 0x6b, 0xa7, 0xb2, 0xb9, 0x9e, 0xb6,
+	0xb6, 0xa2, 0xa7, 0x7d, 0x00, 0x6b, 0x69, 0xe2, 0x28, 0xb6, 0xbd, 0x79, 0x1c, 0x19, 0xd0, 0x11,
+	0x2b, 0x4e, 0xdc, 0x59, 0x3a, 0x3b, 0x2f, 0x47, 0x79, 0x26, 0x52, 0xc8, 0x51, 0x4c, 0xa5, 0x59,
+	0xca, 0x51, 0x4c, 0xa6, 0xd0, 0x4e, 0xb7, 0xfe, 0x93, 0x76, 0x9a, 0x85, 0xc3, 0x05, 0x0d, 0xd9,
+	0xac, 0xa2, 0x2d, 0x86, 0xa0, 0x92, 0x64, 0x9c, 0x2f, 0x13, 0xe2, 0xb1, 0x36, 0xae, 0x23, 0x38,
+	0x92, 0x2c, 0xcf, 0x86, 0x80, 0x73, 0xf3, 0x10, 0x0b, 0x65, 0x47, 0x5e, 0x9b, 0xc9, 0x82, 0x52,
+	0x87, 0x8f, 0xab, 0x36, 0x70, 0x11, 0x64, 0xe5, 0xd1, 0x4e, 0xa2, 0x38, 0x98, 0xd3, 0x50, 0x3e,
+	0xf8, 0xb5, 0x75, 0x2e, 0x57, 0x86, 0x59, 0x0b, 0x13, 0xd2, 0x0b, 0x97, 0x7e, 0xa5, 0x6d, 0x88,
+	0x36, 0x5a, 0x50, 0xbd, 0x0e, 0xb4, 0xe4, 0xdf, 0x01, 0xbd, 0x9b, 0xb0, 0xbd, 0xf4, 0xcf, 0x40,
+	0xef, 0xe7, 0x55, 0x68, 0x8a, 0xa1, 0xfc, 0x7f, 0x5d, 0xf6, 0xd2, 0x4e, 0xb2, 0x96, 0xeb, 0x24,
+	0xb3, 0xd6, 0xaa, 0x5e, 0x6a, 0xad, 0xe4, 0x23, 0xa5, 0x21, 0x47, 0xe5, 0x62, 0x13, 0x85, 0x57,
+	0x4a, 0x96, 0x25, 0x9b, 0x6f, 0x96, 0x25, 0x75, 0xf3, 0x7f, 0xd1, 0x83, 0xf7, 0x7e, 0x57, 0x81,
+	0xaa, 0x65, 0xb2, 0xe3, 0xcc, 0x12, 0x57, 0x25, 0x01, 0xfe, 0xcd, 0x42, 0xed, 0xd4, 0x0b, 0xec,
+	0x97, 0xbc, 0xc3, 0x93, 0xb7, 0xa0, 0x83, 0x0b, 0x18, 0x7a, 0x00, 0xad, 0x45, 0x72, 0xfa, 0x92,
+	0xbd, 0x97, 0xc4, 0x25, 0x58, 0x33, 0x2c, 0xd3, 0x18, 0x0b, 0x08, 0x2b, 0x1e, 0xab, 0x90, 0xa7,
+	0xa9, 0x1d, 0xb9, 0x75, 0xd6, 0x71, 0x0e, 0xd1, 0xbf, 0x09, 0x2d, 0xa9, 0x53, 0xd8, 0xc9, 0xba,
+	0xdc, 0x89, 0x06, 0x2d, 0x29, 0x2c, 0x2f, 0xb5, 0x22, 0x7b, 0xff, 0xaa, 0x40, 0x27, 0x2b, 0xc4,
+	0x4f, 0x58, 0x3b, 0xcd, 0x7b, 0x02, 0xd9, 0x29, 0xa3, 0xec, 0x3f, 0x1c, 0x63, 0x22, 0x38, 0x58,
+	0x89, 0xb0, 0xe4, 0x9f, 0xe6, 0x06, 0x96, 0x20, 0x23, 0xb9, 0x78, 0x09, 0xed, 0x7d, 0x5d, 0x61,
+	0x23, 0x28, 0xa1, 0xb3, 0x06, 0xad, 0x03, 0x6b, 0x32, 0xb5, 0x46, 0xcf, 0xba, 0x37, 0x50, 0x07,
+	0x1a, 0x47, 0xd8, 0x1c, 0xe2, 0x6e, 0x05, 0xdd, 0x01, 0xc4, 0x3f, 0x4f, 0x06, 0x47, 0xa3, 0x7d,
+	0x0b, 0x1f, 0xf6, 0xf9, 0x04, 0xbb, 0xca, 0xe6, 0x2a, 0x02, 0xdf, 0x3f, 0x3e, 0xd8, 0xb7, 0x0e,
+	0x0e, 0x0e, 0x87, 0xa3, 0x69, 0xb7, 0x86, 0x6e, 0x41, 0x57, 0x89, 0x1f, 0x8e, 0x0f, 0x86, 0x5c,
+	0xb8, 0xce, 0x16, 0x37, 0xad, 0xc9, 0xf8, 0x78, 0x3a, 0xec, 0x36, 0xd8, 0x8a, 0x92, 0x38, 0xc1,
+	0xc3, 0xc9, 0xd1, 0xc1, 0x31, 0x17, 0x6a, 0xb2, 0xc6, 0x15, 0x0f, 0xf9, 0x1c, 0xbd, 0xd5, 0x7b,
+	0x9a, 0x4d, 0xe6, 0xd9, 0x50, 0x09, 0x7d, 0xc4, 0x67, 0xc2, 0x9c, 0x56, 0xf5, 0x05, 0x19, 0x4b,
+	0xff, 0x56, 0xe2, 0x4c, 0xe8, 0xb4, 0xc9, 0xf3, 0xc1, 0x37, 0xfe, 0x3d, 0x00, 0x08, 0xe6, 0x8f,
+	0xd5, 0x1d, 0x1d, 0x00, 0x00,
 }
@@ -45,6 +45,7 @@ const (
 	Message_DISPUTE_CLOSE      Message_MessageType = 11
 	Message_REFUND             Message_MessageType = 12
 	Message_OFFLINE_ACK        Message_MessageType = 13
+	Message_CONTRACT_REQUEST   Message_MessageType = 14
 	Message_ERROR              Message_MessageType = 500
 )
 
@@ -63,6 +64,7 @@ var Message_MessageType_name = map[int32]string{
 	11:  "DISPUTE_CLOSE",
 	12:  "REFUND",
 	13:  "OFFLINE_ACK",
+	14:  "CONTRACT_REQUEST",
 	500: "ERROR",
 }
 var Message_MessageType_value = map[string]int32{
@@ -80,6 +82,7 @@ var Message_MessageType_value = map[string]int32{
 	"DISPUTE_CLOSE":      11,
 	"REFUND":             12,
 	"OFFLINE_ACK":        13,
+	"CONTRACT_REQUEST":   14,
 	"ERROR":              500,
 }
 
@@ -130,28 +133,29 @@ func init() {
 }
 
 var fileDescriptor2 = []byte{
-	// 366 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x09, 0x6e, 0x88, 0x02, 0xff, 0x4c, 0x50, 0xcb, 0x6f, 0xa2, 0x40,
-	0x18, 0x5f, 0x51, 0x01, 0x3f, 0x70, 0x77, 0x9c, 0xb8, 0x1b, 0x77, 0xb3, 0x87, 0x86, 0x53, 0x4f,
-	0x98, 0xd8, 0xa4, 0x77, 0x82, 0x83, 0xa1, 0x1d, 0x66, 0xc8, 0x00, 0xe9, 0xd1, 0x60, 0xa4, 0xa4,
-	0xa9, 0x05, 0xe2, 0xa3, 0x09, 0xb7, 0xfe, 0xd1, 0xfe, 0x01, 0x45, 0x1e, 0xd1, 0xd3, 0xcc, 0xf7,
-	0x7b, 0x7c, 0x8f, 0x1f, 0x8c, 0x3f, 0x92, 0xc3, 0x21, 0x4e, 0x13, 0xb3, 0xd8, 0xe7, 0xc7, 0xfc,
-	0xdf, 0xdf, 0x34, 0xcf, 0xd3, 0x5d, 0x32, 0xaf, 0xab, 0xcd, 0xe9, 0x75, 0x1e, 0x67, 0x65, 0x43,
-	0x19, 0x67, 0x09, 0x14, 0xaf, 0x11, 0xe3, 0x47, 0xd0, 0x5a, 0x5f, 0x58, 0x16, 0xc9, 0xac, 0x77,
-	0xd7, 0xbb, 0xff, 0xb9, 0x98, 0x9a, 0x2d, 0xdd, 0xbd, 0x17, 0x4e, 0xdc, 0x0a, 0xb1, 0x09, 0x4a,
-	0x11, 0x97, 0xbb, 0x3c, 0xde, 0xce, 0xa4, 0xca, 0xa3, 0x55, 0x9e, 0x66, 0xa0, 0xd9, 0x0d, 0x34,
-	0xad, 0xac, 0x14, 0x9d, 0xc8, 0xf8, 0x92, 0x40, 0xbb, 0x69, 0x86, 0x55, 0x18, 0xf8, 0x2e, 0x5b,
-	0xa1, 0x1f, 0x58, 0xab, 0x96, 0x21, 0x41, 0x60, 0xad, 0x08, 0xea, 0x61, 0x00, 0xd9, 0xe1, 0x94,
-	0xf2, 0x17, 0x24, 0x61, 0x1d, 0xd4, 0x88, 0xb5, 0x55, 0x1f, 0x8f, 0x60, 0xc8, 0xc5, 0x92, 0x08,
-	0x34, 0xc0, 0x08, 0xf4, 0xfa, 0xbb, 0x16, 0xe4, 0x89, 0xd8, 0x21, 0x1a, 0x5e, 0x11, 0xdb, 0x62,
-	0x36, 0xa1, 0x48, 0xc6, 0x7f, 0x00, 0xb7, 0x08, 0x67, 0x8e, 0x2b, 0x3c, 0x2b, 0x74, 0x39, 0x43,
-	0x0a, 0xfe, 0x0d, 0x93, 0x06, 0x77, 0x22, 0xea, 0xb8, 0x94, 0x7a, 0x84, 0x85, 0x48, 0xc5, 0x53,
-	0x40, 0x9d, 0xdc, 0xf3, 0x29, 0xa9, 0xc5, 0xa3, 0x4b, 0xdb, 0xa5, 0x1b, 0xf8, 0x51, 0x48, 0xd6,
-	0xdc, 0x27, 0x0c, 0x01, 0x9e, 0xc0, 0xb8, 0x43, 0x6c, 0xca, 0x03, 0x82, 0xb4, 0xcb, 0xca, 0x82,
-	0x38, 0x11, 0x5b, 0x22, 0x1d, 0xff, 0x02, 0x8d, 0x3b, 0x0e, 0x75, 0x19, 0x59, 0x5b, 0xf6, 0x33,
-	0x1a, 0x57, 0xe4, 0x90, 0x08, 0xc1, 0x05, 0x3a, 0xf7, 0x8d, 0x2d, 0xa8, 0x24, 0xfb, 0x4c, 0x76,
-	0x79, 0x75, 0xbe, 0x01, 0x4a, 0x9b, 0x66, 0x1d, 0xb9, 0xb6, 0x50, 0xbb, 0xa8, 0x45, 0x47, 0x54,
-	0x27, 0xc8, 0xc5, 0x69, 0xf3, 0x9e, 0x94, 0x75, 0xc2, 0xba, 0x68, 0x2b, 0xfc, 0x1f, 0x46, 0x87,
-	0xb7, 0x34, 0x8b, 0x8f, 0xa7, 0x7d, 0x32, 0xeb, 0xd7, 0xd4, 0x15, 0xd8, 0xc8, 0x75, 0xfe, 0x0f,
-	0xdf, 0x01, 0x00, 0x00, 0xff, 0xff, 0xc3, 0xa5, 0x52, 0x67, 0x0f, 0x02, 0x00, 0x00,
+	// 384 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x09, 0x6e, 0x88, 0x02, 0xff, 0x4c, 0x51, 0xcd, 0x6e, 0xda, 0x40,
+	0x10, 0x2e, 0x10, 0xb0, 0x19, 0x43, 0x3a, 0x19, 0xd1, 0x88, 0x56, 0x3d, 0x44, 0x9c, 0x72, 0x72,
+	0x24, 0x2a, 0xf5, 0x6e, 0x99, 0x71, 0xe4, 0x76, 0xbd, 0x4b, 0xd7, 0x6b, 0xf5, 0x88, 0x8c, 0xb2,
+	0x45, 0x55, 0x29, 0xb6, 0x42, 0xa8, 0xe4, 0x57, 0xe8, 0x03, 0xf4, 0x49, 0xfb, 0x00, 0x11, 0xc6,
+	0x16, 0xdc, 0xf6, 0xfb, 0xd9, 0x6f, 0x46, 0xf3, 0xc1, 0xf8, 0xb7, 0xdd, 0xef, 0xf3, 0x8d, 0xf5,
+	0xcb, 0xe7, 0xe2, 0xa5, 0xf8, 0xf0, 0x7e, 0x53, 0x14, 0x9b, 0xad, 0x7d, 0xa8, 0xd1, 0xfa, 0xf0,
+	0xe3, 0x21, 0xdf, 0x55, 0x27, 0x69, 0xf6, 0xb7, 0x07, 0x4e, 0x72, 0x32, 0xd3, 0x67, 0xf0, 0x9a,
+	0x7f, 0xa6, 0x2a, 0xed, 0xb4, 0x73, 0xd7, 0xb9, 0xbf, 0x9e, 0x4f, 0xfc, 0x46, 0xf6, 0x93, 0xb3,
+	0xa6, 0x2f, 0x8d, 0xe4, 0x83, 0x53, 0xe6, 0xd5, 0xb6, 0xc8, 0x9f, 0xa6, 0xdd, 0xbb, 0xce, 0xbd,
+	0x37, 0x9f, 0xf8, 0xa7, 0x81, 0x7e, 0x3b, 0xd0, 0x0f, 0x76, 0x95, 0x6e, 0x4d, 0xb3, 0x7f, 0x5d,
+	0xf0, 0x2e, 0xc2, 0xc8, 0x85, 0xab, 0x65, 0x2c, 0x1f, 0xf1, 0x0d, 0x79, 0xe0, 0x24, 0x9c, 0xa6,
+	0xc1, 0x23, 0x63, 0x87, 0x00, 0x06, 0x91, 0x12, 0x42, 0x7d, 0xc7, 0x2e, 0x8d, 0xc0, 0xcd, 0x64,
+	0x83, 0x7a, 0x34, 0x84, 0xbe, 0xd2, 0x0b, 0xd6, 0x78, 0x45, 0x08, 0xa3, 0xfa, 0xb9, 0xd2, 0xfc,
+	0x85, 0x43, 0x83, 0xfd, 0x33, 0x13, 0x06, 0x32, 0x64, 0x81, 0x03, 0xba, 0x05, 0x6a, 0x18, 0x25,
+	0xa3, 0x58, 0x27, 0x81, 0x89, 0x95, 0x44, 0x87, 0xde, 0xc1, 0xcd, 0x89, 0x8f, 0x32, 0x11, 0xc5,
+	0x42, 0x24, 0x2c, 0x0d, 0xba, 0x34, 0x01, 0x6c, 0xed, 0xc9, 0x52, 0x70, 0x6d, 0x1e, 0x1e, 0x63,
+	0x17, 0x71, 0xba, 0xcc, 0x0c, 0xaf, 0xd4, 0x92, 0x25, 0x02, 0xdd, 0xc0, 0xb8, 0x65, 0x42, 0xa1,
+	0x52, 0x46, 0xef, 0xb8, 0xb2, 0xe6, 0x28, 0x93, 0x0b, 0x1c, 0xd1, 0x5b, 0xf0, 0x54, 0x14, 0x89,
+	0x58, 0xf2, 0x2a, 0x08, 0xbf, 0xe2, 0xf8, 0x98, 0x1b, 0x2a, 0x69, 0x74, 0x10, 0x9a, 0x95, 0xe6,
+	0x6f, 0x19, 0xa7, 0x06, 0xaf, 0x09, 0xa0, 0xcf, 0x5a, 0x2b, 0x8d, 0xff, 0x7b, 0xb3, 0x27, 0x70,
+	0x79, 0xf7, 0xc7, 0x6e, 0x8b, 0xd2, 0xd2, 0x0c, 0x9c, 0xe6, 0xc6, 0x75, 0x11, 0xde, 0xdc, 0x6d,
+	0x0b, 0xd0, 0xad, 0x40, 0xb7, 0x30, 0x28, 0x0f, 0xeb, 0x5f, 0xb6, 0xaa, 0xef, 0x3e, 0xd2, 0x0d,
+	0xa2, 0x8f, 0x30, 0xdc, 0xff, 0xdc, 0xec, 0xf2, 0x97, 0xc3, 0xb3, 0x9d, 0xf6, 0x6a, 0xe9, 0x4c,
+	0xac, 0x07, 0x75, 0x2b, 0x9f, 0x5e, 0x07, 0x00, 0x8f, 0xb5, 0xa2, 0x46, 0x25, 0x02, 0x00, 0x00,
 }
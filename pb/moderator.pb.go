@@ -56,6 +56,7 @@ type Moderator struct {
 	Languages          []string       `protobuf:"bytes,3,rep,name=languages" json:"languages,omitempty"`
 	Fee                *Moderator_Fee `protobuf:"bytes,4,opt,name=fee" json:"fee,omitempty"`
 	PubKey             []byte         `protobuf:"bytes,5,opt,name=pubKey,proto3" json:"pubKey,omitempty"`
+	FeeAddress         string         `protobuf:"bytes,6,opt,name=feeAddress" json:"feeAddress,omitempty"`
 }
 
 func (m *Moderator) Reset()                    { *m = Moderator{} }
@@ -106,25 +107,26 @@ func init() {
 }
 
 var fileDescriptor4 = []byte{
-	// 320 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x09, 0x6e, 0x88, 0x02, 0xff, 0x6c, 0x91, 0xdd, 0x4a, 0xc3, 0x40,
-	0x10, 0x85, 0x4d, 0xd3, 0x1f, 0x33, 0x2d, 0xb5, 0x0c, 0x58, 0x62, 0x11, 0x29, 0xbd, 0xea, 0x85,
-	0x04, 0xa9, 0x0f, 0x20, 0x25, 0xa6, 0x22, 0xfe, 0x50, 0xd6, 0x0a, 0xde, 0x95, 0x34, 0x99, 0x96,
-	0x80, 0xdd, 0x0d, 0x9b, 0x0d, 0xd8, 0xe7, 0x13, 0x7c, 0x2e, 0x37, 0x4b, 0x5a, 0x53, 0xf0, 0x22,
-	0x90, 0x39, 0xe7, 0xec, 0x9e, 0x8f, 0x59, 0x38, 0xdb, 0x8a, 0x98, 0x64, 0xa8, 0x84, 0xf4, 0x52,
-	0x29, 0x94, 0x18, 0x7d, 0xdb, 0xe0, 0xbc, 0xec, 0x35, 0x1c, 0x42, 0x3b, 0xa6, 0x2c, 0x92, 0x49,
-	0xaa, 0x12, 0xc1, 0x5d, 0x6b, 0x68, 0x8d, 0x1d, 0x56, 0x95, 0xd0, 0x03, 0x54, 0x24, 0xb7, 0xd9,
-	0x94, 0xc7, 0xbe, 0xe0, 0x71, 0x52, 0x88, 0x99, 0x5b, 0x33, 0xc1, 0x7f, 0x1c, 0xbc, 0x04, 0xe7,
-	0x33, 0xe4, 0x9b, 0x3c, 0xdc, 0x50, 0xe6, 0xda, 0x43, 0x5b, 0xc7, 0xfe, 0x04, 0xdd, 0x67, 0xaf,
-	0x89, 0xdc, 0xba, 0x3e, 0xde, 0x9e, 0x74, 0xbd, 0x03, 0x88, 0x37, 0x23, 0x62, 0x85, 0x85, 0x7d,
-	0x68, 0xa6, 0xf9, 0xea, 0x89, 0x76, 0x6e, 0x43, 0x87, 0x3a, 0xac, 0x9c, 0x06, 0x3f, 0x16, 0xd8,
-	0x3a, 0x84, 0xd7, 0x70, 0xba, 0x4e, 0xbe, 0x28, 0xd6, 0xff, 0x06, 0xb7, 0x3d, 0xe9, 0x55, 0xae,
-	0x99, 0xcb, 0x24, 0x22, 0x76, 0x48, 0xe0, 0x15, 0x40, 0x4a, 0x32, 0x22, 0xae, 0x74, 0xbd, 0xa1,
-	0xae, 0xb1, 0x8a, 0x82, 0x37, 0xd0, 0xd2, 0xa5, 0x8b, 0x5d, 0x4a, 0x9a, 0xd5, 0x1a, 0x77, 0x27,
-	0xfd, 0x63, 0xa6, 0xe2, 0x2b, 0x5c, 0xb6, 0x8f, 0x8d, 0xee, 0xa0, 0x55, 0x6a, 0xe8, 0x40, 0x63,
-	0xf6, 0xf8, 0x11, 0xdc, 0xf7, 0x4e, 0xb0, 0x0b, 0x30, 0x0f, 0x98, 0x1f, 0xbc, 0x2e, 0xa6, 0x0f,
-	0x41, 0xcf, 0xc2, 0x0b, 0x38, 0x37, 0xd6, 0x72, 0xfe, 0xfc, 0xfe, 0xb6, 0xac, 0x58, 0xb5, 0x81,
-	0x0f, 0x0d, 0x43, 0x89, 0x23, 0xe8, 0x44, 0xb9, 0x94, 0xc4, 0xa3, 0x9d, 0xaf, 0x3b, 0xcb, 0xe5,
-	0x1f, 0x69, 0xc5, 0x36, 0xc2, 0xad, 0xc8, 0xb9, 0x32, 0xec, 0x75, 0x56, 0x4e, 0xab, 0xa6, 0x79,
-	0xcc, 0xdb, 0xdf, 0x00, 0x00, 0x00, 0xff, 0xff, 0xbf, 0x21, 0x80, 0x21, 0xdf, 0x01, 0x00, 0x00,
+	// 334 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x09, 0x6e, 0x88, 0x02, 0xff, 0x6c, 0x91, 0xdd, 0x4a, 0xf3, 0x40,
+	0x10, 0x86, 0xbf, 0x34, 0xfd, 0xf9, 0x32, 0x2d, 0xb5, 0x2c, 0x58, 0xd6, 0x22, 0xb2, 0xf4, 0x28,
+	0x07, 0x12, 0xa4, 0x5e, 0x80, 0x94, 0x98, 0x88, 0xf8, 0x43, 0x59, 0x2b, 0x78, 0x56, 0xd2, 0xec,
+	0xa4, 0x04, 0xec, 0x6e, 0xd8, 0x24, 0x60, 0x6f, 0xd0, 0x1b, 0xf1, 0x46, 0x24, 0x6b, 0x5a, 0x53,
+	0xf0, 0x70, 0x9e, 0x67, 0xd8, 0x79, 0x67, 0x07, 0x4e, 0xb6, 0x4a, 0xa0, 0x8e, 0x0a, 0xa5, 0xbd,
+	0x4c, 0xab, 0x42, 0x4d, 0xbf, 0x6c, 0x70, 0x9e, 0xf6, 0x8c, 0x30, 0xe8, 0x0b, 0xcc, 0x63, 0x9d,
+	0x66, 0x45, 0xaa, 0x24, 0xb5, 0x98, 0xe5, 0x3a, 0xbc, 0x89, 0x88, 0x07, 0xa4, 0x40, 0xbd, 0xcd,
+	0xe7, 0x52, 0xf8, 0x4a, 0x8a, 0xb4, 0x82, 0x39, 0x6d, 0x99, 0xc6, 0x3f, 0x0c, 0x39, 0x07, 0xe7,
+	0x3d, 0x92, 0x9b, 0x32, 0xda, 0x60, 0x4e, 0x6d, 0x66, 0xbb, 0x0e, 0xff, 0x05, 0x84, 0x81, 0x9d,
+	0x20, 0xd2, 0x36, 0xb3, 0xdc, 0xfe, 0x6c, 0xe8, 0x1d, 0x82, 0x78, 0x21, 0x22, 0xaf, 0x14, 0x19,
+	0x43, 0x37, 0x2b, 0xd7, 0x0f, 0xb8, 0xa3, 0x1d, 0x66, 0xb9, 0x03, 0x5e, 0x57, 0xe4, 0x02, 0x20,
+	0x41, 0x9c, 0x0b, 0xa1, 0x31, 0xcf, 0x69, 0xd7, 0xcc, 0x6f, 0x90, 0xc9, 0xa7, 0x05, 0x76, 0x88,
+	0x48, 0x2e, 0xe1, 0x7f, 0x92, 0x7e, 0xa0, 0x08, 0x11, 0xcd, 0x3a, 0xfd, 0xd9, 0xa8, 0x31, 0x66,
+	0xa1, 0xd3, 0x18, 0xf9, 0xa1, 0xa3, 0x7a, 0x35, 0x43, 0x1d, 0xa3, 0x2c, 0xa2, 0x0d, 0x9a, 0xad,
+	0x5a, 0xbc, 0x41, 0xc8, 0x15, 0xf4, 0x12, 0xc4, 0xe5, 0x2e, 0x43, 0x6a, 0x33, 0xcb, 0x1d, 0xce,
+	0xc6, 0xc7, 0x99, 0xbd, 0xf0, 0xc7, 0xf2, 0x7d, 0xdb, 0xf4, 0x06, 0x7a, 0x35, 0x23, 0x0e, 0x74,
+	0xc2, 0xfb, 0xb7, 0xe0, 0x76, 0xf4, 0x8f, 0x0c, 0x01, 0x16, 0x01, 0xf7, 0x83, 0xe7, 0xe5, 0xfc,
+	0x2e, 0x18, 0x59, 0xe4, 0x0c, 0x4e, 0x8d, 0x5a, 0x2d, 0x1e, 0x5f, 0x5f, 0x56, 0x0d, 0xd5, 0x9a,
+	0xf8, 0xd0, 0x31, 0x29, 0xc9, 0x14, 0x06, 0x71, 0xa9, 0x35, 0xca, 0x78, 0xe7, 0x2b, 0x81, 0xf5,
+	0x71, 0x8e, 0x58, 0xf5, 0x5b, 0xd1, 0x56, 0x95, 0xb2, 0x30, 0xd9, 0xdb, 0xbc, 0xae, 0xd6, 0x5d,
+	0x73, 0xec, 0xeb, 0xef, 0x01, 0x00, 0x5d, 0xf6, 0xa8, 0x9f, 0xff, 0x01, 0x00, 0x00,
 }
@@ -49,31 +49,40 @@ const (
 	OrderState_CANCELED OrderState = 8
 	// Vendor declined to confirm the order (offline order only)
 	OrderState_REJECTED OrderState = 9
+	// Vendor refunded part of the order. The remainder of the order continues
+	// on to fulfillment and completion.
+	OrderState_PARTIALLY_REFUNDED OrderState = 10
+	// Vendor has shipped some, but not all, of the items in the order
+	OrderState_PARTIALLY_FULFILLED OrderState = 11
 )
 
 var OrderState_name = map[int32]string{
-	0: "PENDING",
-	1: "CONFIRMED",
-	2: "FUNDED",
-	3: "FULFILLED",
-	4: "COMPLETE",
-	5: "DISPUTED",
-	6: "RESOLVED",
-	7: "REFUNDED",
-	8: "CANCELED",
-	9: "REJECTED",
+	0:  "PENDING",
+	1:  "CONFIRMED",
+	2:  "FUNDED",
+	3:  "FULFILLED",
+	4:  "COMPLETE",
+	5:  "DISPUTED",
+	6:  "RESOLVED",
+	7:  "REFUNDED",
+	8:  "CANCELED",
+	9:  "REJECTED",
+	10: "PARTIALLY_REFUNDED",
+	11: "PARTIALLY_FULFILLED",
 }
 var OrderState_value = map[string]int32{
-	"PENDING":   0,
-	"CONFIRMED": 1,
-	"FUNDED":    2,
-	"FULFILLED": 3,
-	"COMPLETE":  4,
-	"DISPUTED":  5,
-	"RESOLVED":  6,
-	"REFUNDED":  7,
-	"CANCELED":  8,
-	"REJECTED":  9,
+	"PENDING":             0,
+	"CONFIRMED":           1,
+	"FUNDED":              2,
+	"FULFILLED":           3,
+	"COMPLETE":            4,
+	"DISPUTED":            5,
+	"RESOLVED":            6,
+	"REFUNDED":            7,
+	"CANCELED":            8,
+	"REJECTED":            9,
+	"PARTIALLY_REFUNDED":  10,
+	"PARTIALLY_FULFILLED": 11,
 }
 
 func (x OrderState) String() string {
@@ -86,16 +95,17 @@ func init() {
 }
 
 var fileDescriptor6 = []byte{
-	// 163 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x09, 0x6e, 0x88, 0x02, 0xff, 0x2c, 0x8e, 0x4d, 0x0e, 0xc2, 0x20,
-	0x10, 0x85, 0xad, 0x3f, 0xfd, 0x19, 0x6b, 0xd2, 0x70, 0x0c, 0x17, 0x6e, 0x3c, 0x81, 0x81, 0xc1,
-	0x60, 0x28, 0x34, 0x6d, 0x71, 0xaf, 0xb1, 0x6b, 0x0c, 0x72, 0x17, 0xaf, 0x2b, 0xd3, 0xba, 0x9b,
-	0x6f, 0xbe, 0xbc, 0x97, 0x07, 0xb5, 0x0f, 0xaf, 0x29, 0x7c, 0x4e, 0xef, 0xe0, 0xa3, 0x3f, 0x7e,
-	0x33, 0x00, 0x4b, 0x8f, 0x21, 0x3e, 0xe2, 0xc4, 0xf6, 0x50, 0x74, 0x68, 0x84, 0x32, 0xd7, 0x66,
-	0xc5, 0x0e, 0x50, 0x71, 0x6b, 0xa4, 0xea, 0x5b, 0x14, 0x4d, 0xc6, 0x00, 0x72, 0xe9, 0x8c, 0x48,
-	0xf7, 0x9a, 0x94, 0x74, 0x5a, 0x2a, 0xad, 0x13, 0x6e, 0x58, 0x0d, 0x25, 0xb7, 0x6d, 0xa7, 0x71,
-	0xc4, 0x66, 0x4b, 0x24, 0xd4, 0xd0, 0xb9, 0x31, 0xb9, 0x1d, 0x51, 0x8f, 0x83, 0xd5, 0xf7, 0x44,
-	0xf9, 0x42, 0xff, 0x9a, 0x62, 0xce, 0x5d, 0x0c, 0x47, 0x6a, 0x29, 0x17, 0x77, 0x43, 0x4e, 0xb9,
-	0xea, 0x99, 0xcf, 0x03, 0xcf, 0xbf, 0x00, 0x00, 0x00, 0xff, 0xff, 0x65, 0x3f, 0x75, 0x01, 0xb0,
-	0x00, 0x00, 0x00,
+	// 187 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x09, 0x6e, 0x88, 0x02, 0xff, 0x44, 0x8f, 0xcd, 0xaa, 0xc2, 0x30,
+	0x10, 0x85, 0x6f, 0xef, 0xbd, 0xf6, 0x67, 0x5a, 0x21, 0x44, 0xd0, 0x77, 0x70, 0xe1, 0xc6, 0x27,
+	0x28, 0x99, 0x89, 0x44, 0xd2, 0x24, 0xf4, 0x47, 0x70, 0x25, 0x8a, 0x5d, 0x57, 0x6a, 0x1f, 0xd2,
+	0xc7, 0x92, 0xb4, 0x62, 0x97, 0xdf, 0x39, 0x7c, 0x87, 0x19, 0xc8, 0xba, 0xfe, 0xde, 0xf6, 0xcf,
+	0xdd, 0xa3, 0xef, 0x86, 0x6e, 0xfb, 0x0a, 0x00, 0xac, 0x0f, 0xaa, 0xe1, 0x3a, 0xb4, 0x3c, 0x85,
+	0xc8, 0x91, 0x41, 0x65, 0x0e, 0xec, 0x87, 0x2f, 0x21, 0x11, 0xd6, 0x48, 0x55, 0x16, 0x84, 0x2c,
+	0xe0, 0x00, 0xa1, 0x6c, 0x0c, 0x12, 0xb2, 0x5f, 0x5f, 0xc9, 0x46, 0x4b, 0xa5, 0x35, 0x21, 0xfb,
+	0xe3, 0x19, 0xc4, 0xc2, 0x16, 0x4e, 0x53, 0x4d, 0xec, 0xdf, 0x13, 0xaa, 0xca, 0x35, 0x35, 0x21,
+	0x5b, 0x78, 0x2a, 0xa9, 0xb2, 0xfa, 0x44, 0xc8, 0xc2, 0x89, 0x3e, 0x33, 0xd1, 0xe8, 0xe5, 0x46,
+	0x90, 0x5f, 0x89, 0xa7, 0xee, 0x48, 0xc2, 0x7b, 0x09, 0x5f, 0x03, 0x77, 0x79, 0x59, 0xab, 0x5c,
+	0xeb, 0xf3, 0xe5, 0xeb, 0x00, 0xdf, 0xc0, 0x6a, 0xce, 0xe7, 0x23, 0xd2, 0x5b, 0x38, 0x7e, 0xb4,
+	0x7f, 0x0f, 0x00, 0x72, 0x46, 0x21, 0x07, 0xe1, 0x00, 0x00, 0x00,
 }
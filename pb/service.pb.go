@@ -0,0 +1,991 @@
+// Code generated by protoc-gen-go.
+// source: service.proto
+// DO NOT EDIT!
+
+/*
+Package service is a generated protocol buffer package.
+
+It is generated from these files:
+	service.proto
+
+It has these top-level messages:
+	Empty
+	ListingIndex
+	ListingRequest
+	ListingSlug
+	OrderList
+	OrderRequest
+	OrderConfirmationRequest
+	Settings
+	Balance
+	Address
+	SpendRequest
+	Notification
+*/
+package pb
+
+import proto "github.com/golang/protobuf/proto"
+import fmt "fmt"
+import math "math"
+import google_protobuf "github.com/golang/protobuf/ptypes/timestamp"
+
+import (
+	context "golang.org/x/net/context"
+	grpc "google.golang.org/grpc"
+)
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ = proto.Marshal
+var _ = fmt.Errorf
+var _ = math.Inf
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the proto package it is being compiled against.
+const _ = proto.ProtoPackageIsVersion1
+
+type Empty struct {
+}
+
+func (m *Empty) Reset()                    { *m = Empty{} }
+func (m *Empty) String() string            { return proto.CompactTextString(m) }
+func (*Empty) ProtoMessage()               {}
+func (*Empty) Descriptor() ([]byte, []int) { return fileDescriptor7, []int{0} }
+
+type ListingIndex struct {
+	Listings []*ListingIndex_Entry `protobuf:"bytes,1,rep,name=listings" json:"listings,omitempty"`
+}
+
+func (m *ListingIndex) Reset()                    { *m = ListingIndex{} }
+func (m *ListingIndex) String() string            { return proto.CompactTextString(m) }
+func (*ListingIndex) ProtoMessage()               {}
+func (*ListingIndex) Descriptor() ([]byte, []int) { return fileDescriptor7, []int{1} }
+
+func (m *ListingIndex) GetListings() []*ListingIndex_Entry {
+	if m != nil {
+		return m.Listings
+	}
+	return nil
+}
+
+type ListingIndex_Entry struct {
+	Hash         string                        `protobuf:"bytes,1,opt,name=hash" json:"hash,omitempty"`
+	Slug         string                        `protobuf:"bytes,2,opt,name=slug" json:"slug,omitempty"`
+	Title        string                        `protobuf:"bytes,3,opt,name=title" json:"title,omitempty"`
+	Category     []string                      `protobuf:"bytes,4,rep,name=category" json:"category,omitempty"`
+	ContractType string                        `protobuf:"bytes,5,opt,name=contractType" json:"contractType,omitempty"`
+	Description  string                        `protobuf:"bytes,6,opt,name=description" json:"description,omitempty"`
+	Thumbnail    *ListingIndex_Entry_Thumbnail `protobuf:"bytes,7,opt,name=thumbnail" json:"thumbnail,omitempty"`
+	Price        *ListingIndex_Entry_Price     `protobuf:"bytes,8,opt,name=price" json:"price,omitempty"`
+	ShipsTo      []string                      `protobuf:"bytes,9,rep,name=shipsTo" json:"shipsTo,omitempty"`
+	FreeShipping []string                      `protobuf:"bytes,10,rep,name=freeShipping" json:"freeShipping,omitempty"`
+}
+
+func (m *ListingIndex_Entry) Reset()                    { *m = ListingIndex_Entry{} }
+func (m *ListingIndex_Entry) String() string            { return proto.CompactTextString(m) }
+func (*ListingIndex_Entry) ProtoMessage()               {}
+func (*ListingIndex_Entry) Descriptor() ([]byte, []int) { return fileDescriptor7, []int{1, 0} }
+
+func (m *ListingIndex_Entry) GetThumbnail() *ListingIndex_Entry_Thumbnail {
+	if m != nil {
+		return m.Thumbnail
+	}
+	return nil
+}
+
+func (m *ListingIndex_Entry) GetPrice() *ListingIndex_Entry_Price {
+	if m != nil {
+		return m.Price
+	}
+	return nil
+}
+
+type ListingIndex_Entry_Thumbnail struct {
+	Tiny   string `protobuf:"bytes,1,opt,name=tiny" json:"tiny,omitempty"`
+	Small  string `protobuf:"bytes,2,opt,name=small" json:"small,omitempty"`
+	Medium string `protobuf:"bytes,3,opt,name=medium" json:"medium,omitempty"`
+}
+
+func (m *ListingIndex_Entry_Thumbnail) Reset()         { *m = ListingIndex_Entry_Thumbnail{} }
+func (m *ListingIndex_Entry_Thumbnail) String() string { return proto.CompactTextString(m) }
+func (*ListingIndex_Entry_Thumbnail) ProtoMessage()    {}
+func (*ListingIndex_Entry_Thumbnail) Descriptor() ([]byte, []int) {
+	return fileDescriptor7, []int{1, 0, 0}
+}
+
+type ListingIndex_Entry_Price struct {
+	CurrencyCode string `protobuf:"bytes,1,opt,name=currencyCode" json:"currencyCode,omitempty"`
+	Amount       uint64 `protobuf:"varint,2,opt,name=amount" json:"amount,omitempty"`
+}
+
+func (m *ListingIndex_Entry_Price) Reset()                    { *m = ListingIndex_Entry_Price{} }
+func (m *ListingIndex_Entry_Price) String() string            { return proto.CompactTextString(m) }
+func (*ListingIndex_Entry_Price) ProtoMessage()               {}
+func (*ListingIndex_Entry_Price) Descriptor() ([]byte, []int) { return fileDescriptor7, []int{1, 0, 1} }
+
+type ListingRequest struct {
+	ListingId string `protobuf:"bytes,1,opt,name=listingId" json:"listingId,omitempty"`
+}
+
+func (m *ListingRequest) Reset()                    { *m = ListingRequest{} }
+func (m *ListingRequest) String() string            { return proto.CompactTextString(m) }
+func (*ListingRequest) ProtoMessage()               {}
+func (*ListingRequest) Descriptor() ([]byte, []int) { return fileDescriptor7, []int{2} }
+
+type ListingSlug struct {
+	Slug string `protobuf:"bytes,1,opt,name=slug" json:"slug,omitempty"`
+}
+
+func (m *ListingSlug) Reset()                    { *m = ListingSlug{} }
+func (m *ListingSlug) String() string            { return proto.CompactTextString(m) }
+func (*ListingSlug) ProtoMessage()               {}
+func (*ListingSlug) Descriptor() ([]byte, []int) { return fileDescriptor7, []int{3} }
+
+type OrderList struct {
+	Orders []*OrderList_Summary `protobuf:"bytes,1,rep,name=orders" json:"orders,omitempty"`
+}
+
+func (m *OrderList) Reset()                    { *m = OrderList{} }
+func (m *OrderList) String() string            { return proto.CompactTextString(m) }
+func (*OrderList) ProtoMessage()               {}
+func (*OrderList) Descriptor() ([]byte, []int) { return fileDescriptor7, []int{4} }
+
+func (m *OrderList) GetOrders() []*OrderList_Summary {
+	if m != nil {
+		return m.Orders
+	}
+	return nil
+}
+
+type OrderList_Summary struct {
+	OrderId   string                     `protobuf:"bytes,1,opt,name=orderId" json:"orderId,omitempty"`
+	Type      string                     `protobuf:"bytes,2,opt,name=type" json:"type,omitempty"`
+	PeerId    string                     `protobuf:"bytes,3,opt,name=peerId" json:"peerId,omitempty"`
+	Title     string                     `protobuf:"bytes,4,opt,name=title" json:"title,omitempty"`
+	Total     uint64                     `protobuf:"varint,5,opt,name=total" json:"total,omitempty"`
+	State     OrderState                 `protobuf:"varint,6,opt,name=state,enum=OrderState" json:"state,omitempty"`
+	Funded    bool                       `protobuf:"varint,7,opt,name=funded" json:"funded,omitempty"`
+	Read      bool                       `protobuf:"varint,8,opt,name=read" json:"read,omitempty"`
+	Timestamp *google_protobuf.Timestamp `protobuf:"bytes,9,opt,name=timestamp" json:"timestamp,omitempty"`
+}
+
+func (m *OrderList_Summary) Reset()                    { *m = OrderList_Summary{} }
+func (m *OrderList_Summary) String() string            { return proto.CompactTextString(m) }
+func (*OrderList_Summary) ProtoMessage()               {}
+func (*OrderList_Summary) Descriptor() ([]byte, []int) { return fileDescriptor7, []int{4, 0} }
+
+func (m *OrderList_Summary) GetTimestamp() *google_protobuf.Timestamp {
+	if m != nil {
+		return m.Timestamp
+	}
+	return nil
+}
+
+type OrderRequest struct {
+	OrderId string `protobuf:"bytes,1,opt,name=orderId" json:"orderId,omitempty"`
+}
+
+func (m *OrderRequest) Reset()                    { *m = OrderRequest{} }
+func (m *OrderRequest) String() string            { return proto.CompactTextString(m) }
+func (*OrderRequest) ProtoMessage()               {}
+func (*OrderRequest) Descriptor() ([]byte, []int) { return fileDescriptor7, []int{5} }
+
+type OrderConfirmationRequest struct {
+	OrderId string `protobuf:"bytes,1,opt,name=orderId" json:"orderId,omitempty"`
+	Reject  bool   `protobuf:"varint,2,opt,name=reject" json:"reject,omitempty"`
+}
+
+func (m *OrderConfirmationRequest) Reset()                    { *m = OrderConfirmationRequest{} }
+func (m *OrderConfirmationRequest) String() string            { return proto.CompactTextString(m) }
+func (*OrderConfirmationRequest) ProtoMessage()               {}
+func (*OrderConfirmationRequest) Descriptor() ([]byte, []int) { return fileDescriptor7, []int{6} }
+
+type Settings struct {
+	Json []byte `protobuf:"bytes,1,opt,name=json,proto3" json:"json,omitempty"`
+}
+
+func (m *Settings) Reset()                    { *m = Settings{} }
+func (m *Settings) String() string            { return proto.CompactTextString(m) }
+func (*Settings) ProtoMessage()               {}
+func (*Settings) Descriptor() ([]byte, []int) { return fileDescriptor7, []int{7} }
+
+type Balance struct {
+	Confirmed   uint64 `protobuf:"varint,1,opt,name=confirmed" json:"confirmed,omitempty"`
+	Unconfirmed uint64 `protobuf:"varint,2,opt,name=unconfirmed" json:"unconfirmed,omitempty"`
+}
+
+func (m *Balance) Reset()                    { *m = Balance{} }
+func (m *Balance) String() string            { return proto.CompactTextString(m) }
+func (*Balance) ProtoMessage()               {}
+func (*Balance) Descriptor() ([]byte, []int) { return fileDescriptor7, []int{8} }
+
+type Address struct {
+	Address string `protobuf:"bytes,1,opt,name=address" json:"address,omitempty"`
+}
+
+func (m *Address) Reset()                    { *m = Address{} }
+func (m *Address) String() string            { return proto.CompactTextString(m) }
+func (*Address) ProtoMessage()               {}
+func (*Address) Descriptor() ([]byte, []int) { return fileDescriptor7, []int{9} }
+
+type SpendRequest struct {
+	Address  string `protobuf:"bytes,1,opt,name=address" json:"address,omitempty"`
+	Amount   int64  `protobuf:"varint,2,opt,name=amount" json:"amount,omitempty"`
+	FeeLevel string `protobuf:"bytes,3,opt,name=feeLevel" json:"feeLevel,omitempty"`
+}
+
+func (m *SpendRequest) Reset()                    { *m = SpendRequest{} }
+func (m *SpendRequest) String() string            { return proto.CompactTextString(m) }
+func (*SpendRequest) ProtoMessage()               {}
+func (*SpendRequest) Descriptor() ([]byte, []int) { return fileDescriptor7, []int{10} }
+
+type Notification struct {
+	Json []byte `protobuf:"bytes,1,opt,name=json,proto3" json:"json,omitempty"`
+}
+
+func (m *Notification) Reset()                    { *m = Notification{} }
+func (m *Notification) String() string            { return proto.CompactTextString(m) }
+func (*Notification) ProtoMessage()               {}
+func (*Notification) Descriptor() ([]byte, []int) { return fileDescriptor7, []int{11} }
+
+func init() {
+	proto.RegisterType((*Empty)(nil), "Empty")
+	proto.RegisterType((*ListingIndex)(nil), "ListingIndex")
+	proto.RegisterType((*ListingIndex_Entry)(nil), "ListingIndex.Entry")
+	proto.RegisterType((*ListingIndex_Entry_Thumbnail)(nil), "ListingIndex.Entry.Thumbnail")
+	proto.RegisterType((*ListingIndex_Entry_Price)(nil), "ListingIndex.Entry.Price")
+	proto.RegisterType((*ListingRequest)(nil), "ListingRequest")
+	proto.RegisterType((*ListingSlug)(nil), "ListingSlug")
+	proto.RegisterType((*OrderList)(nil), "OrderList")
+	proto.RegisterType((*OrderList_Summary)(nil), "OrderList.Summary")
+	proto.RegisterType((*OrderRequest)(nil), "OrderRequest")
+	proto.RegisterType((*OrderConfirmationRequest)(nil), "OrderConfirmationRequest")
+	proto.RegisterType((*Settings)(nil), "Settings")
+	proto.RegisterType((*Balance)(nil), "Balance")
+	proto.RegisterType((*Address)(nil), "Address")
+	proto.RegisterType((*SpendRequest)(nil), "SpendRequest")
+	proto.RegisterType((*Notification)(nil), "Notification")
+}
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ context.Context
+var _ grpc.ClientConn
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+const _ = grpc.SupportPackageIsVersion4
+
+// Client API for OpenBazaar service
+
+type OpenBazaarClient interface {
+	GetListings(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*ListingIndex, error)
+	GetListing(ctx context.Context, in *ListingRequest, opts ...grpc.CallOption) (*ListingRespApi, error)
+	CreateListing(ctx context.Context, in *ListingReqApi, opts ...grpc.CallOption) (*ListingSlug, error)
+	UpdateListing(ctx context.Context, in *ListingReqApi, opts ...grpc.CallOption) (*Empty, error)
+	DeleteListing(ctx context.Context, in *ListingSlug, opts ...grpc.CallOption) (*Empty, error)
+	GetOrders(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*OrderList, error)
+	GetOrder(ctx context.Context, in *OrderRequest, opts ...grpc.CallOption) (*OrderRespApi, error)
+	ConfirmOrder(ctx context.Context, in *OrderConfirmationRequest, opts ...grpc.CallOption) (*Empty, error)
+	CancelOrder(ctx context.Context, in *OrderRequest, opts ...grpc.CallOption) (*Empty, error)
+	FulfillOrder(ctx context.Context, in *OrderFulfillment, opts ...grpc.CallOption) (*Empty, error)
+	RefundOrder(ctx context.Context, in *Refund, opts ...grpc.CallOption) (*Empty, error)
+	UpdateProfile(ctx context.Context, in *Profile, opts ...grpc.CallOption) (*Profile, error)
+	GetSettings(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*Settings, error)
+	UpdateSettings(ctx context.Context, in *Settings, opts ...grpc.CallOption) (*Empty, error)
+	GetBalance(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*Balance, error)
+	GetAddress(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*Address, error)
+	Spend(ctx context.Context, in *SpendRequest, opts ...grpc.CallOption) (*Empty, error)
+	Subscribe(ctx context.Context, in *Empty, opts ...grpc.CallOption) (OpenBazaar_SubscribeClient, error)
+}
+
+type openBazaarClient struct {
+	cc *grpc.ClientConn
+}
+
+func NewOpenBazaarClient(cc *grpc.ClientConn) OpenBazaarClient {
+	return &openBazaarClient{cc}
+}
+
+func (c *openBazaarClient) GetListings(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*ListingIndex, error) {
+	out := new(ListingIndex)
+	err := grpc.Invoke(ctx, "/OpenBazaar/GetListings", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *openBazaarClient) GetListing(ctx context.Context, in *ListingRequest, opts ...grpc.CallOption) (*ListingRespApi, error) {
+	out := new(ListingRespApi)
+	err := grpc.Invoke(ctx, "/OpenBazaar/GetListing", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *openBazaarClient) CreateListing(ctx context.Context, in *ListingReqApi, opts ...grpc.CallOption) (*ListingSlug, error) {
+	out := new(ListingSlug)
+	err := grpc.Invoke(ctx, "/OpenBazaar/CreateListing", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *openBazaarClient) UpdateListing(ctx context.Context, in *ListingReqApi, opts ...grpc.CallOption) (*Empty, error) {
+	out := new(Empty)
+	err := grpc.Invoke(ctx, "/OpenBazaar/UpdateListing", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *openBazaarClient) DeleteListing(ctx context.Context, in *ListingSlug, opts ...grpc.CallOption) (*Empty, error) {
+	out := new(Empty)
+	err := grpc.Invoke(ctx, "/OpenBazaar/DeleteListing", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *openBazaarClient) GetOrders(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*OrderList, error) {
+	out := new(OrderList)
+	err := grpc.Invoke(ctx, "/OpenBazaar/GetOrders", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *openBazaarClient) GetOrder(ctx context.Context, in *OrderRequest, opts ...grpc.CallOption) (*OrderRespApi, error) {
+	out := new(OrderRespApi)
+	err := grpc.Invoke(ctx, "/OpenBazaar/GetOrder", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *openBazaarClient) ConfirmOrder(ctx context.Context, in *OrderConfirmationRequest, opts ...grpc.CallOption) (*Empty, error) {
+	out := new(Empty)
+	err := grpc.Invoke(ctx, "/OpenBazaar/ConfirmOrder", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *openBazaarClient) CancelOrder(ctx context.Context, in *OrderRequest, opts ...grpc.CallOption) (*Empty, error) {
+	out := new(Empty)
+	err := grpc.Invoke(ctx, "/OpenBazaar/CancelOrder", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *openBazaarClient) FulfillOrder(ctx context.Context, in *OrderFulfillment, opts ...grpc.CallOption) (*Empty, error) {
+	out := new(Empty)
+	err := grpc.Invoke(ctx, "/OpenBazaar/FulfillOrder", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *openBazaarClient) RefundOrder(ctx context.Context, in *Refund, opts ...grpc.CallOption) (*Empty, error) {
+	out := new(Empty)
+	err := grpc.Invoke(ctx, "/OpenBazaar/RefundOrder", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *openBazaarClient) UpdateProfile(ctx context.Context, in *Profile, opts ...grpc.CallOption) (*Profile, error) {
+	out := new(Profile)
+	err := grpc.Invoke(ctx, "/OpenBazaar/UpdateProfile", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *openBazaarClient) GetSettings(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*Settings, error) {
+	out := new(Settings)
+	err := grpc.Invoke(ctx, "/OpenBazaar/GetSettings", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *openBazaarClient) UpdateSettings(ctx context.Context, in *Settings, opts ...grpc.CallOption) (*Empty, error) {
+	out := new(Empty)
+	err := grpc.Invoke(ctx, "/OpenBazaar/UpdateSettings", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *openBazaarClient) GetBalance(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*Balance, error) {
+	out := new(Balance)
+	err := grpc.Invoke(ctx, "/OpenBazaar/GetBalance", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *openBazaarClient) GetAddress(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*Address, error) {
+	out := new(Address)
+	err := grpc.Invoke(ctx, "/OpenBazaar/GetAddress", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *openBazaarClient) Spend(ctx context.Context, in *SpendRequest, opts ...grpc.CallOption) (*Empty, error) {
+	out := new(Empty)
+	err := grpc.Invoke(ctx, "/OpenBazaar/Spend", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *openBazaarClient) Subscribe(ctx context.Context, in *Empty, opts ...grpc.CallOption) (OpenBazaar_SubscribeClient, error) {
+	stream, err := grpc.NewClientStream(ctx, &_OpenBazaar_serviceDesc.Streams[0], c.cc, "/OpenBazaar/Subscribe", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &openBazaarSubscribeClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type OpenBazaar_SubscribeClient interface {
+	Recv() (*Notification, error)
+	grpc.ClientStream
+}
+
+type openBazaarSubscribeClient struct {
+	grpc.ClientStream
+}
+
+func (x *openBazaarSubscribeClient) Recv() (*Notification, error) {
+	m := new(Notification)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// Server API for OpenBazaar service
+
+type OpenBazaarServer interface {
+	GetListings(context.Context, *Empty) (*ListingIndex, error)
+	GetListing(context.Context, *ListingRequest) (*ListingRespApi, error)
+	CreateListing(context.Context, *ListingReqApi) (*ListingSlug, error)
+	UpdateListing(context.Context, *ListingReqApi) (*Empty, error)
+	DeleteListing(context.Context, *ListingSlug) (*Empty, error)
+	GetOrders(context.Context, *Empty) (*OrderList, error)
+	GetOrder(context.Context, *OrderRequest) (*OrderRespApi, error)
+	ConfirmOrder(context.Context, *OrderConfirmationRequest) (*Empty, error)
+	CancelOrder(context.Context, *OrderRequest) (*Empty, error)
+	FulfillOrder(context.Context, *OrderFulfillment) (*Empty, error)
+	RefundOrder(context.Context, *Refund) (*Empty, error)
+	UpdateProfile(context.Context, *Profile) (*Profile, error)
+	GetSettings(context.Context, *Empty) (*Settings, error)
+	UpdateSettings(context.Context, *Settings) (*Empty, error)
+	GetBalance(context.Context, *Empty) (*Balance, error)
+	GetAddress(context.Context, *Empty) (*Address, error)
+	Spend(context.Context, *SpendRequest) (*Empty, error)
+	Subscribe(*Empty, OpenBazaar_SubscribeServer) error
+}
+
+func RegisterOpenBazaarServer(s *grpc.Server, srv OpenBazaarServer) {
+	s.RegisterService(&_OpenBazaar_serviceDesc, srv)
+}
+
+func _OpenBazaar_GetListings_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Empty)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(OpenBazaarServer).GetListings(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/OpenBazaar/GetListings",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(OpenBazaarServer).GetListings(ctx, req.(*Empty))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _OpenBazaar_GetListing_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListingRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(OpenBazaarServer).GetListing(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/OpenBazaar/GetListing",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(OpenBazaarServer).GetListing(ctx, req.(*ListingRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _OpenBazaar_CreateListing_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListingReqApi)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(OpenBazaarServer).CreateListing(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/OpenBazaar/CreateListing",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(OpenBazaarServer).CreateListing(ctx, req.(*ListingReqApi))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _OpenBazaar_UpdateListing_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListingReqApi)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(OpenBazaarServer).UpdateListing(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/OpenBazaar/UpdateListing",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(OpenBazaarServer).UpdateListing(ctx, req.(*ListingReqApi))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _OpenBazaar_DeleteListing_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListingSlug)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(OpenBazaarServer).DeleteListing(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/OpenBazaar/DeleteListing",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(OpenBazaarServer).DeleteListing(ctx, req.(*ListingSlug))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _OpenBazaar_GetOrders_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Empty)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(OpenBazaarServer).GetOrders(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/OpenBazaar/GetOrders",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(OpenBazaarServer).GetOrders(ctx, req.(*Empty))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _OpenBazaar_GetOrder_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(OrderRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(OpenBazaarServer).GetOrder(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/OpenBazaar/GetOrder",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(OpenBazaarServer).GetOrder(ctx, req.(*OrderRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _OpenBazaar_ConfirmOrder_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(OrderConfirmationRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(OpenBazaarServer).ConfirmOrder(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/OpenBazaar/ConfirmOrder",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(OpenBazaarServer).ConfirmOrder(ctx, req.(*OrderConfirmationRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _OpenBazaar_CancelOrder_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(OrderRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(OpenBazaarServer).CancelOrder(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/OpenBazaar/CancelOrder",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(OpenBazaarServer).CancelOrder(ctx, req.(*OrderRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _OpenBazaar_FulfillOrder_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(OrderFulfillment)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(OpenBazaarServer).FulfillOrder(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/OpenBazaar/FulfillOrder",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(OpenBazaarServer).FulfillOrder(ctx, req.(*OrderFulfillment))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _OpenBazaar_RefundOrder_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Refund)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(OpenBazaarServer).RefundOrder(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/OpenBazaar/RefundOrder",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(OpenBazaarServer).RefundOrder(ctx, req.(*Refund))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _OpenBazaar_UpdateProfile_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Profile)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(OpenBazaarServer).UpdateProfile(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/OpenBazaar/UpdateProfile",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(OpenBazaarServer).UpdateProfile(ctx, req.(*Profile))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _OpenBazaar_GetSettings_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Empty)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(OpenBazaarServer).GetSettings(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/OpenBazaar/GetSettings",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(OpenBazaarServer).GetSettings(ctx, req.(*Empty))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _OpenBazaar_UpdateSettings_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Settings)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(OpenBazaarServer).UpdateSettings(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/OpenBazaar/UpdateSettings",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(OpenBazaarServer).UpdateSettings(ctx, req.(*Settings))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _OpenBazaar_GetBalance_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Empty)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(OpenBazaarServer).GetBalance(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/OpenBazaar/GetBalance",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(OpenBazaarServer).GetBalance(ctx, req.(*Empty))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _OpenBazaar_GetAddress_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Empty)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(OpenBazaarServer).GetAddress(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/OpenBazaar/GetAddress",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(OpenBazaarServer).GetAddress(ctx, req.(*Empty))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _OpenBazaar_Spend_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SpendRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(OpenBazaarServer).Spend(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/OpenBazaar/Spend",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(OpenBazaarServer).Spend(ctx, req.(*SpendRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _OpenBazaar_Subscribe_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(Empty)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(OpenBazaarServer).Subscribe(m, &openBazaarSubscribeServer{stream})
+}
+
+type OpenBazaar_SubscribeServer interface {
+	Send(*Notification) error
+	grpc.ServerStream
+}
+
+type openBazaarSubscribeServer struct {
+	grpc.ServerStream
+}
+
+func (x *openBazaarSubscribeServer) Send(m *Notification) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+var _OpenBazaar_serviceDesc = grpc.ServiceDesc{
+	ServiceName: "OpenBazaar",
+	HandlerType: (*OpenBazaarServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "GetListings",
+			Handler:    _OpenBazaar_GetListings_Handler,
+		},
+		{
+			MethodName: "GetListing",
+			Handler:    _OpenBazaar_GetListing_Handler,
+		},
+		{
+			MethodName: "CreateListing",
+			Handler:    _OpenBazaar_CreateListing_Handler,
+		},
+		{
+			MethodName: "UpdateListing",
+			Handler:    _OpenBazaar_UpdateListing_Handler,
+		},
+		{
+			MethodName: "DeleteListing",
+			Handler:    _OpenBazaar_DeleteListing_Handler,
+		},
+		{
+			MethodName: "GetOrders",
+			Handler:    _OpenBazaar_GetOrders_Handler,
+		},
+		{
+			MethodName: "GetOrder",
+			Handler:    _OpenBazaar_GetOrder_Handler,
+		},
+		{
+			MethodName: "ConfirmOrder",
+			Handler:    _OpenBazaar_ConfirmOrder_Handler,
+		},
+		{
+			MethodName: "CancelOrder",
+			Handler:    _OpenBazaar_CancelOrder_Handler,
+		},
+		{
+			MethodName: "FulfillOrder",
+			Handler:    _OpenBazaar_FulfillOrder_Handler,
+		},
+		{
+			MethodName: "RefundOrder",
+			Handler:    _OpenBazaar_RefundOrder_Handler,
+		},
+		{
+			MethodName: "UpdateProfile",
+			Handler:    _OpenBazaar_UpdateProfile_Handler,
+		},
+		{
+			MethodName: "GetSettings",
+			Handler:    _OpenBazaar_GetSettings_Handler,
+		},
+		{
+			MethodName: "UpdateSettings",
+			Handler:    _OpenBazaar_UpdateSettings_Handler,
+		},
+		{
+			MethodName: "GetBalance",
+			Handler:    _OpenBazaar_GetBalance_Handler,
+		},
+		{
+			MethodName: "GetAddress",
+			Handler:    _OpenBazaar_GetAddress_Handler,
+		},
+		{
+			MethodName: "Spend",
+			Handler:    _OpenBazaar_Spend_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "Subscribe",
+			Handler:       _OpenBazaar_Subscribe_Handler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "service.proto",
+}
+
+var fileDescriptor7 = []byte{
+	// 944 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x09, 0x6e, 0x88, 0x02, 0xff, 0x84, 0x55, 0xcd, 0x8e, 0xe3, 0x44,
+	0x10, 0x56, 0x76, 0x92, 0x89, 0x5d, 0x71, 0x66, 0x45, 0x83, 0x90, 0xd7, 0x82, 0xd9, 0x59, 0xcf,
+	0x22, 0x02, 0x12, 0x3d, 0x30, 0x5c, 0x90, 0x38, 0xcd, 0x86, 0x65, 0x34, 0xd2, 0xc0, 0xae, 0x9c,
+	0xe1, 0xc6, 0xa5, 0x63, 0x57, 0x92, 0x5e, 0xf9, 0x8f, 0x76, 0x7b, 0x45, 0x38, 0xf3, 0x32, 0xbc,
+	0x04, 0x07, 0x5e, 0x86, 0xd7, 0x40, 0xfd, 0x67, 0x3b, 0x28, 0x0b, 0xb7, 0xfa, 0xbe, 0xfe, 0xba,
+	0x5c, 0x55, 0xf9, 0xaa, 0x03, 0xf3, 0x06, 0xc5, 0x5b, 0x9e, 0x22, 0xad, 0x45, 0x25, 0xab, 0xe8,
+	0xe9, 0xb6, 0xaa, 0xb6, 0x39, 0x5e, 0x69, 0xb4, 0x6e, 0x37, 0x57, 0x92, 0x17, 0xd8, 0x48, 0x56,
+	0xd4, 0x56, 0xf0, 0x38, 0xad, 0x4a, 0x29, 0x58, 0x2a, 0x1b, 0x4b, 0x04, 0x95, 0xc8, 0x50, 0x38,
+	0x34, 0xaf, 0x45, 0xb5, 0xe1, 0xb9, 0x4b, 0xe7, 0xb3, 0x9a, 0x9b, 0x30, 0x9e, 0xc2, 0xe4, 0x65,
+	0x51, 0xcb, 0x7d, 0xfc, 0xc7, 0x18, 0x82, 0x7b, 0xde, 0x48, 0x5e, 0x6e, 0xef, 0xca, 0x0c, 0x7f,
+	0x25, 0x57, 0xe0, 0xe5, 0x06, 0x37, 0xe1, 0xe8, 0xe2, 0x64, 0x31, 0xbb, 0x7e, 0x9f, 0x0e, 0x05,
+	0xf4, 0x65, 0x29, 0xc5, 0x3e, 0xe9, 0x44, 0xd1, 0xdf, 0x27, 0x30, 0xd1, 0x1c, 0x21, 0x30, 0xde,
+	0xb1, 0x66, 0x17, 0x8e, 0x2e, 0x46, 0x0b, 0x3f, 0xd1, 0xb1, 0xe2, 0x9a, 0xbc, 0xdd, 0x86, 0x8f,
+	0x0c, 0xa7, 0x62, 0xf2, 0x01, 0x4c, 0x24, 0x97, 0x39, 0x86, 0x27, 0x9a, 0x34, 0x80, 0x44, 0xe0,
+	0xa5, 0x4c, 0xe2, 0xb6, 0x12, 0xfb, 0x70, 0x7c, 0x71, 0xb2, 0xf0, 0x93, 0x0e, 0x93, 0x18, 0x02,
+	0xd7, 0xe9, 0xc3, 0xbe, 0xc6, 0x70, 0xa2, 0x2f, 0x1e, 0x70, 0xe4, 0x02, 0x66, 0x19, 0x36, 0xa9,
+	0xe0, 0xb5, 0xe4, 0x55, 0x19, 0x9e, 0x6a, 0xc9, 0x90, 0x22, 0xdf, 0x82, 0x2f, 0x77, 0x6d, 0xb1,
+	0x2e, 0x19, 0xcf, 0xc3, 0xe9, 0xc5, 0x68, 0x31, 0xbb, 0xfe, 0xf8, 0x48, 0x6f, 0xf4, 0xc1, 0x89,
+	0x92, 0x5e, 0x4f, 0xae, 0x60, 0x52, 0x0b, 0x9e, 0x62, 0xe8, 0xe9, 0x8b, 0x4f, 0x8e, 0x5d, 0x7c,
+	0xad, 0x04, 0x89, 0xd1, 0x91, 0x10, 0xa6, 0xcd, 0x8e, 0xd7, 0xcd, 0x43, 0x15, 0xfa, 0xba, 0x1d,
+	0x07, 0x55, 0x37, 0x1b, 0x81, 0xb8, 0xda, 0xf1, 0xba, 0xe6, 0xe5, 0x36, 0x04, 0x7d, 0x7c, 0xc0,
+	0x45, 0x3f, 0x80, 0xdf, 0x95, 0xa1, 0x86, 0x28, 0x79, 0xb9, 0x77, 0x83, 0x55, 0xb1, 0x1a, 0x62,
+	0x53, 0xb0, 0x3c, 0xb7, 0x93, 0x35, 0x80, 0x7c, 0x08, 0xa7, 0x05, 0x66, 0xbc, 0x2d, 0xec, 0x6c,
+	0x2d, 0x8a, 0x96, 0x30, 0xd1, 0xc5, 0xe9, 0x49, 0xb6, 0x42, 0x60, 0x99, 0xee, 0x97, 0x55, 0x86,
+	0x36, 0xe5, 0x01, 0xa7, 0x92, 0xb0, 0xa2, 0x6a, 0x4b, 0xa9, 0x73, 0x8f, 0x13, 0x8b, 0x62, 0x0a,
+	0x67, 0xb6, 0xe9, 0x04, 0x7f, 0x69, 0xb1, 0x91, 0xe4, 0x23, 0xf0, 0xad, 0x0f, 0xee, 0x32, 0x9b,
+	0xaa, 0x27, 0xe2, 0x67, 0x30, 0xb3, 0xfa, 0x95, 0xfa, 0xd9, 0x9d, 0x15, 0x46, 0xbd, 0x15, 0xe2,
+	0x3f, 0x1f, 0x81, 0xff, 0x4a, 0x59, 0x56, 0x09, 0xc9, 0xe7, 0x70, 0x6a, 0xfc, 0x6b, 0x9d, 0x47,
+	0x68, 0x77, 0x46, 0x57, 0x6d, 0x51, 0x30, 0xb1, 0x4f, 0xac, 0x22, 0xfa, 0xfd, 0x11, 0x4c, 0x2d,
+	0xa7, 0x46, 0xad, 0xd9, 0xae, 0x08, 0x07, 0xf5, 0xe4, 0x94, 0x61, 0xac, 0xfd, 0x54, 0xac, 0xda,
+	0xab, 0x51, 0x8b, 0xed, 0x8c, 0x0c, 0xea, 0x6d, 0x39, 0x1e, 0xda, 0x52, 0xb1, 0x95, 0x64, 0xb9,
+	0xf6, 0xdc, 0x38, 0x31, 0x80, 0x3c, 0x83, 0x49, 0x23, 0x99, 0x44, 0x6d, 0xb3, 0xb3, 0xeb, 0x99,
+	0x29, 0x74, 0xa5, 0xa8, 0xc4, 0x9c, 0xa8, 0xcf, 0x6c, 0xda, 0x32, 0xc3, 0x4c, 0x5b, 0xcd, 0x4b,
+	0x2c, 0x52, 0x25, 0x09, 0x64, 0x99, 0xf6, 0x91, 0x97, 0xe8, 0x98, 0x7c, 0x03, 0x7e, 0xb7, 0xda,
+	0xa1, 0xaf, 0x0d, 0x16, 0x51, 0xb3, 0xfc, 0xd4, 0x2d, 0x3f, 0x7d, 0x70, 0x8a, 0xa4, 0x17, 0xc7,
+	0x0b, 0x08, 0xf4, 0xa7, 0xdd, 0x2f, 0xf2, 0xce, 0x51, 0xc4, 0xf7, 0x10, 0x6a, 0xe5, 0xb2, 0x2a,
+	0x37, 0x5c, 0x14, 0x4c, 0xad, 0xc4, 0xff, 0xde, 0x52, 0x5d, 0x08, 0x7c, 0x83, 0xa9, 0xf1, 0x82,
+	0x97, 0x58, 0x14, 0x9f, 0x83, 0xb7, 0x42, 0xa9, 0x5f, 0x00, 0xd5, 0xd1, 0x9b, 0xa6, 0x2a, 0xf5,
+	0xd5, 0x20, 0xd1, 0x71, 0x7c, 0x07, 0xd3, 0x17, 0x2c, 0x67, 0x65, 0x8a, 0xca, 0x24, 0xa9, 0xf9,
+	0x26, 0x9a, 0xf4, 0xe3, 0xa4, 0x27, 0xd4, 0xda, 0xb6, 0x65, 0x7f, 0x6e, 0x1c, 0x37, 0xa4, 0xe2,
+	0x4b, 0x98, 0xde, 0x64, 0x99, 0xc0, 0xa6, 0x51, 0x75, 0x32, 0x13, 0xba, 0x3a, 0x2d, 0x8c, 0x7f,
+	0x86, 0x60, 0x55, 0x63, 0x99, 0x0d, 0x3a, 0x3a, 0xae, 0xfc, 0x97, 0xbb, 0x4f, 0x9c, 0xbb, 0xd5,
+	0xfb, 0xb3, 0x41, 0xbc, 0xc7, 0xb7, 0x98, 0x5b, 0x63, 0x74, 0x38, 0x8e, 0x21, 0xf8, 0xb1, 0x92,
+	0x7c, 0xc3, 0x53, 0x3d, 0xb6, 0x63, 0x1d, 0x5f, 0xff, 0x35, 0x01, 0x78, 0x55, 0x63, 0xf9, 0x82,
+	0xfd, 0xc6, 0x98, 0x20, 0xcf, 0x61, 0x76, 0x8b, 0xd2, 0xfa, 0xbf, 0x21, 0xa7, 0x54, 0xbf, 0xb7,
+	0xd1, 0xfc, 0xe0, 0xdd, 0x20, 0x14, 0xa0, 0x57, 0x91, 0xc7, 0xf4, 0x70, 0xbf, 0xa2, 0x01, 0xd1,
+	0xd4, 0x37, 0x35, 0x27, 0x5f, 0xc0, 0x7c, 0x29, 0x90, 0x49, 0x74, 0x57, 0xce, 0x06, 0x57, 0x6e,
+	0x6a, 0x1e, 0x05, 0x74, 0xb8, 0x72, 0x9f, 0xc2, 0xfc, 0xa7, 0x3a, 0xfb, 0x0f, 0xb9, 0x2d, 0x8b,
+	0x7c, 0x02, 0xf3, 0xef, 0x30, 0xc7, 0x5e, 0x78, 0x90, 0xa7, 0x93, 0x3d, 0x05, 0xff, 0x16, 0xa5,
+	0xb6, 0x51, 0xdf, 0x12, 0xf4, 0x5b, 0x4a, 0x16, 0xe0, 0x39, 0x01, 0x99, 0xd3, 0xa1, 0x33, 0xa3,
+	0x0e, 0x9a, 0x4e, 0xbe, 0x82, 0xc0, 0x3a, 0xd1, 0xa8, 0x9f, 0xd0, 0x77, 0xb9, 0xb3, 0xfb, 0xfa,
+	0x73, 0x98, 0x2d, 0x95, 0xa3, 0xf2, 0xa3, 0xf9, 0x9d, 0xea, 0x33, 0x08, 0xbe, 0x6f, 0xf3, 0x0d,
+	0xcf, 0xad, 0xec, 0x3d, 0x23, 0xb3, 0x5c, 0x81, 0x65, 0x2f, 0x3d, 0x87, 0x59, 0x82, 0x6a, 0x2d,
+	0x8d, 0x72, 0x4a, 0x0d, 0xea, 0xce, 0x2f, 0xdd, 0xf8, 0x5e, 0x9b, 0xff, 0x51, 0xe2, 0x51, 0x1b,
+	0x45, 0x5d, 0xa4, 0x0c, 0x7c, 0x8b, 0xb2, 0x5b, 0x06, 0x37, 0x15, 0x9f, 0x76, 0xd4, 0x25, 0x9c,
+	0x99, 0x34, 0x1d, 0xd3, 0x1f, 0x0e, 0x6a, 0x51, 0x4e, 0x70, 0x3b, 0xe3, 0xb2, 0x78, 0xd4, 0x31,
+	0xe6, 0xdc, 0x2d, 0x42, 0x7f, 0xee, 0x98, 0x73, 0x98, 0xe8, 0x05, 0x20, 0x73, 0x3a, 0x5c, 0x84,
+	0xc1, 0xf0, 0xfc, 0x55, 0xbb, 0x56, 0x7f, 0x86, 0x6b, 0x1c, 0xb8, 0x71, 0x68, 0xeb, 0x2f, 0x47,
+	0xeb, 0x53, 0xfd, 0xda, 0x7c, 0xfd, 0xcf, 0x00, 0xb2, 0xba, 0xb2, 0x34, 0x89, 0x08, 0x00, 0x00,
+}
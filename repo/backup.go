@@ -0,0 +1,506 @@
+package repo
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"gx/ipfs/QmZy2y8t9zQH2a1b8q2ZSLKp17ATuJoCNxxyMFG5qFExpt/go-net/context"
+	"io"
+	"io/ioutil"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/ipfs/go-ipfs/commands"
+	"github.com/ipfs/go-ipfs/core"
+	ipath "github.com/ipfs/go-ipfs/path"
+	"github.com/ipfs/go-ipfs/repo/config"
+	"github.com/ipfs/go-ipfs/repo/fsrepo"
+	"golang.org/x/crypto/scrypt"
+
+	"github.com/OpenBazaar/openbazaar-go/ipfs"
+)
+
+/* A backup is a gzipped tar archive of the repo encrypted with a key derived from a password.
+   The file starts with a magic string, a format version and the scrypt salt. The archive follows
+   in chunks, each sealed with AES-256-GCM under a counter nonce. The final chunk is sealed with
+   different additional data so a truncated backup can't be mistaken for a complete one. */
+
+const BackupVersion = 1
+
+const backupManifestName = "manifest.json"
+
+const backupChunkSize = 64 * 1024
+
+var backupMagic = []byte("OBBACKUP")
+
+var (
+	ErrInvalidBackup         = errors.New("Not an OpenBazaar backup")
+	ErrInvalidBackupPassword = errors.New("Invalid backup password or corrupt backup")
+	ErrTruncatedBackup       = errors.New("The backup is truncated")
+)
+
+type BackupManifest struct {
+	Version int          `json:"version"`
+	Created time.Time    `json:"created"`
+	PeerID  string       `json:"peerID"`
+	Testnet bool         `json:"testnet"`
+	Files   []BackupFile `json:"files"`
+}
+
+type BackupFile struct {
+	Path   string `json:"path"`
+	Size   int64  `json:"size"`
+	SHA256 string `json:"sha256"`
+}
+
+// Write an encrypted backup of the repo to w. It holds the IPFS config, keystore, the root
+// directory, the digital goods directory and the sqlite datastore at dbPath. When the node is
+// running dbPath should be a snapshot of the datastore so the copy in the backup is consistent.
+func WriteBackup(w io.Writer, repoPath, dbPath, password string) error {
+	conf, err := fsrepo.ConfigAt(repoPath)
+	if err != nil {
+		return err
+	}
+	ew, err := newBackupWriter(w, password)
+	if err != nil {
+		return err
+	}
+	gz := gzip.NewWriter(ew)
+	tw := tar.NewWriter(gz)
+	manifest := BackupManifest{
+		Version: BackupVersion,
+		Created: time.Now(),
+		PeerID:  conf.Identity.PeerID,
+		Testnet: path.Base(dbPath) == "testnet.db",
+	}
+	addFile := func(fpath, name string) error {
+		f, err := os.Open(fpath)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		info, err := f.Stat()
+		if err != nil {
+			return err
+		}
+		hdr, err := tar.FileInfoHeader(info, "")
+		if err != nil {
+			return err
+		}
+		hdr.Name = name
+		if err := tw.WriteHeader(hdr); err != nil {
+			return err
+		}
+		h := sha256.New()
+		n, err := io.Copy(io.MultiWriter(tw, h), f)
+		if err != nil {
+			return err
+		}
+		manifest.Files = append(manifest.Files, BackupFile{Path: name, Size: n, SHA256: hex.EncodeToString(h.Sum(nil))})
+		return nil
+	}
+	addDir := func(dir string) error {
+		root := path.Join(repoPath, dir)
+		if _, err := os.Stat(root); os.IsNotExist(err) {
+			return nil
+		}
+		return filepath.Walk(root, func(fpath string, info os.FileInfo, err error) error {
+			if err != nil || !info.Mode().IsRegular() {
+				return err
+			}
+			rel, err := filepath.Rel(repoPath, fpath)
+			if err != nil {
+				return err
+			}
+			return addFile(fpath, filepath.ToSlash(rel))
+		})
+	}
+	if err := addFile(path.Join(repoPath, "config"), "config"); err != nil {
+		return err
+	}
+	if err := addDir("keystore"); err != nil {
+		return err
+	}
+	if err := addDir("root"); err != nil {
+		return err
+	}
+	if err := addDir("digital"); err != nil {
+		return err
+	}
+	if err := addFile(dbPath, path.Join("datastore", path.Base(dbPath))); err != nil {
+		return err
+	}
+	ser, err := json.MarshalIndent(manifest, "", "    ")
+	if err != nil {
+		return err
+	}
+	hdr := &tar.Header{
+		Name:    backupManifestName,
+		Mode:    0600,
+		Size:    int64(len(ser)),
+		ModTime: manifest.Created,
+	}
+	if err := tw.WriteHeader(hdr); err != nil {
+		return err
+	}
+	if _, err := tw.Write(ser); err != nil {
+		return err
+	}
+	if err := tw.Close(); err != nil {
+		return err
+	}
+	if err := gz.Close(); err != nil {
+		return err
+	}
+	return ew.Close()
+}
+
+// Decrypt and unpack a backup into dir. Every file is checked against the checksums in the manifest.
+func ReadBackup(r io.Reader, dir, password string) (*BackupManifest, error) {
+	er, err := newBackupReader(r, password)
+	if err != nil {
+		return nil, err
+	}
+	gz, err := gzip.NewReader(er)
+	if err != nil {
+		return nil, err
+	}
+	tr := tar.NewReader(gz)
+	sums := make(map[string]BackupFile)
+	var manifest *BackupManifest
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			return nil, err
+		}
+		if hdr.Name == backupManifestName {
+			manifest = new(BackupManifest)
+			if err := json.NewDecoder(tr).Decode(manifest); err != nil {
+				return nil, err
+			}
+			continue
+		}
+		name := path.Clean(hdr.Name)
+		if path.IsAbs(name) || name == ".." || strings.HasPrefix(name, "../") {
+			return nil, fmt.Errorf("Invalid path in backup: %s", hdr.Name)
+		}
+		fpath := filepath.Join(dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(fpath), os.ModePerm); err != nil {
+			return nil, err
+		}
+		f, err := os.OpenFile(fpath, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0600)
+		if err != nil {
+			return nil, err
+		}
+		h := sha256.New()
+		n, err := io.Copy(io.MultiWriter(f, h), tr)
+		f.Close()
+		if err != nil {
+			return nil, err
+		}
+		sums[name] = BackupFile{Path: name, Size: n, SHA256: hex.EncodeToString(h.Sum(nil))}
+	}
+	if manifest == nil {
+		return nil, errors.New("The backup has no manifest")
+	}
+	if manifest.Version > BackupVersion {
+		return nil, fmt.Errorf("Backup version %d is newer than this version of openbazaard supports", manifest.Version)
+	}
+	if len(sums) != len(manifest.Files) {
+		return nil, errors.New("The backup does not match its manifest")
+	}
+	for _, file := range manifest.Files {
+		if sums[file.Path] != file {
+			return nil, fmt.Errorf("Checksum mismatch for %s", file.Path)
+		}
+	}
+	return manifest, nil
+}
+
+// Rebuild a repo from a backup. The IPFS repo is initialized with the identity from the backup
+// and root is re-added to IPFS and published to the local IPNS keyspace. The node republishes it
+// to the network when it next starts. If the restore fails everything it wrote is removed, and
+// repoPath too if it didn't exist, so it can be tried again.
+func RestoreBackup(r io.Reader, repoPath, password string, testnet bool) (manifest *BackupManifest, rerr error) {
+	if fsrepo.IsInitialized(repoPath) {
+		return nil, ErrRepoExists
+	}
+	existing := make(map[string]bool)
+	entries, err := ioutil.ReadDir(repoPath)
+	if os.IsNotExist(err) {
+		existing = nil
+	} else if err != nil {
+		return nil, err
+	}
+	for _, e := range entries {
+		existing[e.Name()] = true
+	}
+	defer func() {
+		if rerr == nil {
+			return
+		}
+		if existing == nil {
+			os.RemoveAll(repoPath)
+			return
+		}
+		entries, _ := ioutil.ReadDir(repoPath)
+		for _, e := range entries {
+			if !existing[e.Name()] {
+				os.RemoveAll(path.Join(repoPath, e.Name()))
+			}
+		}
+	}()
+	staging := path.Join(repoPath, "tmp", "restore")
+	os.RemoveAll(staging)
+	defer os.RemoveAll(path.Join(repoPath, "tmp"))
+	if err := checkWriteable(repoPath); err != nil {
+		return nil, err
+	}
+	manifest, err = ReadBackup(r, staging, password)
+	if err != nil {
+		return nil, err
+	}
+	if manifest.Testnet != testnet {
+		return nil, errors.New("The backup was made on a different network than the repo is being restored for")
+	}
+	conf, err := fsrepo.ConfigAt(staging)
+	if err != nil {
+		return nil, err
+	}
+	if err := fsrepo.Init(repoPath, conf); err != nil {
+		return nil, err
+	}
+	// fsrepo only writes the fields it knows about so put back the original config with our extensions
+	ser, err := ioutil.ReadFile(path.Join(staging, "config"))
+	if err != nil {
+		return nil, err
+	}
+	if err := ioutil.WriteFile(path.Join(repoPath, "config"), ser, 0600); err != nil {
+		return nil, err
+	}
+	for _, file := range manifest.Files {
+		if file.Path == "config" {
+			continue
+		}
+		dst := filepath.Join(repoPath, filepath.FromSlash(file.Path))
+		if err := os.MkdirAll(filepath.Dir(dst), os.ModePerm); err != nil {
+			return nil, err
+		}
+		if err := os.Rename(filepath.Join(staging, filepath.FromSlash(file.Path)), dst); err != nil {
+			return nil, err
+		}
+	}
+	if err := maybeCreateOBDirectories(repoPath); err != nil {
+		return nil, err
+	}
+	if err := republishRoot(repoPath); err != nil {
+		return nil, err
+	}
+	return manifest, nil
+}
+
+// Add the root directory to an offline node and publish its hash to the local IPNS keyspace
+func republishRoot(repoPath string) error {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	r, err := fsrepo.Open(repoPath)
+	if err != nil { // NB: repo is owned by the node
+		return err
+	}
+	nd, err := core.NewNode(ctx, &core.BuildCfg{Repo: r})
+	if err != nil {
+		return err
+	}
+	defer nd.Close()
+
+	if err := nd.SetupOfflineRouting(); err != nil {
+		return err
+	}
+	cctx := commands.Context{
+		Online:     true,
+		ConfigRoot: repoPath,
+		LoadConfig: func(path string) (*config.Config, error) {
+			return r.Config()
+		},
+		ConstructNode: func() (*core.IpfsNode, error) {
+			return nd, nil
+		},
+	}
+	hash, err := ipfs.AddDirectory(cctx, path.Join(repoPath, "root"))
+	if err != nil {
+		return err
+	}
+	return nd.Namesys.Publish(ctx, nd.PrivateKey, ipath.FromString("/ipfs/"+hash))
+}
+
+func deriveBackupKey(password string, salt []byte) (cipher.AEAD, error) {
+	key, err := scrypt.Key([]byte(password), salt, 32768, 8, 1, 32)
+	if err != nil {
+		return nil, err
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+func backupNonce(aead cipher.AEAD, counter uint64) []byte {
+	nonce := make([]byte, aead.NonceSize())
+	binary.BigEndian.PutUint64(nonce[len(nonce)-8:], counter)
+	return nonce
+}
+
+// Additional data for a chunk marks whether it is the final one
+func backupChunkData(last bool) []byte {
+	if last {
+		return []byte{1}
+	}
+	return []byte{0}
+}
+
+type backupWriter struct {
+	w       io.Writer
+	aead    cipher.AEAD
+	buf     []byte
+	counter uint64
+}
+
+func newBackupWriter(w io.Writer, password string) (*backupWriter, error) {
+	salt := make([]byte, 32)
+	if _, err := rand.Read(salt); err != nil {
+		return nil, err
+	}
+	aead, err := deriveBackupKey(password, salt)
+	if err != nil {
+		return nil, err
+	}
+	header := make([]byte, 0, len(backupMagic)+1+len(salt))
+	header = append(header, backupMagic...)
+	header = append(header, byte(BackupVersion))
+	header = append(header, salt...)
+	if _, err := w.Write(header); err != nil {
+		return nil, err
+	}
+	return &backupWriter{w: w, aead: aead, buf: make([]byte, 0, backupChunkSize)}, nil
+}
+
+func (b *backupWriter) Write(p []byte) (int, error) {
+	written := 0
+	for len(p) > 0 {
+		if len(b.buf) == backupChunkSize {
+			if err := b.flush(false); err != nil {
+				return written, err
+			}
+		}
+		n := backupChunkSize - len(b.buf)
+		if n > len(p) {
+			n = len(p)
+		}
+		b.buf = append(b.buf, p[:n]...)
+		p = p[n:]
+		written += n
+	}
+	return written, nil
+}
+
+func (b *backupWriter) flush(last bool) error {
+	sealed := b.aead.Seal(nil, backupNonce(b.aead, b.counter), b.buf, backupChunkData(last))
+	l := make([]byte, 4)
+	binary.BigEndian.PutUint32(l, uint32(len(sealed)))
+	if _, err := b.w.Write(l); err != nil {
+		return err
+	}
+	if _, err := b.w.Write(sealed); err != nil {
+		return err
+	}
+	b.counter++
+	b.buf = b.buf[:0]
+	return nil
+}
+
+// Seal the final chunk. It does not close the underlying writer.
+func (b *backupWriter) Close() error {
+	return b.flush(true)
+}
+
+type backupReader struct {
+	r       io.Reader
+	aead    cipher.AEAD
+	buf     []byte
+	counter uint64
+	done    bool
+}
+
+func newBackupReader(r io.Reader, password string) (*backupReader, error) {
+	header := make([]byte, len(backupMagic)+1+32)
+	if _, err := io.ReadFull(r, header); err != nil {
+		return nil, ErrInvalidBackup
+	}
+	if string(header[:len(backupMagic)]) != string(backupMagic) {
+		return nil, ErrInvalidBackup
+	}
+	if int(header[len(backupMagic)]) > BackupVersion {
+		return nil, fmt.Errorf("Backup version %d is newer than this version of openbazaard supports", header[len(backupMagic)])
+	}
+	aead, err := deriveBackupKey(password, header[len(backupMagic)+1:])
+	if err != nil {
+		return nil, err
+	}
+	return &backupReader{r: r, aead: aead}, nil
+}
+
+func (b *backupReader) Read(p []byte) (int, error) {
+	for len(b.buf) == 0 {
+		if b.done {
+			return 0, io.EOF
+		}
+		if err := b.next(); err != nil {
+			return 0, err
+		}
+	}
+	n := copy(p, b.buf)
+	b.buf = b.buf[n:]
+	return n, nil
+}
+
+func (b *backupReader) next() error {
+	l := make([]byte, 4)
+	if _, err := io.ReadFull(b.r, l); err != nil {
+		return ErrTruncatedBackup
+	}
+	size := binary.BigEndian.Uint32(l)
+	if size > backupChunkSize+uint32(b.aead.Overhead()) {
+		return ErrInvalidBackupPassword
+	}
+	sealed := make([]byte, size)
+	if _, err := io.ReadFull(b.r, sealed); err != nil {
+		return ErrTruncatedBackup
+	}
+	nonce := backupNonce(b.aead, b.counter)
+	plaintext, err := b.aead.Open(nil, nonce, sealed, backupChunkData(false))
+	if err != nil {
+		plaintext, err = b.aead.Open(nil, nonce, sealed, backupChunkData(true))
+		if err != nil {
+			return ErrInvalidBackupPassword
+		}
+		b.done = true
+	}
+	b.counter++
+	b.buf = plaintext
+	return nil
+}
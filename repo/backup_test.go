@@ -0,0 +1,149 @@
+package repo
+
+import (
+	"bytes"
+	"crypto/rand"
+	"io/ioutil"
+	"os"
+	"path"
+	"testing"
+)
+
+func createBackupTestRepo(t *testing.T) (string, []byte) {
+	repoPath, err := ioutil.TempDir("", "backup")
+	if err != nil {
+		t.Fatal(err)
+	}
+	config, err := ioutil.ReadFile("testdata/config")
+	if err != nil {
+		t.Fatal(err)
+	}
+	ioutil.WriteFile(path.Join(repoPath, "config"), config, 0600)
+	os.MkdirAll(path.Join(repoPath, "root", "listings"), os.ModePerm)
+	ioutil.WriteFile(path.Join(repoPath, "root", "profile"), []byte(`{"name": "Seller"}`), 0644)
+	// Larger than a chunk so the archive spans several of them
+	listing := make([]byte, backupChunkSize*3)
+	rand.Read(listing)
+	ioutil.WriteFile(path.Join(repoPath, "root", "listings", "slug.json"), listing, 0644)
+	os.MkdirAll(path.Join(repoPath, "digital"), os.ModePerm)
+	ioutil.WriteFile(path.Join(repoPath, "digital", "ebook"), []byte("digital good"), 0600)
+	os.MkdirAll(path.Join(repoPath, "datastore"), os.ModePerm)
+	ioutil.WriteFile(path.Join(repoPath, "datastore", "testnet.db"), []byte("database"), 0600)
+	return repoPath, listing
+}
+
+func TestBackupRoundTrip(t *testing.T) {
+	repoPath, listing := createBackupTestRepo(t)
+	defer os.RemoveAll(repoPath)
+	var buf bytes.Buffer
+	err := WriteBackup(&buf, repoPath, path.Join(repoPath, "datastore", "testnet.db"), "password")
+	if err != nil {
+		t.Fatal(err)
+	}
+	dir, err := ioutil.TempDir("", "restore")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	manifest, err := ReadBackup(&buf, dir, "password")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !manifest.Testnet {
+		t.Error("Backup of a testnet repo not marked as testnet")
+	}
+	if len(manifest.Files) != 5 {
+		t.Errorf("Expected 5 files in the manifest got %d", len(manifest.Files))
+	}
+	restored, err := ioutil.ReadFile(path.Join(dir, "root", "listings", "slug.json"))
+	if err != nil {
+		t.Error(err)
+	}
+	if !bytes.Equal(restored, listing) {
+		t.Error("Restored listing does not match")
+	}
+	digital, err := ioutil.ReadFile(path.Join(dir, "digital", "ebook"))
+	if err != nil || string(digital) != "digital good" {
+		t.Error("Restored digital good does not match")
+	}
+	db, err := ioutil.ReadFile(path.Join(dir, "datastore", "testnet.db"))
+	if err != nil || string(db) != "database" {
+		t.Error("Restored database does not match")
+	}
+}
+
+func TestBackupWrongPassword(t *testing.T) {
+	repoPath, _ := createBackupTestRepo(t)
+	defer os.RemoveAll(repoPath)
+	var buf bytes.Buffer
+	WriteBackup(&buf, repoPath, path.Join(repoPath, "datastore", "testnet.db"), "password")
+	dir, _ := ioutil.TempDir("", "restore")
+	defer os.RemoveAll(dir)
+	_, err := ReadBackup(&buf, dir, "wrong password")
+	if err != ErrInvalidBackupPassword {
+		t.Errorf("Expected an invalid password error got %v", err)
+	}
+}
+
+func TestBackupTruncated(t *testing.T) {
+	repoPath, _ := createBackupTestRepo(t)
+	defer os.RemoveAll(repoPath)
+	var buf bytes.Buffer
+	WriteBackup(&buf, repoPath, path.Join(repoPath, "datastore", "testnet.db"), "password")
+	truncated := buf.Bytes()[:buf.Len()-backupChunkSize/2]
+	dir, _ := ioutil.TempDir("", "restore")
+	defer os.RemoveAll(dir)
+	if _, err := ReadBackup(bytes.NewReader(truncated), dir, "password"); err == nil {
+		t.Error("Expected an error reading a truncated backup")
+	}
+}
+
+func TestBackupInvalidFile(t *testing.T) {
+	dir, _ := ioutil.TempDir("", "restore")
+	defer os.RemoveAll(dir)
+	_, err := ReadBackup(bytes.NewReader([]byte("not a backup")), dir, "password")
+	if err != ErrInvalidBackup {
+		t.Errorf("Expected an invalid backup error got %v", err)
+	}
+}
+
+func TestRestoreBackupCleansUp(t *testing.T) {
+	repoPath, _ := createBackupTestRepo(t)
+	defer os.RemoveAll(repoPath)
+	var buf bytes.Buffer
+	if err := WriteBackup(&buf, repoPath, path.Join(repoPath, "datastore", "testnet.db"), "password"); err != nil {
+		t.Fatal(err)
+	}
+	backup := buf.Bytes()
+	dir, err := ioutil.TempDir("", "restore")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	// A repo directory created by the restore is removed
+	newRepo := path.Join(dir, "new")
+	if _, err := RestoreBackup(bytes.NewReader(backup), newRepo, "password", false); err == nil {
+		t.Fatal("Expected an error restoring a testnet backup to mainnet")
+	}
+	if _, err := os.Stat(newRepo); !os.IsNotExist(err) {
+		t.Error("Restore left the repo directory behind")
+	}
+
+	// Only what the restore wrote is removed from an existing directory
+	ioutil.WriteFile(path.Join(dir, "keep"), []byte("keep"), 0600)
+	if _, err := RestoreBackup(bytes.NewReader(backup), dir, "password", false); err == nil {
+		t.Fatal("Expected an error restoring a testnet backup to mainnet")
+	}
+	entries, err := ioutil.ReadDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 1 || entries[0].Name() != "keep" {
+		var names []string
+		for _, e := range entries {
+			names = append(names, e.Name())
+		}
+		t.Errorf("Restore left %v behind", names)
+	}
+}
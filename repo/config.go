@@ -2,10 +2,12 @@ package repo
 
 import (
 	"encoding/json"
+	"errors"
 	"github.com/ipfs/go-ipfs/repo"
 	"github.com/ipfs/go-ipfs/repo/config"
 	"io/ioutil"
 	"path"
+	"time"
 )
 
 var DefaultBootstrapAddresses = []string{
@@ -14,6 +16,18 @@ var DefaultBootstrapAddresses = []string{
 	"/ip4/139.59.6.222/tcp/4001/ipfs/QmZAZYJ5MvqkdoTuaFaoeyHkHLd8muENfr9JTo7ikQZPSG",   // Johari
 }
 
+var defaultRetrieverConfig = RetrieverConfig{
+	Interval:       time.Hour,
+	ActiveInterval: 5 * time.Minute,
+}
+
+var defaultS3Config = S3Config{
+	Endpoint: "https://s3.amazonaws.com",
+	Region:   "us-east-1",
+	Prefix:   "openbazaar/",
+	ACL:      "public-read",
+}
+
 type APIConfig struct {
 	Authenticated bool
 	Username      string
@@ -24,6 +38,8 @@ type APIConfig struct {
 	SSL           bool
 	SSLCert       string
 	SSLKey        string
+	Metrics       bool
+	GRPCAddress   string // Multiaddr for the gRPC API. Empty to disable it.
 }
 
 type WalletConfig struct {
@@ -39,6 +55,30 @@ type WalletConfig struct {
 	RPCPassword      string
 }
 
+type S3Config struct {
+	Endpoint        string // For example https://s3.amazonaws.com or the URL of a MinIO server
+	Region          string
+	Bucket          string
+	Prefix          string // Prepended to the name of each object
+	AccessKeyID     string
+	SecretAccessKey string
+	ACL             string // The canned ACL for each object. Empty if a bucket policy makes them public.
+}
+
+type RetrieverConfig struct {
+	// How often to check for offline messages when no orders are waiting on a reply
+	Interval time.Duration
+
+	// How often to check while there are open orders or the last check found messages
+	ActiveInterval time.Duration
+}
+
+type WebhookConfig struct {
+	URL    string
+	Secret string
+	Events []string // Empty for every event
+}
+
 func GetAPIConfig(cfgPath string) (*APIConfig, error) {
 	file, err := ioutil.ReadFile(cfgPath)
 	if err != nil {
@@ -70,6 +110,8 @@ func GetAPIConfig(cfgPath string) (*APIConfig, error) {
 	sslEnabled := api.(map[string]interface{})["SSL"].(bool)
 	certFile := api.(map[string]interface{})["SSLCert"].(string)
 	keyFile := api.(map[string]interface{})["SSLKey"].(string)
+	metrics, _ := api.(map[string]interface{})["Metrics"].(bool)
+	grpcAddress, _ := api.(map[string]interface{})["GRPCAddress"].(string)
 
 	apiConfig := &APIConfig{
 		Authenticated: authenticated,
@@ -81,6 +123,8 @@ func GetAPIConfig(cfgPath string) (*APIConfig, error) {
 		SSL:           sslEnabled,
 		SSLCert:       certFile,
 		SSLKey:        keyFile,
+		Metrics:       metrics,
+		GRPCAddress:   grpcAddress,
 	}
 
 	return apiConfig, nil
@@ -164,6 +208,82 @@ func GetResolverUrl(cfgPath string) (string, error) {
 	return r, nil
 }
 
+func GetGatewayAddress(cfgPath string) (string, error) {
+	file, err := ioutil.ReadFile(cfgPath)
+	if err != nil {
+		return "", err
+	}
+	var cfg interface{}
+	json.Unmarshal(file, &cfg)
+
+	addresses := cfg.(map[string]interface{})["Addresses"]
+	gw := addresses.(map[string]interface{})["Gateway"].(string)
+
+	return gw, nil
+}
+
+func GetWebhooks(cfgPath string) ([]WebhookConfig, error) {
+	file, err := ioutil.ReadFile(cfgPath)
+	if err != nil {
+		return nil, err
+	}
+	var cfg struct {
+		Webhooks []WebhookConfig
+	}
+	if err := json.Unmarshal(file, &cfg); err != nil {
+		return nil, err
+	}
+	return cfg.Webhooks, nil
+}
+
+// Return the message retriever config. The intervals are durations such as "1h" or "5m"
+// and default to an hour and five minutes if they're missing from the config file.
+func GetRetrieverConfig(cfgPath string) (*RetrieverConfig, error) {
+	file, err := ioutil.ReadFile(cfgPath)
+	if err != nil {
+		return nil, err
+	}
+	cfg := struct {
+		MessageRetriever struct {
+			Interval       string
+			ActiveInterval string
+		}
+	}{}
+	cfg.MessageRetriever.Interval = defaultRetrieverConfig.Interval.String()
+	cfg.MessageRetriever.ActiveInterval = defaultRetrieverConfig.ActiveInterval.String()
+	if err := json.Unmarshal(file, &cfg); err != nil {
+		return nil, err
+	}
+	interval, err := time.ParseDuration(cfg.MessageRetriever.Interval)
+	if err != nil {
+		return nil, err
+	}
+	activeInterval, err := time.ParseDuration(cfg.MessageRetriever.ActiveInterval)
+	if err != nil {
+		return nil, err
+	}
+	if interval <= 0 || activeInterval <= 0 {
+		return nil, errors.New("Message retriever intervals must be positive")
+	}
+	return &RetrieverConfig{interval, activeInterval}, nil
+}
+
+// Return the S3 storage config. Settings which are missing from the config file are
+// left at their defaults.
+func GetS3Config(cfgPath string) (*S3Config, error) {
+	file, err := ioutil.ReadFile(cfgPath)
+	if err != nil {
+		return nil, err
+	}
+	cfg := struct {
+		S3 S3Config
+	}{defaultS3Config}
+	if err := json.Unmarshal(file, &cfg); err != nil {
+		return nil, err
+	}
+	return &cfg.S3, nil
+}
+
 func extendConfigFile(r repo.Repo, key string, value interface{}) error {
 	if err := r.SetConfigKey(key, value); err != nil {
 		return err
@@ -3,6 +3,7 @@ package repo
 import (
 	"reflect"
 	"testing"
+	"time"
 
 	"github.com/ipfs/go-ipfs/repo/fsrepo"
 	"os"
@@ -42,6 +43,12 @@ func TestGetApiConfig(t *testing.T) {
 	if config.SSLKey == "" {
 		t.Error("Expected test SSL key, got ", config.SSLKey)
 	}
+	if !config.Metrics {
+		t.Error("Expected Metrics = true")
+	}
+	if config.GRPCAddress != "/ip4/127.0.0.1/tcp/4003" {
+		t.Error("Expected test gRPC address, got ", config.GRPCAddress)
+	}
 	if err != nil {
 		t.Error("GetAPIAuthentication threw an unexpected error")
 	}
@@ -130,6 +137,102 @@ func TestGetResolverUrl(t *testing.T) {
 	}
 }
 
+func TestGetGatewayAddress(t *testing.T) {
+	addr, err := GetGatewayAddress(testConfigPath)
+	if addr != "/ip4/127.0.0.1/tcp/8080" {
+		t.Error("Gateway address does not equal expected value")
+	}
+	if err != nil {
+		t.Error("GetGatewayAddress threw an unexpected error")
+	}
+
+	addr, err = GetGatewayAddress(nonexistentTestConfigPath)
+	if addr != "" {
+		t.Error("Expected empty string, got ", addr)
+	}
+	if err == nil {
+		t.Error("GetGatewayAddress didn't throw an error")
+	}
+}
+
+func TestGetWebhooks(t *testing.T) {
+	hooks, err := GetWebhooks(testConfigPath)
+	if err != nil {
+		t.Error("GetWebhooks threw an unexpected error", err)
+	}
+	if len(hooks) != 1 {
+		t.Fatalf("Expected 1 webhook, got %d", len(hooks))
+	}
+	if hooks[0].URL != "https://erp.example.com/openbazaar" {
+		t.Error("Webhook URL does not equal expected value")
+	}
+	if hooks[0].Secret != "webhooksecret" {
+		t.Error("Webhook secret does not equal expected value")
+	}
+	if len(hooks[0].Events) != 2 || hooks[0].Events[0] != "order" || hooks[0].Events[1] != "payment" {
+		t.Error("Webhook events do not equal expected value")
+	}
+
+	hooks, err = GetWebhooks(nonexistentTestConfigPath)
+	if hooks != nil {
+		t.Error("Expected no webhooks, got ", hooks)
+	}
+	if err == nil {
+		t.Error("GetWebhooks didn't throw an error")
+	}
+}
+
+func TestGetRetrieverConfig(t *testing.T) {
+	config, err := GetRetrieverConfig(testConfigPath)
+	if err != nil {
+		t.Error("GetRetrieverConfig threw an unexpected error", err)
+	}
+	if config.Interval != 30*time.Minute {
+		t.Error("Retriever interval does not equal expected value")
+	}
+	if config.ActiveInterval != 2*time.Minute {
+		t.Error("Retriever active interval does not equal expected value")
+	}
+
+	config, err = GetRetrieverConfig(nonexistentTestConfigPath)
+	if config != nil {
+		t.Error("Expected no retriever config, got ", config)
+	}
+	if err == nil {
+		t.Error("GetRetrieverConfig didn't throw an error")
+	}
+}
+
+func TestGetS3Config(t *testing.T) {
+	config, err := GetS3Config(testConfigPath)
+	if err != nil {
+		t.Error("GetS3Config threw an unexpected error", err)
+	}
+	if config.Endpoint != "http://127.0.0.1:9000" {
+		t.Error("S3 endpoint does not equal expected value")
+	}
+	if config.Bucket != "obmessages" {
+		t.Error("S3 bucket does not equal expected value")
+	}
+	if config.Prefix != "outbox/" {
+		t.Error("S3 prefix does not equal expected value")
+	}
+	if config.AccessKeyID != "s3accesskey" || config.SecretAccessKey != "s3secret" {
+		t.Error("S3 credentials do not equal expected value")
+	}
+	if config.Region != "us-east-1" || config.ACL != "public-read" {
+		t.Error("Missing S3 settings were not set to their defaults")
+	}
+
+	config, err = GetS3Config(nonexistentTestConfigPath)
+	if config != nil {
+		t.Error("Expected no S3 config, got ", config)
+	}
+	if err == nil {
+		t.Error("GetS3Config didn't throw an error")
+	}
+}
+
 func TestExtendConfigFile(t *testing.T) {
 	r, err := fsrepo.Open(testConfigFolder)
 	if err != nil {
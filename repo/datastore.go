@@ -1,6 +1,10 @@
 package repo
 
 import (
+	"encoding/json"
+	"errors"
+	"time"
+
 	"gx/ipfs/QmRBqJF7hb8ZSpRcMwUt8hNhydWcxGEhtk81HKq6oUwKvs/go-libp2p-peer"
 
 	"github.com/OpenBazaar/openbazaar-go/ipfs"
@@ -9,6 +13,24 @@ import (
 	btc "github.com/btcsuite/btcutil"
 )
 
+var (
+	ErrAlreadyEncrypted     = errors.New("The database is already encrypted")
+	ErrNotEncrypted         = errors.New("The database is not encrypted")
+	ErrInvalidPassword      = errors.New("Invalid password")
+	ErrTokenNotFound        = errors.New("API token not found")
+	ErrPointerNotFound      = errors.New("Pointer not found")
+	ErrSessionNotFound      = errors.New("Session not found")
+	ErrPrekeyNotFound       = errors.New("Prekey not found")
+	ErrNotEnoughLicenseKeys = errors.New("Not enough unused license keys left for listing")
+)
+
+// States of a webhook delivery
+const (
+	DeliveryPending   = "pending"
+	DeliveryDelivered = "delivered"
+	DeliveryFailed    = "failed"
+)
+
 type Datastore interface {
 	Config() Config
 	Followers() Followers
@@ -19,7 +41,30 @@ type Datastore interface {
 	Inventory() Inventory
 	Purchases() Purchases
 	Sales() Sales
+	LicenseKeys() LicenseKeys
+	APITokens() APITokens
+	WebhookDeliveries() WebhookDeliveries
+	WebsocketEvents() WebsocketEvents
+	Crossposts() Crossposts
+	Sessions() Sessions
+	Prekeys() Prekeys
+	OrderChaincodes() OrderChaincodes
 	Close()
+
+	// Encrypt a plaintext database with the given password
+	Encrypt(password string) error
+
+	// Decrypt the database using its current password
+	Decrypt(password string) error
+
+	// Change the password of an encrypted database
+	ChangePassword(oldPassword, newPassword string) error
+
+	// Write a consistent copy of the database into dir and return its path
+	Backup(dir string) (string, error)
+
+	// Return an error if the database can't be written to
+	CheckWritable() error
 }
 
 type Config interface {
@@ -85,6 +130,9 @@ type Pointers interface {
 	// Put a pointer to the database
 	Put(p ipfs.Pointer) error
 
+	// Fetch a pointer by its ID
+	Get(id peer.ID) (ipfs.Pointer, error)
+
 	// Delete a pointer from the database
 	Delete(id peer.ID) error
 
@@ -151,6 +199,9 @@ type Purchases interface {
 
 	// Return the IDs for all orders
 	GetAll() ([]string, error)
+
+	// Return the number of orders in any of the given states
+	Count(states ...pb.OrderState) (int, error)
 }
 
 type Sales interface {
@@ -174,4 +225,148 @@ type Sales interface {
 
 	// Return the IDs for all orders
 	GetAll() ([]string, error)
+
+	// Return the number of orders in any of the given states
+	Count(states ...pb.OrderState) (int, error)
+}
+
+type LicenseKeys interface {
+	// Add license keys to the pool for a listing
+	Put(slug string, keys []string) error
+
+	/* Assign quantity unused keys for the listing to the order and return them.
+	   Keys already assigned to the order are returned again so each key is only
+	   ever handed out once. If the pool can't cover the quantity nothing is
+	   assigned and ErrNotEnoughLicenseKeys is returned. */
+	Assign(slug string, orderID string, quantity int) ([]string, error)
+
+	// Return the number of unused keys for a listing
+	Count(slug string) (int, error)
+
+	// Delete all unused keys for a listing
+	DeleteUnused(slug string) error
+}
+
+type APIToken struct {
+	Name    string    `json:"name"`
+	Scopes  []string  `json:"scopes"`
+	Created time.Time `json:"created"`
+}
+
+type APITokens interface {
+	/* Save a named API token. Only the hash of the token is stored
+	   so the token itself can't be recovered from the database. */
+	Put(name string, hash []byte, scopes []string) error
+
+	// Return the token with the given hash
+	GetByHash(hash []byte) (APIToken, error)
+
+	// Return all tokens
+	GetAll() ([]APIToken, error)
+
+	// Delete a token by name
+	Delete(name string) error
+}
+
+type WebhookDelivery struct {
+	Id          string          `json:"id"` // Sent as the idempotency key
+	URL         string          `json:"url"`
+	Event       string          `json:"event"`
+	Payload     json.RawMessage `json:"payload"`
+	Status      string          `json:"status"`
+	Attempts    int             `json:"attempts"`
+	LastError   string          `json:"lastError"`
+	Created     time.Time       `json:"created"`
+	NextAttempt time.Time       `json:"nextAttempt"`
+}
+
+type WebhookDeliveries interface {
+	// Save a delivery, replacing any existing delivery with the same ID
+	Put(delivery WebhookDelivery) error
+
+	// Return the pending deliveries which are due to be attempted at the given time
+	GetDue(now time.Time) ([]WebhookDelivery, error)
+
+	/* Return the most recent deliveries, newest first. If status is
+	   not empty only deliveries in that state are returned. */
+	GetAll(status string, limit int) ([]WebhookDelivery, error)
+}
+
+type WebsocketEvent struct {
+	Seq      uint64
+	Topic    string
+	Envelope []byte
+}
+
+type WebsocketEvents interface {
+	// Save a websocket message so it can be replayed to clients which reconnect
+	Put(event WebsocketEvent) error
+
+	// Return the saved messages with a sequence number greater than seq in order
+	GetSince(seq uint64) ([]WebsocketEvent, error)
+
+	// Return the highest sequence number saved or zero if there are none
+	LastSeq() (uint64, error)
+
+	// Delete all but the most recent keep messages
+	Prune(keep int) error
+}
+
+type Crossposts interface {
+	/* Record the hash a gateway has acknowledged for a path in the root directory.
+	   The empty path holds the root hash of the gateway's copy. */
+	Put(gateway, path, hash string) error
+
+	// Return the hashes a gateway has acknowledged by path
+	Get(gateway string) (map[string]string, error)
+
+	// Delete a path and everything beneath it
+	Delete(gateway, path string) error
+
+	// Delete everything a gateway has acknowledged
+	Clear(gateway string) error
+
+	// Record whether a gateway can only be written to with PUTs because it can't delete paths
+	SetPutOnly(gateway string, putOnly bool) error
+
+	// Return whether a gateway has been recorded as PUT only
+	PutOnly(gateway string) (bool, error)
+}
+
+type Sessions interface {
+	// Save the serialized state of an encrypted session with a peer
+	Put(id, peerID string, state []byte) error
+
+	// Return the peer and state of a session. Returns ErrSessionNotFound if there is none.
+	Get(id string) (peerID string, state []byte, err error)
+
+	// Return the session with a peer which was saved most recently. Returns
+	// ErrSessionNotFound if there is none.
+	GetByPeer(peerID string) (id string, state []byte, err error)
+}
+
+type Prekeys interface {
+	// Save the private key of a signed prekey
+	Put(id uint32, private []byte, created time.Time) error
+
+	// Return the private key of a prekey. Returns ErrPrekeyNotFound if there is none.
+	Get(id uint32) ([]byte, error)
+
+	// Return the prekey which was created most recently. Returns ErrPrekeyNotFound if there is none.
+	GetLatest() (id uint32, private []byte, created time.Time, err error)
+
+	/* Delete the prekeys which had been replaced by a newer one before the given time.
+	   The prekey in use at that time is kept since messages to it may still arrive. */
+	DeleteSuperseded(before time.Time) error
+}
+
+type OrderChaincodes interface {
+	// Allocate the next unused order chaincode index
+	Allocate() (uint32, error)
+
+	// Record an index found in use so neither it nor any index below it is allocated
+	Reserve(index uint32) error
+
+	// Return the index the next allocation will return
+	Next() (uint32, error)
 }
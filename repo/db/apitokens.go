@@ -0,0 +1,92 @@
+package db
+
+import (
+	"database/sql"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/OpenBazaar/openbazaar-go/repo"
+)
+
+type APITokensDB struct {
+	db   *sharedDB
+	lock *sync.Mutex
+}
+
+func (a *APITokensDB) Put(name string, hash []byte, scopes []string) error {
+	a.lock.Lock()
+	defer a.lock.Unlock()
+	tx, err := a.db.Begin()
+	if err != nil {
+		return err
+	}
+	stmt, err := tx.Prepare("insert into apitokens(name, hash, scopes, created) values(?,?,?,?)")
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+	defer stmt.Close()
+	_, err = stmt.Exec(name, hash, strings.Join(scopes, ","), time.Now().Unix())
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+	tx.Commit()
+	return nil
+}
+
+func (a *APITokensDB) GetByHash(hash []byte) (repo.APIToken, error) {
+	a.lock.Lock()
+	defer a.lock.Unlock()
+	var name, scopes string
+	var created int64
+	err := a.db.QueryRow("select name, scopes, created from apitokens where hash=?", hash).Scan(&name, &scopes, &created)
+	if err == sql.ErrNoRows {
+		return repo.APIToken{}, repo.ErrTokenNotFound
+	} else if err != nil {
+		return repo.APIToken{}, err
+	}
+	return newAPIToken(name, scopes, created), nil
+}
+
+func (a *APITokensDB) GetAll() ([]repo.APIToken, error) {
+	a.lock.Lock()
+	defer a.lock.Unlock()
+	rows, err := a.db.Query("select name, scopes, created from apitokens order by created")
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var ret []repo.APIToken
+	for rows.Next() {
+		var name, scopes string
+		var created int64
+		if err := rows.Scan(&name, &scopes, &created); err != nil {
+			return nil, err
+		}
+		ret = append(ret, newAPIToken(name, scopes, created))
+	}
+	return ret, nil
+}
+
+func (a *APITokensDB) Delete(name string) error {
+	a.lock.Lock()
+	defer a.lock.Unlock()
+	res, err := a.db.Exec("delete from apitokens where name=?", name)
+	if err != nil {
+		return err
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return repo.ErrTokenNotFound
+	}
+	return nil
+}
+
+func newAPIToken(name, scopes string, created int64) repo.APIToken {
+	token := repo.APIToken{Name: name, Created: time.Unix(created, 0)}
+	if scopes != "" {
+		token.Scopes = strings.Split(scopes, ",")
+	}
+	return token
+}
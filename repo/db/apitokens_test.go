@@ -0,0 +1,82 @@
+package db
+
+import (
+	"database/sql"
+	"sync"
+	"testing"
+
+	"github.com/OpenBazaar/openbazaar-go/repo"
+)
+
+var atdb APITokensDB
+
+func init() {
+	conn, _ := sql.Open("sqlite3", ":memory:")
+	initDatabaseTables(conn, "")
+	atdb = APITokensDB{
+		db:   &sharedDB{conn},
+		lock: new(sync.Mutex),
+	}
+}
+
+func TestPutAPIToken(t *testing.T) {
+	err := atdb.Put("fulfillment", []byte("hash1"), []string{"read", "orders"})
+	if err != nil {
+		t.Error(err)
+	}
+	token, err := atdb.GetByHash([]byte("hash1"))
+	if err != nil {
+		t.Error(err)
+	}
+	if token.Name != "fulfillment" {
+		t.Errorf("Expected fulfillment got %s", token.Name)
+	}
+	if len(token.Scopes) != 2 || token.Scopes[0] != "read" || token.Scopes[1] != "orders" {
+		t.Errorf("Returned incorrect scopes %v", token.Scopes)
+	}
+}
+
+func TestPutDuplicateAPIToken(t *testing.T) {
+	if err := atdb.Put("dup", []byte("hash2"), []string{"read"}); err != nil {
+		t.Error(err)
+	}
+	if err := atdb.Put("dup", []byte("hash3"), []string{"read"}); err == nil {
+		t.Error("Expected an error adding a token with a duplicate name")
+	}
+}
+
+func TestGetAPITokenNotFound(t *testing.T) {
+	if _, err := atdb.GetByHash([]byte("missing")); err != repo.ErrTokenNotFound {
+		t.Errorf("Expected a not found error got %v", err)
+	}
+}
+
+func TestGetAllAPITokens(t *testing.T) {
+	atdb.Put("all", []byte("hash4"), []string{"admin"})
+	tokens, err := atdb.GetAll()
+	if err != nil {
+		t.Error(err)
+	}
+	found := false
+	for _, token := range tokens {
+		if token.Name == "all" {
+			found = true
+		}
+	}
+	if !found {
+		t.Error("Token missing from GetAll")
+	}
+}
+
+func TestDeleteAPIToken(t *testing.T) {
+	atdb.Put("delete", []byte("hash5"), []string{"read"})
+	if err := atdb.Delete("delete"); err != nil {
+		t.Error(err)
+	}
+	if _, err := atdb.GetByHash([]byte("hash5")); err != repo.ErrTokenNotFound {
+		t.Error("Token was not deleted")
+	}
+	if err := atdb.Delete("delete"); err != repo.ErrTokenNotFound {
+		t.Error("Expected a not found error deleting a missing token")
+	}
+}
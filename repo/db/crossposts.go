@@ -0,0 +1,92 @@
+package db
+
+import (
+	"database/sql"
+	"sync"
+)
+
+type CrosspostsDB struct {
+	db   *sharedDB
+	lock *sync.Mutex
+}
+
+func (c *CrosspostsDB) Put(gateway, path, hash string) error {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	tx, err := c.db.Begin()
+	if err != nil {
+		return err
+	}
+	stmt, err := tx.Prepare("insert or replace into crossposts(gateway, path, hash) values(?,?,?)")
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+	defer stmt.Close()
+	_, err = stmt.Exec(gateway, path, hash)
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+	tx.Commit()
+	return nil
+}
+
+func (c *CrosspostsDB) Get(gateway string) (map[string]string, error) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	rows, err := c.db.Query("select path, hash from crossposts where gateway=?", gateway)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	ret := make(map[string]string)
+	for rows.Next() {
+		var path, hash string
+		if err := rows.Scan(&path, &hash); err != nil {
+			return nil, err
+		}
+		ret[path] = hash
+	}
+	return ret, nil
+}
+
+func (c *CrosspostsDB) Delete(gateway, path string) error {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	// Compare the prefix with substr rather than like so underscores in paths aren't wildcards
+	prefix := path + "/"
+	_, err := c.db.Exec("delete from crossposts where gateway=? and (path=? or substr(path, 1, ?)=?)", gateway, path, len(prefix), prefix)
+	return err
+}
+
+func (c *CrosspostsDB) Clear(gateway string) error {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	_, err := c.db.Exec("delete from crossposts where gateway=?", gateway)
+	return err
+}
+
+func (c *CrosspostsDB) SetPutOnly(gateway string, putOnly bool) error {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	p := 0
+	if putOnly {
+		p = 1
+	}
+	_, err := c.db.Exec("insert or replace into crosspostgateways(gateway, putOnly) values(?,?)", gateway, p)
+	return err
+}
+
+func (c *CrosspostsDB) PutOnly(gateway string) (bool, error) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	var putOnly int
+	err := c.db.QueryRow("select putOnly from crosspostgateways where gateway=?", gateway).Scan(&putOnly)
+	if err == sql.ErrNoRows {
+		return false, nil
+	} else if err != nil {
+		return false, err
+	}
+	return putOnly == 1, nil
+}
@@ -0,0 +1,101 @@
+package db
+
+import (
+	"database/sql"
+	"sync"
+	"testing"
+)
+
+func newCrosspostsDB() CrosspostsDB {
+	conn, _ := sql.Open("sqlite3", ":memory:")
+	initDatabaseTables(conn, "")
+	return CrosspostsDB{
+		db:   &sharedDB{conn},
+		lock: new(sync.Mutex),
+	}
+}
+
+func TestCrosspostsPut(t *testing.T) {
+	cdb := newCrosspostsDB()
+	if err := cdb.Put("http://gateway.ob1.io/", "/profile", "Qm123"); err != nil {
+		t.Error(err)
+	}
+	if err := cdb.Put("http://gateway.ob1.io/", "/profile", "Qm456"); err != nil {
+		t.Error(err)
+	}
+	cdb.Put("http://other.io/", "/profile", "Qm789")
+	paths, err := cdb.Get("http://gateway.ob1.io/")
+	if err != nil {
+		t.Error(err)
+	}
+	if len(paths) != 1 || paths["/profile"] != "Qm456" {
+		t.Errorf("Returned incorrect paths %v", paths)
+	}
+}
+
+func TestCrosspostsDelete(t *testing.T) {
+	cdb := newCrosspostsDB()
+	gw := "http://gateway.ob1.io/"
+	cdb.Put(gw, "", "QmRoot")
+	cdb.Put(gw, "/listings", "")
+	cdb.Put(gw, "/listings/a_b.json", "Qm1")
+	cdb.Put(gw, "/listings_old", "Qm2")
+	cdb.Put(gw, "/listingsXb.json", "Qm3")
+	if err := cdb.Delete(gw, "/listings"); err != nil {
+		t.Error(err)
+	}
+	paths, err := cdb.Get(gw)
+	if err != nil {
+		t.Error(err)
+	}
+	if len(paths) != 3 || paths[""] != "QmRoot" || paths["/listings_old"] != "Qm2" || paths["/listingsXb.json"] != "Qm3" {
+		t.Errorf("Deleted incorrect paths, left %v", paths)
+	}
+}
+
+func TestCrosspostsClear(t *testing.T) {
+	cdb := newCrosspostsDB()
+	cdb.Put("http://gateway.ob1.io/", "", "QmRoot")
+	cdb.Put("http://gateway.ob1.io/", "/profile", "Qm1")
+	cdb.Put("http://other.io/", "/profile", "Qm2")
+	if err := cdb.Clear("http://gateway.ob1.io/"); err != nil {
+		t.Error(err)
+	}
+	paths, _ := cdb.Get("http://gateway.ob1.io/")
+	if len(paths) != 0 {
+		t.Errorf("Gateway was not cleared, left %v", paths)
+	}
+	paths, _ = cdb.Get("http://other.io/")
+	if len(paths) != 1 {
+		t.Error("Cleared the wrong gateway")
+	}
+}
+
+func TestCrosspostsPutOnly(t *testing.T) {
+	cdb := newCrosspostsDB()
+	gw := "http://gateway.ob1.io/"
+	putOnly, err := cdb.PutOnly(gw)
+	if err != nil {
+		t.Error(err)
+	}
+	if putOnly {
+		t.Error("Unknown gateway reported as PUT only")
+	}
+	if err := cdb.SetPutOnly(gw, true); err != nil {
+		t.Error(err)
+	}
+	// Clearing the gateway's copy keeps what is known about the gateway itself
+	cdb.Put(gw, "", "QmRoot")
+	cdb.Clear(gw)
+	putOnly, err = cdb.PutOnly(gw)
+	if err != nil {
+		t.Error(err)
+	}
+	if !putOnly {
+		t.Error("Gateway not reported as PUT only")
+	}
+	cdb.SetPutOnly(gw, false)
+	if putOnly, _ := cdb.PutOnly(gw); putOnly {
+		t.Error("Gateway still reported as PUT only")
+	}
+}
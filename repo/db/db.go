@@ -2,7 +2,9 @@ package db
 
 import (
 	"database/sql"
+	"os"
 	"path"
+	"strconv"
 	"sync"
 
 	"github.com/OpenBazaar/openbazaar-go/repo"
@@ -29,8 +31,17 @@ type SQLiteDatastore struct {
 	inventory       repo.Inventory
 	purchases       repo.Purchases
 	sales           repo.Sales
-	db              *sql.DB
+	licenseKeys     repo.LicenseKeys
+	apiTokens       repo.APITokens
+	webhooks        repo.WebhookDeliveries
+	wsEvents        repo.WebsocketEvents
+	crossposts      repo.Crossposts
+	sessions        repo.Sessions
+	prekeys         repo.Prekeys
+	chaincodes      repo.OrderChaincodes
+	db              *sharedDB
 	lock            *sync.Mutex
+	path            string
 }
 
 func Create(repoPath, password string, testnet bool) (*SQLiteDatastore, error) {
@@ -40,85 +51,135 @@ func Create(repoPath, password string, testnet bool) (*SQLiteDatastore, error) {
 	} else {
 		dbPath = path.Join(repoPath, "datastore", "mainnet.db")
 	}
-	conn, err := sql.Open("sqlite3", dbPath)
+	conn, err := open(dbPath, password)
 	if err != nil {
 		return nil, err
 	}
-	if password != "" {
-		p := "pragma key = '" + password + "';"
-		conn.Exec(p)
-	}
 
-	l := new(sync.Mutex)
 	sqliteDB := &SQLiteDatastore{
-		config: &ConfigDB{
-			db:   conn,
-			lock: l,
-			path: dbPath,
-		},
-		followers: &FollowerDB{
-			db:   conn,
-			lock: l,
-		},
-		following: &FollowingDB{
-			db:   conn,
-			lock: l,
-		},
-		offlineMessages: &OfflineMessagesDB{
-			db:   conn,
-			lock: l,
-		},
-		pointers: &PointersDB{
-			db:   conn,
-			lock: l,
-		},
-		keys: &KeysDB{
-			db:   conn,
-			lock: l,
-		},
-		state: &StateDB{
-			db:   conn,
-			lock: l,
-		},
-		stxos: &StxoDB{
-			db:   conn,
-			lock: l,
-		},
-		txns: &TxnsDB{
-			db:   conn,
-			lock: l,
-		},
-		utxos: &UtxoDB{
-			db:   conn,
-			lock: l,
-		},
-		settings: &SettingsDB{
-			db:   conn,
-			lock: l,
-		},
-		inventory: &InventoryDB{
-			db:   conn,
-			lock: l,
-		},
-		purchases: &PurchasesDB{
-			db:   conn,
-			lock: l,
-		},
-		sales: &SalesDB{
-			db:   conn,
-			lock: l,
-		},
-		watchedScripts: &WatchedScriptsDB{
-			db:   conn,
-			lock: l,
-		},
-		db:   conn,
-		lock: l,
+		db:   &sharedDB{conn},
+		lock: new(sync.Mutex),
+		path: dbPath,
+	}
+	sqliteDB.initTables()
+
+	/* Bring existing databases up to the current schema. New databases get the
+	   current schema when they are initialized and encrypted databases opened
+	   with the wrong password can't be read, so both are skipped here. */
+	if isInitialized(conn) {
+		if err := migrate(conn, dbPath); err != nil {
+			return nil, err
+		}
 	}
 
 	return sqliteDB, nil
 }
 
+// The connection shared by the datastore and its tables. Encrypting or decrypting the
+// database replaces the connection underneath it. That is only done while holding the
+// datastore lock, which the tables take before every use of the connection, so the
+// tables never see a closed connection and ones handed out earlier keep working.
+type sharedDB struct {
+	*sql.DB
+}
+
+// Create each of the datastore's tables on the shared connection
+func (d *SQLiteDatastore) initTables() {
+	d.config = &ConfigDB{
+		db:   d.db,
+		lock: d.lock,
+		path: d.path,
+	}
+	d.followers = &FollowerDB{
+		db:   d.db,
+		lock: d.lock,
+	}
+	d.following = &FollowingDB{
+		db:   d.db,
+		lock: d.lock,
+	}
+	d.offlineMessages = &OfflineMessagesDB{
+		db:   d.db,
+		lock: d.lock,
+	}
+	d.pointers = &PointersDB{
+		db:   d.db,
+		lock: d.lock,
+	}
+	d.keys = &KeysDB{
+		db:   d.db,
+		lock: d.lock,
+	}
+	d.state = &StateDB{
+		db:   d.db,
+		lock: d.lock,
+	}
+	d.stxos = &StxoDB{
+		db:   d.db,
+		lock: d.lock,
+	}
+	d.txns = &TxnsDB{
+		db:   d.db,
+		lock: d.lock,
+	}
+	d.utxos = &UtxoDB{
+		db:   d.db,
+		lock: d.lock,
+	}
+	d.settings = &SettingsDB{
+		db:   d.db,
+		lock: d.lock,
+	}
+	d.inventory = &InventoryDB{
+		db:   d.db,
+		lock: d.lock,
+	}
+	d.purchases = &PurchasesDB{
+		db:   d.db,
+		lock: d.lock,
+	}
+	d.sales = &SalesDB{
+		db:   d.db,
+		lock: d.lock,
+	}
+	d.watchedScripts = &WatchedScriptsDB{
+		db:   d.db,
+		lock: d.lock,
+	}
+	d.licenseKeys = &LicenseKeysDB{
+		db:   d.db,
+		lock: d.lock,
+	}
+	d.apiTokens = &APITokensDB{
+		db:   d.db,
+		lock: d.lock,
+	}
+	d.webhooks = &WebhookDeliveriesDB{
+		db:   d.db,
+		lock: d.lock,
+	}
+	d.wsEvents = &WebsocketEventsDB{
+		db:   d.db,
+		lock: d.lock,
+	}
+	d.crossposts = &CrosspostsDB{
+		db:   d.db,
+		lock: d.lock,
+	}
+	d.sessions = &SessionsDB{
+		db:   d.db,
+		lock: d.lock,
+	}
+	d.prekeys = &PrekeysDB{
+		db:   d.db,
+		lock: d.lock,
+	}
+	d.chaincodes = &OrderChaincodesDB{
+		db:   d.db,
+		lock: d.lock,
+	}
+}
+
 func (d *SQLiteDatastore) Close() {
 	d.db.Close()
 }
@@ -179,6 +240,38 @@ func (d *SQLiteDatastore) Sales() repo.Sales {
 	return d.sales
 }
 
+func (d *SQLiteDatastore) LicenseKeys() repo.LicenseKeys {
+	return d.licenseKeys
+}
+
+func (d *SQLiteDatastore) APITokens() repo.APITokens {
+	return d.apiTokens
+}
+
+func (d *SQLiteDatastore) WebhookDeliveries() repo.WebhookDeliveries {
+	return d.webhooks
+}
+
+func (d *SQLiteDatastore) WebsocketEvents() repo.WebsocketEvents {
+	return d.wsEvents
+}
+
+func (d *SQLiteDatastore) Crossposts() repo.Crossposts {
+	return d.crossposts
+}
+
+func (d *SQLiteDatastore) Sessions() repo.Sessions {
+	return d.sessions
+}
+
+func (d *SQLiteDatastore) Prekeys() repo.Prekeys {
+	return d.prekeys
+}
+
+func (d *SQLiteDatastore) OrderChaincodes() repo.OrderChaincodes {
+	return d.chaincodes
+}
+
 func (d *SQLiteDatastore) WatchedScripts() spvwallet.WatchedScripts {
 	return d.watchedScripts
 }
@@ -221,6 +314,37 @@ func (d *SQLiteDatastore) Copy(dbPath string, password string) error {
 	return nil
 }
 
+// Write a consistent copy of the database into dir and return its path. The copy is
+// encrypted with the same password as the database.
+func (d *SQLiteDatastore) Backup(dir string) (string, error) {
+	d.lock.Lock()
+	defer d.lock.Unlock()
+	dbPath := path.Join(dir, path.Base(d.path))
+	os.Remove(dbPath)
+	stmt := "attach database '" + dbPath + "' as backup;" +
+		"select sqlcipher_export('backup');" +
+		"detach database backup;"
+	if _, err := d.db.Exec(stmt); err != nil {
+		os.Remove(dbPath)
+		return "", err
+	}
+	return dbPath, nil
+}
+
+// Return an error if the database can't be written to. The write is made in a
+// transaction which is rolled back so nothing is changed.
+func (d *SQLiteDatastore) CheckWritable() error {
+	d.lock.Lock()
+	defer d.lock.Unlock()
+	tx, err := d.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+	_, err = tx.Exec("create table writecheck (id integer)")
+	return err
+}
+
 func initDatabaseTables(db *sql.DB, password string) error {
 	var sqlStmt string
 	if password != "" {
@@ -232,7 +356,7 @@ func initDatabaseTables(db *sql.DB, password string) error {
 	create table followers (peerID text primary key not null);
 	create table following (peerID text primary key not null);
 	create table offlinemessages (url text primary key not null, timestamp integer);
-	create table pointers (pointerID text primary key not null, key text, address text, purpose integer, timestamp integer);
+	create table pointers (pointerID text primary key not null, key text, address text, purpose integer, timestamp integer, recipient text);
 	create table keys (scriptPubKey text primary key not null, purpose integer, keyIndex integer, used integer);
 	create table utxos (outpoint text primary key not null, value integer, height integer, scriptPubKey text, freeze int);
 	create table stxos (outpoint text primary key not null, value integer, height integer, scriptPubKey text, spendHeight integer, spendTxid text);
@@ -242,6 +366,15 @@ func initDatabaseTables(db *sql.DB, password string) error {
 	create table purchases (orderID text primary key not null, contract blob, state integer, read integer, date integer, total integer, thumbnail text, vendorID text, vendorBlockchainID text, title text, shippingName text, shippingAddress text, paymentAddr text, funded integer, transactions blob);
 	create table sales (orderID text primary key not null, contract blob, state integer, read integer, date integer, total integer, thumbnail text, buyerID text, buyerBlockchainID text, title text, shippingName text, shippingAddress text, paymentAddr text, funded integer, transactions blob);
 	create table if not exists watchedscripts (scriptPubKey text primary key not null);
+	create table if not exists licensekeys (licenseKey text primary key not null, slug text, orderID text);
+	create table if not exists apitokens (name text primary key not null, hash blob unique not null, scopes text, created integer);
+	create table if not exists webhookdeliveries (id text primary key not null, url text, event text, payload blob, status text, attempts integer, lastError text, created integer, nextAttempt integer);
+	create table if not exists websocketevents (seq integer primary key not null, topic text, envelope blob);
+	create table if not exists crossposts (gateway text not null, path text not null, hash text, primary key (gateway, path));
+	create table if not exists sessions (id text primary key not null, peerID text, state blob, timestamp integer);
+	create table if not exists prekeys (id integer primary key not null, private blob, created integer);
+	create table if not exists crosspostgateways (gateway text primary key not null, putOnly integer);
+	create table if not exists orderchaincodes (id integer primary key not null);
 	`
 	_, err := db.Exec(sqlStmt)
 	if err != nil {
@@ -251,7 +384,7 @@ func initDatabaseTables(db *sql.DB, password string) error {
 }
 
 type ConfigDB struct {
-	db   *sql.DB
+	db   *sharedDB
 	lock *sync.Mutex
 	path string
 }
@@ -259,7 +392,7 @@ type ConfigDB struct {
 func (c *ConfigDB) Init(mnemonic string, identityKey []byte, password string) error {
 	c.lock.Lock()
 	defer c.lock.Unlock()
-	if err := initDatabaseTables(c.db, password); err != nil {
+	if err := initDatabaseTables(c.db.DB, password); err != nil {
 		return err
 	}
 	tx, err := c.db.Begin()
@@ -281,6 +414,11 @@ func (c *ConfigDB) Init(mnemonic string, identityKey []byte, password string) er
 		tx.Rollback()
 		return err
 	}
+	_, err = stmt.Exec(schemaVersionKey, strconv.Itoa(SchemaVersion))
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
 	tx.Commit()
 	return nil
 
@@ -313,6 +451,8 @@ func (c *ConfigDB) GetIdentityKey() ([]byte, error) {
 }
 
 func (c *ConfigDB) IsEncrypted() bool {
+	c.lock.Lock()
+	defer c.lock.Unlock()
 	pwdCheck := "select count(*) from sqlite_master;"
 	_, err := c.db.Exec(pwdCheck) // Fails if wrong password is entered
 	if err != nil {
@@ -96,3 +96,31 @@ func TestEncryptedDb(t *testing.T) {
 		t.Error("IsEncrypted returned incorrectly")
 	}
 }
+
+func TestBackup(t *testing.T) {
+	dir := path.Join("./", "datastore", "backup")
+	os.MkdirAll(dir, os.ModePerm)
+	dbPath, err := testDB.Backup(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if dbPath != path.Join(dir, "mainnet.db") {
+		t.Errorf("Backup written to the wrong path: %s", dbPath)
+	}
+	if isPlaintext(dbPath) {
+		t.Error("Backup is not encrypted")
+	}
+	if !checkPassword(dbPath, "LetMeIn") {
+		t.Error("Backup is not encrypted with the database password")
+	}
+}
+
+func TestCheckWritable(t *testing.T) {
+	if err := testDB.CheckWritable(); err != nil {
+		t.Error(err)
+	}
+	// The check must not leave anything behind
+	if err := testDB.CheckWritable(); err != nil {
+		t.Error(err)
+	}
+}
@@ -1,202 +1,127 @@
 package db
 
 import (
-	"bufio"
-	"fmt"
+	"database/sql"
+	"errors"
+	"net/url"
 	"os"
-	"path"
-	"path/filepath"
 	"strings"
-	"syscall"
 
-	lockfile "github.com/ipfs/go-ipfs/repo/fsrepo/lock"
-	"github.com/mitchellh/go-homedir"
-	"golang.org/x/crypto/ssh/terminal"
+	"github.com/OpenBazaar/openbazaar-go/repo"
 )
 
-// FIXME: the encrypt and decrypt functions here should probably be added to the DB interface
-// FIXME: and the stdin stuff should be moved somewhere outside of this package.
+// Encrypt a plaintext database with the given password. Sqlcipher cannot rekey a plaintext
+// database in place so it is exported into an encrypted copy which then replaces the original.
+func (d *SQLiteDatastore) Encrypt(password string) error {
+	if password == "" {
+		return errors.New("The password must not be empty")
+	}
+	d.lock.Lock()
+	defer d.lock.Unlock()
+	if !isPlaintext(d.path) {
+		return repo.ErrAlreadyEncrypted
+	}
+	return d.export("", password)
+}
 
-// Create a temp encrypted database, read the unencrypted database into it then replace the unencrypted database
-func Encrypt() error {
-	reader := bufio.NewReader(os.Stdin)
-	var repoPath string
-	var filename string
-	var testnet bool
-	for {
-		fmt.Print("Encrypt the mainnet or testnet db?: ")
-		resp, _ := reader.ReadString('\n')
-		if strings.ToLower(resp) == "mainnet\n" {
-			rPath := "~/.openbazaar2.0"
-			filename = "mainnet.db"
-			testnet = false
-			expPath, _ := homedir.Expand(filepath.Clean(rPath))
-			repoPath = expPath
-			repoLockFile := filepath.Join(repoPath, lockfile.LockFile)
-			if _, err := os.Stat(repoLockFile); !os.IsNotExist(err) {
-				fmt.Println("Cannot encrypt while the daemon is running.")
-				return nil
-			}
-			if _, err := os.Stat(expPath); os.IsNotExist(err) {
-				fmt.Println("Database does not exist. You may need to run the node at least once to initialize it.")
-				return nil
-			}
-			break
-		} else if strings.ToLower(resp) == "testnet\n" {
-			rPath := "~/.openbazaar2.0-testnet"
-			filename = "testnet.db"
-			testnet = true
-			expPath, _ := homedir.Expand(filepath.Clean(rPath))
-			repoPath = expPath
-			repoLockFile := filepath.Join(repoPath, lockfile.LockFile)
-			if _, err := os.Stat(repoLockFile); !os.IsNotExist(err) {
-				fmt.Println("Cannot encrypt while the daemon is running.")
-				return nil
-			}
-			if _, err := os.Stat(expPath); os.IsNotExist(err) {
-				fmt.Println("Database does not exist. You may need to run the daemon at least once to initialize it.")
-				return nil
-			}
-			break
-		} else {
-			fmt.Println("No comprende")
-		}
+// Decrypt the database. The password must match the one the database is encrypted with.
+func (d *SQLiteDatastore) Decrypt(password string) error {
+	d.lock.Lock()
+	defer d.lock.Unlock()
+	if isPlaintext(d.path) {
+		return repo.ErrNotEncrypted
 	}
-	var pw string
-	for {
-		fmt.Print("Enter a veerrrry strong password: ")
-		bytePassword, _ := terminal.ReadPassword(int(syscall.Stdin))
-		fmt.Println("")
-		resp := string(bytePassword)
-		if len(resp) < 8 {
-			fmt.Println("You call that a password? Try again.")
-		} else if resp != "" {
-			pw = resp
-			break
-		} else {
-			fmt.Println("Seriously, enter a password.")
-		}
+	if !checkPassword(d.path, password) {
+		return repo.ErrInvalidPassword
 	}
-	for {
-		fmt.Print("Confirm your password: ")
-		bytePassword, _ := terminal.ReadPassword(int(syscall.Stdin))
-		fmt.Println("")
-		resp := string(bytePassword)
-		if resp == pw {
-			break
-		} else {
-			fmt.Println("Quit effin around. Try again.")
-		}
+	return d.export(password, "")
+}
+
+// Change the password of an encrypted database in place
+func (d *SQLiteDatastore) ChangePassword(oldPassword, newPassword string) error {
+	if newPassword == "" {
+		return errors.New("The new password must not be empty")
 	}
-	pw = strings.Replace(pw, "'", "''", -1)
-	tmpPath := path.Join(repoPath, "tmp")
-	sqlliteDB, err := Create(repoPath, "", testnet)
-	if err != nil {
-		fmt.Println(err)
-		return err
+	d.lock.Lock()
+	defer d.lock.Unlock()
+	if isPlaintext(d.path) {
+		return repo.ErrNotEncrypted
 	}
-	if sqlliteDB.Config().IsEncrypted() {
-		fmt.Println("The database is alredy encrypted")
-		return nil
+	if !checkPassword(d.path, oldPassword) {
+		return repo.ErrInvalidPassword
 	}
-	if err := os.MkdirAll(path.Join(repoPath, "tmp", "datastore"), os.ModePerm); err != nil {
+	if _, err := d.db.Exec("pragma rekey = '" + escapePassword(newPassword) + "';"); err != nil {
 		return err
 	}
-	tmpDB, err := Create(tmpPath, pw, testnet)
-	if err != nil {
-		fmt.Println(err)
+	// The rekey only went through one of the pooled connections so reopen them all with the new key
+	return d.reopen(newPassword)
+}
+
+// Export the database into a copy keyed with newPassword, replace the database with the
+// copy and reopen it. An empty password exports a plaintext copy. The caller must hold the lock.
+func (d *SQLiteDatastore) export(oldPassword, newPassword string) error {
+	tmpPath := d.path + ".tmp"
+	os.Remove(tmpPath)
+	stmt := "attach database '" + tmpPath + "' as export key '" + escapePassword(newPassword) + "';" +
+		"select sqlcipher_export('export');" +
+		"detach database export;"
+	if _, err := d.db.Exec(stmt); err != nil {
+		os.Remove(tmpPath)
 		return err
 	}
-
-	initDatabaseTables(tmpDB.db, pw)
-	if err := sqlliteDB.Copy(path.Join(tmpPath, "datastore", filename), pw); err != nil {
-		fmt.Println(err)
+	d.db.Close()
+	if err := os.Rename(tmpPath, d.path); err != nil {
+		os.Remove(tmpPath)
+		if conn, oerr := open(d.path, oldPassword); oerr == nil {
+			d.db.DB = conn
+		}
 		return err
 	}
-	err = os.Rename(path.Join(tmpPath, "datastore", filename), path.Join(repoPath, "datastore", filename))
+	conn, err := open(d.path, newPassword)
 	if err != nil {
-		fmt.Println(err)
 		return err
 	}
-	os.RemoveAll(path.Join(tmpPath))
-	fmt.Println("Success! You must now run openbazaard start with the --password flag.")
+	d.db.DB = conn
 	return nil
 }
 
-// Create a temp database, read the encrypted database into it then replace the encrypted database
-func Decrypt() error {
-	reader := bufio.NewReader(os.Stdin)
-	var repoPath string
-	var filename string
-	var testnet bool
-	for {
-		fmt.Print("Decrypt the mainnet or testnet db?: ")
-		resp, _ := reader.ReadString('\n')
-		if strings.ToLower(resp) == "mainnet\n" {
-			rPath := "~/.openbazaar2.0"
-			filename = "mainnet.db"
-			testnet = false
-			expPath, _ := homedir.Expand(filepath.Clean(rPath))
-			repoPath = expPath
-			repoLockFile := filepath.Join(repoPath, lockfile.LockFile)
-			if _, err := os.Stat(repoLockFile); !os.IsNotExist(err) {
-				fmt.Println("Cannot decrypt while the daemon is running.")
-				return nil
-			}
-			if _, err := os.Stat(expPath); os.IsNotExist(err) {
-				fmt.Println("Database does not exist. You may need to run the daemon at least once to initialize it.")
-				return nil
-			}
-			break
-		} else if strings.ToLower(resp) == "testnet\n" {
-			rPath := "~/.openbazaar2.0-testnet"
-			filename = "testnet.db"
-			testnet = true
-			expPath, _ := homedir.Expand(filepath.Clean(rPath))
-			repoPath = expPath
-			repoLockFile := filepath.Join(repoPath, lockfile.LockFile)
-			if _, err := os.Stat(repoLockFile); !os.IsNotExist(err) {
-				fmt.Println("Cannot decrypt while the daemon is running.")
-				return nil
-			}
-			if _, err := os.Stat(expPath); os.IsNotExist(err) {
-				fmt.Println("Database does not exist. You may need to run the node at least once to initialize it.")
-				return nil
-			}
-			break
-		} else {
-			fmt.Println("No comprende")
-		}
-	}
-	fmt.Print("Enter your password: ")
-	bytePassword, _ := terminal.ReadPassword(int(syscall.Stdin))
-	fmt.Println("")
-	pw := string(bytePassword)
-	pw = strings.Replace(pw, "'", "''", -1)
-	sqlliteDB, err := Create(repoPath, pw, testnet)
-	if err != nil || sqlliteDB.Config().IsEncrypted() {
-		fmt.Println("Invalid password")
-		return err
-	}
-	if err := os.MkdirAll(path.Join(repoPath, "tmp", "datastore"), os.ModePerm); err != nil {
-		return err
-	}
-	tmpDB, err := Create(path.Join(repoPath, "tmp"), "", testnet)
+// Replace the shared connection with one keyed with password. The caller must hold the lock.
+func (d *SQLiteDatastore) reopen(password string) error {
+	conn, err := open(d.path, password)
 	if err != nil {
-		fmt.Println(err)
 		return err
 	}
-	initDatabaseTables(tmpDB.db, "")
-	if err := sqlliteDB.Copy(path.Join(repoPath, "tmp", "datastore", filename), ""); err != nil {
-		fmt.Println(err)
-		return err
+	d.db.Close()
+	d.db.DB = conn
+	return nil
+}
+
+// Open the database at dbPath. The key is passed in the DSN so the driver sets it on every
+// connection it opens for the pool rather than just the first.
+func open(dbPath, password string) (*sql.DB, error) {
+	dsn := dbPath
+	if password != "" {
+		dsn += "?_pragma_key=" + url.QueryEscape(strings.Replace(password, `"`, `""`, -1))
 	}
-	err = os.Rename(path.Join(repoPath, "tmp", "datastore", filename), path.Join(repoPath, "datastore", filename))
+	return sql.Open("sqlite3", dsn)
+}
+
+// Returns true if the database file can be read without a password
+func isPlaintext(dbPath string) bool {
+	return checkPassword(dbPath, "")
+}
+
+// Returns true if the database file can be read with the given password
+func checkPassword(dbPath, password string) bool {
+	conn, err := open(dbPath, password)
 	if err != nil {
-		fmt.Println(err)
-		return err
+		return false
 	}
-	os.RemoveAll(path.Join(repoPath, "tmp"))
-	fmt.Println("Success!")
-	return nil
+	defer conn.Close()
+	_, err = conn.Exec("select count(*) from sqlite_master;")
+	return err == nil
+}
+
+func escapePassword(password string) string {
+	return strings.Replace(password, "'", "''", -1)
 }
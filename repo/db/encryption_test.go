@@ -0,0 +1,125 @@
+package db
+
+import (
+	"io/ioutil"
+	"os"
+	"path"
+	"testing"
+
+	"github.com/OpenBazaar/openbazaar-go/repo"
+)
+
+func createEncryptionTestDB(t *testing.T) (*SQLiteDatastore, string) {
+	dir, err := ioutil.TempDir("", "encryption")
+	if err != nil {
+		t.Fatal(err)
+	}
+	os.MkdirAll(path.Join(dir, "datastore"), os.ModePerm)
+	sqliteDB, err := Create(dir, "", false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := sqliteDB.Config().Init("Mnemonic Passphrase", []byte("Private Key"), ""); err != nil {
+		t.Fatal(err)
+	}
+	if err := sqliteDB.Followers().Put("follower"); err != nil {
+		t.Fatal(err)
+	}
+	return sqliteDB, dir
+}
+
+func TestEncryptDecrypt(t *testing.T) {
+	sqliteDB, dir := createEncryptionTestDB(t)
+	defer os.RemoveAll(dir)
+	defer sqliteDB.Close()
+
+	if err := sqliteDB.Encrypt("let'sGoShopping"); err != nil {
+		t.Fatal(err)
+	}
+	if isPlaintext(sqliteDB.path) {
+		t.Error("Database was not encrypted")
+	}
+	if !checkPassword(sqliteDB.path, "let'sGoShopping") {
+		t.Error("Database cannot be opened with the new password")
+	}
+	if !sqliteDB.Followers().FollowsMe("follower") {
+		t.Error("Data was lost encrypting the database")
+	}
+	if err := sqliteDB.Encrypt("let'sGoShopping"); err != repo.ErrAlreadyEncrypted {
+		t.Error("Expected an already encrypted error")
+	}
+	if err := sqliteDB.Decrypt("wrong password"); err != repo.ErrInvalidPassword {
+		t.Error("Expected an invalid password error")
+	}
+	if err := sqliteDB.Decrypt("let'sGoShopping"); err != nil {
+		t.Fatal(err)
+	}
+	if !isPlaintext(sqliteDB.path) {
+		t.Error("Database was not decrypted")
+	}
+	mn, err := sqliteDB.Config().GetMnemonic()
+	if err != nil || mn != "Mnemonic Passphrase" {
+		t.Error("Data was lost decrypting the database")
+	}
+	if err := sqliteDB.Decrypt("let'sGoShopping"); err != repo.ErrNotEncrypted {
+		t.Error("Expected a not encrypted error")
+	}
+}
+
+func TestChangePassword(t *testing.T) {
+	sqliteDB, dir := createEncryptionTestDB(t)
+	defer os.RemoveAll(dir)
+	defer sqliteDB.Close()
+
+	if err := sqliteDB.ChangePassword("", "NewPassword"); err != repo.ErrNotEncrypted {
+		t.Error("Expected a not encrypted error")
+	}
+	if err := sqliteDB.Encrypt("OldPassword"); err != nil {
+		t.Fatal(err)
+	}
+	if err := sqliteDB.ChangePassword("WrongPassword", "NewPassword"); err != repo.ErrInvalidPassword {
+		t.Error("Expected an invalid password error")
+	}
+	if err := sqliteDB.ChangePassword("OldPassword", "NewPassword"); err != nil {
+		t.Fatal(err)
+	}
+	if checkPassword(sqliteDB.path, "OldPassword") {
+		t.Error("Database can still be opened with the old password")
+	}
+	if !checkPassword(sqliteDB.path, "NewPassword") {
+		t.Error("Database cannot be opened with the new password")
+	}
+	if !sqliteDB.Followers().FollowsMe("follower") {
+		t.Error("Data was lost changing the password")
+	}
+}
+
+func TestEncryptKeepsTablesUsable(t *testing.T) {
+	sqliteDB, dir := createEncryptionTestDB(t)
+	defer os.RemoveAll(dir)
+	defer sqliteDB.Close()
+
+	// Tables handed out before the database is encrypted keep working afterwards
+	followers := sqliteDB.Followers()
+	if err := sqliteDB.Encrypt("password"); err != nil {
+		t.Fatal(err)
+	}
+	if !followers.FollowsMe("follower") {
+		t.Error("Table handed out before encrypting can't read the database")
+	}
+
+	// Every pooled connection is keyed, not just the first
+	sqliteDB.db.SetMaxIdleConns(0)
+	done := make(chan bool)
+	for i := 0; i < 4; i++ {
+		go func() {
+			var count int
+			done <- sqliteDB.db.QueryRow("select count(*) from followers").Scan(&count) == nil
+		}()
+	}
+	for i := 0; i < 4; i++ {
+		if !<-done {
+			t.Error("Pooled connection can't read the encrypted database")
+		}
+	}
+}
@@ -1,13 +1,12 @@
 package db
 
 import (
-	"database/sql"
 	"strconv"
 	"sync"
 )
 
 type FollowingDB struct {
-	db   *sql.DB
+	db   *sharedDB
 	lock *sync.Mutex
 }
 
@@ -13,7 +13,7 @@ func init() {
 	conn, _ := sql.Open("sqlite3", ":memory:")
 	initDatabaseTables(conn, "")
 	fldb = FollowingDB{
-		db:   conn,
+		db:   &sharedDB{conn},
 		lock: new(sync.Mutex),
 	}
 }
@@ -1,12 +1,11 @@
 package db
 
 import (
-	"database/sql"
 	"sync"
 )
 
 type InventoryDB struct {
-	db   *sql.DB
+	db   *sharedDB
 	lock *sync.Mutex
 }
 
@@ -1,7 +1,6 @@
 package db
 
 import (
-	"database/sql"
 	"encoding/hex"
 	"errors"
 	"github.com/OpenBazaar/spvwallet"
@@ -10,7 +9,7 @@ import (
 )
 
 type KeysDB struct {
-	db   *sql.DB
+	db   *sharedDB
 	lock *sync.Mutex
 }
 
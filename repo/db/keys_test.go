@@ -15,7 +15,7 @@ func init() {
 	conn, _ := sql.Open("sqlite3", ":memory:")
 	initDatabaseTables(conn, "")
 	kdb = KeysDB{
-		db:   conn,
+		db:   &sharedDB{conn},
 		lock: new(sync.Mutex),
 	}
 }
@@ -0,0 +1,111 @@
+package db
+
+import (
+	"database/sql"
+	"sync"
+
+	"github.com/OpenBazaar/openbazaar-go/repo"
+)
+
+type LicenseKeysDB struct {
+	db   *sharedDB
+	lock *sync.Mutex
+}
+
+func (l *LicenseKeysDB) Put(slug string, keys []string) error {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+	tx, err := l.db.Begin()
+	if err != nil {
+		return err
+	}
+	stmt, err := tx.Prepare("insert into licensekeys(licenseKey, slug, orderID) values(?,?,'')")
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+	defer stmt.Close()
+	for _, key := range keys {
+		_, err = stmt.Exec(key, slug)
+		if err != nil {
+			tx.Rollback()
+			return err
+		}
+	}
+	tx.Commit()
+	return nil
+}
+
+func (l *LicenseKeysDB) Assign(slug string, orderID string, quantity int) ([]string, error) {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+	tx, err := l.db.Begin()
+	if err != nil {
+		return nil, err
+	}
+	keys, err := selectLicenseKeys(tx, "select licenseKey from licensekeys where slug=? and orderID=? order by rowid", slug, orderID)
+	if err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+	if len(keys) >= quantity {
+		tx.Commit()
+		return keys, nil
+	}
+	unused, err := selectLicenseKeys(tx, "select licenseKey from licensekeys where slug=? and orderID='' order by rowid limit ?", slug, quantity-len(keys))
+	if err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+	if len(keys)+len(unused) < quantity {
+		tx.Rollback()
+		return nil, repo.ErrNotEnoughLicenseKeys
+	}
+	for _, key := range unused {
+		_, err = tx.Exec("update licensekeys set orderID=? where licenseKey=?", orderID, key)
+		if err != nil {
+			tx.Rollback()
+			return nil, err
+		}
+	}
+	tx.Commit()
+	return append(keys, unused...), nil
+}
+
+func selectLicenseKeys(tx *sql.Tx, query string, args ...interface{}) ([]string, error) {
+	rows, err := tx.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var keys []string
+	for rows.Next() {
+		var key string
+		if err := rows.Scan(&key); err != nil {
+			return nil, err
+		}
+		keys = append(keys, key)
+	}
+	return keys, rows.Err()
+}
+
+func (l *LicenseKeysDB) Count(slug string) (int, error) {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+	var count int
+	err := l.db.QueryRow("select count(*) from licensekeys where slug=? and orderID=''", slug).Scan(&count)
+	if err != nil {
+		return 0, err
+	}
+	return count, nil
+}
+
+func (l *LicenseKeysDB) DeleteUnused(slug string) error {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+	_, err := l.db.Exec("delete from licensekeys where slug=? and orderID=''", slug)
+	if err != nil {
+		return err
+	}
+	return nil
+}
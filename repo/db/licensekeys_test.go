@@ -0,0 +1,114 @@
+package db
+
+import (
+	"database/sql"
+	"reflect"
+	"sync"
+	"testing"
+
+	"github.com/OpenBazaar/openbazaar-go/repo"
+)
+
+var lkdb LicenseKeysDB
+
+func init() {
+	conn, _ := sql.Open("sqlite3", ":memory:")
+	initDatabaseTables(conn, "")
+	lkdb = LicenseKeysDB{
+		db:   &sharedDB{conn},
+		lock: new(sync.Mutex),
+	}
+}
+
+func TestPutLicenseKeys(t *testing.T) {
+	err := lkdb.Put("put", []string{"key1", "key2"})
+	if err != nil {
+		t.Error(err)
+	}
+	count, err := lkdb.Count("put")
+	if err != nil {
+		t.Error(err)
+	}
+	if count != 2 {
+		t.Errorf("Expected 2 got %d", count)
+	}
+}
+
+func TestPutDuplicateLicenseKey(t *testing.T) {
+	err := lkdb.Put("dup", []string{"dupkey"})
+	if err != nil {
+		t.Error(err)
+	}
+	err = lkdb.Put("dup", []string{"dupkey"})
+	if err == nil {
+		t.Error("Expected duplicate key to be rejected")
+	}
+}
+
+func TestAssignLicenseKey(t *testing.T) {
+	lkdb.Put("assign", []string{"akey1", "akey2", "akey3"})
+	keys1, err := lkdb.Assign("assign", "order1", 2)
+	if err != nil {
+		t.Error(err)
+	}
+	if len(keys1) != 2 || keys1[0] == keys1[1] {
+		t.Errorf("Expected 2 distinct keys got %v", keys1)
+	}
+	keys2, err := lkdb.Assign("assign", "order2", 1)
+	if err != nil {
+		t.Error(err)
+	}
+	if len(keys2) != 1 || keys2[0] == keys1[0] || keys2[0] == keys1[1] {
+		t.Error("Same key assigned to two orders")
+	}
+	again, err := lkdb.Assign("assign", "order1", 2)
+	if err != nil {
+		t.Error(err)
+	}
+	if !reflect.DeepEqual(again, keys1) {
+		t.Errorf("Expected %v got %v", keys1, again)
+	}
+	_, err = lkdb.Assign("assign", "order3", 1)
+	if err != repo.ErrNotEnoughLicenseKeys {
+		t.Error("Expected error when the key pool is empty")
+	}
+	count, err := lkdb.Count("assign")
+	if err != nil {
+		t.Error(err)
+	}
+	if count != 0 {
+		t.Errorf("Expected 0 got %d", count)
+	}
+}
+
+func TestAssignLicenseKeysPoolTooSmall(t *testing.T) {
+	lkdb.Put("small", []string{"skey1", "skey2"})
+	_, err := lkdb.Assign("small", "order1", 3)
+	if err != repo.ErrNotEnoughLicenseKeys {
+		t.Errorf("Expected ErrNotEnoughLicenseKeys got %v", err)
+	}
+	// Nothing is assigned when the order can't be covered
+	count, err := lkdb.Count("small")
+	if err != nil {
+		t.Error(err)
+	}
+	if count != 2 {
+		t.Errorf("Expected 2 got %d", count)
+	}
+}
+
+func TestDeleteUnusedLicenseKeys(t *testing.T) {
+	lkdb.Put("delete", []string{"dkey1", "dkey2"})
+	lkdb.Assign("delete", "order1", 1)
+	err := lkdb.DeleteUnused("delete")
+	if err != nil {
+		t.Error(err)
+	}
+	stmt, _ := lkdb.db.Prepare("select count(*) from licensekeys where slug=?")
+	defer stmt.Close()
+	var count int
+	stmt.QueryRow("delete").Scan(&count)
+	if count != 1 {
+		t.Errorf("Expected the assigned key to remain, got %d keys", count)
+	}
+}
@@ -0,0 +1,218 @@
+package db
+
+import (
+	"database/sql"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+)
+
+// A schema migration. Each migration moves the schema up by one version and must
+// leave initDatabaseTables and the migrated schema identical.
+type Migration struct {
+	Description string
+	Up          func(tx *sql.Tx) error
+}
+
+// Ordered list of migrations. The schema version of a database is the number of migrations
+// applied to it, so new migrations must only ever be appended to the end of this list.
+var migrations = []Migration{
+	{
+		Description: "Add the licensekeys table",
+		Up: func(tx *sql.Tx) error {
+			_, err := tx.Exec("create table if not exists licensekeys (licenseKey text primary key not null, slug text, orderID text);")
+			return err
+		},
+	},
+	{
+		Description: "Add the apitokens table",
+		Up: func(tx *sql.Tx) error {
+			_, err := tx.Exec("create table if not exists apitokens (name text primary key not null, hash blob unique not null, scopes text, created integer);")
+			return err
+		},
+	},
+	{
+		Description: "Add the webhookdeliveries table",
+		Up: func(tx *sql.Tx) error {
+			_, err := tx.Exec("create table if not exists webhookdeliveries (id text primary key not null, url text, event text, payload blob, status text, attempts integer, lastError text, created integer, nextAttempt integer);")
+			return err
+		},
+	},
+	{
+		Description: "Add the websocketevents table",
+		Up: func(tx *sql.Tx) error {
+			_, err := tx.Exec("create table if not exists websocketevents (seq integer primary key not null, topic text, envelope blob);")
+			return err
+		},
+	},
+	{
+		Description: "Add the crossposts table",
+		Up: func(tx *sql.Tx) error {
+			_, err := tx.Exec("create table if not exists crossposts (gateway text not null, path text not null, hash text, primary key (gateway, path));")
+			return err
+		},
+	},
+	{
+		Description: "Add the sessions and prekeys tables",
+		Up: func(tx *sql.Tx) error {
+			if _, err := tx.Exec("create table if not exists sessions (id text primary key not null, peerID text, state blob, timestamp integer);"); err != nil {
+				return err
+			}
+			_, err := tx.Exec("create table if not exists prekeys (id integer primary key not null, private blob, created integer);")
+			return err
+		},
+	},
+	{
+		Description: "Add the recipient column to the pointers table",
+		Up: func(tx *sql.Tx) error {
+			exists, err := hasColumn(tx, "pointers", "recipient")
+			if err != nil || exists {
+				return err
+			}
+			_, err = tx.Exec("alter table pointers add column recipient text;")
+			return err
+		},
+	},
+	{
+		Description: "Add the crosspostgateways table",
+		Up: func(tx *sql.Tx) error {
+			_, err := tx.Exec("create table if not exists crosspostgateways (gateway text primary key not null, putOnly integer);")
+			return err
+		},
+	},
+	{
+		Description: "Add the orderchaincodes table and move the next chaincode index out of the wallet state",
+		Up: func(tx *sql.Tx) error {
+			if _, err := tx.Exec("create table if not exists orderchaincodes (id integer primary key not null);"); err != nil {
+				return err
+			}
+			var next string
+			err := tx.QueryRow("select value from state where key='orderChaincodeIndex'").Scan(&next)
+			if err == sql.ErrNoRows {
+				return nil
+			} else if err != nil {
+				return err
+			}
+			index, err := strconv.ParseUint(next, 10, 32)
+			if err != nil || index == 0 {
+				return err
+			}
+			if _, err := tx.Exec("insert or ignore into orderchaincodes(id) values(?)", index-1); err != nil {
+				return err
+			}
+			_, err = tx.Exec("delete from state where key='orderChaincodeIndex'")
+			return err
+		},
+	},
+}
+
+// The schema version created by initDatabaseTables
+var SchemaVersion = len(migrations)
+
+const schemaVersionKey = "schemaVersion"
+
+// Return the schema version recorded in the config table. Databases created before
+// versioning was introduced have no version recorded and are at version zero.
+func schemaVersion(db *sql.DB) (int, error) {
+	var value string
+	err := db.QueryRow("select value from config where key=?", schemaVersionKey).Scan(&value)
+	if err == sql.ErrNoRows {
+		return 0, nil
+	} else if err != nil {
+		return 0, err
+	}
+	return strconv.Atoi(value)
+}
+
+func setSchemaVersion(tx *sql.Tx, version int) error {
+	_, err := tx.Exec("insert or replace into config(key, value) values(?,?)", schemaVersionKey, strconv.Itoa(version))
+	return err
+}
+
+// Report whether a table already has a column. Columns can't be added with if not exists.
+func hasColumn(tx *sql.Tx, table, column string) (bool, error) {
+	rows, err := tx.Query("pragma table_info(" + table + ")")
+	if err != nil {
+		return false, err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var cid, notNull, pk int
+		var name, colType string
+		var dflt sql.NullString
+		if err := rows.Scan(&cid, &name, &colType, &notNull, &dflt, &pk); err != nil {
+			return false, err
+		}
+		if name == column {
+			return true, nil
+		}
+	}
+	return false, rows.Err()
+}
+
+// Apply any migrations the database has not yet seen. If dbPath is set the database
+// file is copied to a backup before the first migration runs. The database must already
+// be keyed if it is encrypted.
+func migrate(db *sql.DB, dbPath string) error {
+	version, err := schemaVersion(db)
+	if err != nil {
+		return err
+	}
+	if version > len(migrations) {
+		return fmt.Errorf("Database schema version %d is newer than this version of openbazaard supports", version)
+	}
+	if version == len(migrations) {
+		return nil
+	}
+	if dbPath != "" {
+		backupPath := fmt.Sprintf("%s.v%d.bak", dbPath, version)
+		if err := copyFile(dbPath, backupPath); err != nil {
+			return err
+		}
+		log.Noticef("Backed up database to %s before migrating", backupPath)
+	}
+	for i := version; i < len(migrations); i++ {
+		log.Noticef("Migrating database to schema version %d: %s", i+1, migrations[i].Description)
+		tx, err := db.Begin()
+		if err != nil {
+			return err
+		}
+		if err := migrations[i].Up(tx); err != nil {
+			tx.Rollback()
+			return err
+		}
+		if err := setSchemaVersion(tx, i+1); err != nil {
+			tx.Rollback()
+			return err
+		}
+		if err := tx.Commit(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Returns true if the database has been initialized and can be read with the key it was opened with
+func isInitialized(db *sql.DB) bool {
+	var count int
+	err := db.QueryRow("select count(*) from sqlite_master where type='table' and name='config'").Scan(&count)
+	return err == nil && count > 0
+}
+
+func copyFile(src, dst string) error {
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+	out, err := os.OpenFile(dst, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0600)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(out, in); err != nil {
+		out.Close()
+		return err
+	}
+	return out.Close()
+}
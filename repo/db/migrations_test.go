@@ -0,0 +1,176 @@
+package db
+
+import (
+	"database/sql"
+	"io/ioutil"
+	"os"
+	"path"
+	"strconv"
+	"sync"
+	"testing"
+)
+
+// Create a database with the schema used before versioning was introduced
+func createUnversionedDatabase(conn *sql.DB) error {
+	if err := initDatabaseTables(conn, ""); err != nil {
+		return err
+	}
+	_, err := conn.Exec("drop table licensekeys;")
+	return err
+}
+
+func TestMigrateUnversionedDatabase(t *testing.T) {
+	conn, _ := sql.Open("sqlite3", ":memory:")
+	if err := createUnversionedDatabase(conn); err != nil {
+		t.Fatal(err)
+	}
+	version, err := schemaVersion(conn)
+	if err != nil {
+		t.Error(err)
+	}
+	if version != 0 {
+		t.Errorf("Expected version 0 got %d", version)
+	}
+	if err := migrate(conn, ""); err != nil {
+		t.Error(err)
+	}
+	version, err = schemaVersion(conn)
+	if err != nil {
+		t.Error(err)
+	}
+	if version != SchemaVersion {
+		t.Errorf("Expected version %d got %d", SchemaVersion, version)
+	}
+	_, err = conn.Exec("insert into licensekeys(licenseKey, slug, orderID) values('abc', 'slug', '')")
+	if err != nil {
+		t.Error("Migration did not create the licensekeys table")
+	}
+}
+
+func TestMigrateIsIdempotent(t *testing.T) {
+	conn, _ := sql.Open("sqlite3", ":memory:")
+	if err := createUnversionedDatabase(conn); err != nil {
+		t.Fatal(err)
+	}
+	if err := migrate(conn, ""); err != nil {
+		t.Error(err)
+	}
+	if err := migrate(conn, ""); err != nil {
+		t.Error(err)
+	}
+	version, _ := schemaVersion(conn)
+	if version != SchemaVersion {
+		t.Errorf("Expected version %d got %d", SchemaVersion, version)
+	}
+}
+
+func TestMigrateRejectsNewerSchema(t *testing.T) {
+	conn, _ := sql.Open("sqlite3", ":memory:")
+	initDatabaseTables(conn, "")
+	conn.Exec("insert into config(key, value) values(?,?)", schemaVersionKey, "1000")
+	if err := migrate(conn, ""); err == nil {
+		t.Error("Expected an error migrating a newer schema")
+	}
+}
+
+func TestMigrateBacksUpDatabase(t *testing.T) {
+	dir, err := ioutil.TempDir("", "migrations")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	dbPath := path.Join(dir, "test.db")
+	conn, err := sql.Open("sqlite3", dbPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+	if err := createUnversionedDatabase(conn); err != nil {
+		t.Fatal(err)
+	}
+	if err := migrate(conn, dbPath); err != nil {
+		t.Error(err)
+	}
+	if _, err := os.Stat(dbPath + ".v0.bak"); err != nil {
+		t.Error("Database was not backed up before migrating")
+	}
+}
+
+func TestConfigInitRecordsSchemaVersion(t *testing.T) {
+	dir, err := ioutil.TempDir("", "migrations")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	os.MkdirAll(path.Join(dir, "datastore"), os.ModePerm)
+	sqliteDB, err := Create(dir, "", true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := sqliteDB.Config().Init("mnemonic", []byte("key"), ""); err != nil {
+		t.Error(err)
+	}
+	version, err := schemaVersion(sqliteDB.db.DB)
+	if err != nil {
+		t.Error(err)
+	}
+	if version != SchemaVersion {
+		t.Errorf("Expected version %d got %d", SchemaVersion, version)
+	}
+}
+
+func TestMigrateAddsPointerRecipient(t *testing.T) {
+	conn, _ := sql.Open("sqlite3", ":memory:")
+	if err := initDatabaseTables(conn, ""); err != nil {
+		t.Fatal(err)
+	}
+	// Go back to the pointers table from before the recipient was recorded
+	conn.Exec("drop table pointers;")
+	conn.Exec("create table pointers (pointerID text primary key not null, key text, address text, purpose integer, timestamp integer);")
+	conn.Exec("insert into config(key, value) values(?,?)", schemaVersionKey, strconv.Itoa(migrationIndex(t, "Add the recipient column to the pointers table")))
+	if err := migrate(conn, ""); err != nil {
+		t.Error(err)
+	}
+	_, err := conn.Exec("insert into pointers(pointerID, key, address, purpose, timestamp, recipient) values('id', 'key', 'address', 1, 0, 'recipient')")
+	if err != nil {
+		t.Error("Migration did not add the recipient column")
+	}
+}
+
+// Return the schema version a database is at just before the migration runs
+func migrationIndex(t *testing.T, description string) int {
+	for i, m := range migrations {
+		if m.Description == description {
+			return i
+		}
+	}
+	t.Fatalf("No migration %q", description)
+	return 0
+}
+
+func TestMigrateMovesChaincodeIndex(t *testing.T) {
+	conn, _ := sql.Open("sqlite3", ":memory:")
+	if err := initDatabaseTables(conn, ""); err != nil {
+		t.Fatal(err)
+	}
+	// Go back to keeping the next chaincode index in the wallet state
+	conn.Exec("drop table orderchaincodes;")
+	conn.Exec("insert into state(key, value) values('orderChaincodeIndex', '4')")
+	conn.Exec("insert into config(key, value) values(?,?)", schemaVersionKey, strconv.Itoa(migrationIndex(t, "Add the orderchaincodes table and move the next chaincode index out of the wallet state")))
+	if err := migrate(conn, ""); err != nil {
+		t.Error(err)
+	}
+	odb := OrderChaincodesDB{db: &sharedDB{conn}, lock: new(sync.Mutex)}
+	next, err := odb.Next()
+	if err != nil {
+		t.Error(err)
+	}
+	if next != 4 {
+		t.Errorf("Expected next index 4, got %d", next)
+	}
+	var count int
+	conn.QueryRow("select count(*) from state where key='orderChaincodeIndex'").Scan(&count)
+	if count != 0 {
+		t.Error("Migration left the index in the wallet state")
+	}
+}
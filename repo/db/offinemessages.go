@@ -1,13 +1,12 @@
 package db
 
 import (
-	"database/sql"
 	"sync"
 	"time"
 )
 
 type OfflineMessagesDB struct {
-	db   *sql.DB
+	db   *sharedDB
 	lock *sync.Mutex
 }
 
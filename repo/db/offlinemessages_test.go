@@ -12,7 +12,7 @@ func init() {
 	conn, _ := sql.Open("sqlite3", ":memory:")
 	initDatabaseTables(conn, "")
 	odb = OfflineMessagesDB{
-		db:   conn,
+		db:   &sharedDB{conn},
 		lock: new(sync.Mutex),
 	}
 }
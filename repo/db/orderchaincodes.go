@@ -0,0 +1,63 @@
+package db
+
+import (
+	"database/sql"
+	"sync"
+)
+
+type OrderChaincodesDB struct {
+	db   *sharedDB
+	lock *sync.Mutex
+}
+
+func (o *OrderChaincodesDB) Allocate() (uint32, error) {
+	o.lock.Lock()
+	defer o.lock.Unlock()
+	tx, err := o.db.Begin()
+	if err != nil {
+		return 0, err
+	}
+	next, err := nextChaincodeIndex(tx)
+	if err != nil {
+		tx.Rollback()
+		return 0, err
+	}
+	_, err = tx.Exec("insert into orderchaincodes(id) values(?)", int64(next))
+	if err != nil {
+		tx.Rollback()
+		return 0, err
+	}
+	if err := tx.Commit(); err != nil {
+		return 0, err
+	}
+	return next, nil
+}
+
+func (o *OrderChaincodesDB) Reserve(index uint32) error {
+	o.lock.Lock()
+	defer o.lock.Unlock()
+	_, err := o.db.Exec("insert or ignore into orderchaincodes(id) values(?)", int64(index))
+	return err
+}
+
+func (o *OrderChaincodesDB) Next() (uint32, error) {
+	o.lock.Lock()
+	defer o.lock.Unlock()
+	tx, err := o.db.Begin()
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+	return nextChaincodeIndex(tx)
+}
+
+func nextChaincodeIndex(tx *sql.Tx) (uint32, error) {
+	var max sql.NullInt64
+	if err := tx.QueryRow("select max(id) from orderchaincodes").Scan(&max); err != nil {
+		return 0, err
+	}
+	if !max.Valid {
+		return 0, nil
+	}
+	return uint32(max.Int64 + 1), nil
+}
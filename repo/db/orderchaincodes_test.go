@@ -0,0 +1,64 @@
+package db
+
+import (
+	"database/sql"
+	"sync"
+	"testing"
+)
+
+func newOrderChaincodesDB() OrderChaincodesDB {
+	conn, _ := sql.Open("sqlite3", ":memory:")
+	initDatabaseTables(conn, "")
+	return OrderChaincodesDB{
+		db:   &sharedDB{conn},
+		lock: new(sync.Mutex),
+	}
+}
+
+func TestOrderChaincodesAllocate(t *testing.T) {
+	odb := newOrderChaincodesDB()
+	next, err := odb.Next()
+	if err != nil {
+		t.Error(err)
+	}
+	if next != 0 {
+		t.Errorf("Expected next index 0, got %d", next)
+	}
+	for i := uint32(0); i < 3; i++ {
+		index, err := odb.Allocate()
+		if err != nil {
+			t.Error(err)
+		}
+		if index != i {
+			t.Errorf("Expected index %d, got %d", i, index)
+		}
+	}
+	next, err = odb.Next()
+	if err != nil {
+		t.Error(err)
+	}
+	if next != 3 {
+		t.Errorf("Expected next index 3, got %d", next)
+	}
+}
+
+func TestOrderChaincodesReserve(t *testing.T) {
+	odb := newOrderChaincodesDB()
+	if err := odb.Reserve(5); err != nil {
+		t.Error(err)
+	}
+	// Reserving an index below the next one changes nothing
+	if err := odb.Reserve(2); err != nil {
+		t.Error(err)
+	}
+	if err := odb.Reserve(5); err != nil {
+		t.Error(err)
+	}
+	index, err := odb.Allocate()
+	if err != nil {
+		t.Error(err)
+	}
+	if index != 6 {
+		t.Errorf("Expected index 6, got %d", index)
+	}
+}
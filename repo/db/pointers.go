@@ -2,7 +2,9 @@ package db
 
 import (
 	"database/sql"
+	"errors"
 	"github.com/OpenBazaar/openbazaar-go/ipfs"
+	"github.com/OpenBazaar/openbazaar-go/repo"
 	keys "github.com/ipfs/go-ipfs/blocks/key"
 	ps "gx/ipfs/QmQdnfvZQuhdT93LNc5bos52wAmdr3G2p6G8teLJMEN32P/go-libp2p-peerstore"
 	peer "gx/ipfs/QmRBqJF7hb8ZSpRcMwUt8hNhydWcxGEhtk81HKq6oUwKvs/go-libp2p-peer"
@@ -12,23 +14,30 @@ import (
 )
 
 type PointersDB struct {
-	db   *sql.DB
+	db   *sharedDB
 	lock *sync.Mutex
 }
 
 func (p *PointersDB) Put(pointer ipfs.Pointer) error {
+	if len(pointer.Value.Addrs) == 0 {
+		return errors.New("Pointer has no address")
+	}
+	var recipient string
+	if pointer.Recipient != "" {
+		recipient = pointer.Recipient.Pretty()
+	}
 	p.lock.Lock()
 	defer p.lock.Unlock()
 	tx, err := p.db.Begin()
 	if err != nil {
 		return err
 	}
-	stmt, err := tx.Prepare("insert into pointers(pointerID, key, address, purpose, timestamp) values(?,?,?,?,?)")
+	stmt, err := tx.Prepare("insert into pointers(pointerID, key, address, purpose, timestamp, recipient) values(?,?,?,?,?,?)")
 	if err != nil {
 		return err
 	}
 	defer stmt.Close()
-	_, err = stmt.Exec(pointer.Value.ID.Pretty(), pointer.Key.B58String(), pointer.Value.Addrs[0].String(), pointer.Purpose, int(time.Now().Unix()))
+	_, err = stmt.Exec(pointer.Value.ID.Pretty(), pointer.Key.B58String(), pointer.Value.Addrs[0].String(), pointer.Purpose, int(time.Now().Unix()), recipient)
 	if err != nil {
 		tx.Rollback()
 		return err
@@ -37,6 +46,48 @@ func (p *PointersDB) Put(pointer ipfs.Pointer) error {
 	return nil
 }
 
+func (p *PointersDB) Get(id peer.ID) (ipfs.Pointer, error) {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	var key string
+	var address string
+	var purpose int
+	var timestamp int
+	var recipient sql.NullString
+	err := p.db.QueryRow("select key, address, purpose, timestamp, recipient from pointers where pointerID=?", id.Pretty()).Scan(&key, &address, &purpose, &timestamp, &recipient)
+	if err == sql.ErrNoRows {
+		return ipfs.Pointer{}, repo.ErrPointerNotFound
+	} else if err != nil {
+		return ipfs.Pointer{}, err
+	}
+	maAddr, err := ma.NewMultiaddr(address)
+	if err != nil {
+		return ipfs.Pointer{}, err
+	}
+	recipientID, err := decodeRecipient(recipient)
+	if err != nil {
+		return ipfs.Pointer{}, err
+	}
+	return ipfs.Pointer{
+		Key: keys.B58KeyDecode(key),
+		Value: ps.PeerInfo{
+			ID:    id,
+			Addrs: []ma.Multiaddr{maAddr},
+		},
+		Purpose:   ipfs.Purpose(purpose),
+		Timestamp: time.Unix(int64(timestamp), 0),
+		Recipient: recipientID,
+	}, nil
+}
+
+// Pointers saved before the recipient was recorded have none
+func decodeRecipient(recipient sql.NullString) (peer.ID, error) {
+	if !recipient.Valid || recipient.String == "" {
+		return "", nil
+	}
+	return peer.IDB58Decode(recipient.String)
+}
+
 func (p *PointersDB) Delete(id peer.ID) error {
 	p.lock.Lock()
 	defer p.lock.Unlock()
@@ -60,7 +111,7 @@ func (p *PointersDB) DeleteAll(purpose ipfs.Purpose) error {
 func (p *PointersDB) GetAll() ([]ipfs.Pointer, error) {
 	p.lock.Lock()
 	defer p.lock.Unlock()
-	stm := "select * from pointers"
+	stm := "select pointerID, key, address, purpose, timestamp, recipient from pointers"
 	rows, err := p.db.Query(stm)
 	defer rows.Close()
 	if err != nil {
@@ -73,7 +124,8 @@ func (p *PointersDB) GetAll() ([]ipfs.Pointer, error) {
 		var address string
 		var purpose int
 		var timestamp int
-		if err := rows.Scan(&pointerID, &key, &address, &purpose, &timestamp); err != nil {
+		var recipient sql.NullString
+		if err := rows.Scan(&pointerID, &key, &address, &purpose, &timestamp, &recipient); err != nil {
 			return ret, err
 		}
 		maAddr, err := ma.NewMultiaddr(address)
@@ -84,6 +136,10 @@ func (p *PointersDB) GetAll() ([]ipfs.Pointer, error) {
 		if err != nil {
 			return ret, err
 		}
+		recipientID, err := decodeRecipient(recipient)
+		if err != nil {
+			return ret, err
+		}
 		pointer := ipfs.Pointer{
 			Key: keys.B58KeyDecode(key),
 			Value: ps.PeerInfo{
@@ -92,6 +148,7 @@ func (p *PointersDB) GetAll() ([]ipfs.Pointer, error) {
 			},
 			Purpose:   ipfs.Purpose(purpose),
 			Timestamp: time.Unix(int64(timestamp), 0),
+			Recipient: recipientID,
 		}
 		ret = append(ret, pointer)
 	}
@@ -4,6 +4,7 @@ import (
 	"crypto/rand"
 	"database/sql"
 	"github.com/OpenBazaar/openbazaar-go/ipfs"
+	"github.com/OpenBazaar/openbazaar-go/repo"
 	key "github.com/ipfs/go-ipfs/blocks/key"
 	ps "gx/ipfs/QmQdnfvZQuhdT93LNc5bos52wAmdr3G2p6G8teLJMEN32P/go-libp2p-peerstore"
 	peer "gx/ipfs/QmRBqJF7hb8ZSpRcMwUt8hNhydWcxGEhtk81HKq6oUwKvs/go-libp2p-peer"
@@ -21,7 +22,7 @@ func init() {
 	conn, _ := sql.Open("sqlite3", ":memory:")
 	initDatabaseTables(conn, "")
 	pdb = PointersDB{
-		db:   conn,
+		db:   &sharedDB{conn},
 		lock: new(sync.Mutex),
 	}
 	randBytes := make([]byte, 32)
@@ -37,6 +38,7 @@ func init() {
 		},
 		ipfs.MESSAGE,
 		time.Now(),
+		id,
 	}
 }
 
@@ -68,6 +70,31 @@ func TestPointersPut(t *testing.T) {
 	}
 }
 
+func TestGetPointer(t *testing.T) {
+	pdb.Put(pointer)
+	defer pdb.Delete(pointer.Value.ID)
+	p, err := pdb.Get(pointer.Value.ID)
+	if err != nil {
+		t.Error(err)
+	}
+	if p.Key != pointer.Key || p.Value.ID != pointer.Value.ID || p.Purpose != pointer.Purpose {
+		t.Error("Returned incorrect pointer")
+	}
+	if !p.Value.Addrs[0].Equal(pointer.Value.Addrs[0]) {
+		t.Error("Returned incorrect storage address")
+	}
+	if p.Recipient != pointer.Recipient {
+		t.Error("Returned incorrect recipient")
+	}
+	randBytes := make([]byte, 32)
+	rand.Read(randBytes)
+	h, _ := multihash.Encode(randBytes, multihash.SHA2_256)
+	id, _ := peer.IDFromBytes(h)
+	if _, err := pdb.Get(id); err != repo.ErrPointerNotFound {
+		t.Error("Expected ErrPointerNotFound for a missing pointer")
+	}
+}
+
 func TestDeletePointer(t *testing.T) {
 	pdb.Put(pointer)
 	err := pdb.Delete(pointer.Value.ID)
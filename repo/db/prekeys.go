@@ -0,0 +1,71 @@
+package db
+
+import (
+	"database/sql"
+	"sync"
+	"time"
+
+	"github.com/OpenBazaar/openbazaar-go/repo"
+)
+
+type PrekeysDB struct {
+	db   *sharedDB
+	lock *sync.Mutex
+}
+
+func (p *PrekeysDB) Put(id uint32, private []byte, created time.Time) error {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	tx, err := p.db.Begin()
+	if err != nil {
+		return err
+	}
+	stmt, err := tx.Prepare("insert or replace into prekeys(id, private, created) values(?,?,?)")
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+	defer stmt.Close()
+	_, err = stmt.Exec(int64(id), private, int(created.Unix()))
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+	tx.Commit()
+	return nil
+}
+
+func (p *PrekeysDB) Get(id uint32) ([]byte, error) {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	var private []byte
+	err := p.db.QueryRow("select private from prekeys where id=?", int64(id)).Scan(&private)
+	if err == sql.ErrNoRows {
+		return nil, repo.ErrPrekeyNotFound
+	} else if err != nil {
+		return nil, err
+	}
+	return private, nil
+}
+
+func (p *PrekeysDB) GetLatest() (uint32, []byte, time.Time, error) {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	var id int64
+	var private []byte
+	var created int
+	err := p.db.QueryRow("select id, private, created from prekeys order by created desc, id desc limit 1").Scan(&id, &private, &created)
+	if err == sql.ErrNoRows {
+		return 0, nil, time.Time{}, repo.ErrPrekeyNotFound
+	} else if err != nil {
+		return 0, nil, time.Time{}, err
+	}
+	return uint32(id), private, time.Unix(int64(created), 0), nil
+}
+
+func (p *PrekeysDB) DeleteSuperseded(before time.Time) error {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	_, err := p.db.Exec("delete from prekeys where created < (select max(created) from prekeys where created <= ?)", int(before.Unix()))
+	return err
+}
@@ -0,0 +1,80 @@
+package db
+
+import (
+	"database/sql"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/OpenBazaar/openbazaar-go/repo"
+)
+
+func newPrekeysDB() PrekeysDB {
+	conn, _ := sql.Open("sqlite3", ":memory:")
+	initDatabaseTables(conn, "")
+	return PrekeysDB{
+		db:   &sharedDB{conn},
+		lock: new(sync.Mutex),
+	}
+}
+
+func TestPrekeysPut(t *testing.T) {
+	pdb := newPrekeysDB()
+	if err := pdb.Put(1, []byte("private"), time.Now()); err != nil {
+		t.Error(err)
+	}
+	private, err := pdb.Get(1)
+	if err != nil {
+		t.Error(err)
+	}
+	if string(private) != "private" {
+		t.Error("Returned incorrect private key")
+	}
+	if _, err := pdb.Get(2); err != repo.ErrPrekeyNotFound {
+		t.Error("Expected ErrPrekeyNotFound")
+	}
+}
+
+func TestPrekeysGetLatest(t *testing.T) {
+	pdb := newPrekeysDB()
+	if _, _, _, err := pdb.GetLatest(); err != repo.ErrPrekeyNotFound {
+		t.Error("Expected ErrPrekeyNotFound")
+	}
+	now := time.Now()
+	pdb.Put(1, []byte("one"), now.Add(-time.Hour))
+	pdb.Put(2, []byte("two"), now)
+	id, private, created, err := pdb.GetLatest()
+	if err != nil {
+		t.Error(err)
+	}
+	if id != 2 || string(private) != "two" || created.Unix() != now.Unix() {
+		t.Errorf("Returned incorrect prekey %d", id)
+	}
+}
+
+func TestPrekeysDeleteSuperseded(t *testing.T) {
+	pdb := newPrekeysDB()
+	now := time.Now()
+	pdb.Put(1, []byte("one"), now.Add(-3*time.Hour))
+	pdb.Put(2, []byte("two"), now.Add(-2*time.Hour))
+	pdb.Put(3, []byte("three"), now)
+
+	// Prekey 2 was in use an hour ago so only prekey 1 goes
+	if err := pdb.DeleteSuperseded(now.Add(-time.Hour)); err != nil {
+		t.Error(err)
+	}
+	if _, err := pdb.Get(1); err != repo.ErrPrekeyNotFound {
+		t.Error("Superseded prekey was not deleted")
+	}
+	if _, err := pdb.Get(2); err != nil {
+		t.Error("Prekey in use was deleted")
+	}
+
+	// Nothing is deleted before the first prekey was created
+	if err := pdb.DeleteSuperseded(now.Add(-4 * time.Hour)); err != nil {
+		t.Error(err)
+	}
+	if _, err := pdb.Get(2); err != nil {
+		t.Error("Prekey in use was deleted")
+	}
+}
@@ -1,7 +1,6 @@
 package db
 
 import (
-	"database/sql"
 	"encoding/json"
 	"github.com/OpenBazaar/jsonpb"
 	"github.com/OpenBazaar/openbazaar-go/pb"
@@ -12,7 +11,7 @@ import (
 )
 
 type PurchasesDB struct {
-	db   *sql.DB
+	db   *sharedDB
 	lock *sync.Mutex
 }
 
@@ -137,6 +136,24 @@ func (p *PurchasesDB) GetAll() ([]string, error) {
 	return ret, nil
 }
 
+func (p *PurchasesDB) Count(states ...pb.OrderState) (int, error) {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	if len(states) == 0 {
+		return 0, nil
+	}
+	args := make([]interface{}, len(states))
+	for i, state := range states {
+		args[i] = int(state)
+	}
+	var count int
+	stm := "select count(*) from purchases where state in (?" + strings.Repeat(",?", len(states)-1) + ")"
+	if err := p.db.QueryRow(stm, args...).Scan(&count); err != nil {
+		return 0, err
+	}
+	return count, nil
+}
+
 func (p *PurchasesDB) GetByPaymentAddress(addr btc.Address) (*pb.RicardianContract, pb.OrderState, bool, []*spvwallet.TransactionRecord, error) {
 	p.lock.Lock()
 	defer p.lock.Unlock()
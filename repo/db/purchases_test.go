@@ -20,7 +20,7 @@ func init() {
 	conn, _ := sql.Open("sqlite3", ":memory:")
 	initDatabaseTables(conn, "")
 	purdb = PurchasesDB{
-		db:   conn,
+		db:   &sharedDB{conn},
 		lock: new(sync.Mutex),
 	}
 	contract = new(pb.RicardianContract)
@@ -260,3 +260,29 @@ func TestPurchasesGetByOrderId(t *testing.T) {
 		t.Error("Get by unknown orderId failed to return error")
 	}
 }
+
+func TestPurchasesCount(t *testing.T) {
+	conn, _ := sql.Open("sqlite3", ":memory:")
+	initDatabaseTables(conn, "")
+	pdb := PurchasesDB{
+		db:   &sharedDB{conn},
+		lock: new(sync.Mutex),
+	}
+	pdb.Put("orderID1", *contract, pb.OrderState_PENDING, false)
+	pdb.Put("orderID2", *contract, pb.OrderState_FUNDED, false)
+	pdb.Put("orderID3", *contract, pb.OrderState_COMPLETE, false)
+	count, err := pdb.Count(pb.OrderState_PENDING, pb.OrderState_FUNDED)
+	if err != nil {
+		t.Error(err)
+	}
+	if count != 2 {
+		t.Errorf("Expected 2 purchases, got %d", count)
+	}
+	count, err = pdb.Count()
+	if err != nil {
+		t.Error(err)
+	}
+	if count != 0 {
+		t.Errorf("Expected 0 purchases for no states, got %d", count)
+	}
+}
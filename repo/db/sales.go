@@ -1,7 +1,6 @@
 package db
 
 import (
-	"database/sql"
 	"encoding/json"
 	"github.com/OpenBazaar/jsonpb"
 	"github.com/OpenBazaar/openbazaar-go/pb"
@@ -12,7 +11,7 @@ import (
 )
 
 type SalesDB struct {
-	db   *sql.DB
+	db   *sharedDB
 	lock *sync.Mutex
 }
 
@@ -138,6 +137,24 @@ func (s *SalesDB) GetAll() ([]string, error) {
 	return ret, nil
 }
 
+func (s *SalesDB) Count(states ...pb.OrderState) (int, error) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	if len(states) == 0 {
+		return 0, nil
+	}
+	args := make([]interface{}, len(states))
+	for i, state := range states {
+		args[i] = int(state)
+	}
+	var count int
+	stm := "select count(*) from sales where state in (?" + strings.Repeat(",?", len(states)-1) + ")"
+	if err := s.db.QueryRow(stm, args...).Scan(&count); err != nil {
+		return 0, err
+	}
+	return count, nil
+}
+
 func (s *SalesDB) GetByPaymentAddress(addr btc.Address) (*pb.RicardianContract, pb.OrderState, bool, []*spvwallet.TransactionRecord, error) {
 	s.lock.Lock()
 	defer s.lock.Unlock()
@@ -19,7 +19,7 @@ func init() {
 	conn, _ := sql.Open("sqlite3", ":memory:")
 	initDatabaseTables(conn, "")
 	saldb = SalesDB{
-		db:   conn,
+		db:   &sharedDB{conn},
 		lock: new(sync.Mutex),
 	}
 	contract = new(pb.RicardianContract)
@@ -258,3 +258,22 @@ func TestSalesGetByOrderId(t *testing.T) {
 		t.Error("Get by unknown orderID failed to return error")
 	}
 }
+
+func TestSalesCount(t *testing.T) {
+	conn, _ := sql.Open("sqlite3", ":memory:")
+	initDatabaseTables(conn, "")
+	sdb := SalesDB{
+		db:   &sharedDB{conn},
+		lock: new(sync.Mutex),
+	}
+	sdb.Put("orderID1", *contract, pb.OrderState_FULFILLED, false)
+	sdb.Put("orderID2", *contract, pb.OrderState_DISPUTED, false)
+	sdb.Put("orderID3", *contract, pb.OrderState_CANCELED, false)
+	count, err := sdb.Count(pb.OrderState_FULFILLED)
+	if err != nil {
+		t.Error(err)
+	}
+	if count != 1 {
+		t.Errorf("Expected 1 sale, got %d", count)
+	}
+}
@@ -0,0 +1,65 @@
+package db
+
+import (
+	"database/sql"
+	"sync"
+	"time"
+
+	"github.com/OpenBazaar/openbazaar-go/repo"
+)
+
+type SessionsDB struct {
+	db   *sharedDB
+	lock *sync.Mutex
+}
+
+func (s *SessionsDB) Put(id, peerID string, state []byte) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	tx, err := s.db.Begin()
+	if err != nil {
+		return err
+	}
+	stmt, err := tx.Prepare("insert or replace into sessions(id, peerID, state, timestamp) values(?,?,?,?)")
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+	defer stmt.Close()
+	_, err = stmt.Exec(id, peerID, state, int(time.Now().Unix()))
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+	tx.Commit()
+	return nil
+}
+
+func (s *SessionsDB) Get(id string) (string, []byte, error) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	var peerID string
+	var state []byte
+	err := s.db.QueryRow("select peerID, state from sessions where id=?", id).Scan(&peerID, &state)
+	if err == sql.ErrNoRows {
+		return "", nil, repo.ErrSessionNotFound
+	} else if err != nil {
+		return "", nil, err
+	}
+	return peerID, state, nil
+}
+
+func (s *SessionsDB) GetByPeer(peerID string) (string, []byte, error) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	var id string
+	var state []byte
+	// Replacing a row gives it a new rowid so it breaks ties between sessions saved in the same second
+	err := s.db.QueryRow("select id, state from sessions where peerID=? order by timestamp desc, rowid desc limit 1", peerID).Scan(&id, &state)
+	if err == sql.ErrNoRows {
+		return "", nil, repo.ErrSessionNotFound
+	} else if err != nil {
+		return "", nil, err
+	}
+	return id, state, nil
+}
@@ -0,0 +1,69 @@
+package db
+
+import (
+	"database/sql"
+	"sync"
+	"testing"
+
+	"github.com/OpenBazaar/openbazaar-go/repo"
+)
+
+func newSessionsDB() SessionsDB {
+	conn, _ := sql.Open("sqlite3", ":memory:")
+	initDatabaseTables(conn, "")
+	return SessionsDB{
+		db:   &sharedDB{conn},
+		lock: new(sync.Mutex),
+	}
+}
+
+func TestSessionsPut(t *testing.T) {
+	sdb := newSessionsDB()
+	if err := sdb.Put("abc", "QmPeer", []byte("state")); err != nil {
+		t.Error(err)
+	}
+	if err := sdb.Put("abc", "QmPeer", []byte("new state")); err != nil {
+		t.Error(err)
+	}
+	peerID, state, err := sdb.Get("abc")
+	if err != nil {
+		t.Error(err)
+	}
+	if peerID != "QmPeer" || string(state) != "new state" {
+		t.Errorf("Returned incorrect session %s %s", peerID, string(state))
+	}
+}
+
+func TestSessionsGetNotFound(t *testing.T) {
+	sdb := newSessionsDB()
+	if _, _, err := sdb.Get("abc"); err != repo.ErrSessionNotFound {
+		t.Error("Expected ErrSessionNotFound")
+	}
+	if _, _, err := sdb.GetByPeer("QmPeer"); err != repo.ErrSessionNotFound {
+		t.Error("Expected ErrSessionNotFound")
+	}
+}
+
+func TestSessionsGetByPeer(t *testing.T) {
+	sdb := newSessionsDB()
+	sdb.Put("abc", "QmPeer", []byte("first"))
+	sdb.Put("def", "QmPeer", []byte("second"))
+	sdb.Put("ghi", "QmOther", []byte("other"))
+	id, state, err := sdb.GetByPeer("QmPeer")
+	if err != nil {
+		t.Error(err)
+	}
+	if id != "def" || string(state) != "second" {
+		t.Errorf("Returned incorrect session %s", id)
+	}
+
+	// Saving the older session again makes it the most recent
+	sdb.Put("abc", "QmPeer", []byte("third"))
+	id, _, err = sdb.GetByPeer("QmPeer")
+	if err != nil {
+		t.Error(err)
+	}
+	if id != "abc" {
+		t.Errorf("Returned incorrect session %s", id)
+	}
+}
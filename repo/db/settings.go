@@ -1,7 +1,6 @@
 package db
 
 import (
-	"database/sql"
 	"encoding/json"
 	"errors"
 	"sync"
@@ -10,7 +9,7 @@ import (
 )
 
 type SettingsDB struct {
-	db   *sql.DB
+	db   *sharedDB
 	lock *sync.Mutex
 }
 
@@ -1,12 +1,11 @@
 package db
 
 import (
-	"database/sql"
 	"sync"
 )
 
 type StateDB struct {
-	db   *sql.DB
+	db   *sharedDB
 	lock *sync.Mutex
 }
 
@@ -1,7 +1,6 @@
 package db
 
 import (
-	"database/sql"
 	"encoding/hex"
 	"github.com/OpenBazaar/spvwallet"
 	"github.com/btcsuite/btcd/chaincfg/chainhash"
@@ -12,7 +11,7 @@ import (
 )
 
 type UtxoDB struct {
-	db   *sql.DB
+	db   *sharedDB
 	lock *sync.Mutex
 }
 
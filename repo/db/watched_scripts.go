@@ -1,13 +1,12 @@
 package db
 
 import (
-	"database/sql"
 	"encoding/hex"
 	"sync"
 )
 
 type WatchedScriptsDB struct {
-	db   *sql.DB
+	db   *sharedDB
 	lock *sync.Mutex
 }
 
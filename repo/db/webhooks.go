@@ -0,0 +1,82 @@
+package db
+
+import (
+	"database/sql"
+	"sync"
+	"time"
+
+	"github.com/OpenBazaar/openbazaar-go/repo"
+)
+
+type WebhookDeliveriesDB struct {
+	db   *sharedDB
+	lock *sync.Mutex
+}
+
+func (w *WebhookDeliveriesDB) Put(d repo.WebhookDelivery) error {
+	w.lock.Lock()
+	defer w.lock.Unlock()
+	tx, err := w.db.Begin()
+	if err != nil {
+		return err
+	}
+	stmt, err := tx.Prepare("insert or replace into webhookdeliveries(id, url, event, payload, status, attempts, lastError, created, nextAttempt) values(?,?,?,?,?,?,?,?,?)")
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+	defer stmt.Close()
+	_, err = stmt.Exec(d.Id, d.URL, d.Event, []byte(d.Payload), d.Status, d.Attempts, d.LastError, d.Created.Unix(), d.NextAttempt.Unix())
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+	tx.Commit()
+	return nil
+}
+
+func (w *WebhookDeliveriesDB) GetDue(now time.Time) ([]repo.WebhookDelivery, error) {
+	w.lock.Lock()
+	defer w.lock.Unlock()
+	rows, err := w.db.Query("select id, url, event, payload, status, attempts, lastError, created, nextAttempt from webhookdeliveries where status=? and nextAttempt<=? order by nextAttempt", repo.DeliveryPending, now.Unix())
+	if err != nil {
+		return nil, err
+	}
+	return scanDeliveries(rows)
+}
+
+func (w *WebhookDeliveriesDB) GetAll(status string, limit int) ([]repo.WebhookDelivery, error) {
+	w.lock.Lock()
+	defer w.lock.Unlock()
+	stm := "select id, url, event, payload, status, attempts, lastError, created, nextAttempt from webhookdeliveries"
+	var args []interface{}
+	if status != "" {
+		stm += " where status=?"
+		args = append(args, status)
+	}
+	stm += " order by created desc, rowid desc limit ?"
+	args = append(args, limit)
+	rows, err := w.db.Query(stm, args...)
+	if err != nil {
+		return nil, err
+	}
+	return scanDeliveries(rows)
+}
+
+func scanDeliveries(rows *sql.Rows) ([]repo.WebhookDelivery, error) {
+	defer rows.Close()
+	var ret []repo.WebhookDelivery
+	for rows.Next() {
+		var d repo.WebhookDelivery
+		var payload []byte
+		var created, nextAttempt int64
+		if err := rows.Scan(&d.Id, &d.URL, &d.Event, &payload, &d.Status, &d.Attempts, &d.LastError, &created, &nextAttempt); err != nil {
+			return nil, err
+		}
+		d.Payload = payload
+		d.Created = time.Unix(created, 0)
+		d.NextAttempt = time.Unix(nextAttempt, 0)
+		ret = append(ret, d)
+	}
+	return ret, nil
+}
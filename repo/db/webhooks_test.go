@@ -0,0 +1,119 @@
+package db
+
+import (
+	"database/sql"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/OpenBazaar/openbazaar-go/repo"
+)
+
+var whdb WebhookDeliveriesDB
+
+func init() {
+	conn, _ := sql.Open("sqlite3", ":memory:")
+	initDatabaseTables(conn, "")
+	whdb = WebhookDeliveriesDB{
+		db:   &sharedDB{conn},
+		lock: new(sync.Mutex),
+	}
+}
+
+func newTestDelivery(id string, status string, created time.Time) repo.WebhookDelivery {
+	return repo.WebhookDelivery{
+		Id:          id,
+		URL:         "https://example.com/hook",
+		Event:       "order",
+		Payload:     []byte(`{"orderId":"abc"}`),
+		Status:      status,
+		Created:     created,
+		NextAttempt: created,
+	}
+}
+
+func TestPutWebhookDelivery(t *testing.T) {
+	now := time.Now()
+	if err := whdb.Put(newTestDelivery("put", repo.DeliveryPending, now)); err != nil {
+		t.Error(err)
+	}
+	deliveries, err := whdb.GetAll("", 100)
+	if err != nil {
+		t.Error(err)
+	}
+	for _, d := range deliveries {
+		if d.Id != "put" {
+			continue
+		}
+		if d.URL != "https://example.com/hook" || d.Event != "order" || string(d.Payload) != `{"orderId":"abc"}` {
+			t.Errorf("Returned incorrect delivery %v", d)
+		}
+		if d.Created.Unix() != now.Unix() {
+			t.Error("Returned incorrect created time")
+		}
+		return
+	}
+	t.Error("Failed to return the delivery")
+}
+
+func TestPutWebhookDeliveryReplaces(t *testing.T) {
+	d := newTestDelivery("replace", repo.DeliveryPending, time.Now())
+	whdb.Put(d)
+	d.Status = repo.DeliveryFailed
+	d.Attempts = 3
+	d.LastError = "500 Internal Server Error"
+	if err := whdb.Put(d); err != nil {
+		t.Error(err)
+	}
+	deliveries, err := whdb.GetAll(repo.DeliveryFailed, 100)
+	if err != nil {
+		t.Error(err)
+	}
+	for _, d := range deliveries {
+		if d.Id == "replace" {
+			if d.Attempts != 3 || d.LastError != "500 Internal Server Error" {
+				t.Errorf("Delivery was not updated %v", d)
+			}
+			return
+		}
+	}
+	t.Error("Failed to return the updated delivery")
+}
+
+func TestGetDueWebhookDeliveries(t *testing.T) {
+	now := time.Now()
+	whdb.Put(newTestDelivery("due", repo.DeliveryPending, now.Add(-time.Minute)))
+	later := newTestDelivery("later", repo.DeliveryPending, now)
+	later.NextAttempt = now.Add(time.Hour)
+	whdb.Put(later)
+	whdb.Put(newTestDelivery("done", repo.DeliveryDelivered, now.Add(-time.Minute)))
+	due, err := whdb.GetDue(now)
+	if err != nil {
+		t.Error(err)
+	}
+	found := false
+	for _, d := range due {
+		switch d.Id {
+		case "due":
+			found = true
+		case "later", "done":
+			t.Errorf("Returned delivery %s which is not due", d.Id)
+		}
+	}
+	if !found {
+		t.Error("Failed to return the due delivery")
+	}
+}
+
+func TestGetAllWebhookDeliveriesLimit(t *testing.T) {
+	now := time.Now()
+	whdb.Put(newTestDelivery("old", repo.DeliveryDelivered, now.Add(-time.Hour)))
+	whdb.Put(newTestDelivery("new", repo.DeliveryDelivered, now.Add(time.Hour)))
+	deliveries, err := whdb.GetAll(repo.DeliveryDelivered, 1)
+	if err != nil {
+		t.Error(err)
+	}
+	if len(deliveries) != 1 || deliveries[0].Id != "new" {
+		t.Errorf("Expected the newest delivery got %v", deliveries)
+	}
+}
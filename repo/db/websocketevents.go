@@ -0,0 +1,73 @@
+package db
+
+import (
+	"database/sql"
+	"sync"
+
+	"github.com/OpenBazaar/openbazaar-go/repo"
+)
+
+type WebsocketEventsDB struct {
+	db   *sharedDB
+	lock *sync.Mutex
+}
+
+func (w *WebsocketEventsDB) Put(event repo.WebsocketEvent) error {
+	w.lock.Lock()
+	defer w.lock.Unlock()
+	tx, err := w.db.Begin()
+	if err != nil {
+		return err
+	}
+	stmt, err := tx.Prepare("insert or replace into websocketevents(seq, topic, envelope) values(?,?,?)")
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+	defer stmt.Close()
+	_, err = stmt.Exec(int64(event.Seq), event.Topic, event.Envelope)
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+	tx.Commit()
+	return nil
+}
+
+func (w *WebsocketEventsDB) GetSince(seq uint64) ([]repo.WebsocketEvent, error) {
+	w.lock.Lock()
+	defer w.lock.Unlock()
+	rows, err := w.db.Query("select seq, topic, envelope from websocketevents where seq>? order by seq", int64(seq))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var ret []repo.WebsocketEvent
+	for rows.Next() {
+		var event repo.WebsocketEvent
+		var s int64
+		if err := rows.Scan(&s, &event.Topic, &event.Envelope); err != nil {
+			return nil, err
+		}
+		event.Seq = uint64(s)
+		ret = append(ret, event)
+	}
+	return ret, nil
+}
+
+func (w *WebsocketEventsDB) LastSeq() (uint64, error) {
+	w.lock.Lock()
+	defer w.lock.Unlock()
+	var seq sql.NullInt64
+	if err := w.db.QueryRow("select max(seq) from websocketevents").Scan(&seq); err != nil {
+		return 0, err
+	}
+	return uint64(seq.Int64), nil
+}
+
+func (w *WebsocketEventsDB) Prune(keep int) error {
+	w.lock.Lock()
+	defer w.lock.Unlock()
+	_, err := w.db.Exec("delete from websocketevents where seq not in (select seq from websocketevents order by seq desc limit ?)", keep)
+	return err
+}
@@ -0,0 +1,73 @@
+package db
+
+import (
+	"database/sql"
+	"sync"
+	"testing"
+
+	"github.com/OpenBazaar/openbazaar-go/repo"
+)
+
+func newWebsocketEventsDB() WebsocketEventsDB {
+	conn, _ := sql.Open("sqlite3", ":memory:")
+	initDatabaseTables(conn, "")
+	return WebsocketEventsDB{
+		db:   &sharedDB{conn},
+		lock: new(sync.Mutex),
+	}
+}
+
+func TestWebsocketEventsGetSince(t *testing.T) {
+	wsdb := newWebsocketEventsDB()
+	for i := uint64(1); i <= 3; i++ {
+		if err := wsdb.Put(repo.WebsocketEvent{Seq: i, Topic: "orders", Envelope: []byte("{}")}); err != nil {
+			t.Error(err)
+		}
+	}
+	events, err := wsdb.GetSince(1)
+	if err != nil {
+		t.Error(err)
+	}
+	if len(events) != 2 || events[0].Seq != 2 || events[1].Seq != 3 {
+		t.Errorf("Returned incorrect events %v", events)
+	}
+	if events[0].Topic != "orders" || string(events[0].Envelope) != "{}" {
+		t.Errorf("Returned incorrect event %v", events[0])
+	}
+}
+
+func TestWebsocketEventsLastSeq(t *testing.T) {
+	wsdb := newWebsocketEventsDB()
+	seq, err := wsdb.LastSeq()
+	if err != nil {
+		t.Error(err)
+	}
+	if seq != 0 {
+		t.Errorf("Expected 0 for an empty buffer got %d", seq)
+	}
+	wsdb.Put(repo.WebsocketEvent{Seq: 7, Topic: "wallet", Envelope: []byte("{}")})
+	seq, err = wsdb.LastSeq()
+	if err != nil {
+		t.Error(err)
+	}
+	if seq != 7 {
+		t.Errorf("Expected 7 got %d", seq)
+	}
+}
+
+func TestWebsocketEventsPrune(t *testing.T) {
+	wsdb := newWebsocketEventsDB()
+	for i := uint64(1); i <= 5; i++ {
+		wsdb.Put(repo.WebsocketEvent{Seq: i, Topic: "publishing", Envelope: []byte("{}")})
+	}
+	if err := wsdb.Prune(2); err != nil {
+		t.Error(err)
+	}
+	events, err := wsdb.GetSince(0)
+	if err != nil {
+		t.Error(err)
+	}
+	if len(events) != 2 || events[0].Seq != 4 || events[1].Seq != 5 {
+		t.Errorf("Expected the two most recent events got %v", events)
+	}
+}
@@ -194,6 +194,7 @@ func addConfigExtensions(repoRoot string, testnet bool) error {
 	var a APIConfig = APIConfig{
 		Enabled:     true,
 		HTTPHeaders: nil,
+		GRPCAddress: "/ip4/127.0.0.1/tcp/4003",
 	}
 	if err := extendConfigFile(r, "Wallet", w); err != nil {
 		return err
@@ -210,6 +211,18 @@ func addConfigExtensions(repoRoot string, testnet bool) error {
 	if err := extendConfigFile(r, "JSON-API", a); err != nil {
 		return err
 	}
+	if err := extendConfigFile(r, "Webhooks", []WebhookConfig{}); err != nil {
+		return err
+	}
+	if err := extendConfigFile(r, "S3", defaultS3Config); err != nil {
+		return err
+	}
+	if err := extendConfigFile(r, "MessageRetriever", map[string]string{
+		"Interval":       "1h",
+		"ActiveInterval": "5m",
+	}); err != nil {
+		return err
+	}
 	if err := r.Close(); err != nil {
 		return err
 	}
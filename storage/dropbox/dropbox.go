@@ -4,9 +4,12 @@ import (
 	"bytes"
 	"crypto/sha256"
 	"encoding/hex"
+	"errors"
 	peer "gx/ipfs/QmRBqJF7hb8ZSpRcMwUt8hNhydWcxGEhtk81HKq6oUwKvs/go-libp2p-peer"
 	mh "gx/ipfs/QmYf7ng2hG5XBtJA3tN34DQ2GUN5HNksEw1rLDkmr6vGku/go-multihash"
 	ma "gx/ipfs/QmYzDkkgAEmrcNzFCiYo6L1dTX4EAG1gZkbtdbd9trL4vd/go-multiaddr"
+	neturl "net/url"
+	"path"
 
 	"github.com/dropbox/dropbox-sdk-go-unofficial"
 	"github.com/dropbox/dropbox-sdk-go-unofficial/files"
@@ -64,3 +67,31 @@ func (s *DropBoxStorage) Store(peerID peer.ID, ciphertext []byte) (ma.Multiaddr,
 	}
 	return addr, nil
 }
+
+func (s *DropBoxStorage) Delete(addr ma.Multiaddr) error {
+	if len(addr.Protocols()) != 2 || addr.Protocols()[0].Code != ma.P_IPFS || addr.Protocols()[1].Code != ma.P_HTTPS {
+		return errors.New("Not a Dropbox message address")
+	}
+
+	// Decode the shared link. The file name is the last element of its path.
+	enc, err := addr.ValueForProtocol(ma.P_IPFS)
+	if err != nil {
+		return err
+	}
+	m, err := mh.FromB58String(enc)
+	if err != nil {
+		return err
+	}
+	d, err := mh.Decode(m)
+	if err != nil {
+		return err
+	}
+	u, err := neturl.Parse(string(d.Digest))
+	if err != nil {
+		return err
+	}
+
+	api := dropbox.Client(s.apiToken, dropbox.Options{Verbose: true})
+	_, err = api.Delete(files.NewDeleteArg("/" + path.Base(u.Path)))
+	return err
+}
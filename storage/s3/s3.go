@@ -0,0 +1,186 @@
+package s3
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	peer "gx/ipfs/QmRBqJF7hb8ZSpRcMwUt8hNhydWcxGEhtk81HKq6oUwKvs/go-libp2p-peer"
+	mh "gx/ipfs/QmYf7ng2hG5XBtJA3tN34DQ2GUN5HNksEw1rLDkmr6vGku/go-multihash"
+	ma "gx/ipfs/QmYzDkkgAEmrcNzFCiYo6L1dTX4EAG1gZkbtdbd9trL4vd/go-multiaddr"
+
+	"github.com/OpenBazaar/openbazaar-go/repo"
+)
+
+// The URL of a message is carried in the digest of a multihash which can't be longer
+// than this. Presigned URLs are far too long so the objects must be publicly readable.
+const maxURLLength = 127
+
+// Stores offline messages as objects in an S3 compatible bucket such as AWS, MinIO or
+// Wasabi. Requests are signed with AWS signature version 4 and use path style URLs so
+// any endpoint works without DNS for the bucket. The recipient downloads the object
+// without credentials so it must be public, either through its ACL or a bucket policy.
+type S3Storage struct {
+	cfg    repo.S3Config
+	client *http.Client
+}
+
+func NewS3Storage(cfg repo.S3Config) (*S3Storage, error) {
+	if cfg.Bucket == "" {
+		return nil, errors.New("S3 bucket not set in config file")
+	}
+	cfg.Endpoint = strings.TrimSuffix(cfg.Endpoint, "/")
+	s := &S3Storage{
+		cfg:    cfg,
+		client: &http.Client{Timeout: time.Minute},
+	}
+	if len(s.objectURL(strings.Repeat("0", 32))) > maxURLLength {
+		return nil, errors.New("S3 object URLs would be too long to fit in a pointer, use a shorter endpoint, bucket or prefix")
+	}
+
+	// Make sure the bucket exists and the credentials work
+	if err := s.do("HEAD", s.cfg.Endpoint+"/"+s.cfg.Bucket, nil, nil); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+func (s *S3Storage) Store(peerID peer.ID, ciphertext []byte) (ma.Multiaddr, error) {
+	// Half the hash is plenty to be unique and leaves room for the bucket and prefix
+	hash := sha256.Sum256(ciphertext)
+	u := s.objectURL(hex.EncodeToString(hash[:16]))
+
+	header := make(http.Header)
+	header.Set("Content-Type", "application/octet-stream")
+	if s.cfg.ACL != "" {
+		header.Set("x-amz-acl", s.cfg.ACL)
+	}
+	if err := s.do("PUT", u, header, ciphertext); err != nil {
+		return nil, err
+	}
+
+	// Create encoded multiaddr
+	b, err := mh.Encode([]byte(u), mh.SHA1)
+	if err != nil {
+		return nil, err
+	}
+	m, err := mh.Cast(b)
+	if err != nil {
+		return nil, err
+	}
+	addr, err := ma.NewMultiaddr("/ipfs/" + m.B58String() + "/https/")
+	if err != nil {
+		return nil, err
+	}
+	return addr, nil
+}
+
+func (s *S3Storage) Delete(addr ma.Multiaddr) error {
+	if len(addr.Protocols()) != 2 || addr.Protocols()[0].Code != ma.P_IPFS || addr.Protocols()[1].Code != ma.P_HTTPS {
+		return errors.New("Not an S3 message address")
+	}
+	enc, err := addr.ValueForProtocol(ma.P_IPFS)
+	if err != nil {
+		return err
+	}
+	m, err := mh.FromB58String(enc)
+	if err != nil {
+		return err
+	}
+	d, err := mh.Decode(m)
+	if err != nil {
+		return err
+	}
+	u := string(d.Digest)
+	if !strings.HasPrefix(u, s.objectURL("")) {
+		return errors.New("Not an S3 message address")
+	}
+	return s.do("DELETE", u, nil, nil)
+}
+
+func (s *S3Storage) objectURL(name string) string {
+	return s.cfg.Endpoint + "/" + s.cfg.Bucket + "/" + s.cfg.Prefix + name
+}
+
+// Make a signed request and return an error unless it succeeds
+func (s *S3Storage) do(method, u string, header http.Header, body []byte) error {
+	req, err := http.NewRequest(method, u, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	for k, v := range header {
+		req.Header[k] = v
+	}
+	payloadHash := sha256.Sum256(body)
+	s.sign(req, hex.EncodeToString(payloadHash[:]), time.Now())
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		msg, _ := ioutil.ReadAll(resp.Body)
+		return fmt.Errorf("S3 %s %s: %s %s", method, req.URL.Path, resp.Status, bytes.TrimSpace(msg))
+	}
+	return nil
+}
+
+// Add an AWS signature version 4 Authorization header to a request. The host and every
+// header already set on the request are signed.
+func (s *S3Storage) sign(req *http.Request, payloadHash string, now time.Time) {
+	now = now.UTC()
+	amzDate := now.Format("20060102T150405Z")
+	date := now.Format("20060102")
+	req.Header.Set("x-amz-date", amzDate)
+	req.Header.Set("x-amz-content-sha256", payloadHash)
+
+	headers := map[string]string{"host": req.URL.Host}
+	for k, v := range req.Header {
+		headers[strings.ToLower(k)] = strings.TrimSpace(strings.Join(v, ","))
+	}
+	var names []string
+	for k := range headers {
+		names = append(names, k)
+	}
+	sort.Strings(names)
+	var canonicalHeaders string
+	for _, k := range names {
+		canonicalHeaders += k + ":" + headers[k] + "\n"
+	}
+	signedHeaders := strings.Join(names, ";")
+
+	canonicalRequest := strings.Join([]string{
+		req.Method,
+		req.URL.EscapedPath(),
+		strings.Replace(req.URL.Query().Encode(), "+", "%20", -1),
+		canonicalHeaders,
+		signedHeaders,
+		payloadHash,
+	}, "\n")
+	requestHash := sha256.Sum256([]byte(canonicalRequest))
+
+	scope := date + "/" + s.cfg.Region + "/s3/aws4_request"
+	stringToSign := "AWS4-HMAC-SHA256\n" + amzDate + "\n" + scope + "\n" + hex.EncodeToString(requestHash[:])
+
+	key := hmacSHA256([]byte("AWS4"+s.cfg.SecretAccessKey), date)
+	key = hmacSHA256(key, s.cfg.Region)
+	key = hmacSHA256(key, "s3")
+	key = hmacSHA256(key, "aws4_request")
+	signature := hex.EncodeToString(hmacSHA256(key, stringToSign))
+
+	req.Header.Set("Authorization", "AWS4-HMAC-SHA256 Credential="+s.cfg.AccessKeyID+"/"+scope+", SignedHeaders="+signedHeaders+", Signature="+signature)
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(data))
+	return mac.Sum(nil)
+}
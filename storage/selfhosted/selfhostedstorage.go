@@ -3,6 +3,7 @@ package selfhosted
 import (
 	"crypto/sha256"
 	"encoding/hex"
+	"errors"
 	peer "gx/ipfs/QmRBqJF7hb8ZSpRcMwUt8hNhydWcxGEhtk81HKq6oUwKvs/go-libp2p-peer"
 	ma "gx/ipfs/QmYzDkkgAEmrcNzFCiYo6L1dTX4EAG1gZkbtdbd9trL4vd/go-multiaddr"
 	"os"
@@ -42,10 +43,15 @@ func (s *SelfHostedStorage) Store(peerID peer.ID, ciphertext []byte) (ma.Multiad
 	if ferr != nil {
 		return nil, ferr
 	}
+	f.Close()
 	addr, err := ipfs.AddFile(s.context, filePath)
 	if err != nil {
 		return nil, err
 	}
+	// Name the file after its IPFS hash so it can be found from the address when it's deleted
+	if err := os.Rename(filePath, path.Join(s.repoPath, "outbox", addr)); err != nil {
+		return nil, err
+	}
 	for _, g := range s.crossPostGateways {
 		http.Post(g.String()+"ipfs/", "application/x-www-form-urlencoded", bytes.NewReader(ciphertext))
 	}
@@ -55,3 +61,18 @@ func (s *SelfHostedStorage) Store(peerID peer.ID, ciphertext []byte) (ma.Multiad
 	}
 	return maAddr, nil
 }
+
+func (s *SelfHostedStorage) Delete(addr ma.Multiaddr) error {
+	if len(addr.Protocols()) != 1 || addr.Protocols()[0].Code != ma.P_IPFS {
+		return errors.New("Not a self-hosted message address")
+	}
+	hash, err := addr.ValueForProtocol(ma.P_IPFS)
+	if err != nil {
+		return err
+	}
+	err = os.Remove(path.Join(s.repoPath, "outbox", hash))
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return ipfs.UnPinDir(s.context, hash)
+}
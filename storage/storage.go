@@ -21,4 +21,9 @@ type OfflineMessagingStorage interface {
 
 	   Note all messages are encrypted before passed in here. */
 	Store(peerID peer.ID, ciphertext []byte) (ma.Multiaddr, error)
+
+	/* Delete a message stored at an address returned by Store. This is called once
+	   the recipient acknowledges the message or its pointer expires so the storage
+	   doesn't grow without bound. */
+	Delete(addr ma.Multiaddr) error
 }